@@ -17,6 +17,7 @@ package backend
 
 import (
 	"crypto/sha256"
+	"fmt"
 	"hash"
 
 	"github.com/consensys/gnark/constraint/solver"
@@ -143,6 +144,7 @@ type VerifierConfig struct {
 	HashToFieldFn  hash.Hash
 	ChallengeHash  hash.Hash
 	KZGFoldingHash hash.Hash
+	BatchBisection bool
 }
 
 // NewVerifierConfig returns a default [VerifierConfig] with given verifier
@@ -194,3 +196,26 @@ func WithVerifierKZGFoldingHashFunction(hFunc hash.Hash) VerifierOption {
 		return nil
 	}
 }
+
+// WithBatchBisection enables the search of invalid proofs in batch
+// verification. If the batch is invalid, then the verifier recursively
+// verifies the halves of the batch and returns a [*BatchVerifyError] with the
+// indices of the invalid proofs. Without the option the batch verifier only
+// returns an error without identifying the invalid proofs.
+func WithBatchBisection() VerifierOption {
+	return func(pc *VerifierConfig) error {
+		pc.BatchBisection = true
+		return nil
+	}
+}
+
+// BatchVerifyError is returned by batch verification with the
+// [WithBatchBisection] option when some of the proofs are invalid.
+type BatchVerifyError struct {
+	// Invalid are the indices of the invalid proofs in increasing order.
+	Invalid []int
+}
+
+func (e *BatchVerifyError) Error() string {
+	return fmt.Sprintf("invalid proofs at indices %v", e.Invalid)
+}
@@ -0,0 +1,205 @@
+// Package aggregate implements the aggregation of Groth16 proofs of the same
+// circuit following SnarkPack. The aggregated proof has logarithmic size and
+// verification time in the number of aggregated proofs.
+//
+// The structured reference string (SRS) of the aggregation is derived from
+// the outputs of two independent powers-of-tau ceremonies using the NewSRS
+// function of the curve specific packages. Aggregation is supported for
+// BN254 and BLS12-381 and for circuits without commitments.
+//
+// # See also
+//
+// https://eprint.iacr.org/2021/529.pdf
+package aggregate
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	aggregate_bls12381 "github.com/consensys/gnark/backend/groth16/aggregate/bls12-381"
+	aggregate_bn254 "github.com/consensys/gnark/backend/groth16/aggregate/bn254"
+	groth16_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	gnarkio "github.com/consensys/gnark/io"
+)
+
+type aggregateObject interface {
+	gnarkio.WriterRawTo
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// AggregatedProof represents an aggregation of Groth16 proofs generated by
+// Aggregate.
+//
+// it's underlying implementation is curve specific (see gnark/backend/groth16/aggregate/...)
+type AggregatedProof interface {
+	aggregateObject
+}
+
+// ProvingKey represents the part of the SRS used for aggregating proofs.
+//
+// it's underlying implementation is curve specific (see gnark/backend/groth16/aggregate/...)
+type ProvingKey interface {
+	aggregateObject
+}
+
+// VerifyingKey represents the part of the SRS used for verifying aggregated
+// proofs.
+//
+// it's underlying implementation is curve specific (see gnark/backend/groth16/aggregate/...)
+type VerifyingKey interface {
+	aggregateObject
+}
+
+// Aggregate aggregates the Groth16 proofs of the same circuit with verifying
+// key vk. The number of proofs must be a power of two not larger than the
+// size of the SRS and the public witnesses must be given in the same order as
+// the proofs.
+func Aggregate(pk ProvingKey, proofs []groth16.Proof, vk groth16.VerifyingKey, publicWitnesses []witness.Witness) (AggregatedProof, error) {
+	switch _pk := pk.(type) {
+	case *aggregate_bn254.ProvingKey:
+		ps := make([]*groth16_bn254.Proof, len(proofs))
+		for i := range proofs {
+			p, ok := proofs[i].(*groth16_bn254.Proof)
+			if !ok {
+				return nil, fmt.Errorf("proof %d: expected a BN254 proof", i)
+			}
+			ps[i] = p
+		}
+		ws := make([]fr_bn254.Vector, len(publicWitnesses))
+		for i := range publicWitnesses {
+			w, ok := publicWitnesses[i].Vector().(fr_bn254.Vector)
+			if !ok {
+				return nil, witness.ErrInvalidWitness
+			}
+			ws[i] = w
+		}
+		gvk, ok := vk.(*groth16_bn254.VerifyingKey)
+		if !ok {
+			return nil, fmt.Errorf("expected a BN254 verifying key")
+		}
+		proof, err := aggregate_bn254.Aggregate(_pk, ps, gvk, ws)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+	case *aggregate_bls12381.ProvingKey:
+		ps := make([]*groth16_bls12381.Proof, len(proofs))
+		for i := range proofs {
+			p, ok := proofs[i].(*groth16_bls12381.Proof)
+			if !ok {
+				return nil, fmt.Errorf("proof %d: expected a BLS12-381 proof", i)
+			}
+			ps[i] = p
+		}
+		ws := make([]fr_bls12381.Vector, len(publicWitnesses))
+		for i := range publicWitnesses {
+			w, ok := publicWitnesses[i].Vector().(fr_bls12381.Vector)
+			if !ok {
+				return nil, witness.ErrInvalidWitness
+			}
+			ws[i] = w
+		}
+		gvk, ok := vk.(*groth16_bls12381.VerifyingKey)
+		if !ok {
+			return nil, fmt.Errorf("expected a BLS12-381 verifying key")
+		}
+		proof, err := aggregate_bls12381.Aggregate(_pk, ps, gvk, ws)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+	default:
+		panic("unrecognized aggregation proving key")
+	}
+}
+
+// Verify verifies the aggregated proof of the Groth16 proofs of the circuit
+// with verifying key gvk against the given public witnesses.
+func Verify(vk VerifyingKey, gvk groth16.VerifyingKey, proof AggregatedProof, publicWitnesses []witness.Witness) error {
+	switch _proof := proof.(type) {
+	case *aggregate_bn254.AggregatedProof:
+		ws := make([]fr_bn254.Vector, len(publicWitnesses))
+		for i := range publicWitnesses {
+			w, ok := publicWitnesses[i].Vector().(fr_bn254.Vector)
+			if !ok {
+				return witness.ErrInvalidWitness
+			}
+			ws[i] = w
+		}
+		_vk, ok := vk.(*aggregate_bn254.VerifyingKey)
+		if !ok {
+			return fmt.Errorf("expected a BN254 aggregation verifying key")
+		}
+		_gvk, ok := gvk.(*groth16_bn254.VerifyingKey)
+		if !ok {
+			return fmt.Errorf("expected a BN254 verifying key")
+		}
+		return aggregate_bn254.Verify(_vk, _gvk, _proof, ws)
+	case *aggregate_bls12381.AggregatedProof:
+		ws := make([]fr_bls12381.Vector, len(publicWitnesses))
+		for i := range publicWitnesses {
+			w, ok := publicWitnesses[i].Vector().(fr_bls12381.Vector)
+			if !ok {
+				return witness.ErrInvalidWitness
+			}
+			ws[i] = w
+		}
+		_vk, ok := vk.(*aggregate_bls12381.VerifyingKey)
+		if !ok {
+			return fmt.Errorf("expected a BLS12-381 aggregation verifying key")
+		}
+		_gvk, ok := gvk.(*groth16_bls12381.VerifyingKey)
+		if !ok {
+			return fmt.Errorf("expected a BLS12-381 verifying key")
+		}
+		return aggregate_bls12381.Verify(_vk, _gvk, _proof, ws)
+	default:
+		panic("unrecognized aggregated proof")
+	}
+}
+
+// NewAggregatedProof instantiates a curve-typed AggregatedProof and returns
+// an interface object. This function exists for serialization purposes.
+func NewAggregatedProof(curveID ecc.ID) AggregatedProof {
+	switch curveID {
+	case ecc.BN254:
+		return &aggregate_bn254.AggregatedProof{}
+	case ecc.BLS12_381:
+		return &aggregate_bls12381.AggregatedProof{}
+	default:
+		panic("not implemented")
+	}
+}
+
+// NewProvingKey instantiates a curve-typed ProvingKey and returns an interface
+// object. This function exists for serialization purposes.
+func NewProvingKey(curveID ecc.ID) ProvingKey {
+	switch curveID {
+	case ecc.BN254:
+		return &aggregate_bn254.ProvingKey{}
+	case ecc.BLS12_381:
+		return &aggregate_bls12381.ProvingKey{}
+	default:
+		panic("not implemented")
+	}
+}
+
+// NewVerifyingKey instantiates a curve-typed VerifyingKey and returns an
+// interface object. This function exists for serialization purposes.
+func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
+	switch curveID {
+	case ecc.BN254:
+		return &aggregate_bn254.VerifyingKey{}
+	case ecc.BLS12_381:
+		return &aggregate_bls12381.VerifyingKey{}
+	default:
+		panic("not implemented")
+	}
+}
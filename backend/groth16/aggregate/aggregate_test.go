@@ -0,0 +1,142 @@
+package aggregate_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/groth16/aggregate"
+	aggregate_bls12381 "github.com/consensys/gnark/backend/groth16/aggregate/bls12-381"
+	aggregate_bn254 "github.com/consensys/gnark/backend/groth16/aggregate/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/io"
+	"github.com/consensys/gnark/test"
+)
+
+type circuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *circuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.Y, api.Mul(c.X, c.X, c.X))
+	return nil
+}
+
+// powersOfTau returns the first n powers of a fixed secret τ. It is only for
+// testing.
+func powersOfTau(tau int64, n int, mod *big.Int) []*big.Int {
+	pows := make([]*big.Int, n)
+	pows[0] = big.NewInt(1)
+	for i := 1; i < n; i++ {
+		pows[i] = new(big.Int).Mul(pows[i-1], big.NewInt(tau))
+		pows[i].Mod(pows[i], mod)
+	}
+	return pows
+}
+
+func newSRS(assert *test.Assert, curveID ecc.ID, size int) (aggregate.ProvingKey, aggregate.VerifyingKey) {
+	switch curveID {
+	case ecc.BN254:
+		_, _, g1, g2 := bn254.Generators()
+		tau := func(s int64) aggregate_bn254.PowersOfTau {
+			var ret aggregate_bn254.PowersOfTau
+			for i, p := range powersOfTau(s, 2*size, curveID.ScalarField()) {
+				ret.G1 = append(ret.G1, *new(bn254.G1Affine).ScalarMultiplication(&g1, p))
+				if i < size {
+					ret.G2 = append(ret.G2, *new(bn254.G2Affine).ScalarMultiplication(&g2, p))
+				}
+			}
+			return ret
+		}
+		srs, err := aggregate_bn254.NewSRS(uint64(size), tau(3), tau(5))
+		assert.NoError(err)
+		return &srs.Pk, &srs.Vk
+	case ecc.BLS12_381:
+		_, _, g1, g2 := bls12381.Generators()
+		tau := func(s int64) aggregate_bls12381.PowersOfTau {
+			var ret aggregate_bls12381.PowersOfTau
+			for i, p := range powersOfTau(s, 2*size, curveID.ScalarField()) {
+				ret.G1 = append(ret.G1, *new(bls12381.G1Affine).ScalarMultiplication(&g1, p))
+				if i < size {
+					ret.G2 = append(ret.G2, *new(bls12381.G2Affine).ScalarMultiplication(&g2, p))
+				}
+			}
+			return ret
+		}
+		srs, err := aggregate_bls12381.NewSRS(uint64(size), tau(3), tau(5))
+		assert.NoError(err)
+		return &srs.Pk, &srs.Vk
+	default:
+		panic("not implemented")
+	}
+}
+
+func prove(assert *test.Assert, curveID ecc.ID, n int) ([]groth16.Proof, groth16.VerifyingKey, []witness.Witness) {
+	ccs, err := frontend.Compile(curveID.ScalarField(), r1cs.NewBuilder, &circuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	proofs := make([]groth16.Proof, n)
+	publicWitnesses := make([]witness.Witness, n)
+	for i := 0; i < n; i++ {
+		w, err := frontend.NewWitness(&circuit{X: i + 2, Y: (i + 2) * (i + 2) * (i + 2)}, curveID.ScalarField())
+		assert.NoError(err)
+		proofs[i], err = groth16.Prove(ccs, pk, w)
+		assert.NoError(err)
+		publicWitnesses[i], err = w.Public()
+		assert.NoError(err)
+	}
+	return proofs, vk, publicWitnesses
+}
+
+func TestAggregate(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curveID := range []ecc.ID{ecc.BN254, ecc.BLS12_381} {
+		assert.Run(func(assert *test.Assert) {
+			pk, vk := newSRS(assert, curveID, 4)
+			proofs, gvk, publicWitnesses := prove(assert, curveID, 4)
+
+			proof, err := aggregate.Aggregate(pk, proofs, gvk, publicWitnesses)
+			assert.NoError(err)
+			assert.NoError(aggregate.Verify(vk, gvk, proof, publicWitnesses))
+
+			// proofs aggregated against another verifying key
+			_, otherGvk, _ := prove(assert, curveID, 1)
+			assert.Error(aggregate.Verify(vk, otherGvk, proof, publicWitnesses))
+
+			// fewer proofs than aggregated
+			assert.Error(aggregate.Verify(vk, gvk, proof, publicWitnesses[:2]))
+
+			assert.NoError(io.RoundTripCheck(proof, func() any { return aggregate.NewAggregatedProof(curveID) }))
+			assert.NoError(io.RoundTripCheck(pk, func() any { return aggregate.NewProvingKey(curveID) }))
+			assert.NoError(io.RoundTripCheck(vk, func() any { return aggregate.NewVerifyingKey(curveID) }))
+		}, curveID.String())
+	}
+}
+
+func TestAggregateMismatchingCurves(t *testing.T) {
+	assert := test.NewAssert(t)
+	pk, vk := newSRS(assert, ecc.BN254, 2)
+	proofs, gvk, publicWitnesses := prove(assert, ecc.BN254, 2)
+	otherProofs, otherGvk, otherWitnesses := prove(assert, ecc.BLS12_381, 2)
+	_, otherVk := newSRS(assert, ecc.BLS12_381, 2)
+
+	_, err := aggregate.Aggregate(pk, otherProofs, gvk, publicWitnesses)
+	assert.Error(err, "proofs")
+	_, err = aggregate.Aggregate(pk, proofs, otherGvk, publicWitnesses)
+	assert.Error(err, "verifying key")
+	_, err = aggregate.Aggregate(pk, proofs, gvk, otherWitnesses)
+	assert.Error(err, "witnesses")
+
+	proof, err := aggregate.Aggregate(pk, proofs, gvk, publicWitnesses)
+	assert.NoError(err)
+	assert.Error(aggregate.Verify(otherVk, gvk, proof, publicWitnesses), "aggregation verifying key")
+	assert.Error(aggregate.Verify(vk, otherGvk, proof, publicWitnesses), "verifying key")
+	assert.Error(aggregate.Verify(vk, gvk, proof, otherWitnesses), "witnesses")
+}
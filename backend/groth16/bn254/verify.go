@@ -19,7 +19,10 @@ package groth16
 import (
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"math/big"
+	"sort"
 	"text/template"
 	"time"
 
@@ -37,6 +40,7 @@ import (
 var (
 	errPairingCheckFailed         = errors.New("pairing doesn't match")
 	errCorrectSubgroupCheckFailed = errors.New("points in the proof are not in the correct subgroup")
+	errEmptyBatch                 = errors.New("no proofs to verify")
 )
 
 // Verify verifies a proof with given VerifyingKey and publicWitness
@@ -73,31 +77,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		close(chDone)
 	}()
 
-	maxNbPublicCommitted := 0
-	for _, s := range vk.PublicAndCommitmentCommitted { // iterate over commitments
-		maxNbPublicCommitted = utils.Max(maxNbPublicCommitted, len(s))
-	}
-	commitmentsSerialized := make([]byte, len(vk.PublicAndCommitmentCommitted)*fr.Bytes)
-	commitmentPrehashSerialized := make([]byte, curve.SizeOfG1AffineUncompressed+maxNbPublicCommitted*fr.Bytes)
-	for i := range vk.PublicAndCommitmentCommitted { // solveCommitmentWire
-		copy(commitmentPrehashSerialized, proof.Commitments[i].Marshal())
-		offset := curve.SizeOfG1AffineUncompressed
-		for j := range vk.PublicAndCommitmentCommitted[i] {
-			copy(commitmentPrehashSerialized[offset:], publicWitness[vk.PublicAndCommitmentCommitted[i][j]-1].Marshal())
-			offset += fr.Bytes
-		}
-		opt.HashToFieldFn.Write(commitmentPrehashSerialized[:offset])
-		hashBts := opt.HashToFieldFn.Sum(nil)
-		opt.HashToFieldFn.Reset()
-		nbBuf := fr.Bytes
-		if opt.HashToFieldFn.Size() < fr.Bytes {
-			nbBuf = opt.HashToFieldFn.Size()
-		}
-		var res fr.Element
-		res.SetBytes(hashBts[:nbBuf])
-		publicWitness = append(publicWitness, res)
-		copy(commitmentsSerialized[i*fr.Bytes:], res.Marshal())
-	}
+	publicWitness, commitmentsSerialized := solveCommitmentWires(proof, vk, publicWitness, opt.HashToFieldFn)
 
 	if folded, err := pedersen.FoldCommitments(proof.Commitments, commitmentsSerialized); err != nil {
 		return err
@@ -140,6 +120,259 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	return nil
 }
 
+// solveCommitmentWires returns the public witness extended with the values of
+// the commitment wires and the serialized values of the commitment wires used
+// for folding the commitments.
+func solveCommitmentWires(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, hashToField hash.Hash) (fr.Vector, []byte) {
+	maxNbPublicCommitted := 0
+	for _, s := range vk.PublicAndCommitmentCommitted { // iterate over commitments
+		maxNbPublicCommitted = utils.Max(maxNbPublicCommitted, len(s))
+	}
+	commitmentsSerialized := make([]byte, len(vk.PublicAndCommitmentCommitted)*fr.Bytes)
+	commitmentPrehashSerialized := make([]byte, curve.SizeOfG1AffineUncompressed+maxNbPublicCommitted*fr.Bytes)
+	for i := range vk.PublicAndCommitmentCommitted { // solveCommitmentWire
+		copy(commitmentPrehashSerialized, proof.Commitments[i].Marshal())
+		offset := curve.SizeOfG1AffineUncompressed
+		for j := range vk.PublicAndCommitmentCommitted[i] {
+			copy(commitmentPrehashSerialized[offset:], publicWitness[vk.PublicAndCommitmentCommitted[i][j]-1].Marshal())
+			offset += fr.Bytes
+		}
+		hashToField.Write(commitmentPrehashSerialized[:offset])
+		hashBts := hashToField.Sum(nil)
+		hashToField.Reset()
+		nbBuf := fr.Bytes
+		if hashToField.Size() < fr.Bytes {
+			nbBuf = hashToField.Size()
+		}
+		var res fr.Element
+		res.SetBytes(hashBts[:nbBuf])
+		publicWitness = append(publicWitness, res)
+		copy(commitmentsSerialized[i*fr.Bytes:], res.Marshal())
+	}
+	return publicWitness, commitmentsSerialized
+}
+
+// BatchVerify verifies the proofs with given VerifyingKey and public witnesses.
+// The pairing equations of the proofs are combined with random coefficients
+// and checked with a single multi-Miller loop and final exponentiation.
+//
+// If the batch is invalid, then by default an error is returned without
+// identifying the invalid proofs. With the option [backend.WithBatchBisection]
+// the invalid proofs are searched by bisection and a
+// [*backend.BatchVerifyError] with their indices is returned.
+func BatchVerify(proofs []*Proof, vk *VerifyingKey, publicWitnesses []fr.Vector, opts ...backend.VerifierOption) error {
+	opt, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("new verifier config: %w", err)
+	}
+	if opt.HashToFieldFn == nil {
+		opt.HashToFieldFn = hash_to_field.New([]byte(constraint.CommitmentDst))
+	}
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("got %d public witnesses for %d proofs", len(publicWitnesses), len(proofs))
+	}
+	if len(proofs) == 0 {
+		return errEmptyBatch
+	}
+	log := logger.Logger().With().Str("curve", vk.CurveID().String()).Str("backend", "groth16").Int("nbProofs", len(proofs)).Logger()
+	start := time.Now()
+
+	// the public inputs of the proofs, extended with the commitment wires, and
+	// the folded commitments for checking the proofs of knowledge.
+	inputs := make([]fr.Vector, len(proofs))
+	folded := make([]curve.G1Affine, len(proofs))
+	var invalid, valid []int
+	for i := range proofs {
+		if inputs[i], folded[i], err = batchInputs(proofs[i], vk, publicWitnesses[i], opt.HashToFieldFn); err != nil {
+			if !opt.BatchBisection {
+				return fmt.Errorf("proof %d: %w", i, err)
+			}
+			invalid = append(invalid, i)
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	check := func(indices []int) (bool, error) {
+		return batchCheck(proofs, vk, inputs, folded, indices)
+	}
+	ok, err := check(valid)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if !opt.BatchBisection {
+			return errPairingCheckFailed
+		}
+		failing, err := bisect(valid, check)
+		if err != nil {
+			return err
+		}
+		invalid = append(invalid, failing...)
+	}
+	log.Debug().Dur("took", time.Since(start)).Msg("batch verifier done")
+	if len(invalid) > 0 {
+		sort.Ints(invalid)
+		return &backend.BatchVerifyError{Invalid: invalid}
+	}
+	return nil
+}
+
+// batchInputs performs the checks of a single proof which do not require
+// pairings. It returns the public inputs extended with the commitment wires
+// and the folded commitment.
+func batchInputs(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, hashToField hash.Hash) (fr.Vector, curve.G1Affine, error) {
+	var folded curve.G1Affine
+	nbPublicVars := len(vk.G1.K) - len(vk.PublicAndCommitmentCommitted)
+	if len(publicWitness) != nbPublicVars-1 {
+		return nil, folded, fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(publicWitness), nbPublicVars-1)
+	}
+	if len(proof.Commitments) != len(vk.PublicAndCommitmentCommitted) {
+		return nil, folded, errors.New("commitment number mismatch")
+	}
+	if !proof.isValid() {
+		return nil, folded, errCorrectSubgroupCheckFailed
+	}
+	if len(vk.PublicAndCommitmentCommitted) == 0 {
+		return publicWitness, folded, nil
+	}
+	if !proof.CommitmentPok.IsInSubGroup() {
+		return nil, folded, errCorrectSubgroupCheckFailed
+	}
+	for i := range proof.Commitments {
+		if !proof.Commitments[i].IsInSubGroup() {
+			return nil, folded, errCorrectSubgroupCheckFailed
+		}
+	}
+	// copy the public witness as solving the commitment wires appends to it
+	publicWitness = append(make(fr.Vector, 0, len(vk.G1.K)-1), publicWitness...)
+	publicWitness, commitmentsSerialized := solveCommitmentWires(proof, vk, publicWitness, hashToField)
+	folded, err := pedersen.FoldCommitments(proof.Commitments, commitmentsSerialized)
+	if err != nil {
+		return nil, folded, err
+	}
+	return publicWitness, folded, nil
+}
+
+// batchCheck checks the proofs at the given indices with a random linear
+// combination rᵢ of their pairing equations:
+//
+//	∏ e(rᵢ⋅Arᵢ, Bsᵢ) ⋅ e(∑ rᵢ⋅Kᵢ, -γ) ⋅ e(∑ rᵢ⋅Krsᵢ, -δ) ⋅ e(-(∑ rᵢ)⋅α, β) == 1
+//
+// and with independent random coefficients ρᵢ of the commitment proofs of
+// knowledge:
+//
+//	e(∑ ρᵢ⋅Dᵢ, G) ⋅ e(∑ ρᵢ⋅Pokᵢ, G^{-1/σ}) == 1
+func batchCheck(proofs []*Proof, vk *VerifyingKey, inputs []fr.Vector, folded []curve.G1Affine, indices []int) (bool, error) {
+	if len(indices) == 0 {
+		return true, nil
+	}
+	n := len(indices)
+	r := make([]fr.Element, n)
+	for i := range r {
+		if _, err := r[i].SetRandom(); err != nil {
+			return false, err
+		}
+	}
+
+	// scalars of the verifying key and the commitments for computing ∑ rᵢ⋅Kᵢ
+	kScalars := make([]fr.Element, len(vk.G1.K))
+	kPoints := append(make([]curve.G1Affine, 0, len(vk.G1.K)+n*len(vk.PublicAndCommitmentCommitted)), vk.G1.K...)
+	ar := make([]curve.G1Affine, n, n+3)
+	bs := make([]curve.G2Affine, n, n+5)
+	krs := make([]curve.G1Affine, n)
+	var sumR, t fr.Element
+	var bi big.Int
+	for i, idx := range indices {
+		sumR.Add(&sumR, &r[i])
+		for j := range inputs[idx] {
+			t.Mul(&inputs[idx][j], &r[i])
+			kScalars[j+1].Add(&kScalars[j+1], &t)
+		}
+		for j := range proofs[idx].Commitments {
+			kPoints = append(kPoints, proofs[idx].Commitments[j])
+			kScalars = append(kScalars, r[i])
+		}
+		r[i].BigInt(&bi)
+		ar[i].ScalarMultiplication(&proofs[idx].Ar, &bi)
+		bs[i] = proofs[idx].Bs
+		krs[i] = proofs[idx].Krs
+	}
+	kScalars[0] = sumR
+
+	var kSum, krsSum, alpha curve.G1Affine
+	config := ecc.MultiExpConfig{}
+	if _, err := kSum.MultiExp(kPoints, kScalars, config); err != nil {
+		return false, err
+	}
+	if _, err := krsSum.MultiExp(krs, r, config); err != nil {
+		return false, err
+	}
+	sumR.Neg(&sumR).BigInt(&bi)
+	alpha.ScalarMultiplication(&vk.G1.Alpha, &bi)
+	ar = append(ar, kSum, krsSum, alpha)
+	bs = append(bs, vk.G2.gammaNeg, vk.G2.deltaNeg, vk.G2.Beta)
+
+	if len(vk.PublicAndCommitmentCommitted) > 0 {
+		rho := make([]fr.Element, n)
+		foldedI := make([]curve.G1Affine, n)
+		poks := make([]curve.G1Affine, n)
+		for i, idx := range indices {
+			if _, err := rho[i].SetRandom(); err != nil {
+				return false, err
+			}
+			foldedI[i] = folded[idx]
+			poks[i] = proofs[idx].CommitmentPok
+		}
+		var foldedSum, pokSum curve.G1Affine
+		if _, err := foldedSum.MultiExp(foldedI, rho, config); err != nil {
+			return false, err
+		}
+		if _, err := pokSum.MultiExp(poks, rho, config); err != nil {
+			return false, err
+		}
+		ar = append(ar, foldedSum, pokSum)
+		bs = append(bs, vk.CommitmentKey.G, vk.CommitmentKey.GRootSigmaNeg)
+	}
+
+	return curve.PairingCheck(ar, bs)
+}
+
+// bisect returns the indices of the invalid proofs in a batch which fails the
+// check. The batch is split recursively in halves and only the failing halves
+// are searched.
+func bisect(indices []int, check func([]int) (bool, error)) ([]int, error) {
+	if len(indices) == 1 {
+		return indices, nil
+	}
+	m := len(indices) / 2
+	left, right := indices[:m], indices[m:]
+	okLeft, err := check(left)
+	if err != nil {
+		return nil, err
+	}
+	if okLeft {
+		// the batch fails, so the right half fails
+		return bisect(right, check)
+	}
+	invalid, err := bisect(left, check)
+	if err != nil {
+		return nil, err
+	}
+	okRight, err := check(right)
+	if err != nil {
+		return nil, err
+	}
+	if okRight {
+		return invalid, nil
+	}
+	invalidRight, err := bisect(right, check)
+	if err != nil {
+		return nil, err
+	}
+	return append(invalid, invalidRight...), nil
+}
+
 // ExportSolidity writes a solidity Verifier contract on provided writer.
 // This is an experimental feature and gnark solidity generator as not been thoroughly tested.
 //
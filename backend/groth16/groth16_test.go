@@ -1,6 +1,7 @@
 package groth16_test
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
@@ -9,6 +10,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
@@ -51,6 +53,49 @@ func TestCustomHashToField(t *testing.T) {
 	}
 }
 
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbProofs = 5
+	for _, curve := range getCurves() {
+		curve := curve
+		for _, circuit := range []frontend.Circuit{&batchCircuit{}, &batchCommitmentCircuit{}} {
+			circuit := circuit
+			assert.Run(func(assert *test.Assert) {
+				ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, circuit)
+				assert.NoError(err)
+				pk, vk, err := groth16.Setup(ccs)
+				assert.NoError(err)
+
+				proofs := make([]groth16.Proof, nbProofs)
+				publicWitnesses := make([]witness.Witness, nbProofs)
+				for i := range proofs {
+					var assignment frontend.Circuit = &batchCircuit{X: i + 2, Y: (i + 2) * (i + 2)}
+					if _, ok := circuit.(*batchCommitmentCircuit); ok {
+						assignment = &batchCommitmentCircuit{X: i + 2, Y: (i + 2) * (i + 2)}
+					}
+					w, err := frontend.NewWitness(assignment, curve.ScalarField())
+					assert.NoError(err)
+					proofs[i], err = groth16.Prove(ccs, pk, w)
+					assert.NoError(err)
+					publicWitnesses[i], err = w.Public()
+					assert.NoError(err)
+				}
+				assert.NoError(groth16.BatchVerify(proofs, vk, publicWitnesses))
+				assert.NoError(groth16.BatchVerify(proofs, vk, publicWitnesses, backend.WithBatchBisection()))
+				assert.Error(groth16.BatchVerify(nil, vk, nil), "empty batch")
+
+				// swap the public witnesses of two proofs
+				publicWitnesses[1], publicWitnesses[3] = publicWitnesses[3], publicWitnesses[1]
+				assert.Error(groth16.BatchVerify(proofs, vk, publicWitnesses))
+				err = groth16.BatchVerify(proofs, vk, publicWitnesses, backend.WithBatchBisection())
+				var batchErr *backend.BatchVerifyError
+				assert.True(errors.As(err, &batchErr))
+				assert.Equal([]int{1, 3}, batchErr.Invalid)
+			}, curve.String(), fmt.Sprintf("%T", circuit))
+		}
+	}
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
@@ -168,6 +213,31 @@ func (c *commitmentCircuit) Define(api frontend.API) error {
 	return nil
 }
 
+type batchCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *batchCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+type batchCommitmentCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *batchCommitmentCircuit) Define(api frontend.API) error {
+	cmt, err := api.(frontend.Committer).Commit(c.X, c.Y)
+	if err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	api.AssertIsDifferent(cmt, 0)
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
 type constantHash struct{}
 
 func (h constantHash) Write(p []byte) (n int, err error) { return len(p), nil }
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"sort"
 	"text/template"
 	"time"
 
@@ -41,6 +42,8 @@ import (
 var (
 	errAlgebraicRelation = errors.New("algebraic relation does not hold")
 	errInvalidWitness    = errors.New("witness length is invalid")
+	errBatchCheckFailed  = errors.New("batched opening proofs don't match")
+	errEmptyBatch        = errors.New("no proofs to verify")
 )
 
 func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...backend.VerifierOption) error {
@@ -52,12 +55,151 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		return fmt.Errorf("create backend config: %w", err)
 	}
 
+	claims, err := verifyRelation(proof, vk, publicWitness, &cfg)
+	if err != nil {
+		return err
+	}
+
+	// Batch verify
+	err = kzg.BatchVerifyMultiPoints(claims.digests[:], claims.proofs[:], claims.points[:], vk.Kzg)
+
+	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
+
+	return err
+}
+
+// BatchVerify verifies the proofs with given VerifyingKey and public witnesses.
+// The algebraic relation is checked for each proof, and the KZG opening
+// claims of all the proofs are folded with random coefficients and checked
+// with a single pairing check.
+//
+// If the batch is invalid, then by default an error is returned without
+// identifying the invalid proofs. With the option [backend.WithBatchBisection]
+// the invalid proofs are searched by bisection and a
+// [*backend.BatchVerifyError] with their indices is returned.
+func BatchVerify(proofs []*Proof, vk *VerifyingKey, publicWitnesses []fr.Vector, opts ...backend.VerifierOption) error {
+	log := logger.Logger().With().Str("curve", "bn254").Str("backend", "plonk").Int("nbProofs", len(proofs)).Logger()
+	start := time.Now()
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("create backend config: %w", err)
+	}
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("got %d public witnesses for %d proofs", len(publicWitnesses), len(proofs))
+	}
+	if len(proofs) == 0 {
+		return errEmptyBatch
+	}
+
+	claims := make([]kzgClaims, len(proofs))
+	var invalid, valid []int
+	for i := range proofs {
+		if claims[i], err = verifyRelation(proofs[i], vk, publicWitnesses[i], &cfg); err != nil {
+			if !cfg.BatchBisection {
+				return fmt.Errorf("proof %d: %w", i, err)
+			}
+			invalid = append(invalid, i)
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	check := func(indices []int) (bool, error) {
+		if len(indices) == 0 {
+			return true, nil
+		}
+		digests := make([]kzg.Digest, 0, 2*len(indices))
+		openings := make([]kzg.OpeningProof, 0, 2*len(indices))
+		points := make([]fr.Element, 0, 2*len(indices))
+		for _, i := range indices {
+			digests = append(digests, claims[i].digests[:]...)
+			openings = append(openings, claims[i].proofs[:]...)
+			points = append(points, claims[i].points[:]...)
+		}
+		// only the failure of the pairing check makes the batch invalid, the
+		// other errors are not related to the validity of the proofs.
+		err := kzg.BatchVerifyMultiPoints(digests, openings, points, vk.Kzg)
+		if errors.Is(err, kzg.ErrVerifyOpeningProof) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	ok, err := check(valid)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if !cfg.BatchBisection {
+			return errBatchCheckFailed
+		}
+		failing, err := bisect(valid, check)
+		if err != nil {
+			return err
+		}
+		invalid = append(invalid, failing...)
+	}
+	log.Debug().Dur("took", time.Since(start)).Msg("batch verifier done")
+	if len(invalid) > 0 {
+		sort.Ints(invalid)
+		return &backend.BatchVerifyError{Invalid: invalid}
+	}
+	return nil
+}
+
+// bisect returns the indices of the invalid proofs in a batch which fails the
+// check. The batch is split recursively in halves and only the failing halves
+// are searched.
+func bisect(indices []int, check func([]int) (bool, error)) ([]int, error) {
+	if len(indices) == 1 {
+		return indices, nil
+	}
+	m := len(indices) / 2
+	left, right := indices[:m], indices[m:]
+	okLeft, err := check(left)
+	if err != nil {
+		return nil, err
+	}
+	if okLeft {
+		// the batch fails, so the right half fails
+		return bisect(right, check)
+	}
+	invalid, err := bisect(left, check)
+	if err != nil {
+		return nil, err
+	}
+	okRight, err := check(right)
+	if err != nil {
+		return nil, err
+	}
+	if okRight {
+		return invalid, nil
+	}
+	invalidRight, err := bisect(right, check)
+	if err != nil {
+		return nil, err
+	}
+	return append(invalid, invalidRight...), nil
+}
+
+// kzgClaims are the KZG opening claims of a proof, at ζ for the folded
+// polynomials and at ωζ for Z.
+type kzgClaims struct {
+	digests [2]kzg.Digest
+	proofs  [2]kzg.OpeningProof
+	points  [2]fr.Element
+}
+
+// verifyRelation checks the algebraic relation of the proof at the challenge
+// ζ and returns the KZG opening claims which remain to be checked.
+func verifyRelation(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, cfg *backend.VerifierConfig) (kzgClaims, error) {
+	var claims kzgClaims
+
 	if len(proof.Bsb22Commitments) != len(vk.Qcp) {
-		return errors.New("BSB22 Commitment number mismatch")
+		return claims, errors.New("BSB22 Commitment number mismatch")
 	}
 
 	if len(publicWitness) != int(vk.NbPublicVariables) {
-		return errInvalidWitness
+		return claims, errInvalidWitness
 	}
 
 	// transcript to derive the challenge
@@ -67,17 +209,17 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	// the coefficients of the circuit, and the public inputs.
 	// derive gamma from the Comm(blinded cl), Comm(blinded cr), Comm(blinded co)
 	if err := bindPublicData(fs, "gamma", vk, publicWitness); err != nil {
-		return err
+		return claims, err
 	}
 	gamma, err := deriveRandomness(fs, "gamma", &proof.LRO[0], &proof.LRO[1], &proof.LRO[2])
 	if err != nil {
-		return err
+		return claims, err
 	}
 
 	// derive beta from Comm(l), Comm(r), Comm(o)
 	beta, err := deriveRandomness(fs, "beta")
 	if err != nil {
-		return err
+		return claims, err
 	}
 
 	// derive alpha from Com(Z), Bsb22Commitments
@@ -88,13 +230,13 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	alphaDeps[len(alphaDeps)-1] = &proof.Z
 	alpha, err := deriveRandomness(fs, "alpha", alphaDeps...)
 	if err != nil {
-		return err
+		return claims, err
 	}
 
 	// derive zeta, the point of evaluation
 	zeta, err := deriveRandomness(fs, "zeta", &proof.H[0], &proof.H[1], &proof.H[2])
 	if err != nil {
-		return err
+		return claims, err
 	}
 
 	// evaluation of zhZeta=ζⁿ-1
@@ -195,7 +337,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	// check that the opening of the linearised polynomial is equal to -constLin
 	openingLinPol := proof.BatchedProof.ClaimedValues[0]
 	if !constLin.Equal(&openingLinPol) {
-		return errAlgebraicRelation
+		return claims, errAlgebraicRelation
 	}
 
 	// computing the linearised polynomial digest
@@ -252,7 +394,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		zh, zetaNPlusTwoZh, zetaNPlusTwoSquareZh,
 	)
 	if _, err := linearizedPolynomialDigest.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
-		return err
+		return claims, err
 	}
 
 	// Fold the first proof
@@ -272,30 +414,15 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		zu.Marshal(),
 	)
 	if err != nil {
-		return err
+		return claims, err
 	}
 
-	// Batch verify
 	var shiftedZeta fr.Element
 	shiftedZeta.Mul(&zeta, &vk.Generator)
-	err = kzg.BatchVerifyMultiPoints([]kzg.Digest{
-		foldedDigest,
-		proof.Z,
-	},
-		[]kzg.OpeningProof{
-			foldedProof,
-			proof.ZShiftedOpening,
-		},
-		[]fr.Element{
-			zeta,
-			shiftedZeta,
-		},
-		vk.Kzg,
-	)
-
-	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
-
-	return err
+	claims.digests = [2]kzg.Digest{foldedDigest, proof.Z}
+	claims.proofs = [2]kzg.OpeningProof{foldedProof, proof.ZShiftedOpening}
+	claims.points = [2]fr.Element{zeta, shiftedZeta}
+	return claims, nil
 }
 
 func bindPublicData(fs *fiatshamir.Transcript, challenge string, vk *VerifyingKey, publicInputs []fr.Element) error {
@@ -20,6 +20,7 @@
 package plonk
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -216,6 +217,85 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 	}
 }
 
+// BatchVerify verifies the proofs of the same circuit with given verifying key
+// and public witnesses. The KZG opening proofs of all the proofs are folded
+// and checked with a single pairing check, which is considerably faster than
+// verifying the proofs one by one.
+//
+// By default the invalid proofs of a failing batch are not identified. Use
+// [backend.WithBatchBisection] to search them by bisection, in which case the
+// returned error is a [*backend.BatchVerifyError] with their indices.
+func BatchVerify(proofs []Proof, vk VerifyingKey, publicWitnesses []witness.Witness, opts ...backend.VerifierOption) error {
+	switch _vk := vk.(type) {
+	case *plonk_bn254.VerifyingKey:
+		ps, ws, err := batchInputs[*plonk_bn254.Proof, fr_bn254.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bn254.BatchVerify(ps, _vk, ws, opts...)
+	case *plonk_bls12381.VerifyingKey:
+		ps, ws, err := batchInputs[*plonk_bls12381.Proof, fr_bls12381.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bls12381.BatchVerify(ps, _vk, ws, opts...)
+	case *plonk_bls12377.VerifyingKey:
+		ps, ws, err := batchInputs[*plonk_bls12377.Proof, fr_bls12377.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bls12377.BatchVerify(ps, _vk, ws, opts...)
+	case *plonk_bw6761.VerifyingKey:
+		ps, ws, err := batchInputs[*plonk_bw6761.Proof, fr_bw6761.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bw6761.BatchVerify(ps, _vk, ws, opts...)
+	case *plonk_bls24317.VerifyingKey:
+		ps, ws, err := batchInputs[*plonk_bls24317.Proof, fr_bls24317.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bls24317.BatchVerify(ps, _vk, ws, opts...)
+	case *plonk_bls24315.VerifyingKey:
+		ps, ws, err := batchInputs[*plonk_bls24315.Proof, fr_bls24315.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bls24315.BatchVerify(ps, _vk, ws, opts...)
+	case *plonk_bw6633.VerifyingKey:
+		ps, ws, err := batchInputs[*plonk_bw6633.Proof, fr_bw6633.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bw6633.BatchVerify(ps, _vk, ws, opts...)
+	default:
+		panic("unrecognized verifying key curve type")
+	}
+}
+
+// batchInputs converts the proofs and the public witnesses to the curve typed
+// inputs of the batch verifier.
+func batchInputs[P Proof, V any](proofs []Proof, publicWitnesses []witness.Witness) ([]P, []V, error) {
+	ps := make([]P, len(proofs))
+	for i := range proofs {
+		p, ok := proofs[i].(P)
+		if !ok {
+			return nil, nil, fmt.Errorf("proof %d: unexpected type %T", i, proofs[i])
+		}
+		ps[i] = p
+	}
+	ws := make([]V, len(publicWitnesses))
+	for i := range publicWitnesses {
+		w, ok := publicWitnesses[i].Vector().(V)
+		if !ok {
+			return nil, nil, witness.ErrInvalidWitness
+		}
+		ws[i] = w
+	}
+	return ps, ws, nil
+}
+
 // NewCS instantiate a concrete curved-typed SparseR1CS and return a ConstraintSystem interface
 // This method exists for (de)serialization purposes
 func NewCS(curveID ecc.ID) constraint.ConstraintSystem {
@@ -2,6 +2,7 @@ package plonk_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
@@ -11,6 +12,14 @@ import (
 	"github.com/consensys/gnark-crypto/kzg"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/plonk"
+	plonk_bls12377 "github.com/consensys/gnark/backend/plonk/bls12-377"
+	plonk_bls12381 "github.com/consensys/gnark/backend/plonk/bls12-381"
+	plonk_bls24315 "github.com/consensys/gnark/backend/plonk/bls24-315"
+	plonk_bls24317 "github.com/consensys/gnark/backend/plonk/bls24-317"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	plonk_bw6633 "github.com/consensys/gnark/backend/plonk/bw6-633"
+	plonk_bw6761 "github.com/consensys/gnark/backend/plonk/bw6-761"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/scs"
@@ -194,6 +203,92 @@ func TestCustomKZGFoldingHash(t *testing.T) {
 	}
 }
 
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbProofs = 5
+	for _, curve := range getCurves() {
+		curve := curve
+		for _, circuit := range []frontend.Circuit{&batchCircuit{}, &batchCommitmentCircuit{}} {
+			circuit := circuit
+			assert.Run(func(assert *test.Assert) {
+				ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, circuit)
+				assert.NoError(err)
+				srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+				assert.NoError(err)
+				pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+				assert.NoError(err)
+
+				proofs := make([]plonk.Proof, nbProofs)
+				publicWitnesses := make([]witness.Witness, nbProofs)
+				for i := range proofs {
+					var assignment frontend.Circuit = &batchCircuit{X: i + 2, Y: (i + 2) * (i + 2)}
+					if _, ok := circuit.(*batchCommitmentCircuit); ok {
+						assignment = &batchCommitmentCircuit{X: i + 2, Y: (i + 2) * (i + 2)}
+					}
+					w, err := frontend.NewWitness(assignment, curve.ScalarField())
+					assert.NoError(err)
+					proofs[i], err = plonk.Prove(ccs, pk, w)
+					assert.NoError(err)
+					publicWitnesses[i], err = w.Public()
+					assert.NoError(err)
+				}
+				assert.NoError(plonk.BatchVerify(proofs, vk, publicWitnesses))
+				assert.NoError(plonk.BatchVerify(proofs, vk, publicWitnesses, backend.WithBatchBisection()))
+				assert.Error(plonk.BatchVerify(nil, vk, nil), "empty batch")
+
+				// swap the public witnesses of two proofs
+				publicWitnesses[1], publicWitnesses[3] = publicWitnesses[3], publicWitnesses[1]
+				assert.Error(plonk.BatchVerify(proofs, vk, publicWitnesses))
+				err = plonk.BatchVerify(proofs, vk, publicWitnesses, backend.WithBatchBisection())
+				var batchErr *backend.BatchVerifyError
+				assert.True(errors.As(err, &batchErr))
+				assert.Equal([]int{1, 3}, batchErr.Invalid)
+				publicWitnesses[1], publicWitnesses[3] = publicWitnesses[3], publicWitnesses[1]
+
+				// swap the opening proofs of Z at ωζ of two proofs. The
+				// algebraic relations still hold, only the folded KZG check
+				// fails.
+				swapShiftedOpeningProofs(proofs[0], proofs[2])
+				assert.Error(plonk.BatchVerify(proofs, vk, publicWitnesses))
+				err = plonk.BatchVerify(proofs, vk, publicWitnesses, backend.WithBatchBisection())
+				batchErr = nil
+				assert.True(errors.As(err, &batchErr))
+				assert.Equal([]int{0, 2}, batchErr.Invalid)
+			}, curve.String(), fmt.Sprintf("%T", circuit))
+		}
+	}
+}
+
+// swapShiftedOpeningProofs swaps the quotients of the openings of Z at ωζ of
+// the proofs p and q, which have a curve specific type.
+func swapShiftedOpeningProofs(p, q plonk.Proof) {
+	switch _p := p.(type) {
+	case *plonk_bn254.Proof:
+		_q := q.(*plonk_bn254.Proof)
+		_p.ZShiftedOpening.H, _q.ZShiftedOpening.H = _q.ZShiftedOpening.H, _p.ZShiftedOpening.H
+	case *plonk_bls12381.Proof:
+		_q := q.(*plonk_bls12381.Proof)
+		_p.ZShiftedOpening.H, _q.ZShiftedOpening.H = _q.ZShiftedOpening.H, _p.ZShiftedOpening.H
+	case *plonk_bls12377.Proof:
+		_q := q.(*plonk_bls12377.Proof)
+		_p.ZShiftedOpening.H, _q.ZShiftedOpening.H = _q.ZShiftedOpening.H, _p.ZShiftedOpening.H
+	case *plonk_bw6761.Proof:
+		_q := q.(*plonk_bw6761.Proof)
+		_p.ZShiftedOpening.H, _q.ZShiftedOpening.H = _q.ZShiftedOpening.H, _p.ZShiftedOpening.H
+	case *plonk_bls24317.Proof:
+		_q := q.(*plonk_bls24317.Proof)
+		_p.ZShiftedOpening.H, _q.ZShiftedOpening.H = _q.ZShiftedOpening.H, _p.ZShiftedOpening.H
+	case *plonk_bls24315.Proof:
+		_q := q.(*plonk_bls24315.Proof)
+		_p.ZShiftedOpening.H, _q.ZShiftedOpening.H = _q.ZShiftedOpening.H, _p.ZShiftedOpening.H
+	case *plonk_bw6633.Proof:
+		_q := q.(*plonk_bw6633.Proof)
+		_p.ZShiftedOpening.H, _q.ZShiftedOpening.H = _q.ZShiftedOpening.H, _p.ZShiftedOpening.H
+	default:
+		panic("unrecognized proof type")
+	}
+}
+
 func BenchmarkSetup(b *testing.B) {
 	for _, curve := range getCurves() {
 		b.Run(curve.String(), func(b *testing.B) {
@@ -320,6 +415,31 @@ func (c *smallCircuit) Define(api frontend.API) error {
 	return nil
 }
 
+type batchCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *batchCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+type batchCommitmentCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *batchCommitmentCircuit) Define(api frontend.API) error {
+	cmt, err := api.(frontend.Committer).Commit(c.X, c.Y)
+	if err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	api.AssertIsDifferent(cmt, 0)
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
 type constantHash struct{}
 
 func (h constantHash) Write(p []byte) (n int, err error) { return len(p), nil }
@@ -189,6 +189,24 @@ func main() {
 				panic(err) // TODO handle
 			}
 
+			// groth16 aggregation
+			if d.Curve == "BN254" || d.Curve == "BLS12-381" {
+				groth16AggregateDir := strings.Replace(d.RootPath, "{?}", "groth16/aggregate", 1)
+				if err := os.MkdirAll(groth16AggregateDir, 0700); err != nil {
+					panic(err)
+				}
+				entries = []bavard.Entry{
+					{File: filepath.Join(groth16AggregateDir, "aggregate.go"), Templates: []string{"groth16/aggregate/aggregate.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregateDir, "verify.go"), Templates: []string{"groth16/aggregate/verify.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregateDir, "srs.go"), Templates: []string{"groth16/aggregate/srs.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregateDir, "marshal.go"), Templates: []string{"groth16/aggregate/marshal.go.tmpl", importCurve}},
+					{File: filepath.Join(groth16AggregateDir, "aggregate_test.go"), Templates: []string{"groth16/aggregate/tests/aggregate.go.tmpl", importCurve}},
+				}
+				if err := bgen.Generate(d, "aggregate", "./template/zkpschemes/", entries...); err != nil {
+					panic(err)
+				}
+			}
+
 			// plonk
 			entries = []bavard.Entry{
 				{File: filepath.Join(plonkDir, "verify.go"), Templates: []string{"plonk/plonk.verify.go.tmpl", importCurve}},
@@ -15,6 +15,7 @@ import (
 	"github.com/consensys/gnark/std/hash/mimc"
 	"github.com/consensys/gnark/std/math/bits"
 	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
 )
 
 var (
@@ -77,6 +78,18 @@ func initSnippets() {
 		_ = bits.ToNAF(api, newVariable(), bits.WithUnconstrainedOutputs())
 	})
 
+	registerSnippet("math/uints.ValueOf", func(api frontend.API, newVariable func() frontend.Variable) {
+		bf, _ := uints.New[uints.U32](api)
+		_ = bf.ValueOf(newVariable())
+	})
+	registerSnippet("math/uints.Add", func(api frontend.API, newVariable func() frontend.Variable) {
+		bf, _ := uints.New[uints.U32](api)
+		a := bf.ValueOf(newVariable())
+		b := bf.ValueOf(newVariable())
+		c := bf.ValueOf(newVariable())
+		_ = bf.Add(a, b, c)
+	})
+
 	registerSnippet("hash/mimc", func(api frontend.API, newVariable func() frontend.Variable) {
 		mimc, _ := mimc.NewMiMC(api)
 		mimc.Write(newVariable())
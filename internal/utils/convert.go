@@ -116,3 +116,11 @@ func Uint64SliceSliceToIntSliceSlice(in [][]uint64) [][]int {
 	}
 	return res
 }
+
+// BoolToInt returns 1 if b is true and 0 otherwise.
+func BoolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,186 @@
+// Package abi implements in-circuit encoding and decoding of the Solidity
+// contract ABI, as used for Ethereum calldata, return data and event logs.
+//
+// The types of the values are described statically by [Type]. The lengths of
+// dynamic byte strings and arrays are variables bounded by the maximum lengths
+// given in the type description, so that the same circuit can handle values
+// of different lengths. The bytes are represented as [uints.U8] and the values
+// can be converted to native variables with [ToVariable] and to emulated
+// elements with [ToElement].
+//
+// The decoder does not check that the padding bytes of dynamic byte strings are
+// zero and that the offsets of the dynamic values are in the canonical order.
+// When the decoded data is authenticated, for example by a hash, then this is
+// not an issue.
+//
+// See https://docs.soliditylang.org/en/latest/abi-spec.html for the
+// description of the encoding.
+package abi
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// WordSize is the size in bytes of the words of the encoding.
+const WordSize = 32
+
+// Kind is the kind of a type.
+type Kind int
+
+const (
+	// KindUint is an unsigned integer uint<M>.
+	KindUint Kind = iota
+	// KindAddress is an address, equivalent to uint160.
+	KindAddress
+	// KindBool is a boolean, equivalent to uint8 restricted to 0 and 1.
+	KindBool
+	// KindFixedBytes is a fixed length byte string bytes<M>.
+	KindFixedBytes
+	// KindBytes is a dynamic length byte string bytes or string.
+	KindBytes
+	// KindArray is a fixed length array T[k].
+	KindArray
+	// KindSlice is a dynamic length array T[].
+	KindSlice
+	// KindTuple is a tuple (T1,...,Tk).
+	KindTuple
+)
+
+// Type is the static description of a Solidity type.
+type Type struct {
+	Kind Kind
+	// Size is the number of bits of unsigned integers, the number of bytes of
+	// fixed length byte strings, the number of elements of fixed length arrays
+	// and the maximum length of dynamic byte strings and arrays.
+	Size int
+	// Elem is the type of the elements of arrays.
+	Elem *Type
+	// Fields are the types of the fields of tuples.
+	Fields []*Type
+}
+
+// Uint returns the type uint<nbBits>.
+func Uint(nbBits int) *Type { return &Type{Kind: KindUint, Size: nbBits} }
+
+// Address returns the type address.
+func Address() *Type { return &Type{Kind: KindAddress, Size: 160} }
+
+// Bool returns the type bool.
+func Bool() *Type { return &Type{Kind: KindBool, Size: 1} }
+
+// FixedBytes returns the type bytes<nbBytes>.
+func FixedBytes(nbBytes int) *Type { return &Type{Kind: KindFixedBytes, Size: nbBytes} }
+
+// Bytes returns the type bytes of length at most maxLen. The type string has
+// the same encoding.
+func Bytes(maxLen int) *Type { return &Type{Kind: KindBytes, Size: maxLen} }
+
+// Array returns the type elem[n].
+func Array(elem *Type, n int) *Type { return &Type{Kind: KindArray, Size: n, Elem: elem} }
+
+// Slice returns the type elem[] of length at most maxLen.
+func Slice(elem *Type, maxLen int) *Type { return &Type{Kind: KindSlice, Size: maxLen, Elem: elem} }
+
+// Tuple returns the tuple type of the fields. The arguments of functions and
+// the non-indexed arguments of events are encoded as a tuple.
+func Tuple(fields ...*Type) *Type { return &Type{Kind: KindTuple, Fields: fields} }
+
+// IsDynamic returns true if the encoding of the type is of variable length.
+func (t *Type) IsDynamic() bool {
+	switch t.Kind {
+	case KindBytes, KindSlice:
+		return true
+	case KindArray:
+		return t.Elem.IsDynamic()
+	case KindTuple:
+		for _, f := range t.Fields {
+			if f.IsDynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize returns the size in bytes of the encoding of the type in the head
+// of the enclosing tuple or array.
+func (t *Type) headSize() int {
+	if t.IsDynamic() {
+		return WordSize
+	}
+	switch t.Kind {
+	case KindArray:
+		return t.Size * t.Elem.headSize()
+	case KindTuple:
+		res := 0
+		for _, f := range t.Fields {
+			res += f.headSize()
+		}
+		return res
+	}
+	return WordSize
+}
+
+// elems returns the types of the elements of the tuple or array of n
+// elements.
+func (t *Type) elems(n int) []*Type {
+	if t.Kind == KindTuple {
+		return t.Fields
+	}
+	res := make([]*Type, n)
+	for i := range res {
+		res[i] = t.Elem
+	}
+	return res
+}
+
+// check returns an error if the type description is invalid.
+func (t *Type) check() error {
+	if t == nil {
+		return fmt.Errorf("nil type")
+	}
+	switch t.Kind {
+	case KindUint:
+		if t.Size <= 0 || t.Size > 256 || t.Size%8 != 0 {
+			return fmt.Errorf("invalid integer size %d", t.Size)
+		}
+	case KindAddress, KindBool:
+	case KindFixedBytes:
+		if t.Size <= 0 || t.Size > WordSize {
+			return fmt.Errorf("invalid fixed bytes size %d", t.Size)
+		}
+	case KindBytes:
+		if t.Size < 0 {
+			return fmt.Errorf("invalid maximum length %d", t.Size)
+		}
+	case KindArray, KindSlice:
+		if t.Size < 0 {
+			return fmt.Errorf("invalid array length %d", t.Size)
+		}
+		if err := t.Elem.check(); err != nil {
+			return fmt.Errorf("array element: %w", err)
+		}
+	case KindTuple:
+		for i, f := range t.Fields {
+			if err := f.check(); err != nil {
+				return fmt.Errorf("tuple field %d: %w", i, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown kind %d", t.Kind)
+	}
+	return nil
+}
+
+// Selector returns the function selector of the canonical function signature,
+// for example "transfer(address,uint256)". The selector is the first four
+// bytes of calldata and the encoding of the arguments follows.
+func Selector(signature string) [4]byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(signature))
+	var res [4]byte
+	copy(res[:], h.Sum(nil))
+	return res
+}
@@ -0,0 +1,278 @@
+package abi
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func assertEncoded(api frontend.API, enc Encoded, expected []uints.U8, length frontend.Variable) {
+	api.AssertIsEqual(enc.Length, length)
+	for i := range enc.Bytes {
+		if i < len(expected) {
+			api.AssertIsEqual(enc.Bytes[i].Val, expected[i].Val)
+		} else {
+			api.AssertIsEqual(enc.Bytes[i].Val, 0)
+		}
+	}
+}
+
+func TestSelector(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.Equal([4]byte{0xcd, 0xcd, 0x77, 0xc0}, Selector("baz(uint32,bool)"))
+	assert.Equal([4]byte{0x8b, 0xe6, 0x52, 0x46}, Selector("f(uint256,uint32[],bytes10,bytes)"))
+}
+
+type staticCircuit struct {
+	Data    [3 * WordSize]uints.U8
+	Address frontend.Variable
+	Bool    frontend.Variable
+	Amount  emulated.Element[emulated.Secp256k1Fr]
+}
+
+func (c *staticCircuit) Define(api frontend.API) error {
+	typ := Tuple(Address(), Bool(), Uint(256))
+	v, err := NewDecoder(api, c.Data[:]).Decode(typ, 0)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(ToVariable(api, v.Elems[0]), c.Address)
+	api.AssertIsEqual(ToVariable(api, v.Elems[1]), c.Bool)
+	amount, err := ToElement[emulated.Secp256k1Fr](api, v.Elems[2])
+	if err != nil {
+		return err
+	}
+	f, err := emulated.NewField[emulated.Secp256k1Fr](api)
+	if err != nil {
+		return err
+	}
+	f.AssertIsEqual(amount, &c.Amount)
+
+	fromAmount, err := FromElement(api, &c.Amount)
+	if err != nil {
+		return err
+	}
+	enc, err := NewEncoder(api).Encode(typ, FromTuple(FromVariable(api, c.Address, 160), FromVariable(api, c.Bool, 1), fromAmount))
+	if err != nil {
+		return err
+	}
+	assertEncoded(api, enc, c.Data[:], len(c.Data))
+	return nil
+}
+
+func TestStatic(t *testing.T) {
+	assert := test.NewAssert(t)
+	address, _ := new(big.Int).SetString("dac17f958d2ee523a2206206994597c13d831ec7", 16)
+	amount, _ := new(big.Int).SetString("fedcba9876543210fedcba9876543210fedcba9876543210fedcba987654321", 16)
+	data := append(append(address.FillBytes(make([]byte, WordSize)), big.NewInt(1).FillBytes(make([]byte, WordSize))...), amount.FillBytes(make([]byte, WordSize))...)
+	var assignment staticCircuit
+	copy(assignment.Data[:], uints.NewU8Array(data))
+	assignment.Address = address
+	assignment.Bool = 1
+	assignment.Amount = emulated.ValueOf[emulated.Secp256k1Fr](amount)
+	err := test.IsSolved(&staticCircuit{}, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the boolean must be 0 or 1.
+	wrong := assignment
+	data[2*WordSize-1] = 2
+	copy(wrong.Data[:], uints.NewU8Array(data))
+	wrong.Bool = 2
+	err = test.IsSolved(&staticCircuit{}, &wrong, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// the padding of the address must be zero.
+	data[2*WordSize-1] = 1
+	data[0] = 1
+	copy(wrong.Data[:], uints.NewU8Array(data))
+	wrong.Bool = 1
+	err = test.IsSolved(&staticCircuit{}, &wrong, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+// the example f(0x123, [0x456, 0x789], "1234567890", "Hello, world!") of the
+// specification.
+const dynamicData = `
+	0000000000000000000000000000000000000000000000000000000000000123
+	0000000000000000000000000000000000000000000000000000000000000080
+	3132333435363738393000000000000000000000000000000000000000000000
+	00000000000000000000000000000000000000000000000000000000000000e0
+	0000000000000000000000000000000000000000000000000000000000000002
+	0000000000000000000000000000000000000000000000000000000000000456
+	0000000000000000000000000000000000000000000000000000000000000789
+	000000000000000000000000000000000000000000000000000000000000000d
+	48656c6c6f2c20776f726c642100000000000000000000000000000000000000`
+
+type dynamicCircuit struct {
+	Data      []uints.U8
+	Ints      [3]frontend.Variable
+	NbInts    frontend.Variable
+	Fixed     [10]uints.U8
+	Str       [20]uints.U8
+	StrLength frontend.Variable
+}
+
+func (c *dynamicCircuit) Define(api frontend.API) error {
+	typ := Tuple(Uint(256), Slice(Uint(32), len(c.Ints)), FixedBytes(len(c.Fixed)), Bytes(len(c.Str)))
+	v, err := NewDecoder(api, c.Data).Decode(typ, 0)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(ToVariable(api, v.Elems[0]), 0x123)
+	api.AssertIsEqual(v.Elems[1].Length, c.NbInts)
+	for i := range c.Ints {
+		api.AssertIsEqual(ToVariable(api, v.Elems[1].Elems[i]), c.Ints[i])
+	}
+	for i := range c.Fixed {
+		api.AssertIsEqual(v.Elems[2].Word[i].Val, c.Fixed[i].Val)
+	}
+	api.AssertIsEqual(v.Elems[3].Length, c.StrLength)
+	for i := range c.Str {
+		api.AssertIsEqual(v.Elems[3].Bytes[i].Val, c.Str[i].Val)
+	}
+
+	ints := make([]Value, len(c.Ints))
+	for i := range ints {
+		ints[i] = FromVariable(api, c.Ints[i], 32)
+	}
+	fixed, err := FromFixedBytes(c.Fixed[:])
+	if err != nil {
+		return err
+	}
+	enc, err := NewEncoder(api).Encode(typ, FromTuple(
+		FromVariable(api, 0x123, 256),
+		FromSlice(ints, c.NbInts),
+		fixed,
+		FromBytes(c.Str[:], c.StrLength),
+	))
+	if err != nil {
+		return err
+	}
+	// the data is padded with one word.
+	assertEncoded(api, enc, c.Data, len(c.Data)-WordSize)
+	return nil
+}
+
+func TestDynamic(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the data is padded for reading the maximum length of the string.
+	data := append(mustHex(dynamicData), make([]byte, WordSize)...)
+	str := make([]byte, 20)
+	copy(str, "Hello, world!")
+	assignment := dynamicCircuit{
+		Data:      uints.NewU8Array(data),
+		Ints:      [3]frontend.Variable{0x456, 0x789, 0},
+		NbInts:    2,
+		StrLength: 13,
+	}
+	copy(assignment.Fixed[:], uints.NewU8Array([]byte("1234567890")))
+	copy(assignment.Str[:], uints.NewU8Array(str))
+	circuit := dynamicCircuit{Data: make([]uints.U8, len(data))}
+	err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the string is longer than the maximum length.
+	wrong := assignment
+	wrongData := append([]byte{}, data...)
+	wrongData[8*WordSize-1] = 21
+	wrong.Data = uints.NewU8Array(wrongData)
+	err = test.IsSolved(&circuit, &wrong, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+// the example g([[1, 2], [3]], ["one", "two", "three"]) of the specification.
+const nestedData = `
+	0000000000000000000000000000000000000000000000000000000000000040
+	0000000000000000000000000000000000000000000000000000000000000140
+	0000000000000000000000000000000000000000000000000000000000000002
+	0000000000000000000000000000000000000000000000000000000000000040
+	00000000000000000000000000000000000000000000000000000000000000a0
+	0000000000000000000000000000000000000000000000000000000000000002
+	0000000000000000000000000000000000000000000000000000000000000001
+	0000000000000000000000000000000000000000000000000000000000000002
+	0000000000000000000000000000000000000000000000000000000000000001
+	0000000000000000000000000000000000000000000000000000000000000003
+	0000000000000000000000000000000000000000000000000000000000000003
+	0000000000000000000000000000000000000000000000000000000000000060
+	00000000000000000000000000000000000000000000000000000000000000a0
+	00000000000000000000000000000000000000000000000000000000000000e0
+	0000000000000000000000000000000000000000000000000000000000000003
+	6f6e650000000000000000000000000000000000000000000000000000000000
+	0000000000000000000000000000000000000000000000000000000000000003
+	74776f0000000000000000000000000000000000000000000000000000000000
+	0000000000000000000000000000000000000000000000000000000000000005
+	7468726565000000000000000000000000000000000000000000000000000000`
+
+type nestedCircuit struct {
+	Data    []uints.U8
+	Lengths [3]frontend.Variable
+	Sum     frontend.Variable
+	Strings [4][8]uints.U8
+}
+
+func (c *nestedCircuit) Define(api frontend.API) error {
+	typ := Tuple(Slice(Slice(Uint(256), 2), 3), Slice(Bytes(8), 4))
+	v, err := NewDecoder(api, c.Data).Decode(typ, 0)
+	if err != nil {
+		return err
+	}
+	var sum frontend.Variable = 0
+	for i, inner := range v.Elems[0].Elems {
+		api.AssertIsEqual(inner.Length, c.Lengths[i])
+		for _, e := range inner.Elems {
+			sum = api.Add(sum, ToVariable(api, e))
+		}
+	}
+	api.AssertIsEqual(sum, c.Sum)
+	for i, s := range v.Elems[1].Elems {
+		for j := range s.Bytes {
+			api.AssertIsEqual(s.Bytes[j].Val, c.Strings[i][j].Val)
+		}
+	}
+
+	// the decoded value encodes back to the data.
+	enc, err := NewEncoder(api).Encode(typ, v)
+	if err != nil {
+		return err
+	}
+	assertEncoded(api, enc, c.Data, len(c.Data)-WordSize)
+	return nil
+}
+
+func TestNested(t *testing.T) {
+	assert := test.NewAssert(t)
+	data := mustHex(nestedData)
+	// the data is padded for reading the maximum lengths of the strings.
+	padded := append(data, make([]byte, WordSize)...)
+	assignment := nestedCircuit{
+		Data:    uints.NewU8Array(padded),
+		Lengths: [3]frontend.Variable{2, 1, 0},
+		Sum:     6,
+	}
+	for i, s := range []string{"one", "two", "three"} {
+		b := make([]byte, 8)
+		copy(b, s)
+		copy(assignment.Strings[i][:], uints.NewU8Array(b))
+	}
+	for i := range assignment.Strings[3] {
+		assignment.Strings[3][i] = uints.NewU8(0)
+	}
+	circuit := nestedCircuit{Data: make([]uints.U8, len(padded))}
+	err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
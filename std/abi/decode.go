@@ -0,0 +1,147 @@
+package abi
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Decoder decodes ABI encoded values from data at variable positions.
+type Decoder struct {
+	api   frontend.API
+	table *logderivlookup.Table
+}
+
+// NewDecoder returns a new decoder over data. For calldata, data should not
+// include the function selector. The bytes are looked up at variable positions
+// and looking up a byte outside of data fails at solving time. As the decoder
+// reads the maximum lengths of the dynamic values, data should be padded with
+// zero bytes if the values are shorter than their maximum lengths.
+func NewDecoder(api frontend.API, data []uints.U8) *Decoder {
+	table := logderivlookup.New(api)
+	for i := range data {
+		table.Insert(data[i].Val)
+	}
+	return &Decoder{
+		api:   api,
+		table: table,
+	}
+}
+
+// Decode decodes the value of type t whose encoding starts at position pos.
+// The arguments of a function are decoded as a tuple starting at position 0.
+//
+// It asserts that the padding of integers, addresses, booleans and fixed length
+// byte strings is zero and that the lengths of dynamic values are at most
+// their maximum lengths. The elements of dynamic arrays after the length are
+// zero values.
+func (d *Decoder) Decode(t *Type, pos frontend.Variable) (Value, error) {
+	if err := t.check(); err != nil {
+		return Value{}, fmt.Errorf("invalid type: %w", err)
+	}
+	return d.decode(t, pos, 1), nil
+}
+
+// decode decodes the value of type t at position pos. If active is 0, then the
+// value is not part of the encoding and the decoded value is zero. In that
+// case pos must be 0 so that the lookups stay inside of the data.
+func (d *Decoder) decode(t *Type, pos, active frontend.Variable) Value {
+	api := d.api
+	switch t.Kind {
+	case KindBytes:
+		length := d.uint(pos, active)
+		flags := selector.PrefixMask(api, length, t.Size)
+		b := d.bytes(api.Mul(active, api.Add(pos, WordSize)), t.Size)
+		for i := range b {
+			b[i] = uints.U8{Val: api.Mul(flags[i], b[i].Val)}
+		}
+		return Value{Bytes: b, Length: length}
+	case KindSlice:
+		length := d.uint(pos, active)
+		flags := selector.PrefixMask(api, length, t.Size)
+		elems := d.sequence(t.elems(t.Size), api.Add(pos, WordSize), flags)
+		return Value{Elems: elems, Length: length}
+	case KindArray, KindTuple:
+		types := t.elems(t.Size)
+		actives := make([]frontend.Variable, len(types))
+		for i := range actives {
+			actives[i] = active
+		}
+		return Value{Elems: d.sequence(types, pos, actives)}
+	}
+	word := d.bytes(pos, WordSize)
+	// the bytes of the word which must be zero.
+	padding := make([]bool, WordSize)
+	switch t.Kind {
+	case KindUint, KindAddress:
+		for i := 0; i < WordSize-t.Size/8; i++ {
+			padding[i] = true
+		}
+	case KindBool:
+		for i := 0; i < WordSize-1; i++ {
+			padding[i] = true
+		}
+		last := word[WordSize-1].Val
+		api.AssertIsEqual(api.Mul(active, last, api.Sub(last, 1)), 0)
+	case KindFixedBytes:
+		for i := t.Size; i < WordSize; i++ {
+			padding[i] = true
+		}
+	}
+	for i := range word {
+		word[i] = uints.U8{Val: api.Mul(active, word[i].Val)}
+		if padding[i] {
+			api.AssertIsEqual(word[i].Val, 0)
+		}
+	}
+	return Value{Word: word}
+}
+
+// sequence decodes the elements of the given types of a tuple or an array
+// whose encoding starts at position base. The element i is decoded only if
+// actives[i] is 1.
+func (d *Decoder) sequence(types []*Type, base frontend.Variable, actives []frontend.Variable) []Value {
+	api := d.api
+	res := make([]Value, len(types))
+	off := 0
+	for i, t := range types {
+		head := api.Mul(actives[i], api.Add(base, off))
+		if t.IsDynamic() {
+			// the head is the offset of the value relative to the start of
+			// the sequence.
+			o := d.uint(head, actives[i])
+			res[i] = d.decode(t, api.Mul(actives[i], api.Add(base, o)), actives[i])
+		} else {
+			res[i] = d.decode(t, head, actives[i])
+		}
+		off += t.headSize()
+	}
+	return res
+}
+
+// uint returns the length or offset encoded in the word at position pos. It
+// asserts that the value fits in 64 bits. If active is 0, then it returns 0.
+func (d *Decoder) uint(pos, active frontend.Variable) frontend.Variable {
+	word := d.bytes(pos, WordSize)
+	for i := range word {
+		word[i] = uints.U8{Val: d.api.Mul(active, word[i].Val)}
+	}
+	return packWord(d.api, word, 8)
+}
+
+// bytes returns n bytes starting at position pos.
+func (d *Decoder) bytes(pos frontend.Variable, n int) []uints.U8 {
+	inds := make([]frontend.Variable, n)
+	for i := range inds {
+		inds[i] = d.api.Add(pos, i)
+	}
+	vals := d.table.Lookup(inds...)
+	res := make([]uints.U8, n)
+	for i := range res {
+		res[i] = uints.U8{Val: vals[i]}
+	}
+	return res
+}
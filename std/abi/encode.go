@@ -0,0 +1,200 @@
+package abi
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Encoded is an ABI encoding of variable length. Only the first Length bytes of
+// Bytes are part of the encoding and the remaining bytes are zero. The length
+// is always a multiple of 32.
+type Encoded struct {
+	Bytes  []uints.U8
+	Length frontend.Variable
+}
+
+// Encoder encodes values into the ABI layout in circuit.
+type Encoder struct {
+	api frontend.API
+}
+
+// NewEncoder returns a new encoder.
+func NewEncoder(api frontend.API) *Encoder {
+	return &Encoder{api: api}
+}
+
+// Encode returns the encoding of the value v of type t. The arguments of a
+// function are encoded as a tuple. It asserts that the lengths of dynamic
+// values are at most the number of bytes or elements given in the value and
+// returns an error if the shape of the value does not correspond to the type.
+func (e *Encoder) Encode(t *Type, v Value) (Encoded, error) {
+	if err := t.check(); err != nil {
+		return Encoded{}, fmt.Errorf("invalid type: %w", err)
+	}
+	return e.encode(t, v)
+}
+
+func (e *Encoder) encode(t *Type, v Value) (Encoded, error) {
+	api := e.api
+	switch t.Kind {
+	case KindBytes:
+		if len(v.Bytes) > t.Size {
+			return Encoded{}, fmt.Errorf("byte string has %d bytes, maximum is %d", len(v.Bytes), t.Size)
+		}
+		flags := selector.PrefixMask(api, v.Length, len(v.Bytes))
+		nbWords := (len(v.Bytes) + WordSize - 1) / WordSize
+		res := make([]uints.U8, WordSize*(nbWords+1))
+		copy(res, e.word(v.Length))
+		for i := range res[WordSize:] {
+			res[WordSize+i] = uints.NewU8(0)
+			if i < len(v.Bytes) {
+				res[WordSize+i] = uints.U8{Val: api.Mul(flags[i], v.Bytes[i].Val)}
+			}
+		}
+		// the data is padded to a multiple of 32 bytes, the word w is used if
+		// the length is larger than 32*w.
+		var length frontend.Variable = WordSize
+		for w := 0; w < nbWords; w++ {
+			length = api.Add(length, api.Mul(flags[WordSize*w], WordSize))
+		}
+		return Encoded{Bytes: res, Length: length}, nil
+	case KindSlice:
+		if len(v.Elems) > t.Size {
+			return Encoded{}, fmt.Errorf("array has %d elements, maximum is %d", len(v.Elems), t.Size)
+		}
+		flags := selector.PrefixMask(api, v.Length, len(v.Elems))
+		seq, err := e.sequence(t.elems(len(v.Elems)), v.Elems, flags)
+		if err != nil {
+			return Encoded{}, err
+		}
+		return e.concat(Encoded{Bytes: e.word(v.Length), Length: WordSize}, seq), nil
+	case KindArray, KindTuple:
+		types := t.elems(t.Size)
+		if len(v.Elems) != len(types) {
+			return Encoded{}, fmt.Errorf("value has %d elements, expected %d", len(v.Elems), len(types))
+		}
+		return e.sequence(types, v.Elems, nil)
+	}
+	if len(v.Word) != WordSize {
+		return Encoded{}, fmt.Errorf("word has %d bytes, expected %d", len(v.Word), WordSize)
+	}
+	return Encoded{Bytes: v.Word, Length: WordSize}, nil
+}
+
+// sequence returns the encoding of the elements of a tuple or an array. If
+// actives is not nil, then the element i is encoded only if actives[i] is 1.
+// The inactive elements must follow the active elements.
+func (e *Encoder) sequence(types []*Type, elems []Value, actives []frontend.Variable) (Encoded, error) {
+	api := e.api
+	heads := make([]Encoded, len(types))
+	var tails []Encoded
+	var headsLen frontend.Variable = 0
+	for i, t := range types {
+		enc, err := e.encode(t, elems[i])
+		if err != nil {
+			return Encoded{}, fmt.Errorf("element %d: %w", i, err)
+		}
+		if actives != nil {
+			enc = e.mask(enc, actives[i])
+		}
+		if t.IsDynamic() {
+			tails = append(tails, enc)
+		} else {
+			heads[i] = enc
+		}
+		if actives != nil {
+			headsLen = api.Add(headsLen, api.Mul(actives[i], t.headSize()))
+		} else {
+			headsLen = api.Add(headsLen, t.headSize())
+		}
+	}
+	// the heads of the dynamic elements are the offsets of the tails relative
+	// to the start of the sequence.
+	off, j := headsLen, 0
+	for i, t := range types {
+		if !t.IsDynamic() {
+			continue
+		}
+		heads[i] = Encoded{Bytes: e.word(off), Length: WordSize}
+		if actives != nil {
+			heads[i] = e.mask(heads[i], actives[i])
+		}
+		off = api.Add(off, tails[j].Length)
+		j++
+	}
+	return e.concat(append(heads, tails...)...), nil
+}
+
+// concat returns the concatenation of the encodings. The maximum length of the
+// result is the sum of the maximum lengths of the inputs.
+func (e *Encoder) concat(items ...Encoded) Encoded {
+	res := Encoded{Length: 0}
+	for i := range items {
+		res = e.concat2(res, items[i])
+	}
+	return res
+}
+
+// concat2 returns the concatenation of a and b. The bytes of b are looked up at
+// the positions shifted by the length of a. As the lengths are multiples of
+// 32, then it is sufficient to compute the position of b per word.
+func (e *Encoder) concat2(a, b Encoded) Encoded {
+	api := e.api
+	if len(b.Bytes) == 0 {
+		return a
+	}
+	if l, ok := a.Length.(int); ok && l == len(a.Bytes) {
+		// a is of fixed length.
+		res := make([]uints.U8, 0, len(a.Bytes)+len(b.Bytes))
+		res = append(append(res, a.Bytes...), b.Bytes...)
+		if lb, ok := b.Length.(int); ok {
+			return Encoded{Bytes: res, Length: l + lb}
+		}
+		return Encoded{Bytes: res, Length: api.Add(l, b.Length)}
+	}
+	nbWords := (len(a.Bytes) + len(b.Bytes)) / WordSize
+	table := logderivlookup.New(api)
+	for i := range b.Bytes {
+		table.Insert(b.Bytes[i].Val)
+	}
+	length := api.Add(a.Length, b.Length)
+	// the lengths in words are exact divisions.
+	inA := selector.PrefixMask(api, api.Div(a.Length, WordSize), nbWords)
+	inRes := selector.PrefixMask(api, api.Div(length, WordSize), nbWords)
+	inds := make([]frontend.Variable, WordSize*nbWords)
+	for k := range inds {
+		inB := api.Sub(inRes[k/WordSize], inA[k/WordSize])
+		inds[k] = api.Mul(inB, api.Sub(k, a.Length))
+	}
+	vals := table.Lookup(inds...)
+	res := make([]uints.U8, len(inds))
+	for k := range res {
+		w := k / WordSize
+		v := api.Mul(api.Sub(inRes[w], inA[w]), vals[k])
+		if k < len(a.Bytes) {
+			v = api.Add(v, api.Mul(inA[w], a.Bytes[k].Val))
+		}
+		res[k] = uints.U8{Val: v}
+	}
+	return Encoded{Bytes: res, Length: length}
+}
+
+// mask returns the encoding if active is 1 and the empty encoding otherwise.
+func (e *Encoder) mask(enc Encoded, active frontend.Variable) Encoded {
+	res := make([]uints.U8, len(enc.Bytes))
+	for i := range res {
+		res[i] = uints.U8{Val: e.api.Mul(active, enc.Bytes[i].Val)}
+	}
+	return Encoded{Bytes: res, Length: e.api.Mul(active, enc.Length)}
+}
+
+// word returns the word of the length or offset v. It asserts that v fits in
+// 64 bits.
+func (e *Encoder) word(v frontend.Variable) []uints.U8 {
+	return wordFromBits(e.api, bits.ToBinary(e.api, v, bits.WithNbDigits(64)))
+}
@@ -0,0 +1,149 @@
+package abi
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Value is an in-circuit ABI value. Which fields are set depends on the kind
+// of the type of the value.
+type Value struct {
+	// Word is the 32-byte encoding of integers, addresses, booleans and fixed
+	// length byte strings. Integers are big-endian and byte strings are left
+	// aligned.
+	Word []uints.U8
+	// Bytes are the bytes of dynamic byte strings. Only the first Length bytes
+	// are part of the string and the remaining bytes are zero.
+	Bytes []uints.U8
+	// Length is the length of dynamic byte strings and arrays.
+	Length frontend.Variable
+	// Elems are the elements of arrays and the fields of tuples. For dynamic
+	// arrays, only the first Length elements are part of the array.
+	Elems []Value
+}
+
+// FromVariable returns the value of the unsigned integer v of at most nbBits
+// bits. It is used for integers, addresses and booleans. It asserts that v
+// fits in nbBits bits.
+func FromVariable(api frontend.API, v frontend.Variable, nbBits int) Value {
+	vb := bits.ToBinary(api, v, bits.WithNbDigits(nbBits))
+	for len(vb)%8 != 0 {
+		vb = append(vb, 0)
+	}
+	return Value{Word: wordFromBits(api, vb)}
+}
+
+// ToVariable returns the integer value v as a native variable. It asserts that
+// the value fits in the bytes which can be packed into a native field element,
+// i.e. 31 bytes for 254-bit fields. Use [ToElement] for larger values.
+func ToVariable(api frontend.API, v Value) frontend.Variable {
+	nbBytes := (api.Compiler().FieldBitLen() - 1) / 8
+	return packWord(api, v.Word, nbBytes)
+}
+
+// FromElement returns the value of the emulated element. It asserts that the
+// reduced element fits in 256 bits.
+func FromElement[T emulated.FieldParams](api frontend.API, el *emulated.Element[T]) (Value, error) {
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return Value{}, fmt.Errorf("new field: %w", err)
+	}
+	r := f.Reduce(el)
+	f.AssertIsInRange(r)
+	eb := f.ToBits(r)
+	for i := 8 * WordSize; i < len(eb); i++ {
+		api.AssertIsEqual(eb[i], 0)
+	}
+	for len(eb) < 8*WordSize {
+		eb = append(eb, 0)
+	}
+	return Value{Word: wordFromBits(api, eb[:8*WordSize])}, nil
+}
+
+// ToElement returns the integer value v as an emulated element. The element is
+// not reduced and can be larger than the modulus of the emulated field. If the
+// emulated elements have less than 256 bits, then it asserts that the value
+// fits.
+func ToElement[T emulated.FieldParams](api frontend.API, v Value) (*emulated.Element[T], error) {
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return nil, fmt.Errorf("new field: %w", err)
+	}
+	if len(v.Word) != WordSize {
+		return nil, fmt.Errorf("word has %d bytes, expected %d", len(v.Word), WordSize)
+	}
+	var fp T
+	nbBits := min(int(fp.NbLimbs()*fp.BitsPerLimb()), 8*WordSize)
+	vb := make([]frontend.Variable, 0, 8*WordSize)
+	for i := WordSize - 1; i >= 0; i-- {
+		vb = append(vb, bits.ToBinary(api, v.Word[i].Val, bits.WithNbDigits(8))...)
+	}
+	for i := nbBits; i < len(vb); i++ {
+		api.AssertIsEqual(vb[i], 0)
+	}
+	return f.FromBits(vb[:nbBits]...), nil
+}
+
+// FromFixedBytes returns the value of the fixed length byte string b of at
+// most 32 bytes.
+func FromFixedBytes(b []uints.U8) (Value, error) {
+	if len(b) > WordSize {
+		return Value{}, fmt.Errorf("fixed bytes have %d bytes, maximum is %d", len(b), WordSize)
+	}
+	word := make([]uints.U8, WordSize)
+	copy(word, b)
+	for i := len(b); i < WordSize; i++ {
+		word[i] = uints.NewU8(0)
+	}
+	return Value{Word: word}, nil
+}
+
+// FromBytes returns the value of the dynamic byte string given by the first
+// length bytes of b. The remaining bytes must be zero.
+func FromBytes(b []uints.U8, length frontend.Variable) Value {
+	return Value{Bytes: b, Length: length}
+}
+
+// FromSlice returns the value of the dynamic array given by the first length
+// elements. The elements after the length are not encoded, but must be valid
+// values of the element type, for example zero values.
+func FromSlice(elems []Value, length frontend.Variable) Value {
+	return Value{Elems: elems, Length: length}
+}
+
+// FromTuple returns the value of the fixed length array or tuple of the
+// elements.
+func FromTuple(elems ...Value) Value {
+	return Value{Elems: elems}
+}
+
+// wordFromBits returns the big-endian word of the little-endian bits, padded
+// with zero bytes.
+func wordFromBits(api frontend.API, vb []frontend.Variable) []uints.U8 {
+	word := make([]uints.U8, WordSize)
+	for i := range word {
+		word[WordSize-1-i] = uints.NewU8(0)
+		if 8*i < len(vb) {
+			word[WordSize-1-i] = uints.U8{Val: bits.FromBinary(api, vb[8*i:8*i+8], bits.WithUnconstrainedInputs())}
+		}
+	}
+	return word
+}
+
+// packWord returns the integer given by the last nbBytes bytes of the
+// big-endian word and asserts that the preceding bytes are zero.
+func packWord(api frontend.API, word []uints.U8, nbBytes int) frontend.Variable {
+	var res frontend.Variable = 0
+	for i := range word {
+		if i < len(word)-nbBytes {
+			api.AssertIsEqual(word[i].Val, 0)
+			continue
+		}
+		res = api.Add(api.Mul(res, 256), word[i].Val)
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+package merkle
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// IncrementalTree is the state of an append-only Merkle tree of fixed depth,
+// as maintained by the incremental Merkle tree contracts of Tornado Cash or
+// Semaphore. The leaves are inserted from left to right and the empty leaves
+// are set to a zero value. The empty subtrees are thus the hashes of the zero
+// value, zeros[i+1] = H(zeros[i], zeros[i]) with zeros[0] the zero value.
+//
+// Instead of storing the whole tree, the state consists of the rightmost
+// filled subtree at every level. When the next index is a right child at some
+// level, then its left sibling is the filled subtree of the level, otherwise
+// its right sibling is empty. The leaves are inserted as is, without hashing.
+//
+// Use [AppendOnlyTree] for building the tree and the assignments out of
+// circuit.
+type IncrementalTree struct {
+	// RootHash is the root of the tree.
+	RootHash frontend.Variable
+
+	// NextIndex is the index of the next inserted leaf.
+	NextIndex frontend.Variable
+
+	// FilledSubtrees are the last filled left subtrees at every level,
+	// starting from the leaves. The depth of the tree is the number of
+	// filled subtrees.
+	FilledSubtrees []frontend.Variable
+}
+
+// PlaceholderIncrementalTree returns a placeholder state for a tree of the
+// given depth to be used for compiling the circuit.
+func PlaceholderIncrementalTree(depth int) IncrementalTree {
+	return IncrementalTree{FilledSubtrees: make([]frontend.Variable, depth)}
+}
+
+// Insert asserts that the state is consistent with the root t.RootHash and
+// returns the state of the tree after inserting the leaf at index
+// t.NextIndex. It asserts that the tree is not full. The returned state can be
+// used for inserting more leaves.
+func (t *IncrementalTree) Insert(api frontend.API, h hash.FieldHasher, zeroValue, leaf frontend.Variable) IncrementalTree {
+	depth := len(t.FilledSubtrees)
+	path := api.ToBinary(t.NextIndex, depth)
+	res := IncrementalTree{
+		NextIndex:      api.Add(t.NextIndex, 1),
+		FilledSubtrees: make([]frontend.Variable, depth),
+	}
+	// the path of the next leaf is empty, so we recompute the root from an
+	// empty leaf for checking the filled subtrees on the path.
+	oldNode := zeroValue
+	newNode := leaf
+	zero := zeroValue
+	for i := 0; i < depth; i++ {
+		res.FilledSubtrees[i] = api.Select(path[i], t.FilledSubtrees[i], newNode)
+		left := api.Select(path[i], t.FilledSubtrees[i], oldNode)
+		right := api.Select(path[i], oldNode, zero)
+		oldNode = nodeSum(api, h, left, right)
+		left = api.Select(path[i], t.FilledSubtrees[i], newNode)
+		right = api.Select(path[i], newNode, zero)
+		newNode = nodeSum(api, h, left, right)
+		zero = nodeSum(api, h, zero, zero)
+	}
+	api.AssertIsEqual(oldNode, t.RootHash)
+	res.RootHash = newNode
+	return res
+}
@@ -0,0 +1,120 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type incrementalInsertCircuit struct {
+	Tree      IncrementalTree
+	ZeroValue frontend.Variable `gnark:",public"`
+	Leaves    [2]frontend.Variable
+	NewRoot   frontend.Variable `gnark:",public"`
+}
+
+func (c *incrementalInsertCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	tree := c.Tree
+	for i := range c.Leaves {
+		tree = tree.Insert(api, &h, c.ZeroValue, c.Leaves[i])
+	}
+	api.AssertIsEqual(tree.RootHash, c.NewRoot)
+	api.AssertIsEqual(tree.NextIndex, api.Add(c.Tree.NextIndex, len(c.Leaves)))
+	return nil
+}
+
+func TestIncrementalInsert(t *testing.T) {
+	assert := test.NewAssert(t)
+	const depth = 4
+	zeroValue := big.NewInt(42)
+	circuit := incrementalInsertCircuit{Tree: PlaceholderIncrementalTree(depth)}
+
+	for _, tc := range []struct {
+		name  string
+		start int
+	}{
+		{"empty", 0},
+		{"odd", 5},
+		{"last", 14},
+	} {
+		tc := tc
+		assert.Run(func(assert *test.Assert) {
+			tree, err := NewAppendOnlyTree(hash.MIMC_BN254.New(), ecc.BN254.ScalarField(), depth, zeroValue)
+			assert.NoError(err)
+			for i := 0; i < tc.start; i++ {
+				assert.NoError(tree.Insert(big.NewInt(int64(100 + i))))
+			}
+			state := tree.Assignment()
+			assert.NoError(tree.Insert(big.NewInt(1)))
+			assert.NoError(tree.Insert(big.NewInt(2)))
+			newRoot := tree.Root()
+
+			wrongState := tree.Assignment()
+			wrongState.NextIndex = state.NextIndex
+			assert.CheckCircuit(&circuit,
+				test.WithValidAssignment(&incrementalInsertCircuit{Tree: state, ZeroValue: zeroValue, Leaves: [2]frontend.Variable{1, 2}, NewRoot: newRoot}),
+				test.WithInvalidAssignment(&incrementalInsertCircuit{Tree: state, ZeroValue: zeroValue, Leaves: [2]frontend.Variable{2, 1}, NewRoot: newRoot}),
+				test.WithInvalidAssignment(&incrementalInsertCircuit{Tree: state, ZeroValue: 0, Leaves: [2]frontend.Variable{1, 2}, NewRoot: newRoot}),
+				test.WithInvalidAssignment(&incrementalInsertCircuit{Tree: wrongState, ZeroValue: zeroValue, Leaves: [2]frontend.Variable{1, 2}, NewRoot: newRoot}),
+				test.WithCurves(ecc.BN254))
+		}, tc.name)
+	}
+}
+
+func TestIncrementalInsertFull(t *testing.T) {
+	assert := test.NewAssert(t)
+	const depth = 2
+	tree, err := NewAppendOnlyTree(hash.MIMC_BN254.New(), ecc.BN254.ScalarField(), depth, new(big.Int))
+	assert.NoError(err)
+	for i := 0; i < 3; i++ {
+		assert.NoError(tree.Insert(big.NewInt(int64(i + 1))))
+	}
+	state := tree.Assignment()
+	assert.NoError(tree.Insert(big.NewInt(4)))
+	assert.Error(tree.Insert(big.NewInt(5)))
+
+	// inserting two leaves into a tree with a single empty leaf fails.
+	assert.CheckCircuit(&incrementalInsertCircuit{Tree: PlaceholderIncrementalTree(depth)},
+		test.WithInvalidAssignment(&incrementalInsertCircuit{Tree: state, ZeroValue: 0, Leaves: [2]frontend.Variable{4, 5}, NewRoot: tree.Root()}),
+		test.WithCurves(ecc.BN254), test.NoFuzzing())
+}
+
+func TestAppendOnlyTreeRoot(t *testing.T) {
+	assert := test.NewAssert(t)
+	const depth = 3
+	modulus := ecc.BN254.ScalarField()
+	nbBytes := (modulus.BitLen() + 7) / 8
+	zeroValue := big.NewInt(7)
+	tree, err := NewAppendOnlyTree(hash.MIMC_BN254.New(), modulus, depth, zeroValue)
+	assert.NoError(err)
+
+	// compare against the root of the full tree where the empty leaves are
+	// set to the zero value.
+	leaves := make([]*big.Int, 1<<depth)
+	for i := range leaves {
+		leaves[i] = zeroValue
+	}
+	for i := range leaves {
+		leaves[i] = big.NewInt(int64(1000 + i))
+		assert.NoError(tree.Insert(leaves[i]))
+		level := leaves
+		for len(level) > 1 {
+			next := make([]*big.Int, len(level)/2)
+			for j := range next {
+				next[j] = hashElements(hash.MIMC_BN254.New(), nbBytes, level[2*j], level[2*j+1])
+			}
+			level = next
+		}
+		assert.Equal(level[0], tree.Root(), i)
+		assert.Equal(uint64(i+1), tree.NextIndex())
+	}
+}
@@ -0,0 +1,131 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// AppendOnlyTree is an out-of-circuit incremental Merkle tree matching the
+// gadget [IncrementalTree]. It follows the insertion algorithm of the
+// incremental Merkle tree contracts and only stores the filled subtrees.
+type AppendOnlyTree struct {
+	h         hash.Hash
+	modulus   *big.Int
+	nbBytes   int
+	depth     int
+	nextIndex uint64
+
+	// zeros[i] is the root of an empty subtree of height i
+	zeros []*big.Int
+	// filled[i] is the last filled left subtree of height i
+	filled []*big.Int
+	root   *big.Int
+}
+
+// NewAppendOnlyTree returns an empty incremental Merkle tree of the given
+// depth over the scalar field with the modulus, where the empty leaves are
+// set to zeroValue. The depth must be at most 63.
+func NewAppendOnlyTree(h hash.Hash, modulus *big.Int, depth int, zeroValue *big.Int) (*AppendOnlyTree, error) {
+	if depth <= 0 || depth > 63 || depth >= modulus.BitLen() {
+		return nil, fmt.Errorf("invalid depth %d", depth)
+	}
+	if zeroValue.Sign() < 0 || zeroValue.Cmp(modulus) >= 0 {
+		return nil, errors.New("zero value is not a reduced field element")
+	}
+	t := &AppendOnlyTree{
+		h:       h,
+		modulus: modulus,
+		nbBytes: (modulus.BitLen() + 7) / 8,
+		depth:   depth,
+		zeros:   make([]*big.Int, depth+1),
+		filled:  make([]*big.Int, depth),
+	}
+	t.zeros[0] = new(big.Int).Set(zeroValue)
+	for i := 1; i <= depth; i++ {
+		t.zeros[i] = hashElements(t.h, t.nbBytes, t.zeros[i-1], t.zeros[i-1])
+	}
+	for i := range t.filled {
+		t.filled[i] = t.zeros[i]
+	}
+	t.root = t.zeros[depth]
+	return t, nil
+}
+
+// Depth returns the depth of the tree.
+func (t *AppendOnlyTree) Depth() int {
+	return t.depth
+}
+
+// Root returns the root of the tree.
+func (t *AppendOnlyTree) Root() *big.Int {
+	return new(big.Int).Set(t.root)
+}
+
+// NextIndex returns the index of the next inserted leaf.
+func (t *AppendOnlyTree) NextIndex() uint64 {
+	return t.nextIndex
+}
+
+// Zeros returns the roots of the empty subtrees of heights 0 to the depth of
+// the tree.
+func (t *AppendOnlyTree) Zeros() []*big.Int {
+	res := make([]*big.Int, len(t.zeros))
+	for i := range res {
+		res[i] = new(big.Int).Set(t.zeros[i])
+	}
+	return res
+}
+
+// FilledSubtrees returns the last filled left subtrees at every level,
+// starting from the leaves.
+func (t *AppendOnlyTree) FilledSubtrees() []*big.Int {
+	res := make([]*big.Int, len(t.filled))
+	for i := range res {
+		res[i] = new(big.Int).Set(t.filled[i])
+	}
+	return res
+}
+
+// Insert inserts the leaf at the next index. It returns an error if the tree
+// is full.
+func (t *AppendOnlyTree) Insert(leaf *big.Int) error {
+	if t.nextIndex >= 1<<t.depth {
+		return errors.New("tree is full")
+	}
+	if leaf.Sign() < 0 || leaf.Cmp(t.modulus) >= 0 {
+		return errors.New("leaf is not a reduced field element")
+	}
+	idx := t.nextIndex
+	node := new(big.Int).Set(leaf)
+	for i := 0; i < t.depth; i++ {
+		if idx&1 == 0 {
+			t.filled[i] = node
+			node = hashElements(t.h, t.nbBytes, node, t.zeros[i])
+		} else {
+			node = hashElements(t.h, t.nbBytes, t.filled[i], node)
+		}
+		idx >>= 1
+	}
+	t.root = node
+	t.nextIndex++
+	return nil
+}
+
+// Assignment returns the assignment of [IncrementalTree] for the current
+// state of the tree. For proving an insertion with [IncrementalTree.Insert],
+// the assignment must be computed before inserting the leaf.
+func (t *AppendOnlyTree) Assignment() IncrementalTree {
+	res := IncrementalTree{
+		RootHash:       t.Root(),
+		NextIndex:      t.nextIndex,
+		FilledSubtrees: make([]frontend.Variable, t.depth),
+	}
+	for i := range t.filled {
+		res.FilledSubtrees[i] = new(big.Int).Set(t.filled[i])
+	}
+	return res
+}
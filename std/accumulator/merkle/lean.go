@@ -0,0 +1,59 @@
+package merkle
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// LeanProof is a proof of membership in a lean incremental Merkle tree, the
+// LeanIMT of zk-kit used by Semaphore v4. In this binary tree, the leaves are
+// inserted from left to right and a node without right sibling is not hashed
+// but moved up as is to its parent. The depth of the tree grows with the
+// number of leaves, so the proof has a variable number of siblings, which are
+// padded with zeros up to the maximal depth of the circuit.
+//
+// The verification matches the BinaryMerkleRoot template of zk-kit. Use
+// [LeanTree] for building the tree and the proofs out of circuit.
+type LeanProof struct {
+	// RootHash is the root of the tree.
+	RootHash frontend.Variable
+
+	// Depth is the number of siblings on the path, at most len(Siblings).
+	Depth frontend.Variable
+
+	// Index is the path of the leaf. Its i-th bit is set if the node is the
+	// right child at the i-th level with a sibling, starting from the leaves.
+	Index frontend.Variable
+
+	// Siblings are the siblings on the path, starting from the leaves, padded
+	// with zeros. The maximal depth is the number of siblings.
+	Siblings []frontend.Variable
+}
+
+// PlaceholderLeanProof returns a placeholder proof for a tree of the given
+// maximal depth to be used for compiling the circuit.
+func PlaceholderLeanProof(maxDepth int) LeanProof {
+	return LeanProof{Siblings: make([]frontend.Variable, maxDepth)}
+}
+
+// VerifyProof asserts that leaf is a leaf of the tree with root p.RootHash. It
+// asserts that the depth is at most the maximal depth.
+func (p *LeanProof) VerifyProof(api frontend.API, h hash.FieldHasher, leaf frontend.Variable) {
+	maxDepth := len(p.Siblings)
+	path := api.ToBinary(p.Index, maxDepth)
+	node := leaf
+	var root, found frontend.Variable = 0, 0
+	for i := 0; i <= maxDepth; i++ {
+		// the root is the node at the level of the depth
+		isDepth := api.IsZero(api.Sub(p.Depth, i))
+		root = api.Add(root, api.Mul(isDepth, node))
+		found = api.Add(found, isDepth)
+		if i < maxDepth {
+			left := api.Select(path[i], p.Siblings[i], node)
+			right := api.Select(path[i], node, p.Siblings[i])
+			node = nodeSum(api, h, left, right)
+		}
+	}
+	api.AssertIsEqual(found, 1)
+	api.AssertIsEqual(root, p.RootHash)
+}
@@ -0,0 +1,106 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type leanProofCircuit struct {
+	Proof LeanProof
+	Leaf  frontend.Variable
+}
+
+func (c *leanProofCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	c.Proof.VerifyProof(api, &h, c.Leaf)
+	return nil
+}
+
+func TestLeanProof(t *testing.T) {
+	assert := test.NewAssert(t)
+	const maxDepth = 3
+	circuit := leanProofCircuit{Proof: PlaceholderLeanProof(maxDepth)}
+	tree := NewLeanTree(hash.MIMC_BN254.New(), ecc.BN254.ScalarField())
+	for size := 1; size <= 1<<maxDepth; size++ {
+		assert.NoError(tree.Insert(big.NewInt(int64(100 + size))))
+		for i := 0; i < size; i++ {
+			proof, err := tree.Proof(i, maxDepth)
+			assert.NoError(err)
+			err = test.IsSolved(&circuit, &leanProofCircuit{Proof: proof, Leaf: 101 + i}, ecc.BN254.ScalarField())
+			assert.NoError(err, size, i)
+			err = test.IsSolved(&circuit, &leanProofCircuit{Proof: proof, Leaf: 100 + i}, ecc.BN254.ScalarField())
+			assert.Error(err, size, i)
+		}
+	}
+	_, err := tree.Proof(1<<maxDepth, maxDepth)
+	assert.Error(err)
+	assert.NoError(tree.Insert(big.NewInt(1)))
+	_, err = tree.Proof(0, maxDepth)
+	assert.Error(err)
+}
+
+func TestLeanProofDepth(t *testing.T) {
+	assert := test.NewAssert(t)
+	const maxDepth = 4
+	tree := NewLeanTree(hash.MIMC_BN254.New(), ecc.BN254.ScalarField())
+	for i := 0; i < 5; i++ {
+		assert.NoError(tree.Insert(big.NewInt(int64(i + 1))))
+	}
+	proof, err := tree.Proof(4, maxDepth)
+	assert.NoError(err)
+	// the last leaf of a tree of 5 leaves has a single sibling at height 2.
+	assert.Equal(1, proof.Depth)
+	wrongDepth, _ := tree.Proof(4, maxDepth)
+	wrongDepth.Depth = maxDepth + 1
+	wrongRoot, _ := tree.Proof(4, maxDepth)
+	wrongRoot.RootHash = 5
+	assert.CheckCircuit(&leanProofCircuit{Proof: PlaceholderLeanProof(maxDepth)},
+		test.WithValidAssignment(&leanProofCircuit{Proof: proof, Leaf: 5}),
+		test.WithInvalidAssignment(&leanProofCircuit{Proof: wrongDepth, Leaf: 5}),
+		test.WithInvalidAssignment(&leanProofCircuit{Proof: wrongRoot, Leaf: 5}),
+		test.WithCurves(ecc.BN254))
+}
+
+func TestLeanTreeRoot(t *testing.T) {
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.ScalarField()
+	nbBytes := (modulus.BitLen() + 7) / 8
+	tree := NewLeanTree(hash.MIMC_BN254.New(), modulus)
+	_, err := tree.Root()
+	assert.Error(err)
+
+	// compare against the root computed level by level, where the last node of
+	// a level without sibling is moved up.
+	var leaves []*big.Int
+	for i := 0; i < 11; i++ {
+		leaves = append(leaves, big.NewInt(int64(1000+i)))
+		assert.NoError(tree.Insert(leaves[i]))
+		level := leaves
+		for len(level) > 1 {
+			next := make([]*big.Int, (len(level)+1)/2)
+			for j := range next {
+				if 2*j+1 < len(level) {
+					next[j] = hashElements(hash.MIMC_BN254.New(), nbBytes, level[2*j], level[2*j+1])
+				} else {
+					next[j] = level[2*j]
+				}
+			}
+			level = next
+		}
+		root, err := tree.Root()
+		assert.NoError(err)
+		assert.Equal(level[0], root, i)
+		assert.Equal(i+1, tree.Size())
+	}
+	assert.Equal(4, tree.Depth())
+	assert.Equal(leaves, tree.Leaves())
+}
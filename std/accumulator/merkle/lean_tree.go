@@ -0,0 +1,123 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"math/bits"
+)
+
+// LeanTree is an out-of-circuit lean incremental Merkle tree matching the
+// gadget [LeanProof]. It follows the LeanIMT of zk-kit, where a parent is the
+// hash of its children and a node without right sibling is its own parent.
+// Semaphore uses it with the native Poseidon.
+type LeanTree struct {
+	h       hash.Hash
+	modulus *big.Int
+	nbBytes int
+
+	// nodes[i] are the nodes at height i, starting from the leaves
+	nodes [][]*big.Int
+}
+
+// NewLeanTree returns an empty lean incremental Merkle tree over the scalar
+// field with the modulus.
+func NewLeanTree(h hash.Hash, modulus *big.Int) *LeanTree {
+	return &LeanTree{
+		h:       h,
+		modulus: modulus,
+		nbBytes: (modulus.BitLen() + 7) / 8,
+		nodes:   [][]*big.Int{nil},
+	}
+}
+
+// Size returns the number of leaves of the tree.
+func (t *LeanTree) Size() int {
+	return len(t.nodes[0])
+}
+
+// Depth returns the depth of the tree, which is the smallest d such that the
+// tree has at most 2^d leaves.
+func (t *LeanTree) Depth() int {
+	return len(t.nodes) - 1
+}
+
+// Root returns the root of the tree. It returns an error if the tree is
+// empty.
+func (t *LeanTree) Root() (*big.Int, error) {
+	if t.Size() == 0 {
+		return nil, errors.New("empty tree")
+	}
+	return new(big.Int).Set(t.nodes[t.Depth()][0]), nil
+}
+
+// Leaves returns the leaves of the tree.
+func (t *LeanTree) Leaves() []*big.Int {
+	res := make([]*big.Int, t.Size())
+	for i := range res {
+		res[i] = new(big.Int).Set(t.nodes[0][i])
+	}
+	return res
+}
+
+// Insert inserts the leaf at the next index. It returns an error if the leaf
+// is not a reduced field element.
+func (t *LeanTree) Insert(leaf *big.Int) error {
+	if leaf.Sign() < 0 || leaf.Cmp(t.modulus) >= 0 {
+		return errors.New("leaf is not a reduced field element")
+	}
+	index := t.Size()
+	depth := bits.Len(uint(index))
+	for len(t.nodes) <= depth {
+		t.nodes = append(t.nodes, nil)
+	}
+	node := new(big.Int).Set(leaf)
+	for i := 0; i <= depth; i++ {
+		idx := index >> i
+		if idx == len(t.nodes[i]) {
+			t.nodes[i] = append(t.nodes[i], node)
+		} else {
+			t.nodes[i][idx] = node
+		}
+		if idx&1 == 1 {
+			node = hashElements(t.h, t.nbBytes, t.nodes[i][idx-1], node)
+		}
+	}
+	return nil
+}
+
+// Proof returns the assignment of [LeanProof] for the leaf at the index, with
+// the siblings padded to the maximal depth. It returns an error if the index
+// is out of range or if the depth of the tree exceeds the maximal depth.
+func (t *LeanTree) Proof(index, maxDepth int) (LeanProof, error) {
+	if index < 0 || index >= t.Size() {
+		return LeanProof{}, fmt.Errorf("index %d out of range", index)
+	}
+	if t.Depth() > maxDepth {
+		return LeanProof{}, fmt.Errorf("tree depth %d exceeds maximal depth %d", t.Depth(), maxDepth)
+	}
+	root, err := t.Root()
+	if err != nil {
+		return LeanProof{}, err
+	}
+	res := PlaceholderLeanProof(maxDepth)
+	res.RootHash = root
+	depth, path := 0, 0
+	for i := 0; i < t.Depth(); i++ {
+		idx := index >> i
+		sibling := idx ^ 1
+		// the last node of a level may not have a sibling
+		if sibling < len(t.nodes[i]) {
+			path |= (idx & 1) << depth
+			res.Siblings[depth] = new(big.Int).Set(t.nodes[i][sibling])
+			depth++
+		}
+	}
+	for i := depth; i < maxDepth; i++ {
+		res.Siblings[i] = 0
+	}
+	res.Depth = depth
+	res.Index = path
+	return res, nil
+}
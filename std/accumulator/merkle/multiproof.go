@@ -0,0 +1,205 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"sort"
+
+	"github.com/consensys/gnark/frontend"
+	fhash "github.com/consensys/gnark/std/hash"
+)
+
+// MultiProof is a proof of membership of several leaves in a Merkle tree of
+// fixed depth. The nodes shared by the paths of the leaves are computed only
+// once and the proof only contains the nodes which cannot be computed from the
+// leaves.
+//
+// The positions of the leaves are part of the circuit definition and have to
+// be set both in the circuit and in the assignment. The tree is compatible
+// with the gnark-crypto merkletree package and [MerkleProof].
+type MultiProof struct {
+	// RootHash root of the Merkle tree
+	RootHash frontend.Variable
+
+	// Nodes are the nodes required for computing the root from the leaves,
+	// ordered by height and then by position in the tree.
+	Nodes []frontend.Variable
+
+	// Depth is the depth of the tree.
+	Depth int `gnark:"-"`
+
+	// Indices are the positions of the leaves in the tree.
+	Indices []int `gnark:"-"`
+}
+
+// PlaceholderMultiProof returns a placeholder multiproof for the leaves at the
+// given positions in a tree of the given depth to be used for compiling the
+// circuit.
+func PlaceholderMultiProof(depth int, indices []int) (MultiProof, error) {
+	layout, err := newMultiProofLayout(depth, indices)
+	if err != nil {
+		return MultiProof{}, err
+	}
+	return MultiProof{
+		Nodes:   make([]frontend.Variable, layout.nbNodes),
+		Depth:   depth,
+		Indices: append([]int{}, indices...),
+	}, nil
+}
+
+// VerifyProof asserts that leaves[i] is the data of the leaf at position
+// mp.Indices[i] in the tree with root mp.RootHash. It returns an error if the
+// number of leaves or proof nodes doesn't match the positions.
+func (mp *MultiProof) VerifyProof(api frontend.API, h fhash.FieldHasher, leaves []frontend.Variable) error {
+	if len(leaves) != len(mp.Indices) {
+		return fmt.Errorf("got %d leaves for %d indices", len(leaves), len(mp.Indices))
+	}
+	layout, err := newMultiProofLayout(mp.Depth, mp.Indices)
+	if err != nil {
+		return err
+	}
+	if len(mp.Nodes) != layout.nbNodes {
+		return fmt.Errorf("got %d proof nodes, expected %d", len(mp.Nodes), layout.nbNodes)
+	}
+
+	known := make(map[int]frontend.Variable, len(leaves))
+	for i := range leaves {
+		known[mp.Indices[i]] = leafSum(api, h, leaves[i])
+	}
+	next := 0
+	for _, positions := range layout.levels {
+		parents := make(map[int]frontend.Variable, len(positions))
+		for _, p := range positions {
+			if p&1 == 1 {
+				if _, ok := known[p^1]; ok {
+					// already hashed with the left sibling
+					continue
+				}
+			}
+			sibling, ok := known[p^1]
+			if !ok {
+				sibling = mp.Nodes[next]
+				next++
+			}
+			if p&1 == 0 {
+				parents[p>>1] = nodeSum(api, h, known[p], sibling)
+			} else {
+				parents[p>>1] = nodeSum(api, h, sibling, known[p])
+			}
+		}
+		known = parents
+	}
+	api.AssertIsEqual(known[0], mp.RootHash)
+	return nil
+}
+
+// BuildMultiProof returns the assignment of [MultiProof] for the leaves at the
+// given positions in the tree whose leaves are the hashes of the data
+// segments. The number of segments must be a power of two. The data of the
+// proven leaves must be assigned separately.
+func BuildMultiProof(h hash.Hash, segments [][]byte, indices []int) (MultiProof, error) {
+	if len(segments) < 2 || len(segments)&(len(segments)-1) != 0 {
+		return MultiProof{}, errors.New("number of segments is not a power of two")
+	}
+	depth := 0
+	for 1<<depth < len(segments) {
+		depth++
+	}
+	layout, err := newMultiProofLayout(depth, indices)
+	if err != nil {
+		return MultiProof{}, err
+	}
+
+	// compute all the levels of the tree
+	tree := make([][][]byte, depth+1)
+	tree[0] = make([][]byte, len(segments))
+	for i := range segments {
+		tree[0][i] = sum(h, segments[i])
+	}
+	for l := 1; l <= depth; l++ {
+		tree[l] = make([][]byte, len(tree[l-1])/2)
+		for i := range tree[l] {
+			tree[l][i] = sum(h, tree[l-1][2*i], tree[l-1][2*i+1])
+		}
+	}
+
+	res := MultiProof{
+		RootHash: tree[depth][0],
+		Nodes:    make([]frontend.Variable, 0, layout.nbNodes),
+		Depth:    depth,
+		Indices:  append([]int{}, indices...),
+	}
+	for l, positions := range layout.levels {
+		for _, p := range missingSiblings(positions) {
+			res.Nodes = append(res.Nodes, tree[l][p])
+		}
+	}
+	return res, nil
+}
+
+// multiProofLayout describes the nodes on the paths of the leaves of a
+// multiproof.
+type multiProofLayout struct {
+	// levels[l] are the sorted positions of the nodes at height l which are
+	// on the paths from the leaves to the root.
+	levels [][]int
+	// nbNodes is the number of nodes in the proof.
+	nbNodes int
+}
+
+func newMultiProofLayout(depth int, indices []int) (multiProofLayout, error) {
+	if depth <= 0 || depth >= 63 {
+		return multiProofLayout{}, fmt.Errorf("invalid depth %d", depth)
+	}
+	if len(indices) == 0 {
+		return multiProofLayout{}, errors.New("no leaves")
+	}
+	positions := append([]int{}, indices...)
+	sort.Ints(positions)
+	for i := range positions {
+		if positions[i] < 0 || positions[i] >= 1<<depth {
+			return multiProofLayout{}, fmt.Errorf("index %d out of range for depth %d", positions[i], depth)
+		}
+		if i > 0 && positions[i] == positions[i-1] {
+			return multiProofLayout{}, fmt.Errorf("duplicate index %d", positions[i])
+		}
+	}
+	var layout multiProofLayout
+	for l := 0; l < depth; l++ {
+		layout.levels = append(layout.levels, positions)
+		layout.nbNodes += len(missingSiblings(positions))
+		parents := make([]int, 0, len(positions))
+		for _, p := range positions {
+			if len(parents) == 0 || parents[len(parents)-1] != p>>1 {
+				parents = append(parents, p>>1)
+			}
+		}
+		positions = parents
+	}
+	return layout, nil
+}
+
+// missingSiblings returns the positions of the siblings of the sorted
+// positions which are not themselves in positions.
+func missingSiblings(positions []int) []int {
+	var res []int
+	for i, p := range positions {
+		if p&1 == 0 && i+1 < len(positions) && positions[i+1] == p+1 {
+			continue
+		}
+		if p&1 == 1 && i > 0 && positions[i-1] == p-1 {
+			continue
+		}
+		res = append(res, p^1)
+	}
+	return res
+}
+
+func sum(h hash.Hash, data ...[]byte) []byte {
+	h.Reset()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,91 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type multiProofCircuit struct {
+	M      MultiProof
+	Leaves []frontend.Variable
+}
+
+func (c *multiProofCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return c.M.VerifyProof(api, &h, c.Leaves)
+}
+
+func TestMultiProof(t *testing.T) {
+	assert := test.NewAssert(t)
+	const depth = 5
+	mod := ecc.BN254.ScalarField()
+	segments := make([][]byte, 1<<depth)
+	var buf bytes.Buffer
+	for i := range segments {
+		leaf, err := rand.Int(rand.Reader, mod)
+		assert.NoError(err)
+		segments[i] = leaf.FillBytes(make([]byte, 32))
+		buf.Write(segments[i])
+	}
+	root, _, _, err := merkletree.BuildReaderProof(&buf, hash.MIMC_BN254.New(), 32, 0)
+	assert.NoError(err)
+
+	for _, indices := range [][]int{
+		{7},
+		{0, 1},
+		{31, 17, 5, 0, 30, 1},
+	} {
+		indices := indices
+		assert.Run(func(assert *test.Assert) {
+			proof, err := BuildMultiProof(hash.MIMC_BN254.New(), segments, indices)
+			assert.NoError(err)
+			assert.Equal(root, proof.RootHash)
+
+			placeholder, err := PlaceholderMultiProof(depth, indices)
+			assert.NoError(err)
+			circuit := multiProofCircuit{M: placeholder, Leaves: make([]frontend.Variable, len(indices))}
+			valid := multiProofCircuit{M: proof, Leaves: make([]frontend.Variable, len(indices))}
+			invalid := multiProofCircuit{M: proof, Leaves: make([]frontend.Variable, len(indices))}
+			for i, idx := range indices {
+				valid.Leaves[i] = segments[idx]
+				invalid.Leaves[i] = segments[idx]
+			}
+			invalid.Leaves[len(indices)-1] = segments[(indices[len(indices)-1]+2)%len(segments)]
+			assert.CheckCircuit(&circuit, test.WithValidAssignment(&valid), test.WithInvalidAssignment(&invalid), test.WithCurves(ecc.BN254))
+		})
+	}
+}
+
+func TestMultiProofLayout(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// two sibling leaves share the whole path
+	layout, err := newMultiProofLayout(5, []int{0, 1})
+	assert.NoError(err)
+	assert.Equal(4, layout.nbNodes)
+
+	// all the leaves don't need any node
+	all := make([]int, 8)
+	for i := range all {
+		all[i] = i
+	}
+	layout, err = newMultiProofLayout(3, all)
+	assert.NoError(err)
+	assert.Equal(0, layout.nbNodes)
+
+	_, err = newMultiProofLayout(3, []int{1, 1})
+	assert.Error(err)
+	_, err = newMultiProofLayout(3, []int{8})
+	assert.Error(err)
+}
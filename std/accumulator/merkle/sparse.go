@@ -0,0 +1,93 @@
+package merkle
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// the domain separation tags of the leaves and of the internal nodes of the
+// sparse Merkle tree.
+const (
+	sparseLeafTag = 1
+	sparseNodeTag = 2
+)
+
+// SparseMerkleProof is a proof for a key in a sparse Merkle tree of fixed
+// depth. The tree has a leaf for every key in [0, 2ᵈ) where d is the depth.
+// Empty leaves are 0 and the leaf of a non-zero value v is H(1, v), so a zero
+// value denotes an absent key. An internal node with children l and r is
+// H(2, l, r), so empty subtrees are hashes of zeros. The tags ensure that an
+// internal node cannot be presented as a leaf and conversely.
+//
+// Use [SparseTree] for building the tree and the assignments out of circuit.
+type SparseMerkleProof struct {
+	// RootHash is the root of the tree.
+	RootHash frontend.Variable
+
+	// Siblings are the siblings of the nodes on the path from the leaf to the
+	// root, starting from the sibling of the leaf. The depth of the tree is
+	// the number of siblings.
+	Siblings []frontend.Variable
+}
+
+// PlaceholderSparseMerkleProof returns a placeholder proof for a tree of the
+// given depth to be used for compiling the circuit.
+func PlaceholderSparseMerkleProof(depth int) SparseMerkleProof {
+	return SparseMerkleProof{Siblings: make([]frontend.Variable, depth)}
+}
+
+// VerifyMembership asserts that the key is set to the non-zero value in the
+// tree with root mp.RootHash.
+func (mp *SparseMerkleProof) VerifyMembership(api frontend.API, h hash.FieldHasher, key, value frontend.Variable) {
+	api.AssertIsDifferent(value, 0)
+	path := api.ToBinary(key, len(mp.Siblings))
+	root := mp.computeRoot(api, h, path, sparseLeaf(api, h, value))
+	api.AssertIsEqual(root, mp.RootHash)
+}
+
+// VerifyNonMembership asserts that the key is absent from the tree with root
+// mp.RootHash, that is its leaf is empty.
+func (mp *SparseMerkleProof) VerifyNonMembership(api frontend.API, h hash.FieldHasher, key frontend.Variable) {
+	path := api.ToBinary(key, len(mp.Siblings))
+	root := mp.computeRoot(api, h, path, 0)
+	api.AssertIsEqual(root, mp.RootHash)
+}
+
+// UpdateLeaf asserts that the key is set to oldValue in the tree with root
+// mp.RootHash and returns the root of the tree where the key is set to
+// newValue. A zero oldValue proves that the key is inserted and a zero
+// newValue that the key is removed.
+func (mp *SparseMerkleProof) UpdateLeaf(api frontend.API, h hash.FieldHasher, key, oldValue, newValue frontend.Variable) frontend.Variable {
+	path := api.ToBinary(key, len(mp.Siblings))
+	oldRoot := mp.computeRoot(api, h, path, sparseLeaf(api, h, oldValue))
+	api.AssertIsEqual(oldRoot, mp.RootHash)
+	return mp.computeRoot(api, h, path, sparseLeaf(api, h, newValue))
+}
+
+// computeRoot returns the root of the tree from the leaf node and the
+// siblings. The bits of the path are the little-endian decomposition of the
+// key, a set bit means that the node is the right child.
+func (mp *SparseMerkleProof) computeRoot(api frontend.API, h hash.FieldHasher, path []frontend.Variable, leaf frontend.Variable) frontend.Variable {
+	sum := leaf
+	for i := range mp.Siblings {
+		d1 := api.Select(path[i], mp.Siblings[i], sum)
+		d2 := api.Select(path[i], sum, mp.Siblings[i])
+		sum = sparseNode(api, h, d1, d2)
+	}
+	return sum
+}
+
+// sparseLeaf returns the leaf node of the value, which is 0 for a zero value
+// and H(1, value) otherwise.
+func sparseLeaf(api frontend.API, h hash.FieldHasher, value frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(sparseLeafTag, value)
+	return api.Select(api.IsZero(value), 0, h.Sum())
+}
+
+// sparseNode returns the internal node H(2, left, right).
+func sparseNode(api frontend.API, h hash.FieldHasher, left, right frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(sparseNodeTag, left, right)
+	return h.Sum()
+}
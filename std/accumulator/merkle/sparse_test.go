@@ -0,0 +1,174 @@
+package merkle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type sparseMembershipCircuit struct {
+	Proof SparseMerkleProof
+	Key   frontend.Variable
+	Value frontend.Variable
+}
+
+func (c *sparseMembershipCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	c.Proof.VerifyMembership(api, &h, c.Key, c.Value)
+	return nil
+}
+
+type sparseNonMembershipCircuit struct {
+	Proof SparseMerkleProof
+	Key   frontend.Variable
+}
+
+func (c *sparseNonMembershipCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	c.Proof.VerifyNonMembership(api, &h, c.Key)
+	return nil
+}
+
+type sparseUpdateCircuit struct {
+	Proof    SparseMerkleProof
+	Key      frontend.Variable
+	OldValue frontend.Variable
+	NewValue frontend.Variable
+	NewRoot  frontend.Variable
+}
+
+func (c *sparseUpdateCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	newRoot := c.Proof.UpdateLeaf(api, &h, c.Key, c.OldValue, c.NewValue)
+	api.AssertIsEqual(newRoot, c.NewRoot)
+	return nil
+}
+
+func newTestSparseTree(assert *test.Assert, depth int) *SparseTree {
+	tree, err := NewSparseTree(hash.MIMC_BN254.New(), ecc.BN254.ScalarField(), depth)
+	assert.NoError(err)
+	for i, kv := range [][2]int64{{3, 30}, {4, 40}, {1000, 1}, {1001, 77}} {
+		assert.NoError(tree.Set(big.NewInt(kv[0]), big.NewInt(kv[1])), i)
+	}
+	return tree
+}
+
+func TestSparseMembership(t *testing.T) {
+	assert := test.NewAssert(t)
+	const depth = 10
+	tree := newTestSparseTree(assert, depth)
+
+	proof, err := tree.Assignment(big.NewInt(4))
+	assert.NoError(err)
+	siblings, err := tree.Prove(big.NewInt(4))
+	assert.NoError(err)
+	assert.True(tree.VerifySparseProof(tree.Root(), big.NewInt(4), big.NewInt(40), siblings))
+
+	circuit := sparseMembershipCircuit{Proof: PlaceholderSparseMerkleProof(depth)}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&sparseMembershipCircuit{Proof: proof, Key: 4, Value: 40}),
+		test.WithInvalidAssignment(&sparseMembershipCircuit{Proof: proof, Key: 4, Value: 41}),
+		test.WithInvalidAssignment(&sparseMembershipCircuit{Proof: proof, Key: 3, Value: 40}),
+		test.WithCurves(ecc.BN254))
+}
+
+func TestSparseNonMembership(t *testing.T) {
+	assert := test.NewAssert(t)
+	const depth = 10
+	tree := newTestSparseTree(assert, depth)
+
+	proof, err := tree.Assignment(big.NewInt(5))
+	assert.NoError(err)
+	occupied, err := tree.Assignment(big.NewInt(1001))
+	assert.NoError(err)
+
+	circuit := sparseNonMembershipCircuit{Proof: PlaceholderSparseMerkleProof(depth)}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&sparseNonMembershipCircuit{Proof: proof, Key: 5}),
+		test.WithInvalidAssignment(&sparseNonMembershipCircuit{Proof: occupied, Key: 1001}),
+		test.WithInvalidAssignment(&sparseNonMembershipCircuit{Proof: proof, Key: 4}),
+		test.WithCurves(ecc.BN254))
+}
+
+func TestSparseUpdate(t *testing.T) {
+	assert := test.NewAssert(t)
+	const depth = 10
+	circuit := sparseUpdateCircuit{Proof: PlaceholderSparseMerkleProof(depth)}
+
+	for _, tc := range []struct {
+		name      string
+		key       int64
+		old, next int64
+	}{
+		{"update", 1000, 1, 2},
+		{"insert", 7, 0, 70},
+		{"remove", 3, 30, 0},
+	} {
+		tc := tc
+		assert.Run(func(assert *test.Assert) {
+			tree := newTestSparseTree(assert, depth)
+			proof, err := tree.Assignment(big.NewInt(tc.key))
+			assert.NoError(err)
+			assert.NoError(tree.Set(big.NewInt(tc.key), big.NewInt(tc.next)))
+			newRoot := tree.Root()
+
+			assert.CheckCircuit(&circuit,
+				test.WithValidAssignment(&sparseUpdateCircuit{Proof: proof, Key: tc.key, OldValue: tc.old, NewValue: tc.next, NewRoot: newRoot}),
+				test.WithInvalidAssignment(&sparseUpdateCircuit{Proof: proof, Key: tc.key, OldValue: tc.old + 1, NewValue: tc.next, NewRoot: newRoot}),
+				test.WithInvalidAssignment(&sparseUpdateCircuit{Proof: proof, Key: tc.key, OldValue: tc.old, NewValue: tc.next + 1, NewRoot: newRoot}),
+				test.WithCurves(ecc.BN254))
+		}, tc.name)
+	}
+}
+
+func TestSparseTreeRemove(t *testing.T) {
+	assert := test.NewAssert(t)
+	empty, err := NewSparseTree(hash.MIMC_BN254.New(), ecc.BN254.ScalarField(), 10)
+	assert.NoError(err)
+	tree := newTestSparseTree(assert, 10)
+	for _, k := range []int64{3, 4, 1000, 1001} {
+		assert.NoError(tree.Set(big.NewInt(k), new(big.Int)))
+	}
+	assert.Equal(empty.Root(), tree.Root())
+	for i := range tree.nodes {
+		assert.Empty(tree.nodes[i])
+	}
+}
+
+func TestSparseTreeDomainSeparation(t *testing.T) {
+	assert := test.NewAssert(t)
+	tree, err := NewSparseTree(hash.MIMC_BN254.New(), ecc.BN254.ScalarField(), 2)
+	assert.NoError(err)
+	assert.NoError(tree.Set(big.NewInt(1), big.NewInt(10)))
+
+	// the leaves are H(1, v) and the internal nodes H(2, l, r), so that a node
+	// cannot be opened as a leaf.
+	h, nbBytes := hash.MIMC_BN254.New(), len(ecc.BN254.ScalarField().Bytes())
+	zero := new(big.Int)
+	leaf := hashElements(h, nbBytes, big.NewInt(1), big.NewInt(10))
+	left := hashElements(h, nbBytes, big.NewInt(2), zero, leaf)
+	right := hashElements(h, nbBytes, big.NewInt(2), zero, zero)
+	root := hashElements(h, nbBytes, big.NewInt(2), left, right)
+	assert.Equal(root, tree.Root())
+
+	proof, err := tree.Assignment(big.NewInt(1))
+	assert.NoError(err)
+	circuit := sparseMembershipCircuit{Proof: PlaceholderSparseMerkleProof(2)}
+	assert.CheckCircuit(&circuit,
+		test.WithValidAssignment(&sparseMembershipCircuit{Proof: proof, Key: 1, Value: 10}),
+		test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,207 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// SparseTree is an out-of-circuit sparse Merkle tree matching the gadget
+// [SparseMerkleProof]. Only the non-empty nodes are stored.
+type SparseTree struct {
+	h       hash.Hash
+	modulus *big.Int
+	nbBytes int
+	depth   int
+
+	// zeros[i] is the root of an empty subtree of height i
+	zeros []*big.Int
+	// nodes[i] are the non-empty nodes at height i indexed by position
+	nodes []map[string]*big.Int
+}
+
+// NewSparseTree returns an empty sparse Merkle tree of the given depth over
+// the scalar field with the modulus. The keys are in [0, 2ᵈ) where d is the
+// depth, which must be smaller than the bit length of the modulus.
+func NewSparseTree(h hash.Hash, modulus *big.Int, depth int) (*SparseTree, error) {
+	if depth <= 0 || depth >= modulus.BitLen() {
+		return nil, fmt.Errorf("invalid depth %d", depth)
+	}
+	t := &SparseTree{
+		h:       h,
+		modulus: modulus,
+		nbBytes: (modulus.BitLen() + 7) / 8,
+		depth:   depth,
+		zeros:   make([]*big.Int, depth+1),
+		nodes:   make([]map[string]*big.Int, depth+1),
+	}
+	t.zeros[0] = new(big.Int)
+	for i := 1; i <= depth; i++ {
+		t.zeros[i] = t.hashNode(t.zeros[i-1], t.zeros[i-1])
+	}
+	for i := range t.nodes {
+		t.nodes[i] = make(map[string]*big.Int)
+	}
+	return t, nil
+}
+
+// Depth returns the depth of the tree.
+func (t *SparseTree) Depth() int {
+	return t.depth
+}
+
+// Root returns the root of the tree.
+func (t *SparseTree) Root() *big.Int {
+	return t.node(t.depth, new(big.Int))
+}
+
+// Get returns the value of the key, which is zero for absent keys.
+func (t *SparseTree) Get(key *big.Int) (*big.Int, error) {
+	if err := t.checkKey(key); err != nil {
+		return nil, err
+	}
+	if v, ok := t.nodes[0][key.String()]; ok {
+		return new(big.Int).Set(v), nil
+	}
+	return new(big.Int), nil
+}
+
+// Set sets the key to the value. A zero value removes the key from the tree.
+func (t *SparseTree) Set(key, value *big.Int) error {
+	if err := t.checkKey(key); err != nil {
+		return err
+	}
+	if value.Sign() < 0 || value.Cmp(t.modulus) >= 0 {
+		return errors.New("value is not a reduced field element")
+	}
+	// the values are stored at height 0, the leaf nodes are their tagged hashes
+	idx := new(big.Int).Set(key)
+	var node *big.Int
+	if value.Sign() == 0 {
+		delete(t.nodes[0], idx.String())
+		node = t.zeros[0]
+	} else {
+		t.nodes[0][idx.String()] = new(big.Int).Set(value)
+		node = t.hashLeaf(value)
+	}
+	for i := 1; i <= t.depth; i++ {
+		sibling := t.leafOrNode(i-1, new(big.Int).Xor(idx, big.NewInt(1)))
+		if idx.Bit(0) == 0 {
+			node = t.hashNode(node, sibling)
+		} else {
+			node = t.hashNode(sibling, node)
+		}
+		idx.Rsh(idx, 1)
+		if node.Cmp(t.zeros[i]) == 0 {
+			delete(t.nodes[i], idx.String())
+		} else {
+			t.nodes[i][idx.String()] = node
+		}
+	}
+	return nil
+}
+
+// Prove returns the siblings of the path from the leaf of the key to the
+// root, starting from the sibling of the leaf.
+func (t *SparseTree) Prove(key *big.Int) ([]*big.Int, error) {
+	if err := t.checkKey(key); err != nil {
+		return nil, err
+	}
+	siblings := make([]*big.Int, t.depth)
+	idx := new(big.Int).Set(key)
+	for i := 0; i < t.depth; i++ {
+		siblings[i] = new(big.Int).Set(t.leafOrNode(i, new(big.Int).Xor(idx, big.NewInt(1))))
+		idx.Rsh(idx, 1)
+	}
+	return siblings, nil
+}
+
+// Assignment returns the assignment of [SparseMerkleProof] for the key in
+// the current state of the tree. For proving an update with
+// [SparseMerkleProof.UpdateLeaf], the assignment must be computed before
+// setting the new value.
+func (t *SparseTree) Assignment(key *big.Int) (SparseMerkleProof, error) {
+	siblings, err := t.Prove(key)
+	if err != nil {
+		return SparseMerkleProof{}, err
+	}
+	res := SparseMerkleProof{
+		RootHash: t.Root(),
+		Siblings: make([]frontend.Variable, len(siblings)),
+	}
+	for i := range siblings {
+		res.Siblings[i] = siblings[i]
+	}
+	return res, nil
+}
+
+// VerifySparseProof verifies out of circuit that the key is set to the value
+// in the tree with the root, where a zero value proves non-membership.
+func (t *SparseTree) VerifySparseProof(root, key, value *big.Int, siblings []*big.Int) bool {
+	if len(siblings) != t.depth || t.checkKey(key) != nil {
+		return false
+	}
+	node := t.zeros[0]
+	if value.Sign() != 0 {
+		node = t.hashLeaf(value)
+	}
+	for i := range siblings {
+		if key.Bit(i) == 0 {
+			node = t.hashNode(node, siblings[i])
+		} else {
+			node = t.hashNode(siblings[i], node)
+		}
+	}
+	return node.Cmp(root) == 0
+}
+
+// leafOrNode returns the node at height i and position idx, where the nodes
+// at height 0 are the leaves of the stored values.
+func (t *SparseTree) leafOrNode(i int, idx *big.Int) *big.Int {
+	if i == 0 {
+		if v, ok := t.nodes[0][idx.String()]; ok {
+			return t.hashLeaf(v)
+		}
+		return t.zeros[0]
+	}
+	return t.node(i, idx)
+}
+
+func (t *SparseTree) node(i int, idx *big.Int) *big.Int {
+	if n, ok := t.nodes[i][idx.String()]; ok {
+		return n
+	}
+	return t.zeros[i]
+}
+
+func (t *SparseTree) checkKey(key *big.Int) error {
+	if key.Sign() < 0 || key.BitLen() > t.depth {
+		return fmt.Errorf("key out of range for depth %d", t.depth)
+	}
+	return nil
+}
+
+// hashLeaf returns the leaf H(1, value) of a non-zero value.
+func (t *SparseTree) hashLeaf(value *big.Int) *big.Int {
+	return hashElements(t.h, t.nbBytes, big.NewInt(sparseLeafTag), value)
+}
+
+// hashNode returns the internal node H(2, left, right).
+func (t *SparseTree) hashNode(left, right *big.Int) *big.Int {
+	return hashElements(t.h, t.nbBytes, big.NewInt(sparseNodeTag), left, right)
+}
+
+// hashElements returns the hash of the field elements written in big-endian
+// form on nbBytes bytes.
+func hashElements(h hash.Hash, nbBytes int, data ...*big.Int) *big.Int {
+	h.Reset()
+	buf := make([]byte, nbBytes)
+	for _, d := range data {
+		d.FillBytes(buf)
+		h.Write(buf)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
@@ -43,6 +43,11 @@ limitations under the License.
 */
 
 // Package merkle provides a ZKP-circuit function to verify merkle proofs.
+//
+// The out-of-circuit trees of this package take the native counterpart of the
+// in-circuit hash, for example gnark-crypto MiMC for the MiMC gadget. The
+// field elements are written into the hash function in big-endian form,
+// padded to the size of the modulus.
 package merkle
 
 import (
@@ -0,0 +1,310 @@
+// Package mpt implements the verification of Ethereum Merkle-Patricia trie
+// proofs.
+//
+// A proof is the list of the RLP-encoded trie nodes on the path from the root
+// to the leaf containing the value, as returned by the eth_getProof RPC call.
+// Every node is hashed with Keccak-256 and the hash is compared against the
+// reference in the parent node (or the root for the first node). The path of
+// the proof is checked against the nibbles of the key:
+//   - a branch node consumes a single nibble of the key and references the
+//     child at the position given by the nibble;
+//   - an extension node consumes the nibbles of its shared path and references
+//     the next node;
+//   - a leaf node consumes the remaining nibbles of the key and contains the
+//     value.
+//
+// The number of nodes in the proof is variable and bounded by the number of
+// nodes in the placeholder proof. The lengths of the nodes are variable and
+// bounded by the size of the node slices in the placeholder.
+//
+// For the secure tries used in Ethereum state and storage tries, the key is
+// the Keccak-256 hash of the address or the storage slot. As the keys are then
+// 32 bytes, all nodes except the root are referenced by their hashes. The
+// verifier does not support tries with nodes shorter than 32 bytes embedded in
+// their parents.
+package mpt
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/sha3"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/cmp"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rlp"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Proof is a Merkle-Patricia trie inclusion proof.
+type Proof struct {
+	// Nodes are the RLP-encoded nodes of the proof, starting from the root
+	// node. The nodes after Depth are ignored.
+	Nodes [][]uints.U8
+	// Lengths are the lengths of the encoded nodes.
+	Lengths []frontend.Variable
+	// Depth is the number of nodes in the proof.
+	Depth frontend.Variable
+}
+
+// PlaceholderProof returns a placeholder proof for compiling the circuit. The
+// proof can contain up to maxDepth nodes of at most maxNodeLen bytes. The
+// branch nodes of Ethereum tries are at most 532 bytes long.
+func PlaceholderProof(maxDepth, maxNodeLen int) Proof {
+	p := Proof{
+		Nodes:   make([][]uints.U8, maxDepth),
+		Lengths: make([]frontend.Variable, maxDepth),
+	}
+	for i := range p.Nodes {
+		p.Nodes[i] = make([]uints.U8, maxNodeLen)
+	}
+	return p
+}
+
+// ValueOfProof returns the assignment of the proof given by the encoded nodes
+// for the circuit compiled with [PlaceholderProof] of the same maxDepth and
+// maxNodeLen.
+func ValueOfProof(nodes [][]byte, maxDepth, maxNodeLen int) (Proof, error) {
+	if len(nodes) == 0 {
+		return Proof{}, errors.New("empty proof")
+	}
+	if len(nodes) > maxDepth {
+		return Proof{}, fmt.Errorf("proof has %d nodes, maximum is %d", len(nodes), maxDepth)
+	}
+	p := Proof{
+		Nodes:   make([][]uints.U8, maxDepth),
+		Lengths: make([]frontend.Variable, maxDepth),
+		Depth:   len(nodes),
+	}
+	for i := range p.Nodes {
+		node := make([]byte, maxNodeLen)
+		if i < len(nodes) {
+			if len(nodes[i]) > maxNodeLen {
+				return Proof{}, fmt.Errorf("node %d has %d bytes, maximum is %d", i, len(nodes[i]), maxNodeLen)
+			}
+			copy(node, nodes[i])
+			p.Lengths[i] = len(nodes[i])
+		} else {
+			p.Lengths[i] = 0
+		}
+		p.Nodes[i] = uints.NewU8Array(node)
+	}
+	return p, nil
+}
+
+// VerifyProof asserts that the proof is a valid inclusion proof of the key in
+// the trie with the given root. It returns the value stored at the key padded
+// with zeros to maxValueLen bytes and the length of the value. The value is
+// asserted to be non-empty and at most maxValueLen bytes.
+//
+// The key is used as is, so for secure tries the caller should provide the
+// hash of the key.
+func (p *Proof) VerifyProof(api frontend.API, root []uints.U8, key []uints.U8, maxValueLen int) ([]uints.U8, frontend.Variable, error) {
+	if len(p.Nodes) == 0 || len(p.Nodes) != len(p.Lengths) {
+		return nil, nil, errors.New("invalid proof size")
+	}
+	if len(root) != 32 {
+		return nil, nil, fmt.Errorf("root must be 32 bytes, got %d", len(root))
+	}
+	if len(key) == 0 {
+		return nil, nil, errors.New("empty key")
+	}
+	if maxValueLen <= 0 {
+		return nil, nil, errors.New("maximum value length must be positive")
+	}
+	maxNodeLen := 0
+	for i := range p.Nodes {
+		if len(p.Nodes[i]) > maxNodeLen {
+			maxNodeLen = len(p.Nodes[i])
+		}
+	}
+	keyNibbles := 2 * len(key)
+	// the path of an extension or leaf node is hex-prefix encoded with a flag
+	// nibble and up to keyNibbles nibbles.
+	maxPathBytes := len(key) + 1
+	maxPathNibbles := keyNibbles + 1
+
+	// nibbles of the key. The lookups are done at k+j where k is the number
+	// of consumed nibbles and j is the position in the extension path, so we
+	// pad the table to avoid looking up out of bounds.
+	keyTable := logderivlookup.New(api)
+	for i := range key {
+		bits := api.ToBinary(key[i].Val, 8)
+		keyTable.Insert(api.FromBinary(bits[4:]...))
+		keyTable.Insert(api.FromBinary(bits[:4]...))
+	}
+	for i := 0; i < maxPathNibbles; i++ {
+		keyTable.Insert(0)
+	}
+	// the items are decoded at most 17 positions after the end of the node
+	// and the bytes are read at most the length of the longest read after
+	// the beginning of an item.
+	padding := 32
+	if maxPathBytes > padding {
+		padding = maxPathBytes
+	}
+	if maxValueLen > padding {
+		padding = maxValueLen
+	}
+	padding += 17 + 8
+
+	comparator := cmp.NewBoundedComparator(api, big.NewInt(int64(maxNodeLen+padding)), false)
+
+	ref := make([]frontend.Variable, 32)
+	for i := range root {
+		ref[i] = root[i].Val
+	}
+	value := make([]uints.U8, maxValueLen)
+	for i := range value {
+		value[i] = uints.NewU8(0)
+	}
+	var valueLen frontend.Variable = 0
+	var k frontend.Variable = 0
+	var active frontend.Variable = 1
+	for i := range p.Nodes {
+		// active is 1 for the nodes in the proof. The proof must contain at
+		// least one node.
+		active = api.Sub(active, api.IsZero(api.Sub(p.Depth, i)))
+		if i == 0 {
+			api.AssertIsEqual(active, 1)
+		}
+		isLast := api.IsZero(api.Sub(p.Depth, i+1))
+		notLast := api.Sub(active, isLast)
+
+		bh, err := sha3.NewLegacyKeccak256(api)
+		if err != nil {
+			return nil, nil, fmt.Errorf("new keccak: %w", err)
+		}
+		h, ok := bh.(hash.BinaryFixedLengthHasher)
+		if !ok {
+			return nil, nil, errors.New("keccak does not support variable length inputs")
+		}
+		h.Write(p.Nodes[i])
+		digest := h.FixedLengthSum(p.Lengths[i])
+		for j := range digest {
+			api.AssertIsEqual(api.Mul(active, api.Sub(digest[j].Val, ref[j])), 0)
+		}
+
+		// decode the node with the bytes after its length zeroed.
+		data := make([]uints.U8, len(p.Nodes[i])+padding)
+		var inData frontend.Variable = 1
+		for j := range p.Nodes[i] {
+			inData = api.Sub(inData, api.IsZero(api.Sub(j, p.Lengths[i])))
+			data[j] = uints.U8{Val: api.Mul(inData, p.Nodes[i][j].Val)}
+		}
+		for j := len(p.Nodes[i]); j < len(data); j++ {
+			data[j] = uints.NewU8(0)
+		}
+		dec := rlp.NewDecoder(api, data)
+		node := dec.Decode(0)
+		api.AssertIsEqual(api.Mul(active, api.Sub(1, node.IsList)), 0)
+		api.AssertIsEqual(api.Mul(active, api.Sub(node.End, p.Lengths[i])), 0)
+		items := dec.Items(node, 17)
+		// extension and leaf nodes have two items and branch nodes have 17.
+		isShort := api.IsZero(api.Sub(items[1].End, node.End))
+		isBranch := api.Sub(1, isShort)
+		api.AssertIsEqual(api.Mul(active, isBranch, api.Sub(items[16].End, node.End)), 0)
+
+		// the hex-prefix encoded path of an extension or leaf node. The high
+		// nibble of the first byte is the flag with the parity of the path
+		// length as the lowest bit and the leaf flag as the second bit.
+		short := api.Mul(active, isShort)
+		pathItem := items[0]
+		api.AssertIsEqual(api.Mul(short, pathItem.IsList), 0)
+		comparator.AssertIsLessEq(api.Mul(short, pathItem.Length), maxPathBytes)
+		pathBytes := dec.Bytes(pathItem.Offset, maxPathBytes)
+		flagBits := api.ToBinary(pathBytes[0].Val, 8)
+		isOdd := flagBits[4]
+		isLeaf := api.Mul(isShort, flagBits[5])
+		isExt := api.Sub(isShort, isLeaf)
+		api.AssertIsEqual(api.Mul(short, flagBits[6]), 0)
+		api.AssertIsEqual(api.Mul(short, flagBits[7]), 0)
+		pathNibbles := make([]frontend.Variable, 2*(maxPathBytes-1))
+		for j := 1; j < maxPathBytes; j++ {
+			bits := api.ToBinary(pathBytes[j].Val, 8)
+			pathNibbles[2*j-2] = api.FromBinary(bits[4:]...)
+			pathNibbles[2*j-1] = api.FromBinary(bits[:4]...)
+		}
+		pathLen := api.Add(api.Mul(2, api.Sub(pathItem.Length, 1)), isOdd)
+
+		inds := make([]frontend.Variable, maxPathNibbles)
+		for j := range inds {
+			inds[j] = api.Add(k, j)
+		}
+		keyNibs := keyTable.Lookup(inds...)
+		var inPath frontend.Variable = 1
+		for j := 0; j < maxPathNibbles; j++ {
+			inPath = api.Sub(inPath, api.IsZero(api.Sub(pathLen, j)))
+			// for odd paths the first nibble is the low nibble of the flag
+			// byte.
+			var odd, even frontend.Variable = 0, 0
+			if j == 0 {
+				odd = api.FromBinary(flagBits[:4]...)
+			} else {
+				odd = pathNibbles[j-1]
+			}
+			if j < len(pathNibbles) {
+				even = pathNibbles[j]
+			}
+			nib := api.Select(isOdd, odd, even)
+			api.AssertIsEqual(api.Mul(short, inPath, api.Sub(nib, keyNibs[j])), 0)
+		}
+		// the path length must be at most maxPathNibbles (and non-negative).
+		inPath = api.Sub(inPath, api.IsZero(api.Sub(pathLen, maxPathNibbles)))
+		api.AssertIsEqual(api.Mul(short, inPath), 0)
+
+		// leaf nodes are last and extension nodes are not. A branch node may
+		// be last if the key ends in it.
+		api.AssertIsEqual(api.Mul(active, isLeaf, api.Sub(1, isLast)), 0)
+		api.AssertIsEqual(api.Mul(isExt, isLast), 0)
+
+		// the reference to the next node is the item given by the key nibble
+		// for a branch node or the second item for an extension node.
+		offsets := make([]frontend.Variable, 16)
+		lengths := make([]frontend.Variable, 16)
+		lists := make([]frontend.Variable, 16)
+		for j := range offsets {
+			offsets[j] = items[j].Offset
+			lengths[j] = items[j].Length
+			lists[j] = items[j].IsList
+		}
+		branchNib := keyNibs[0]
+		childOffset := api.Select(isBranch, selector.Mux(api, branchNib, offsets...), items[1].Offset)
+		childLen := api.Select(isBranch, selector.Mux(api, branchNib, lengths...), items[1].Length)
+		childIsList := api.Select(isBranch, selector.Mux(api, branchNib, lists...), items[1].IsList)
+		api.AssertIsEqual(api.Mul(notLast, api.Sub(childLen, 32)), 0)
+		api.AssertIsEqual(api.Mul(notLast, childIsList), 0)
+		childRef := dec.Bytes(childOffset, 32)
+		for j := range ref {
+			ref[j] = childRef[j].Val
+		}
+
+		// the value is the last item of a branch node or the second item of a
+		// leaf node.
+		valOffset := api.Select(isBranch, items[16].Offset, items[1].Offset)
+		valLen := api.Select(isBranch, items[16].Length, items[1].Length)
+		valIsList := api.Select(isBranch, items[16].IsList, items[1].IsList)
+		api.AssertIsEqual(api.Mul(isLast, valIsList), 0)
+		api.AssertIsEqual(api.Mul(isLast, api.IsZero(valLen)), 0)
+		comparator.AssertIsLessEq(api.Mul(isLast, valLen), maxValueLen)
+		valBytes := dec.Bytes(valOffset, maxValueLen)
+		var inVal frontend.Variable = 1
+		for j := range value {
+			inVal = api.Sub(inVal, api.IsZero(api.Sub(valLen, j)))
+			value[j].Val = api.Select(isLast, api.Mul(inVal, valBytes[j].Val), value[j].Val)
+		}
+		valueLen = api.Select(isLast, valLen, valueLen)
+
+		// all nibbles of the key must be consumed at the last node.
+		kEnd := api.Select(isBranch, k, api.Add(k, pathLen))
+		api.AssertIsEqual(api.Mul(isLast, api.Sub(kEnd, keyNibbles)), 0)
+		k = api.Add(k, api.Mul(notLast, api.Select(isBranch, 1, pathLen)))
+	}
+	// the depth must be at most the number of nodes.
+	active = api.Sub(active, api.IsZero(api.Sub(p.Depth, len(p.Nodes))))
+	api.AssertIsEqual(active, 0)
+	return value, valueLen, nil
+}
@@ -0,0 +1,275 @@
+package mpt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/sha3"
+)
+
+// minimal Merkle-Patricia trie implementation for generating the test
+// vectors.
+
+func keccak(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func encodeLength(length int, offset byte) []byte {
+	if length <= 55 {
+		return []byte{offset + byte(length)}
+	}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(length))
+	lenBytes := bytes.TrimLeft(buf[:], "\x00")
+	return append([]byte{offset + 55 + byte(len(lenBytes))}, lenBytes...)
+}
+
+func encodeString(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return []byte{b[0]}
+	}
+	return append(encodeLength(len(b), 0x80), b...)
+}
+
+func encodeList(items ...[]byte) []byte {
+	payload := bytes.Join(items, nil)
+	return append(encodeLength(len(payload), 0xc0), payload...)
+}
+
+func toNibbles(key []byte) []byte {
+	res := make([]byte, 2*len(key))
+	for i := range key {
+		res[2*i] = key[i] >> 4
+		res[2*i+1] = key[i] & 0xf
+	}
+	return res
+}
+
+func hexPrefix(nibbles []byte, leaf bool) []byte {
+	flag := byte(0)
+	if leaf {
+		flag = 2
+	}
+	if len(nibbles)%2 == 1 {
+		flag |= 1
+		nibbles = append([]byte{flag}, nibbles...)
+	} else {
+		nibbles = append([]byte{flag, 0}, nibbles...)
+	}
+	res := make([]byte, len(nibbles)/2)
+	for i := range res {
+		res[i] = nibbles[2*i]<<4 | nibbles[2*i+1]
+	}
+	return res
+}
+
+type entry struct {
+	path  []byte
+	value []byte
+}
+
+// reference returns the reference to the node in its parent.
+func reference(enc []byte) []byte {
+	if len(enc) < 32 {
+		return enc
+	}
+	return encodeString(keccak(enc))
+}
+
+// build returns the encoding of the node of the entries. If target is not nil,
+// then the encodings of the nodes on the path of target are appended to proof.
+func build(entries []entry, target []byte, proof *[][]byte) []byte {
+	var enc []byte
+	idx := -1
+	if target != nil {
+		idx = len(*proof)
+		*proof = append(*proof, nil)
+	}
+	if len(entries) == 1 {
+		enc = encodeList(encodeString(hexPrefix(entries[0].path, true)), encodeString(entries[0].value))
+	} else {
+		prefix := entries[0].path
+		for _, e := range entries[1:] {
+			n := 0
+			for n < len(prefix) && n < len(e.path) && prefix[n] == e.path[n] {
+				n++
+			}
+			prefix = prefix[:n]
+		}
+		if len(prefix) > 0 {
+			children := make([]entry, len(entries))
+			for i, e := range entries {
+				children[i] = entry{e.path[len(prefix):], e.value}
+			}
+			var childTarget []byte
+			if target != nil && bytes.HasPrefix(target, prefix) {
+				childTarget = target[len(prefix):]
+			}
+			child := build(children, childTarget, proof)
+			enc = encodeList(encodeString(hexPrefix(prefix, false)), reference(child))
+		} else {
+			items := make([][]byte, 17)
+			items[16] = encodeString(nil)
+			for nib := byte(0); nib < 16; nib++ {
+				var children []entry
+				for _, e := range entries {
+					if len(e.path) == 0 {
+						items[16] = encodeString(e.value)
+					} else if e.path[0] == nib {
+						children = append(children, entry{e.path[1:], e.value})
+					}
+				}
+				if len(children) == 0 {
+					items[nib] = encodeString(nil)
+					continue
+				}
+				var childTarget []byte
+				if target != nil && len(target) > 0 && target[0] == nib {
+					childTarget = target[1:]
+				}
+				items[nib] = reference(build(children, childTarget, proof))
+			}
+			enc = encodeList(items...)
+		}
+	}
+	if idx >= 0 {
+		(*proof)[idx] = enc
+	}
+	return enc
+}
+
+// buildTrie returns the root of the trie of the key-value pairs and the proof
+// of the key.
+func buildTrie(kvs map[string][]byte, key []byte) (root []byte, proof [][]byte) {
+	entries := make([]entry, 0, len(kvs))
+	for k, v := range kvs {
+		entries = append(entries, entry{toNibbles([]byte(k)), v})
+	}
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].path, entries[j].path) < 0 })
+	enc := build(entries, toNibbles(key), &proof)
+	return keccak(enc), proof
+}
+
+func TestTrieVector(t *testing.T) {
+	assert := test.NewAssert(t)
+	root, _ := buildTrie(map[string][]byte{
+		"doe":          []byte("reindeer"),
+		"dog":          []byte("puppy"),
+		"dogglesworth": []byte("cat"),
+	}, []byte("dog"))
+	expected, err := hex.DecodeString("8aad789dff2f538bca5d8ea56e8abe10f4c7ba3a5dea95fea4cd6e7c3a1168d3")
+	assert.NoError(err)
+	assert.Equal(expected, root)
+}
+
+type mptCircuit struct {
+	Root        [32]uints.U8
+	Key         [32]uints.U8
+	Proof       Proof
+	Value       []uints.U8
+	ValueLength frontend.Variable
+}
+
+func (c *mptCircuit) Define(api frontend.API) error {
+	value, length, err := c.Proof.VerifyProof(api, c.Root[:], c.Key[:], len(c.Value))
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(length, c.ValueLength)
+	for i := range value {
+		api.AssertIsEqual(value[i].Val, c.Value[i].Val)
+	}
+	return nil
+}
+
+func mustDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestVerifyProof(t *testing.T) {
+	const (
+		maxDepth    = 6
+		maxNodeLen  = 300
+		maxValueLen = 40
+	)
+	assert := test.NewAssert(t)
+	// the keys are chosen so that the proofs contain branch, extension and
+	// leaf nodes with paths of odd and even lengths.
+	keys := [][]byte{
+		mustDecode("112233aa00000000000000000000000000000000000000000000000000000001"),
+		mustDecode("112234bb00000000000000000000000000000000000000000000000000000002"),
+		mustDecode("112233cc00000000000000000000000000000000000000000000000000000003"),
+		mustDecode("ff00000000000000000000000000000000000000000000000000000000000004"),
+	}
+	for i := 0; i < 8; i++ {
+		keys = append(keys, keccak([]byte{byte(i)}))
+	}
+	kvs := make(map[string][]byte)
+	for i, k := range keys {
+		kvs[string(k)] = bytes.Repeat([]byte{byte(i + 1)}, 3+3*i)
+	}
+	placeholder := func() *mptCircuit {
+		return &mptCircuit{
+			Proof: PlaceholderProof(maxDepth, maxNodeLen),
+			Value: make([]uints.U8, maxValueLen),
+		}
+	}
+	assignment := func(root []byte, key []byte, nodes [][]byte, value []byte) *mptCircuit {
+		p, err := ValueOfProof(nodes, maxDepth, maxNodeLen)
+		assert.NoError(err)
+		c := &mptCircuit{
+			Proof:       p,
+			Value:       uints.NewU8Array(append(append([]byte{}, value...), make([]byte, maxValueLen-len(value))...)),
+			ValueLength: len(value),
+		}
+		copy(c.Root[:], uints.NewU8Array(root))
+		copy(c.Key[:], uints.NewU8Array(key))
+		return c
+	}
+	for i, key := range []int{0, 1, 2, 3, 4} {
+		key := keys[key]
+		assert.Run(func(assert *test.Assert) {
+			root, nodes := buildTrie(kvs, key)
+			err := test.IsSolved(placeholder(), assignment(root, key, nodes, kvs[string(key)]), ecc.BN254.ScalarField())
+			assert.NoError(err)
+		}, fmt.Sprintf("key=%d", i))
+	}
+	assert.Run(func(assert *test.Assert) {
+		root, nodes := buildTrie(kvs, keys[0])
+		value := append([]byte{}, kvs[string(keys[0])]...)
+		value[0] ^= 1
+		err := test.IsSolved(placeholder(), assignment(root, keys[0], nodes, value), ecc.BN254.ScalarField())
+		assert.Error(err)
+	}, "wrong value")
+	assert.Run(func(assert *test.Assert) {
+		root, nodes := buildTrie(kvs, keys[0])
+		err := test.IsSolved(placeholder(), assignment(root, keys[2], nodes, kvs[string(keys[0])]), ecc.BN254.ScalarField())
+		assert.Error(err)
+	}, "wrong key")
+	assert.Run(func(assert *test.Assert) {
+		root, nodes := buildTrie(kvs, keys[0])
+		nodes[len(nodes)-1] = append([]byte{}, nodes[len(nodes)-1]...)
+		nodes[len(nodes)-1][len(nodes[len(nodes)-1])-1] ^= 1
+		err := test.IsSolved(placeholder(), assignment(root, keys[0], nodes, kvs[string(keys[0])]), ecc.BN254.ScalarField())
+		assert.Error(err)
+	}, "wrong node")
+	assert.Run(func(assert *test.Assert) {
+		root, nodes := buildTrie(kvs, keys[0])
+		err := test.IsSolved(placeholder(), assignment(root, keys[0], nodes[:len(nodes)-1], kvs[string(keys[0])]), ecc.BN254.ScalarField())
+		assert.Error(err)
+	}, "truncated proof")
+}
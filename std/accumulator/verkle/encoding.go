@@ -0,0 +1,177 @@
+package verkle
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+)
+
+const (
+	// DomainSize is the number of elements in the committed vectors.
+	DomainSize = 256
+	// NbRounds is the number of rounds of the IPA opening argument.
+	NbRounds = 8
+
+	// crsSeed is the seed for deriving the basis points of the Pedersen
+	// vector commitment.
+	crsSeed = "eth_verkle_oct_2021"
+)
+
+var (
+	basisOnce sync.Once
+	basis     []bandersnatch.PointAffine
+)
+
+// basisPoints returns the basis points of the Pedersen vector commitment. The
+// points are derived from the SHA-256 hashes of the seed and an incrementing
+// big-endian 64-bit counter. The hashes which are not the encodings of
+// Banderwagon elements are skipped.
+func basisPoints() []bandersnatch.PointAffine {
+	basisOnce.Do(func() {
+		basis = make([]bandersnatch.PointAffine, 0, DomainSize)
+		var buf [8]byte
+		for inc := uint64(0); len(basis) < DomainSize; inc++ {
+			h := sha256.New()
+			h.Write([]byte(crsSeed))
+			binary.BigEndian.PutUint64(buf[:], inc)
+			h.Write(buf[:])
+			var x fr.Element
+			x.SetBytes(h.Sum(nil))
+			p, err := pointFromX(&x)
+			if err != nil {
+				continue
+			}
+			basis = append(basis, p)
+		}
+	})
+	return basis
+}
+
+// pointFromX returns the Banderwagon element with the x coordinate. The y
+// coordinate is chosen lexicographically largest. It returns an error if x is
+// not the coordinate of a point on the curve or if the point is not in the
+// Banderwagon subgroup.
+func pointFromX(x *fr.Element) (bandersnatch.PointAffine, error) {
+	curve := bandersnatch.GetEdwardsCurve()
+	var one, num, den, y fr.Element
+	one.SetOne()
+	// y² = (ax² - 1) / (dx² - 1)
+	num.Square(x)
+	den.Mul(&num, &curve.D).Sub(&den, &one)
+	num.Mul(&num, &curve.A).Sub(&num, &one)
+	y.Div(&num, &den)
+	if y.Sqrt(&y) == nil {
+		return bandersnatch.PointAffine{}, errors.New("point is not on the curve")
+	}
+	if !y.LexicographicallyLargest() {
+		y.Neg(&y)
+	}
+	// the point is in the subgroup if 1 - ax² is a square
+	var t fr.Element
+	t.Square(x).Mul(&t, &curve.A)
+	t.Sub(&one, &t)
+	if t.Legendre() != 1 {
+		return bandersnatch.PointAffine{}, errors.New("point is not in the subgroup")
+	}
+	return bandersnatch.PointAffine{X: *x, Y: y}, nil
+}
+
+// decodePoint returns the Banderwagon element of the compressed serialization.
+func decodePoint(b []byte) (bandersnatch.PointAffine, error) {
+	if len(b) != 32 {
+		return bandersnatch.PointAffine{}, fmt.Errorf("invalid compressed point size %d", len(b))
+	}
+	var x fr.Element
+	if err := x.SetBytesCanonical(b); err != nil {
+		return bandersnatch.PointAffine{}, fmt.Errorf("invalid compressed point: %w", err)
+	}
+	return pointFromX(&x)
+}
+
+// encodePoint returns the compressed serialization of the Banderwagon element,
+// which is the x coordinate multiplied by the sign of the y coordinate in
+// big-endian form.
+func encodePoint(p *bandersnatch.PointAffine) [32]byte {
+	x := p.X
+	if !p.Y.LexicographicallyLargest() {
+		x.Neg(&x)
+	}
+	return x.Bytes()
+}
+
+// ValueOfPoint returns the assignment of the compressed serialization of a
+// Banderwagon element.
+func ValueOfPoint(b []byte) (twistededwards.Point, error) {
+	p, err := decodePoint(b)
+	if err != nil {
+		return twistededwards.Point{}, err
+	}
+	return twistededwards.Point{X: p.X.String(), Y: p.Y.String()}, nil
+}
+
+// ValueOfScalar returns the assignment of the 32-byte little-endian
+// serialization of a scalar. The scalar must be reduced.
+func ValueOfScalar(b []byte) (Scalar, error) {
+	if len(b) != 32 {
+		return Scalar{}, fmt.Errorf("invalid scalar size %d", len(b))
+	}
+	le := make([]byte, 32)
+	for i := range b {
+		le[31-i] = b[i]
+	}
+	s := new(big.Int).SetBytes(le)
+	if s.Cmp(emparams.BandersnatchFr{}.Modulus()) >= 0 {
+		return Scalar{}, errors.New("scalar is not reduced")
+	}
+	return emulated.ValueOf[emparams.BandersnatchFr](s), nil
+}
+
+// ValueOfIPAProof returns the assignment of the serialized IPA proof. The
+// serialization is the compressed points L, then the compressed points R and
+// then the little-endian scalar A.
+func ValueOfIPAProof(b []byte) (IPAProof, error) {
+	if len(b) != (2*NbRounds+1)*32 {
+		return IPAProof{}, fmt.Errorf("invalid IPA proof size %d", len(b))
+	}
+	var proof IPAProof
+	var err error
+	for i := 0; i < NbRounds; i++ {
+		if proof.L[i], err = ValueOfPoint(b[i*32 : (i+1)*32]); err != nil {
+			return IPAProof{}, fmt.Errorf("L[%d]: %w", i, err)
+		}
+		if proof.R[i], err = ValueOfPoint(b[(NbRounds+i)*32 : (NbRounds+i+1)*32]); err != nil {
+			return IPAProof{}, fmt.Errorf("R[%d]: %w", i, err)
+		}
+	}
+	if proof.A, err = ValueOfScalar(b[2*NbRounds*32:]); err != nil {
+		return IPAProof{}, fmt.Errorf("A: %w", err)
+	}
+	return proof, nil
+}
+
+// ValueOfMultiProof returns the assignment of the serialized multiproof. The
+// serialization is the compressed point D followed by the serialized IPA
+// proof.
+func ValueOfMultiProof(b []byte) (MultiProof, error) {
+	if len(b) < 32 {
+		return MultiProof{}, fmt.Errorf("invalid multiproof size %d", len(b))
+	}
+	d, err := ValueOfPoint(b[:32])
+	if err != nil {
+		return MultiProof{}, fmt.Errorf("D: %w", err)
+	}
+	ipa, err := ValueOfIPAProof(b[32:])
+	if err != nil {
+		return MultiProof{}, err
+	}
+	return MultiProof{D: d, IPA: ipa}, nil
+}
@@ -0,0 +1,31 @@
+package verkle
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hints used in the package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		sqrtHint,
+	}
+}
+
+// sqrtHint returns a square root of the input in the native field. It returns
+// an error if the input is not a square.
+func sqrtHint(mod *big.Int, inputs, outputs []*big.Int) error {
+	if len(inputs) != 1 || len(outputs) != 1 {
+		return errors.New("expecting one input and one output")
+	}
+	if outputs[0].ModSqrt(inputs[0], mod) == nil {
+		return errors.New("input is not a square")
+	}
+	return nil
+}
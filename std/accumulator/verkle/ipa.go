@@ -0,0 +1,104 @@
+package verkle
+
+import (
+	"fmt"
+	"math/big"
+
+	edwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/commitments/ipa"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+)
+
+// VerifyIPA asserts that the IPA proof is a valid opening of the commitment at
+// the point z to the value y. The polynomial is committed in evaluation form
+// over the domain [0, DomainSize) and the point z must be outside of the
+// domain. The value y must be in canonical form.
+func (v *Verifier) VerifyIPA(commitment edwards.Point, z, y *Scalar, proof *IPAProof) error {
+	v.assertIsValid(commitment)
+	t := v.newTranscript()
+	return v.verifyIPA(t, commitment, v.canonical(z), v.canonical(y), proof)
+}
+
+// verifyIPA verifies the IPA proof using the transcript. The scalars z and y
+// must be in canonical form. The challenges are derived with the transcript of
+// the Verkle proofs and the opening is checked with the generic IPA verifier,
+// using the fixed basis points and the evaluation form.
+func (v *Verifier) verifyIPA(t *transcript, commitment edwards.Point, z, y *Scalar, proof *IPAProof) error {
+	f := v.fr
+	t.domainSep("ipa")
+	t.appendPoint("C", commitment)
+	t.appendScalar("input point", z)
+	t.appendScalar("output point", y)
+	w, err := t.challenge("w")
+	if err != nil {
+		return fmt.Errorf("challenge w: %w", err)
+	}
+	xs := make([]*Scalar, NbRounds)
+	for i := range xs {
+		v.assertIsValid(proof.L[i])
+		v.assertIsValid(proof.R[i])
+		t.appendPoint("L", proof.L[i])
+		t.appendPoint("R", proof.R[i])
+		if xs[i], err = t.challenge("x"); err != nil {
+			return fmt.Errorf("challenge x: %w", err)
+		}
+	}
+	folding := v.ipa.NewFolding(w, xs)
+	g0 := v.fixedBaseMSM(folding.S)
+
+	// the evaluation of the folded barycentric weights at z:
+	//   b0 = A(z) Σ sᵢ / (A'(i) (z - i)),
+	// where A(X) = Π (X - i) is the vanishing polynomial of the domain.
+	weights := domainWeights()
+	var az, b0 *Scalar
+	for i := 0; i < DomainSize; i++ {
+		d := f.Sub(z, f.NewElement(i))
+		if i == 0 {
+			az = d
+		} else {
+			az = f.Mul(az, d)
+		}
+		term := f.Div(f.Mul(folding.S[i], f.NewElement(weights[i])), d)
+		if i == 0 {
+			b0 = term
+		} else {
+			b0 = f.Add(b0, term)
+		}
+	}
+	b0 = f.Mul(az, b0)
+
+	// the inner product base is the generator of the curve.
+	params := v.curve.Params()
+	u := edwards.Point{X: params.Base[0], Y: params.Base[1]}
+	opening := ipa.OpeningProof[emparams.BandersnatchFr, edwards.Point]{
+		L:            proof.L[:],
+		R:            proof.R[:],
+		A:            proof.A,
+		ClaimedValue: *y,
+	}
+	got, err := v.ipa.FoldedCommitment(opening, &u, folding, &g0, b0)
+	if err != nil {
+		return fmt.Errorf("folded commitment: %w", err)
+	}
+	v.AssertIsEqual(*got, commitment)
+	return nil
+}
+
+// domainWeights returns the inverses of the derivative of the vanishing
+// polynomial of the domain at the domain points, 1 / Π_{j≠i} (i - j), modulo
+// the scalar field order.
+func domainWeights() []*big.Int {
+	r := emparams.BandersnatchFr{}.Modulus()
+	res := make([]*big.Int, DomainSize)
+	for i := range res {
+		w := big.NewInt(1)
+		for j := 0; j < DomainSize; j++ {
+			if i != j {
+				w.Mul(w, big.NewInt(int64(i-j)))
+				w.Mod(w, r)
+			}
+		}
+		res[i] = w.ModInverse(w, r)
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+package verkle
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	edwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+)
+
+// nbWindows is the number of 2-bit windows of the scalars in the fixed-base
+// multi-scalar multiplication.
+const nbWindows = 127
+
+var (
+	tablesOnce sync.Once
+	tables     [][nbWindows][2][4]*big.Int
+)
+
+// basisTables returns the precomputed multiples of the basis points. For the
+// basis point G, the window j contains the coordinates of the points
+// k*4ʲ*G for k in [0, 4).
+func basisTables() [][nbWindows][2][4]*big.Int {
+	tablesOnce.Do(func() {
+		g := basisPoints()
+		tables = make([][nbWindows][2][4]*big.Int, len(g))
+		for i := range g {
+			p := g[i]
+			for j := 0; j < nbWindows; j++ {
+				var q [4]bandersnatch.PointAffine
+				q[0].Y.SetOne()
+				q[1] = p
+				q[2].Double(&p)
+				q[3].Add(&q[2], &p)
+				for k := range q {
+					tables[i][j][0][k] = q[k].X.BigInt(new(big.Int))
+					tables[i][j][1][k] = q[k].Y.BigInt(new(big.Int))
+				}
+				p.Double(&q[2])
+			}
+		}
+	})
+	return tables
+}
+
+// fixedBaseMSM returns the multi-scalar multiplication of the basis points by
+// the scalars. The scalars are decomposed in 2-bit windows and the multiples of
+// the basis points are selected from the precomputed tables.
+func (v *Verifier) fixedBaseMSM(scalars []*Scalar) edwards.Point {
+	api := v.api
+	tbl := basisTables()
+	var res edwards.Point
+	for i := range scalars {
+		b := v.fr.ToBits(v.canonical(scalars[i]))
+		for j := 0; j < nbWindows; j++ {
+			t := tbl[i][j]
+			q := edwards.Point{
+				X: api.Lookup2(b[2*j], b[2*j+1], t[0][0], t[0][1], t[0][2], t[0][3]),
+				Y: api.Lookup2(b[2*j], b[2*j+1], t[1][0], t[1][1], t[1][2], t[1][3]),
+			}
+			if i == 0 && j == 0 {
+				res = q
+			} else {
+				res = v.curve.Add(res, q)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,76 @@
+package verkle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	edwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// VerifyMultiProof asserts that the multiproof is a valid opening of the
+// commitments at the points zs to the values ys. The points are the indices in
+// the domain [0, DomainSize) of the opened elements and the values must be in
+// canonical form.
+//
+// The openings are aggregated into the opening of a single commitment at a
+// random point t outside of the domain, which is verified with the IPA proof
+// in the multiproof.
+func (v *Verifier) VerifyMultiProof(commitments []edwards.Point, zs []frontend.Variable, ys []*Scalar, proof *MultiProof) error {
+	if len(commitments) == 0 {
+		return errors.New("no openings")
+	}
+	if len(commitments) != len(zs) || len(commitments) != len(ys) {
+		return fmt.Errorf("mismatching number of commitments %d, points %d and values %d", len(commitments), len(zs), len(ys))
+	}
+	api := v.api
+	f := v.fr
+	t := v.newTranscript()
+	t.domainSep("multiproof")
+	z := make([]*Scalar, len(zs))
+	y := make([]*Scalar, len(ys))
+	for i := range commitments {
+		v.assertIsValid(commitments[i])
+		zb := bits.ToBinary(api, zs[i], bits.WithNbDigits(8))
+		z[i] = f.FromBits(zb...)
+		y[i] = v.canonical(ys[i])
+		t.appendPoint("C", commitments[i])
+		t.appendScalar("z", z[i])
+		t.appendScalar("y", y[i])
+	}
+	r, err := t.challenge("r")
+	if err != nil {
+		return fmt.Errorf("challenge r: %w", err)
+	}
+	v.assertIsValid(proof.D)
+	t.appendPoint("D", proof.D)
+	tc, err := t.challenge("t")
+	if err != nil {
+		return fmt.Errorf("challenge t: %w", err)
+	}
+
+	// g₂(t) = Σ rⁱ yᵢ / (t - zᵢ) and E = Σ rⁱ / (t - zᵢ) Cᵢ
+	var g2t *Scalar
+	var e edwards.Point
+	ri := f.One()
+	for i := range commitments {
+		if i > 0 {
+			ri = f.Mul(ri, r)
+		}
+		c := v.canonical(f.Div(ri, f.Sub(tc, z[i])))
+		ce := v.curve.ScalarMul(commitments[i], v.toNative(c))
+		if i == 0 {
+			g2t = f.Mul(c, y[i])
+			e = ce
+		} else {
+			g2t = f.Add(g2t, f.Mul(c, y[i]))
+			e = v.curve.Add(e, ce)
+		}
+	}
+	t.appendPoint("E", e)
+	if err := v.verifyIPA(t, v.curve.Add(e, v.curve.Neg(proof.D)), tc, v.canonical(g2t), &proof.IPA); err != nil {
+		return fmt.Errorf("verify IPA: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package verkle
+
+import "errors"
+
+type config struct {
+	label string
+}
+
+// Option allows to modify the behaviour of the [Verifier].
+type Option func(cfg *config) error
+
+// WithTranscriptLabel sets the label used for initializing the Fiat-Shamir
+// transcript. If not set, then the label "vt" of the Verkle trie is used.
+func WithTranscriptLabel(label string) Option {
+	return func(cfg *config) error {
+		if label == "" {
+			return errors.New("empty transcript label")
+		}
+		cfg.label = label
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+package verkle
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// transcript is the Fiat-Shamir transcript of the Verkle proofs. The messages
+// are buffered together with their labels. When deriving a challenge, the
+// buffer is hashed with SHA-256 and the challenge is the little-endian hash
+// reduced modulo the scalar field order. The buffer is then reset to the
+// serialized challenge. The first hash additionally includes the label of the
+// transcript.
+type transcript struct {
+	v      *Verifier
+	prefix []uints.U8
+	buf    []uints.U8
+}
+
+func (v *Verifier) newTranscript() *transcript {
+	return &transcript{v: v, prefix: uints.NewU8Array([]byte(v.label))}
+}
+
+func (t *transcript) domainSep(label string) {
+	t.buf = append(t.buf, uints.NewU8Array([]byte(label))...)
+}
+
+func (t *transcript) appendMessage(label string, msg []uints.U8) {
+	t.domainSep(label)
+	t.buf = append(t.buf, msg...)
+}
+
+func (t *transcript) appendPoint(label string, p twistededwards.Point) {
+	t.appendMessage(label, t.v.serialize(p))
+}
+
+// appendScalar appends the scalar to the transcript. The scalar must be in
+// canonical form.
+func (t *transcript) appendScalar(label string, c *Scalar) {
+	t.appendMessage(label, t.v.scalarBytes(c))
+}
+
+// challenge returns the challenge derived from the transcript. The challenge
+// is in canonical form.
+func (t *transcript) challenge(label string) (*Scalar, error) {
+	api := t.v.api
+	t.domainSep(label)
+	h, err := sha2.New(api)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(t.prefix)
+	h.Write(t.buf)
+	digest := h.Sum()
+	t.prefix = nil
+	t.buf = nil
+
+	b := make([]frontend.Variable, 0, 8*len(digest))
+	for i := range digest {
+		b = append(b, bits.ToBinary(api, digest[i].Val, bits.WithNbDigits(8))...)
+	}
+	c := t.v.canonical(t.v.fr.Mul(t.v.fr.FromBits(b...), t.v.fr.One()))
+	t.appendScalar(label, c)
+	return c, nil
+}
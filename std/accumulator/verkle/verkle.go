@@ -0,0 +1,174 @@
+// Package verkle implements the verification of the Verkle trie proofs used in
+// Ethereum.
+//
+// The nodes of a Verkle trie are committed with Pedersen vector commitments of
+// [DomainSize] elements over the Banderwagon group, the prime order quotient
+// of the Bandersnatch curve. The commitments are opened with an inner product
+// argument (IPA) over the evaluation form of the committed polynomials, and
+// several openings are aggregated into a single IPA proof with the multipoint
+// scheme. The proofs, the Fiat-Shamir transcript and the basis points of the
+// commitments follow the Verkle trie specification and its reference
+// implementation [go-ipa].
+//
+// The circuit has to be defined over the BLS12-381 scalar field, so that the
+// Bandersnatch points are native. The scalars of the commitments are elements
+// of the Bandersnatch scalar field and are emulated. The Banderwagon elements
+// are represented by any of the two Bandersnatch points of their class. The
+// points provided as witness are checked to be on the curve and in the
+// subgroup of the Banderwagon elements.
+//
+// [go-ipa]: https://github.com/crate-crypto/go-ipa
+package verkle
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	edwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/commitments/ipa"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Scalar is an element of the Bandersnatch scalar field.
+type Scalar = emulated.Element[emparams.BandersnatchFr]
+
+// IPAProof is a proof of the inner product argument for opening a commitment
+// at a single point.
+type IPAProof struct {
+	L, R [NbRounds]edwards.Point
+	A    Scalar
+}
+
+// MultiProof is a proof for opening several commitments at points of the
+// domain.
+type MultiProof struct {
+	D   edwards.Point
+	IPA IPAProof
+}
+
+// Verifier verifies Verkle proofs in-circuit.
+type Verifier struct {
+	api   frontend.API
+	curve edwards.Curve
+	fr    *emulated.Field[emparams.BandersnatchFr]
+	ipa   *ipa.Verifier[emparams.BandersnatchFr, edwards.Point]
+	label string
+}
+
+// NewVerifier returns a new verifier. The native field of the circuit must be
+// the BLS12-381 scalar field.
+func NewVerifier(api frontend.API, opts ...Option) (*Verifier, error) {
+	cfg := config{label: "vt"}
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			return nil, fmt.Errorf("apply option: %w", err)
+		}
+	}
+	if api.Compiler().Field().Cmp(ecc.BLS12_381.ScalarField()) != 0 {
+		return nil, fmt.Errorf("native field must be the BLS12-381 scalar field")
+	}
+	curve, err := edwards.NewEdCurve(api, twistededwards.BLS12_381_BANDERSNATCH)
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	fr, err := emulated.NewField[emparams.BandersnatchFr](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar field: %w", err)
+	}
+	iv, err := ipa.NewVerifier[emparams.BandersnatchFr, edwards.Point](api)
+	if err != nil {
+		return nil, fmt.Errorf("new IPA verifier: %w", err)
+	}
+	return &Verifier{api: api, curve: curve, fr: fr, ipa: iv, label: cfg.label}, nil
+}
+
+// Commit returns the Pedersen vector commitment of the values. The number of
+// values must be at most [DomainSize].
+func (v *Verifier) Commit(values []*Scalar) (edwards.Point, error) {
+	if len(values) == 0 || len(values) > DomainSize {
+		return edwards.Point{}, fmt.Errorf("invalid number of values %d", len(values))
+	}
+	return v.fixedBaseMSM(values), nil
+}
+
+// AssertIsEqual asserts that the points represent the same Banderwagon
+// element.
+func (v *Verifier) AssertIsEqual(p, q edwards.Point) {
+	v.api.AssertIsEqual(v.api.Mul(p.X, q.Y), v.api.Mul(q.X, p.Y))
+}
+
+// MapToScalarField returns the scalar of the commitment used as a value in
+// the parent node of the trie. It is the little-endian interpretation of x/y
+// reduced modulo the scalar field order. The result is in canonical form.
+func (v *Verifier) MapToScalarField(p edwards.Point) *Scalar {
+	q := v.api.Div(p.X, p.Y)
+	b := bits.ToBinary(v.api, q)
+	return v.canonical(v.fr.Mul(v.fr.FromBits(b...), v.fr.One()))
+}
+
+// assertIsValid asserts that the point is on the curve and represents a
+// Banderwagon element. The latter holds when 1-ax² is a square in the base
+// field.
+func (v *Verifier) assertIsValid(p edwards.Point) {
+	v.curve.AssertIsOnCurve(p)
+	t := v.api.Sub(1, v.api.Mul(v.curve.Params().A, p.X, p.X))
+	s, err := v.api.Compiler().NewHint(sqrtHint, 1, t)
+	if err != nil {
+		panic(err)
+	}
+	v.api.AssertIsEqual(v.api.Mul(s[0], s[0]), t)
+}
+
+// serialize returns the compressed serialization of the Banderwagon element.
+// It is the x coordinate of the representative with the lexicographically
+// largest y coordinate in big-endian form.
+func (v *Verifier) serialize(p edwards.Point) []uints.U8 {
+	api := v.api
+	half := new(big.Int).Rsh(api.Compiler().Field(), 1)
+	largest := api.IsZero(api.Sub(api.Cmp(p.Y, half), 1))
+	x := api.Select(largest, p.X, api.Neg(p.X))
+	xb := append(bits.ToBinary(api, x), 0)
+	res := make([]uints.U8, 32)
+	for i := range res {
+		res[31-i] = uints.U8{Val: bits.FromBinary(api, xb[8*i:8*i+8], bits.WithUnconstrainedInputs())}
+	}
+	return res
+}
+
+// canonical returns the element reduced modulo the scalar field order and
+// asserts that it is in canonical form.
+func (v *Verifier) canonical(s *Scalar) *Scalar {
+	c := v.fr.Reduce(s)
+	v.fr.AssertIsInRange(c)
+	return c
+}
+
+// scalarBytes returns the 32-byte little-endian serialization of the scalar in
+// canonical form.
+func (v *Verifier) scalarBytes(c *Scalar) []uints.U8 {
+	b := v.fr.ToBits(c)
+	for len(b) < 256 {
+		b = append(b, 0)
+	}
+	res := make([]uints.U8, 32)
+	for i := range res {
+		res[i] = uints.U8{Val: bits.FromBinary(v.api, b[8*i:8*i+8], bits.WithUnconstrainedInputs())}
+	}
+	return res
+}
+
+// toNative returns the native variable of the scalar in canonical form.
+func (v *Verifier) toNative(c *Scalar) frontend.Variable {
+	shift := new(big.Int).Lsh(big.NewInt(1), emparams.BandersnatchFr{}.BitsPerLimb())
+	var res frontend.Variable = 0
+	for i := len(c.Limbs) - 1; i >= 0; i-- {
+		res = v.api.Add(v.api.Mul(res, shift), c.Limbs[i])
+	}
+	return res
+}
@@ -0,0 +1,395 @@
+package verkle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/frontend"
+	edwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+// the test vectors are from the consistency tests of go-ipa.
+
+const (
+	ipaProofHex   = "273395a8febdaed38e94c3d874e99c911a47dd84616d54c55021d5c4131b507e46a4ec2c7e82b77ec2f533994c91ca7edaef212c666a1169b29c323eabb0cf690e0146638d0e2d543f81da4bd597bf3013e1663f340a8f87b845495598d0a3951590b6417f868edaeb3424ff174901d1185a53a3ee127fb7be0af42dda44bf992885bde279ef821a298087717ef3f2b78b2ede7f5d2ea1b60a4195de86a530eb247fd7e456012ae9a070c61635e55d1b7a340dfab8dae991d6273d099d9552815434cc1ba7bcdae341cf7928c6f25102370bdf4b26aad3af654d9dff4b3735661db3177342de5aad774a59d3e1b12754aee641d5f9cd1ecd2751471b308d2d8410add1c9fcc5a2b7371259f0538270832a98d18151f653efbc60895fab8be9650510449081626b5cd24671d1a3253487d44f589c2ff0da3557e307e520cf4e0054bbf8bdffaa24b7e4cce5092ccae5a08281ee24758374f4e65f126cacce64051905b5e2038060ad399c69ca6cb1d596d7c9cb5e161c7dcddc1a7ad62660dd4a5f69b31229b80e6b3df520714e4ea2b5896ebd48d14c7455e91c1ecf4acc5ffb36937c49413b7d1005dd6efbd526f5af5d61131ca3fcdae1218ce81c75e62b39100ec7f474b48a2bee6cef453fa1bc3db95c7c6575bc2d5927cbf7413181ac905766a4038a7b422a8ef2bf7b5059b5c546c19a33c1049482b9a9093f864913ca82290decf6e9a65bf3f66bc3ba4a8ed17b56d890a83bcbe74435a42499dec115"
+	multiProofHex = "4f53588244efaf07a370ee3f9c467f933eed360d4fbf7a19dfc8bc49b67df4711bf1d0a720717cd6a8c75f1a668cb7cbdd63b48c676b89a7aee4298e71bd7f4013d7657146aa9736817da47051ed6a45fc7b5a61d00eb23e5df82a7f285cc10e67d444e91618465ca68d8ae4f2c916d1942201b7e2aae491ef0f809867d00e83468fb7f9af9b42ede76c1e90d89dd789ff22eb09e8b1d062d8a58b6f88b3cbe80136fc68331178cd45a1df9496ded092d976911b5244b85bc3de41e844ec194256b39aeee4ea55538a36139211e9910ad6b7a74e75d45b869d0a67aa4bf600930a5f760dfb8e4df9938d1f47b743d71c78ba8585e3b80aba26d24b1f50b36fa1458e79d54c05f58049245392bc3e2b5c5f9a1b99d43ed112ca82b201fb143d401741713188e47f1d6682b0bf496a5d4182836121efff0fd3b030fc6bfb5e21d6314a200963fe75cb856d444a813426b2084dfdc49dca2e649cb9da8bcb47859a4c629e97898e3547c591e39764110a224150d579c33fb74fa5eb96427036899c04154feab5344873d36a53a5baefd78c132be419f3f3a8dd8f60f72eb78dd5f43c53226f5ceb68947da3e19a750d760fb31fa8d4c7f53bfef11c4b89158aa56b1f4395430e16a3128f88e234ce1df7ef865f2d2c4975e8c82225f578310c31fd41d265fd530cbfa2b8895b228a510b806c31dff3b1fa5c08bffad443d567ed0e628febdd22775776e0cc9cebcaea9c6df9279a5d91dd0ee5e7a0434e989a160005321c97026cb559f71db23360105460d959bcdf74bee22c4ad8805a1d497507"
+)
+
+func mustDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// testPolynomial returns the values [1, ..., 32] repeated 8 times, reversed if
+// rev is set.
+func testPolynomial(rev bool) []int64 {
+	res := make([]int64, DomainSize)
+	for i := range res {
+		res[i] = int64(i%32 + 1)
+		if rev {
+			res[i] = 32 - int64(i%32)
+		}
+	}
+	return res
+}
+
+func commit(values []int64) bandersnatch.PointAffine {
+	g := basisPoints()
+	var res, tmp bandersnatch.PointAffine
+	res.Y.SetOne()
+	for i := range values {
+		tmp.ScalarMultiplication(&g[i], big.NewInt(values[i]))
+		res.Add(&res, &tmp)
+	}
+	return res
+}
+
+func valueOfPoint(p bandersnatch.PointAffine) edwards.Point {
+	return edwards.Point{X: p.X.String(), Y: p.Y.String()}
+}
+
+func TestBasisPoints(t *testing.T) {
+	assert := test.NewAssert(t)
+	g := basisPoints()
+	assert.Equal(DomainSize, len(g))
+	first := encodePoint(&g[0])
+	assert.Equal("01587ad1336675eb912550ec2a28eb8923b824b490dd2ba82e48f14590a298a0", hex.EncodeToString(first[:]))
+	last := encodePoint(&g[DomainSize-1])
+	assert.Equal("3de2be346b539395b0c0de56a5ccca54a317f1b5c80107b0802af9a62276a4d8", hex.EncodeToString(last[:]))
+	h := sha256.New()
+	for i := range g {
+		b := encodePoint(&g[i])
+		h.Write(b[:])
+	}
+	assert.Equal("1fcaea10bf24f750200e06fa473c76ff0468007291fa548e2d99f09ba9256fdb", hex.EncodeToString(h.Sum(nil)))
+}
+
+type serializeCircuit struct {
+	P        edwards.Point
+	Expected [32]uints.U8
+}
+
+func (c *serializeCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api)
+	if err != nil {
+		return err
+	}
+	v.assertIsValid(c.P)
+	b := v.serialize(c.P)
+	for i := range b {
+		api.AssertIsEqual(b[i].Val, c.Expected[i].Val)
+	}
+	return nil
+}
+
+func TestSerialize(t *testing.T) {
+	assert := test.NewAssert(t)
+	expected := []string{
+		"4a2c7486fd924882bf02c6908de395122843e3e05264d7991e18e7985dad51e9",
+		"43aa74ef706605705989e8fd38df46873b7eae5921fbed115ac9d937399ce4d5",
+		"5e5f550494159f38aa54d2ed7f11a7e93e4968617990445cc93ac8e59808c126",
+	}
+	p := bandersnatch.GetEdwardsCurve().Base
+	for i := range expected {
+		enc := encodePoint(&p)
+		assert.Equal(expected[i], hex.EncodeToString(enc[:]))
+		// the point and its equivalent (-x, -y) have the same serialization.
+		var q bandersnatch.PointAffine
+		q.X.Neg(&p.X)
+		q.Y.Neg(&p.Y)
+		for _, r := range []bandersnatch.PointAffine{p, q} {
+			var assignment serializeCircuit
+			assignment.P = valueOfPoint(r)
+			copy(assignment.Expected[:], uints.NewU8Array(enc[:]))
+			assert.NoError(test.IsSolved(&serializeCircuit{}, &assignment, ecc.BLS12_381.ScalarField()))
+			assignment.Expected[31] = uints.NewU8(enc[31] ^ 1)
+			assert.Error(test.IsSolved(&serializeCircuit{}, &assignment, ecc.BLS12_381.ScalarField()))
+		}
+		p.Double(&p)
+	}
+}
+
+type subgroupCircuit struct {
+	P edwards.Point
+}
+
+func (c *subgroupCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api)
+	if err != nil {
+		return err
+	}
+	v.assertIsValid(c.P)
+	return nil
+}
+
+func TestSubgroupCheck(t *testing.T) {
+	assert := test.NewAssert(t)
+	p := basisPoints()[0]
+	assert.NoError(test.IsSolved(&subgroupCircuit{}, &subgroupCircuit{P: valueOfPoint(p)}, ecc.BLS12_381.ScalarField()))
+
+	// find a point on the curve which is not in the Banderwagon subgroup, i.e.
+	// where 1-ax² is not a square.
+	curve := bandersnatch.GetEdwardsCurve()
+	var x, y, num, den, one fr.Element
+	one.SetOne()
+	for x.SetUint64(2); ; x.Add(&x, &one) {
+		num.Square(&x)
+		den.Mul(&num, &curve.D).Sub(&den, &one)
+		num.Mul(&num, &curve.A).Sub(&num, &one)
+		y.Div(&num, &den)
+		if y.Sqrt(&y) == nil {
+			continue
+		}
+		if _, err := pointFromX(&x); err != nil {
+			break
+		}
+	}
+	q := bandersnatch.PointAffine{X: x, Y: y}
+	assert.True(q.IsOnCurve())
+	assert.Error(test.IsSolved(&subgroupCircuit{}, &subgroupCircuit{P: valueOfPoint(q)}, ecc.BLS12_381.ScalarField()))
+}
+
+type commitCircuit struct {
+	Values   []Scalar
+	Expected edwards.Point
+}
+
+func (c *commitCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api)
+	if err != nil {
+		return err
+	}
+	values := make([]*Scalar, len(c.Values))
+	for i := range values {
+		values[i] = &c.Values[i]
+	}
+	res, err := v.Commit(values)
+	if err != nil {
+		return err
+	}
+	v.AssertIsEqual(res, c.Expected)
+	return nil
+}
+
+func TestCommit(t *testing.T) {
+	assert := test.NewAssert(t)
+	poly := testPolynomial(false)
+	expected := commit(poly)
+	enc := encodePoint(&expected)
+	assert.Equal("1b9dff8f5ebbac250d291dfe90e36283a227c64b113c37f1bfb9e7a743cdb128", hex.EncodeToString(enc[:]))
+
+	const n = 16
+	values := make([]Scalar, n)
+	for i := range values {
+		values[i] = emulated.ValueOf[emparams.BandersnatchFr](poly[i])
+	}
+	circuit := commitCircuit{Values: make([]Scalar, n)}
+	assignment := commitCircuit{Values: values, Expected: valueOfPoint(commit(poly[:n]))}
+	assert.NoError(test.IsSolved(&circuit, &assignment, ecc.BLS12_381.ScalarField()))
+	assignment.Expected = valueOfPoint(commit(poly[1 : n+1]))
+	assert.Error(test.IsSolved(&circuit, &assignment, ecc.BLS12_381.ScalarField()))
+}
+
+type mapToScalarCircuit struct {
+	P        edwards.Point
+	Expected Scalar
+}
+
+func (c *mapToScalarCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api)
+	if err != nil {
+		return err
+	}
+	s := v.MapToScalarField(c.P)
+	v.fr.AssertIsEqual(s, &c.Expected)
+	return nil
+}
+
+func TestMapToScalarField(t *testing.T) {
+	assert := test.NewAssert(t)
+	p := commit(testPolynomial(false))
+	// x/y is the same for both representatives of the element.
+	var q bandersnatch.PointAffine
+	q.X.Neg(&p.X)
+	q.Y.Neg(&p.Y)
+	var xy fr.Element
+	var s big.Int
+	xy.Div(&p.X, &p.Y).BigInt(&s)
+	s.Mod(&s, emparams.BandersnatchFr{}.Modulus())
+	for _, r := range []bandersnatch.PointAffine{p, q} {
+		assignment := mapToScalarCircuit{P: valueOfPoint(r), Expected: emulated.ValueOf[emparams.BandersnatchFr](&s)}
+		assert.NoError(test.IsSolved(&mapToScalarCircuit{}, &assignment, ecc.BLS12_381.ScalarField()))
+	}
+}
+
+type ipaCircuit struct {
+	Commitment edwards.Point
+	Z, Y       Scalar
+	Proof      IPAProof
+}
+
+func (c *ipaCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api, WithTranscriptLabel("test"))
+	if err != nil {
+		return err
+	}
+	return v.VerifyIPA(c.Commitment, &c.Z, &c.Y, &c.Proof)
+}
+
+func TestVerifyIPA(t *testing.T) {
+	assert := test.NewAssert(t)
+	proof, err := ValueOfIPAProof(mustDecode(ipaProofHex))
+	assert.NoError(err)
+	y, err := ValueOfScalar(mustDecode("4a353e70b03c89f161de002e8713beec0d740a5e20722fd5bd68b30540a33208"))
+	assert.NoError(err)
+	assignment := ipaCircuit{
+		Commitment: valueOfPoint(commit(testPolynomial(false))),
+		Z:          emulated.ValueOf[emparams.BandersnatchFr](2101),
+		Y:          y,
+		Proof:      proof,
+	}
+	assert.NoError(test.IsSolved(&ipaCircuit{}, &assignment, ecc.BLS12_381.ScalarField()))
+	if testing.Short() {
+		return
+	}
+	assignment.Z = emulated.ValueOf[emparams.BandersnatchFr](2102)
+	assert.Error(test.IsSolved(&ipaCircuit{}, &assignment, ecc.BLS12_381.ScalarField()))
+}
+
+type multiProofCircuit struct {
+	Commitments [2]edwards.Point
+	Zs          [2]frontend.Variable
+	Ys          [2]Scalar
+	Proof       MultiProof
+}
+
+func (c *multiProofCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api, WithTranscriptLabel("test"))
+	if err != nil {
+		return err
+	}
+	return v.VerifyMultiProof(c.Commitments[:], c.Zs[:], []*Scalar{&c.Ys[0], &c.Ys[1]}, &c.Proof)
+}
+
+func TestVerifyMultiProof(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping multiproof verification in short mode")
+	}
+	assert := test.NewAssert(t)
+	proof, err := ValueOfMultiProof(mustDecode(multiProofHex))
+	assert.NoError(err)
+	assignment := multiProofCircuit{
+		Commitments: [2]edwards.Point{
+			valueOfPoint(commit(testPolynomial(false))),
+			valueOfPoint(commit(testPolynomial(true))),
+		},
+		Zs:    [2]frontend.Variable{0, 0},
+		Ys:    [2]Scalar{emulated.ValueOf[emparams.BandersnatchFr](1), emulated.ValueOf[emparams.BandersnatchFr](32)},
+		Proof: proof,
+	}
+	assert.NoError(test.IsSolved(&multiProofCircuit{}, &assignment, ecc.BLS12_381.ScalarField()))
+	assignment.Ys[1] = emulated.ValueOf[emparams.BandersnatchFr](31)
+	assert.Error(test.IsSolved(&multiProofCircuit{}, &assignment, ecc.BLS12_381.ScalarField()))
+}
+
+// trieProof is the proof of the key 0x0101...01 of value 0x0202...02 in the
+// Verkle trie containing only this key, computed with go-verkle, the Ethereum
+// implementation. The openings are the root at the first byte of the stem, the
+// leaf node at the marker, the stem and C1, and C1 at the low and high parts of
+// the value.
+var trieProof = struct {
+	root        string
+	commitments []string
+	zs          []int
+	ys          []string
+	proof       string
+}{
+	root: "673753e38b8d8f8329bb8bf386048dda618c229aee306491da57c8c3c246434e",
+	commitments: []string{
+		"673753e38b8d8f8329bb8bf386048dda618c229aee306491da57c8c3c246434e",
+		"64fed67d53d4741d7e0bddecb27ab403819aecd89fad3c7c820ebe2543e1330e",
+		"64fed67d53d4741d7e0bddecb27ab403819aecd89fad3c7c820ebe2543e1330e",
+		"64fed67d53d4741d7e0bddecb27ab403819aecd89fad3c7c820ebe2543e1330e",
+		"53ec98c3515159463df61142130b236ea1ab8f37487e2b0cadf87731994b216d",
+		"53ec98c3515159463df61142130b236ea1ab8f37487e2b0cadf87731994b216d",
+	},
+	zs: []int{1, 0, 1, 2, 2, 3},
+	ys: []string{
+		"e4a2d4589193066bd1a1ba7ca105e6d5e90388055c4b66cda7a729cc9155321a",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"0101010101010101010101010101010101010101010101010101010101010100",
+		"09e92ddfb657c3b230b37d6f7c500af18362d9b9b63ca10ae4c48fc56050af14",
+		"0202020202020202020202020202020201000000000000000000000000000000",
+		"0202020202020202020202020202020200000000000000000000000000000000",
+	},
+	proof: "4980b5addc9fed9846dcf1ebc004f06b6f0d85e0e1c0cab5ccc8652981bddbdb4c080bc4f9167b12324ebcd491d63197039b3e0823a6529924fd6904db076479211c81e84f1f69b20c153f217bae8a023a58cf907d822e4c8978fc49274d71800322affc2aef71a2654ac894c5728bd355da9a5be05bbfc772fb15a2360d094c486bfb98ad51c8364d0b432a2cc2d90c08394d7bb47cf60221e8e0d89719224663c4c78e9ddb7de374d06a2fd4045cc632845e92810048508e781726a6eb78fd095f9ced85fb5e1252656fcb3f5f1a973d591936a7df0b5766def2101190cf6426c3d92186650d15abaa458d38a9de737dd1f1ba4f05fa55c28093bc554d049720b7b0533480316e2243b1d76d29effece2f23e1155cc575b5dff493c1c7cd06338cc1a6e0c56d8dfa5b5a320e8660e98e6c28a9421fb016bf2a7ff597de77904a0abfc8256e5ec15718a5744b45450536241de6f698b4da6b7a113639e1453950a06924ac434ffdcf9d61923c8fad040a21ae4be9944f8da534557f6245dcdd4454cb147db03923de64432073ff5382d4174338b0ec1517f881255f1a2caf335cf14cc78f499005358b475eef409f104b0945c177c6d969a60c71619ace53cc618a8445860e33814de3d46be93fcb5eab41868cce1b1301cf1384045e4c43c410302233ba8990ff062226aeacf0e6964ed9cb64bad441ad9c4444ffd00e152012751416288a2b395352e0808faf765647cbc535182ba5a2bb66ad3ad20801adc93355cabd9a7ecf49c8353a0d0ab1dec35662eba224216b7f52e12407ca0e0a",
+}
+
+type trieProofCircuit struct {
+	Root        edwards.Point `gnark:",public"`
+	Commitments []edwards.Point
+	Zs          []frontend.Variable
+	Ys          []Scalar
+	Proof       MultiProof
+}
+
+func (c *trieProofCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api)
+	if err != nil {
+		return err
+	}
+	v.AssertIsEqual(c.Root, c.Commitments[0])
+	ys := make([]*Scalar, len(c.Ys))
+	for i := range ys {
+		ys[i] = &c.Ys[i]
+	}
+	return v.VerifyMultiProof(c.Commitments, c.Zs, ys, &c.Proof)
+}
+
+func TestVerifyTrieProof(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping trie proof verification in short mode")
+	}
+	assert := test.NewAssert(t)
+	n := len(trieProof.commitments)
+	circuit := trieProofCircuit{
+		Commitments: make([]edwards.Point, n),
+		Zs:          make([]frontend.Variable, n),
+		Ys:          make([]Scalar, n),
+	}
+	root, err := ValueOfPoint(mustDecode(trieProof.root))
+	assert.NoError(err)
+	proof, err := ValueOfMultiProof(mustDecode(trieProof.proof))
+	assert.NoError(err)
+	assignment := trieProofCircuit{
+		Root:        root,
+		Commitments: make([]edwards.Point, n),
+		Zs:          make([]frontend.Variable, n),
+		Ys:          make([]Scalar, n),
+		Proof:       proof,
+	}
+	for i := 0; i < n; i++ {
+		assignment.Commitments[i], err = ValueOfPoint(mustDecode(trieProof.commitments[i]))
+		assert.NoError(err)
+		assignment.Zs[i] = trieProof.zs[i]
+		assignment.Ys[i], err = ValueOfScalar(mustDecode(trieProof.ys[i]))
+		assert.NoError(err)
+	}
+	assert.NoError(test.IsSolved(&circuit, &assignment, ecc.BLS12_381.ScalarField()))
+	// the value is changed.
+	assignment.Ys[n-1], err = ValueOfScalar(mustDecode("0302020202020202020202020202020200000000000000000000000000000000"))
+	assert.NoError(err)
+	assert.Error(test.IsSolved(&circuit, &assignment, ecc.BLS12_381.ScalarField()))
+}
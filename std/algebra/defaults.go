@@ -3,6 +3,7 @@ package algebra
 import (
 	"fmt"
 
+	edwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
 	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
@@ -10,6 +11,7 @@ import (
 	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
 	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
 	"github.com/consensys/gnark/std/algebra/native/sw_bls24315"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
 	"github.com/consensys/gnark/std/math/emulated"
 	"github.com/consensys/gnark/std/math/emulated/emparams"
 )
@@ -18,6 +20,11 @@ import (
 // G1 type parameters. The method allows to have a fully generic implementation
 // without taking into consideration the initialization differences of different
 // curves.
+//
+// The native twisted Edwards curves all use the point type
+// [twistededwards.Point], so they are identified by the emulated scalar field,
+// for example [emparams.BabyJubjubFr] for the twisted Edwards curve over BN254
+// and [emparams.BandersnatchFr] for Bandersnatch.
 func GetCurve[FR emulated.FieldParams, G1El G1ElementT](api frontend.API) (Curve[FR, G1El], error) {
 	var ret Curve[FR, G1El]
 	switch s := any(&ret).(type) {
@@ -57,6 +64,54 @@ func GetCurve[FR emulated.FieldParams, G1El G1ElementT](api frontend.API) (Curve
 			return ret, fmt.Errorf("new curve: %w", err)
 		}
 		*s = c
+	case *Curve[emparams.BandersnatchFr, twistededwards.Point]:
+		c, err := twistededwards.NewAlgebraCurve[emparams.BandersnatchFr](api, edwards.BLS12_381_BANDERSNATCH)
+		if err != nil {
+			return ret, fmt.Errorf("new curve: %w", err)
+		}
+		*s = c
+	case *Curve[emparams.JubjubFr, twistededwards.Point]:
+		c, err := twistededwards.NewAlgebraCurve[emparams.JubjubFr](api, edwards.BLS12_381)
+		if err != nil {
+			return ret, fmt.Errorf("new curve: %w", err)
+		}
+		*s = c
+	case *Curve[emparams.BabyJubjubFr, twistededwards.Point]:
+		c, err := twistededwards.NewAlgebraCurve[emparams.BabyJubjubFr](api, edwards.BN254)
+		if err != nil {
+			return ret, fmt.Errorf("new curve: %w", err)
+		}
+		*s = c
+	case *Curve[emparams.EdBLS12377Fr, twistededwards.Point]:
+		c, err := twistededwards.NewAlgebraCurve[emparams.EdBLS12377Fr](api, edwards.BLS12_377)
+		if err != nil {
+			return ret, fmt.Errorf("new curve: %w", err)
+		}
+		*s = c
+	case *Curve[emparams.EdBLS24315Fr, twistededwards.Point]:
+		c, err := twistededwards.NewAlgebraCurve[emparams.EdBLS24315Fr](api, edwards.BLS24_315)
+		if err != nil {
+			return ret, fmt.Errorf("new curve: %w", err)
+		}
+		*s = c
+	case *Curve[emparams.EdBLS24317Fr, twistededwards.Point]:
+		c, err := twistededwards.NewAlgebraCurve[emparams.EdBLS24317Fr](api, edwards.BLS24_317)
+		if err != nil {
+			return ret, fmt.Errorf("new curve: %w", err)
+		}
+		*s = c
+	case *Curve[emparams.EdBW6761Fr, twistededwards.Point]:
+		c, err := twistededwards.NewAlgebraCurve[emparams.EdBW6761Fr](api, edwards.BW6_761)
+		if err != nil {
+			return ret, fmt.Errorf("new curve: %w", err)
+		}
+		*s = c
+	case *Curve[emparams.EdBW6633Fr, twistededwards.Point]:
+		c, err := twistededwards.NewAlgebraCurve[emparams.EdBW6633Fr](api, edwards.BW6_633)
+		if err != nil {
+			return ret, fmt.Errorf("new curve: %w", err)
+		}
+		*s = c
 	default:
 		return ret, fmt.Errorf("unknown type parametrisation")
 	}
@@ -463,3 +463,20 @@ func (e Ext6) Lookup2(s1, s2 frontend.Variable, a, b, c, d *E6) *E6 {
 	b2 := e.Ext2.Lookup2(s1, s2, &a.B2, &b.B2, &c.B2, &d.B2)
 	return &E6{B0: *b0, B1: *b1, B2: *b2}
 }
+
+// Mux selects the element inputs[sel] and returns it. It is most efficient for
+// power of two lengths of the inputs, but works for any number of inputs.
+func (e Ext6) Mux(sel frontend.Variable, inputs ...*E6) *E6 {
+	b0s := make([]*E2, len(inputs))
+	b1s := make([]*E2, len(inputs))
+	b2s := make([]*E2, len(inputs))
+	for i := range inputs {
+		b0s[i] = &inputs[i].B0
+		b1s[i] = &inputs[i].B1
+		b2s[i] = &inputs[i].B2
+	}
+	b0 := e.Ext2.Mux(sel, b0s...)
+	b1 := e.Ext2.Mux(sel, b1s...)
+	b2 := e.Ext2.Mux(sel, b2s...)
+	return &E6{B0: *b0, B1: *b1, B2: *b2}
+}
@@ -196,3 +196,17 @@ func (e Ext12) Lookup2(s1, s2 frontend.Variable, a, b, c, d *E12) *E12 {
 	c1 := e.Ext6.Lookup2(s1, s2, &a.C1, &b.C1, &c.C1, &d.C1)
 	return &E12{C0: *c0, C1: *c1}
 }
+
+// Mux selects the element inputs[sel] and returns it. It is most efficient for
+// power of two lengths of the inputs, but works for any number of inputs.
+func (e Ext12) Mux(sel frontend.Variable, inputs ...*E12) *E12 {
+	c0s := make([]*E6, len(inputs))
+	c1s := make([]*E6, len(inputs))
+	for i := range inputs {
+		c0s[i] = &inputs[i].C0
+		c1s[i] = &inputs[i].C1
+	}
+	c0 := e.Ext6.Mux(sel, c0s...)
+	c1 := e.Ext6.Mux(sel, c1s...)
+	return &E12{C0: *c0, C1: *c1}
+}
@@ -354,3 +354,17 @@ func (e Ext2) Lookup2(s1, s2 frontend.Variable, a, b, c, d *E2) *E2 {
 	a1 := e.fp.Lookup2(s1, s2, &a.A1, &b.A1, &c.A1, &d.A1)
 	return &E2{A0: *a0, A1: *a1}
 }
+
+// Mux selects the element inputs[sel] and returns it. It is most efficient for
+// power of two lengths of the inputs, but works for any number of inputs.
+func (e Ext2) Mux(sel frontend.Variable, inputs ...*E2) *E2 {
+	a0s := make([]*baseEl, len(inputs))
+	a1s := make([]*baseEl, len(inputs))
+	for i := range inputs {
+		a0s[i] = &inputs[i].A0
+		a1s[i] = &inputs[i].A1
+	}
+	a0 := e.fp.Mux(sel, a0s...)
+	a1 := e.fp.Mux(sel, a1s...)
+	return &E2{A0: *a0, A1: *a1}
+}
@@ -990,6 +990,31 @@ func (e Ext6) Select(selector frontend.Variable, z1, z0 *E6) *E6 {
 	return &E6{A0: *a0, A1: *a1, A2: *a2, A3: *a3, A4: *a4, A5: *a5}
 }
 
+// Mux selects the element inputs[sel] and returns it. It is most efficient for
+// power of two lengths of the inputs, but works for any number of inputs.
+func (e Ext6) Mux(sel frontend.Variable, inputs ...*E6) *E6 {
+	var as [6][]*baseEl
+	for j := range as {
+		as[j] = make([]*baseEl, len(inputs))
+	}
+	for i := range inputs {
+		as[0][i] = &inputs[i].A0
+		as[1][i] = &inputs[i].A1
+		as[2][i] = &inputs[i].A2
+		as[3][i] = &inputs[i].A3
+		as[4][i] = &inputs[i].A4
+		as[5][i] = &inputs[i].A5
+	}
+	a0 := e.fp.Mux(sel, as[0]...)
+	a1 := e.fp.Mux(sel, as[1]...)
+	a2 := e.fp.Mux(sel, as[2]...)
+	a3 := e.fp.Mux(sel, as[3]...)
+	a4 := e.fp.Mux(sel, as[4]...)
+	a5 := e.fp.Mux(sel, as[5]...)
+
+	return &E6{A0: *a0, A1: *a1, A2: *a2, A3: *a3, A4: *a4, A5: *a5}
+}
+
 // Frobenius set z in E6 to Frobenius(x), return z
 func (e Ext6) Frobenius(x *E6) *E6 {
 	_frobA := emulated.ValueOf[emulated.BW6761Fp]("4922464560225523242118178942575080391082002530232324381063048548642823052024664478336818169867474395270858391911405337707247735739826664939444490469542109391530482826728203582549674992333383150446779312029624171857054392282775648")
@@ -260,6 +260,62 @@ func (pr Pairing) AssertIsEqual(x, y *GTEl) {
 	pr.Ext12.AssertIsEqual(x, y)
 }
 
+// MuxG2 selects the G2 element inputs[sel] and returns it. The line
+// precomputations are selected only when all the inputs have them. Otherwise
+// the result has no precomputations and the lines are computed in-circuit
+// during the Miller loop.
+func (pr Pairing) MuxG2(sel frontend.Variable, inputs ...*G2Affine) *G2Affine {
+	if len(inputs) == 0 {
+		return nil
+	}
+	xs := make([]*fields_bn254.E2, len(inputs))
+	ys := make([]*fields_bn254.E2, len(inputs))
+	for i := range inputs {
+		xs[i] = &inputs[i].P.X
+		ys[i] = &inputs[i].P.Y
+	}
+	ret := &G2Affine{
+		P: g2AffP{
+			X: *pr.Ext2.Mux(sel, xs...),
+			Y: *pr.Ext2.Mux(sel, ys...),
+		},
+	}
+	for i := range inputs {
+		if inputs[i].Lines == nil {
+			return ret
+		}
+	}
+	var lines lineEvaluations
+	r0s := make([]*fields_bn254.E2, len(inputs))
+	r1s := make([]*fields_bn254.E2, len(inputs))
+	for k := range lines {
+	nextLine:
+		for j := range lines[k] {
+			for i := range inputs {
+				if inputs[i].Lines[k][j] == nil {
+					continue nextLine
+				}
+				r0s[i] = &inputs[i].Lines[k][j].R0
+				r1s[i] = &inputs[i].Lines[k][j].R1
+			}
+			lines[k][j] = &lineEvaluation{
+				R0: *pr.Ext2.Mux(sel, r0s...),
+				R1: *pr.Ext2.Mux(sel, r1s...),
+			}
+		}
+	}
+	ret.Lines = &lines
+	return ret
+}
+
+// MuxGt selects the target group element inputs[sel] and returns it.
+func (pr Pairing) MuxGt(sel frontend.Variable, inputs ...*GTEl) *GTEl {
+	if len(inputs) == 0 {
+		return nil
+	}
+	return pr.Ext12.Mux(sel, inputs...)
+}
+
 func (pr Pairing) AssertIsOnCurve(P *G1Affine) {
 	pr.curve.AssertIsOnCurve(P)
 }
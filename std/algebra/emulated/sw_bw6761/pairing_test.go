@@ -224,6 +224,48 @@ func TestGroupMembershipSolve(t *testing.T) {
 	assert.NoError(err)
 }
 
+type MuxCircuit struct {
+	Sel  frontend.Variable
+	InG1 G1Affine
+	InG2 [2]G2Affine
+	Res  [2]GTEl
+}
+
+func (c *MuxCircuit) Define(api frontend.API) error {
+	pairing, err := NewPairing(api)
+	if err != nil {
+		return fmt.Errorf("new pairing: %w", err)
+	}
+	q := pairing.MuxG2(c.Sel, &c.InG2[0], &c.InG2[1])
+	res, err := pairing.Pair([]*G1Affine{&c.InG1}, []*G2Affine{q})
+	if err != nil {
+		return fmt.Errorf("pair: %w", err)
+	}
+	pairing.AssertIsEqual(res, pairing.MuxGt(c.Sel, &c.Res[0], &c.Res[1]))
+	return nil
+}
+
+func TestMuxFixedTestSolve(t *testing.T) {
+	assert := test.NewAssert(t)
+	p, q1 := randomG1G2Affines()
+	_, q2 := randomG1G2Affines()
+	res1, err := bw6761.Pair([]bw6761.G1Affine{p}, []bw6761.G2Affine{q1})
+	assert.NoError(err)
+	res2, err := bw6761.Pair([]bw6761.G1Affine{p}, []bw6761.G2Affine{q2})
+	assert.NoError(err)
+	circuit := MuxCircuit{
+		InG2: [2]G2Affine{NewG2AffineFixedPlaceholder(), NewG2AffineFixedPlaceholder()},
+	}
+	witness := MuxCircuit{
+		Sel:  1,
+		InG1: NewG1Affine(p),
+		InG2: [2]G2Affine{NewG2AffineFixed(q1), NewG2AffineFixed(q2)},
+		Res:  [2]GTEl{NewGTEl(res1), NewGTEl(res2)},
+	}
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
 // bench
 func BenchmarkPairing(b *testing.B) {
 
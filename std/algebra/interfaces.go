@@ -106,3 +106,20 @@ type Pairing[G1El G1ElementT, G2El G2ElementT, GtEl GtElementT] interface {
 	// AssertIsOnG2 asserts that the input is on the G2 curve.
 	AssertIsOnG2(*G2El)
 }
+
+// PairingMuxer is implemented by the [Pairing] implementations which allow
+// selecting G2 and Gt elements in-circuit. It is optional and checked with a
+// type assertion by the gadgets which need it, for example for switching the
+// verifying keys in the recursive verifiers. All the pairings in gnark
+// implement it.
+type PairingMuxer[G2El G2ElementT, GtEl GtElementT] interface {
+	// MuxG2 performs a lookup from the G2 inputs and returns inputs[sel]. It
+	// is most efficient for power of two lengths of the inputs, but works for
+	// any number of inputs.
+	MuxG2(sel frontend.Variable, inputs ...*G2El) *G2El
+
+	// MuxGt performs a lookup from the Gt inputs and returns inputs[sel]. It
+	// is most efficient for power of two lengths of the inputs, but works for
+	// any number of inputs.
+	MuxGt(sel frontend.Variable, inputs ...*GtEl) *GtEl
+}
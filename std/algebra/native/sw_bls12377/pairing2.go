@@ -313,6 +313,76 @@ func (p *Pairing) AssertIsEqual(e1, e2 *GT) {
 	e1.AssertIsEqual(p.api, *e2)
 }
 
+// MuxG2 selects the G2 element inputs[sel] and returns it. The line
+// precomputations are selected only when all the inputs have them. Otherwise
+// the result has no precomputations and the lines are computed in-circuit
+// during the Miller loop.
+func (p *Pairing) MuxG2(sel frontend.Variable, inputs ...*G2Affine) *G2Affine {
+	if len(inputs) == 0 {
+		return nil
+	}
+	ret := &G2Affine{
+		P: g2AffP{
+			X: muxE2(p.api, sel, inputs, func(e *G2Affine) *fields_bls12377.E2 { return &e.P.X }),
+			Y: muxE2(p.api, sel, inputs, func(e *G2Affine) *fields_bls12377.E2 { return &e.P.Y }),
+		},
+	}
+	for i := range inputs {
+		if inputs[i].Lines == nil {
+			return ret
+		}
+	}
+	var lines lineEvaluations
+	evals := make([]*lineEvaluation, len(inputs))
+	for k := range lines {
+	nextLine:
+		for j := range lines[k] {
+			for i := range inputs {
+				if inputs[i].Lines[k][j] == nil {
+					continue nextLine
+				}
+				evals[i] = inputs[i].Lines[k][j]
+			}
+			lines[k][j] = &lineEvaluation{
+				R0: muxE2(p.api, sel, evals, func(e *lineEvaluation) *fields_bls12377.E2 { return &e.R0 }),
+				R1: muxE2(p.api, sel, evals, func(e *lineEvaluation) *fields_bls12377.E2 { return &e.R1 }),
+			}
+		}
+	}
+	ret.Lines = &lines
+	return ret
+}
+
+// MuxGt selects the target group element inputs[sel] and returns it.
+func (p *Pairing) MuxGt(sel frontend.Variable, inputs ...*GT) *GT {
+	if len(inputs) == 0 {
+		return nil
+	}
+	var ret GT
+	ret.C0.B0 = muxE2(p.api, sel, inputs, func(e *GT) *fields_bls12377.E2 { return &e.C0.B0 })
+	ret.C0.B1 = muxE2(p.api, sel, inputs, func(e *GT) *fields_bls12377.E2 { return &e.C0.B1 })
+	ret.C0.B2 = muxE2(p.api, sel, inputs, func(e *GT) *fields_bls12377.E2 { return &e.C0.B2 })
+	ret.C1.B0 = muxE2(p.api, sel, inputs, func(e *GT) *fields_bls12377.E2 { return &e.C1.B0 })
+	ret.C1.B1 = muxE2(p.api, sel, inputs, func(e *GT) *fields_bls12377.E2 { return &e.C1.B1 })
+	ret.C1.B2 = muxE2(p.api, sel, inputs, func(e *GT) *fields_bls12377.E2 { return &e.C1.B2 })
+	return &ret
+}
+
+// muxE2 selects the E2 element get(inputs[sel]).
+func muxE2[T any](api frontend.API, sel frontend.Variable, inputs []*T, get func(*T) *fields_bls12377.E2) fields_bls12377.E2 {
+	a0s := make([]frontend.Variable, len(inputs))
+	a1s := make([]frontend.Variable, len(inputs))
+	for i := range inputs {
+		e := get(inputs[i])
+		a0s[i] = e.A0
+		a1s[i] = e.A1
+	}
+	return fields_bls12377.E2{
+		A0: selector.Mux(api, sel, a0s...),
+		A1: selector.Mux(api, sel, a1s...),
+	}
+}
+
 // AssertIsOnCurve asserts if p belongs to the curve. It doesn't modify p.
 func (c *Pairing) AssertIsOnCurve(p *G1Affine) {
 	// (X,Y) ∈ {Y² == X³ + 1} U (0,0)
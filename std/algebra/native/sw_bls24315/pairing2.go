@@ -313,6 +313,84 @@ func (p *Pairing) AssertIsEqual(e1, e2 *GT) {
 	e1.AssertIsEqual(p.api, *e2)
 }
 
+// MuxG2 selects the G2 element inputs[sel] and returns it. The line
+// precomputations are selected only when all the inputs have them. Otherwise
+// the result has no precomputations and the lines are computed in-circuit
+// during the Miller loop.
+func (p *Pairing) MuxG2(sel frontend.Variable, inputs ...*G2Affine) *G2Affine {
+	if len(inputs) == 0 {
+		return nil
+	}
+	ret := &G2Affine{
+		P: g2AffP{
+			X: muxE4(p.api, sel, inputs, func(e *G2Affine) *fields_bls24315.E4 { return &e.P.X }),
+			Y: muxE4(p.api, sel, inputs, func(e *G2Affine) *fields_bls24315.E4 { return &e.P.Y }),
+		},
+	}
+	for i := range inputs {
+		if inputs[i].Lines == nil {
+			return ret
+		}
+	}
+	var lines lineEvaluations
+	evals := make([]*lineEvaluation, len(inputs))
+	for k := range lines {
+	nextLine:
+		for j := range lines[k] {
+			for i := range inputs {
+				if inputs[i].Lines[k][j] == nil {
+					continue nextLine
+				}
+				evals[i] = inputs[i].Lines[k][j]
+			}
+			lines[k][j] = &lineEvaluation{
+				R0: muxE4(p.api, sel, evals, func(e *lineEvaluation) *fields_bls24315.E4 { return &e.R0 }),
+				R1: muxE4(p.api, sel, evals, func(e *lineEvaluation) *fields_bls24315.E4 { return &e.R1 }),
+			}
+		}
+	}
+	ret.Lines = &lines
+	return ret
+}
+
+// MuxGt selects the target group element inputs[sel] and returns it.
+func (p *Pairing) MuxGt(sel frontend.Variable, inputs ...*GT) *GT {
+	if len(inputs) == 0 {
+		return nil
+	}
+	var ret GT
+	ret.D0.C0 = muxE4(p.api, sel, inputs, func(e *GT) *fields_bls24315.E4 { return &e.D0.C0 })
+	ret.D0.C1 = muxE4(p.api, sel, inputs, func(e *GT) *fields_bls24315.E4 { return &e.D0.C1 })
+	ret.D0.C2 = muxE4(p.api, sel, inputs, func(e *GT) *fields_bls24315.E4 { return &e.D0.C2 })
+	ret.D1.C0 = muxE4(p.api, sel, inputs, func(e *GT) *fields_bls24315.E4 { return &e.D1.C0 })
+	ret.D1.C1 = muxE4(p.api, sel, inputs, func(e *GT) *fields_bls24315.E4 { return &e.D1.C1 })
+	ret.D1.C2 = muxE4(p.api, sel, inputs, func(e *GT) *fields_bls24315.E4 { return &e.D1.C2 })
+	return &ret
+}
+
+// muxE2 selects the E2 element get(inputs[sel]).
+func muxE2[T any](api frontend.API, sel frontend.Variable, inputs []*T, get func(*T) *fields_bls24315.E2) fields_bls24315.E2 {
+	a0s := make([]frontend.Variable, len(inputs))
+	a1s := make([]frontend.Variable, len(inputs))
+	for i := range inputs {
+		e := get(inputs[i])
+		a0s[i] = e.A0
+		a1s[i] = e.A1
+	}
+	return fields_bls24315.E2{
+		A0: selector.Mux(api, sel, a0s...),
+		A1: selector.Mux(api, sel, a1s...),
+	}
+}
+
+// muxE4 selects the E4 element get(inputs[sel]).
+func muxE4[T any](api frontend.API, sel frontend.Variable, inputs []*T, get func(*T) *fields_bls24315.E4) fields_bls24315.E4 {
+	return fields_bls24315.E4{
+		B0: muxE2(api, sel, inputs, func(e *T) *fields_bls24315.E2 { return &get(e).B0 }),
+		B1: muxE2(api, sel, inputs, func(e *T) *fields_bls24315.E2 { return &get(e).B1 }),
+	}
+}
+
 func (p *Pairing) AssertIsOnG1(P *G1Affine) {
 	panic("not implemented")
 }
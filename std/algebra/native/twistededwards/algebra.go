@@ -0,0 +1,293 @@
+package twistededwards
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/algopts"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// AlgebraCurve allows using the twisted Edwards curve through the generic
+// elliptic curve interface of the algebra package, where the scalars are
+// elements of the emulated scalar field S of the curve. The points are native.
+//
+// The twisted Edwards addition law is complete, so the operations do not have
+// exceptional cases and the [algopts.WithCompleteArithmetic] option is not
+// needed.
+//
+// The points are assumed to be in the prime order subgroup, as the scalars are
+// reduced modulo its order. The operations do not check it, so the caller must
+// assert it for the points given as witness using
+// [AlgebraCurve.AssertIsInSubgroup].
+type AlgebraCurve[S emulated.FieldParams] struct {
+	api   frontend.API
+	id    twistededwards.ID
+	curve Curve
+	fr    *emulated.Field[S]
+}
+
+// NewAlgebraCurve returns a new [AlgebraCurve] for the twisted Edwards curve
+// id. It returns an error if the modulus of S is not the order of the curve.
+func NewAlgebraCurve[S emulated.FieldParams](api frontend.API, id twistededwards.ID) (*AlgebraCurve[S], error) {
+	curve, err := NewEdCurve(api, id)
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	var fr S
+	if fr.Modulus().Cmp(curve.Params().Order) != 0 {
+		return nil, fmt.Errorf("scalar field modulus does not match the curve order")
+	}
+	f, err := emulated.NewField[S](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar field: %w", err)
+	}
+	return &AlgebraCurve[S]{api: api, id: id, curve: curve, fr: f}, nil
+}
+
+// AssertIsOnCurve asserts that p is on the curve.
+func (c *AlgebraCurve[S]) AssertIsOnCurve(p *Point) {
+	c.curve.AssertIsOnCurve(*p)
+}
+
+// AssertIsInSubgroup asserts that p is in the prime order subgroup. The point
+// Q = [h⁻¹ mod r]P is computed in a hint and the circuit asserts that Q is on
+// the curve and that P = [h]Q. As the cofactor h is coprime with the order r,
+// the multiples of h are exactly the points of order r.
+func (c *AlgebraCurve[S]) AssertIsInSubgroup(p *Point) {
+	q, err := c.api.NewHint(clearCofactorHint, 2, int(c.id), p.X, p.Y)
+	if err != nil {
+		panic(err)
+	}
+	qp := Point{X: q[0], Y: q[1]}
+	c.curve.AssertIsOnCurve(qp)
+	// the cofactor is a small constant, so [h]Q is computed by double-and-add.
+	h := c.curve.Params().Cofactor
+	res := qp
+	for i := h.BitLen() - 2; i >= 0; i-- {
+		res = c.curve.Double(res)
+		if h.Bit(i) == 1 {
+			res = c.curve.Add(res, qp)
+		}
+	}
+	c.AssertIsEqual(&res, p)
+}
+
+func init() {
+	solver.RegisterHint(clearCofactorHint)
+}
+
+// clearCofactorHint returns [h⁻¹ mod r]P for the curve ID inputs[0] and the
+// point P = (inputs[1], inputs[2]), where h is the cofactor and r the order of
+// the prime order subgroup.
+func clearCofactorHint(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 3 || len(outputs) != 2 {
+		return fmt.Errorf("expected 3 inputs and 2 outputs")
+	}
+	params, err := GetCurveParams(twistededwards.ID(inputs[0].Uint64()))
+	if err != nil {
+		return err
+	}
+	add := func(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+		// x₃ = (x₁y₂ + y₁x₂) / (1 + d x₁x₂y₁y₂)
+		// y₃ = (y₁y₂ - a x₁x₂) / (1 - d x₁x₂y₁y₂)
+		xx := new(big.Int).Mul(x1, x2)
+		yy := new(big.Int).Mul(y1, y2)
+		t := new(big.Int).Mul(xx, yy)
+		t.Mul(t, params.D).Mod(t, mod)
+		x3 := new(big.Int).Mul(x1, y2)
+		x3.Add(x3, new(big.Int).Mul(y1, x2))
+		den := new(big.Int).Add(big.NewInt(1), t)
+		if den.ModInverse(den, mod) == nil {
+			return nil, nil
+		}
+		x3.Mul(x3, den).Mod(x3, mod)
+		y3 := new(big.Int).Mul(params.A, xx)
+		y3.Sub(yy, y3)
+		den.Sub(big.NewInt(1), t)
+		if den.ModInverse(den.Mod(den, mod), mod) == nil {
+			return nil, nil
+		}
+		y3.Mul(y3, den).Mod(y3, mod)
+		return x3, y3
+	}
+	s := new(big.Int).ModInverse(params.Cofactor, params.Order)
+	if s == nil {
+		return fmt.Errorf("cofactor not invertible modulo the order")
+	}
+	x, y := big.NewInt(0), big.NewInt(1)
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		if x, y = add(x, y, x, y); x == nil {
+			return fmt.Errorf("point not on the curve")
+		}
+		if s.Bit(i) == 1 {
+			if x, y = add(x, y, inputs[1], inputs[2]); x == nil {
+				return fmt.Errorf("point not on the curve")
+			}
+		}
+	}
+	outputs[0].Set(x)
+	outputs[1].Set(y)
+	return nil
+}
+
+// packScalar returns the native variable of the scalar reduced modulo the
+// curve order. The order is smaller than the native modulus.
+func (c *AlgebraCurve[S]) packScalar(s *emulated.Element[S]) frontend.Variable {
+	r := c.fr.Reduce(s)
+	c.fr.AssertIsInRange(r)
+	var fr S
+	shift := new(big.Int).Lsh(big.NewInt(1), fr.BitsPerLimb())
+	var res frontend.Variable = 0
+	for i := len(r.Limbs) - 1; i >= 0; i-- {
+		res = c.api.Add(c.api.Mul(res, shift), r.Limbs[i])
+	}
+	return res
+}
+
+// MarshalScalar returns the binary decomposition of the reduced scalar in
+// big-endian form, padded to a full number of bytes.
+func (c *AlgebraCurve[S]) MarshalScalar(s emulated.Element[S]) []frontend.Variable {
+	var fr S
+	nbBits := 8 * ((fr.Modulus().BitLen() + 7) / 8)
+	sr := c.fr.Reduce(&s)
+	res := c.fr.ToBits(sr)[:nbBits]
+	for i, j := 0, nbBits-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
+
+// MarshalG1 returns the binary decomposition P.X || P.Y, where the
+// coordinates are in big-endian form and padded to a full number of bytes. It
+// does not match the compressed point encoding of gnark-crypto.
+func (c *AlgebraCurve[S]) MarshalG1(p Point) []frontend.Variable {
+	nbBits := 8 * ((c.api.Compiler().FieldBitLen() + 7) / 8)
+	res := make([]frontend.Variable, 2*nbBits)
+	x := bits.ToBinary(c.api, p.X, bits.WithNbDigits(nbBits))
+	y := bits.ToBinary(c.api, p.Y, bits.WithNbDigits(nbBits))
+	for i := 0; i < nbBits; i++ {
+		res[i] = x[nbBits-1-i]
+		res[i+nbBits] = y[nbBits-1-i]
+	}
+	return res
+}
+
+// Add returns p + q. It does not modify the inputs.
+func (c *AlgebraCurve[S]) Add(p, q *Point) *Point {
+	res := c.curve.Add(*p, *q)
+	return &res
+}
+
+// AddUnified returns p + q. It is the same as [AlgebraCurve.Add] as the
+// addition law is complete.
+func (c *AlgebraCurve[S]) AddUnified(p, q *Point) *Point {
+	return c.Add(p, q)
+}
+
+// AssertIsEqual asserts that p and q are equal.
+func (c *AlgebraCurve[S]) AssertIsEqual(p, q *Point) {
+	c.api.AssertIsEqual(p.X, q.X)
+	c.api.AssertIsEqual(p.Y, q.Y)
+}
+
+// Neg returns -p. It does not modify the input.
+func (c *AlgebraCurve[S]) Neg(p *Point) *Point {
+	res := c.curve.Neg(*p)
+	return &res
+}
+
+// ScalarMul returns s*p. It does not modify the inputs.
+func (c *AlgebraCurve[S]) ScalarMul(p *Point, s *emulated.Element[S], _ ...algopts.AlgebraOption) *Point {
+	res := c.curve.ScalarMul(*p, c.packScalar(s))
+	return &res
+}
+
+// ScalarMulBase returns s*G, where G is the base point of the curve. It does
+// not modify the scalar.
+func (c *AlgebraCurve[S]) ScalarMulBase(s *emulated.Element[S], opts ...algopts.AlgebraOption) *Point {
+	base := c.curve.Params().Base
+	return c.ScalarMul(&Point{X: base[0], Y: base[1]}, s, opts...)
+}
+
+// MultiScalarMul returns ∑ sᵢ Pᵢ. It does not modify the inputs. It returns
+// an error if the lengths of the inputs mismatch. With the
+// [algopts.WithFoldingScalarMul] option, the scalars are the powers of the
+// first scalar and only the first scalar is used.
+func (c *AlgebraCurve[S]) MultiScalarMul(p []*Point, scalars []*emulated.Element[S], opts ...algopts.AlgebraOption) (*Point, error) {
+	if len(p) == 0 {
+		return &Point{X: 0, Y: 1}, nil
+	}
+	cfg, err := algopts.NewConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("new config: %w", err)
+	}
+	if cfg.FoldMulti {
+		if len(scalars) == 0 {
+			return nil, fmt.Errorf("need scalar for folding")
+		}
+		gamma := c.packScalar(scalars[0])
+		res := *p[len(p)-1]
+		for i := len(p) - 2; i >= 0; i-- {
+			res = c.curve.Add(*p[i], c.curve.ScalarMul(res, gamma))
+		}
+		return &res, nil
+	}
+	if len(p) != len(scalars) {
+		return nil, fmt.Errorf("mismatching points and scalars slice lengths")
+	}
+	n := len(p)
+	var res Point
+	if n%2 == 1 {
+		res = c.curve.ScalarMul(*p[n-1], c.packScalar(scalars[n-1]))
+	} else {
+		res = c.curve.DoubleBaseScalarMul(*p[n-2], *p[n-1], c.packScalar(scalars[n-2]), c.packScalar(scalars[n-1]))
+	}
+	for i := 1; i < n-1; i += 2 {
+		q := c.curve.DoubleBaseScalarMul(*p[i-1], *p[i], c.packScalar(scalars[i-1]), c.packScalar(scalars[i]))
+		res = c.curve.Add(res, q)
+	}
+	return &res, nil
+}
+
+// Select returns p1 if b=1 and p2 if b=0. b must be boolean constrained.
+func (c *AlgebraCurve[S]) Select(b frontend.Variable, p1, p2 *Point) *Point {
+	return &Point{
+		X: c.api.Select(b, p1.X, p2.X),
+		Y: c.api.Select(b, p1.Y, p2.Y),
+	}
+}
+
+// Lookup2 performs a 2-bit lookup between p1, p2, p3, p4 based on bits b0 and
+// b1. Returns:
+//   - p1 if b0=0 and b1=0,
+//   - p2 if b0=1 and b1=0,
+//   - p3 if b0=0 and b1=1,
+//   - p4 if b0=1 and b1=1.
+func (c *AlgebraCurve[S]) Lookup2(b1, b2 frontend.Variable, p1, p2, p3, p4 *Point) *Point {
+	return &Point{
+		X: c.api.Lookup2(b1, b2, p1.X, p2.X, p3.X, p4.X),
+		Y: c.api.Lookup2(b1, b2, p1.Y, p2.Y, p3.Y, p4.Y),
+	}
+}
+
+// Mux performs a lookup from the inputs and returns inputs[sel]. It is most
+// efficient for power of two lengths of the inputs, but works for any number of
+// inputs.
+func (c *AlgebraCurve[S]) Mux(sel frontend.Variable, inputs ...*Point) *Point {
+	xs := make([]frontend.Variable, len(inputs))
+	ys := make([]frontend.Variable, len(inputs))
+	for i := range inputs {
+		xs[i] = inputs[i].X
+		ys[i] = inputs[i].Y
+	}
+	return &Point{
+		X: selector.Mux(c.api, sel, xs...),
+		Y: selector.Mux(c.api, sel, ys...),
+	}
+}
@@ -0,0 +1,97 @@
+package twistededwards
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/test"
+)
+
+type algebraMSMCircuit struct {
+	Points   [3]Point
+	Scalars  [3]emulated.Element[emparams.BandersnatchFr]
+	Expected Point
+}
+
+func (c *algebraMSMCircuit) Define(api frontend.API) error {
+	curve, err := NewAlgebraCurve[emparams.BandersnatchFr](api, twistededwards.BLS12_381_BANDERSNATCH)
+	if err != nil {
+		return err
+	}
+	ps := make([]*Point, len(c.Points))
+	ss := make([]*emulated.Element[emparams.BandersnatchFr], len(c.Scalars))
+	for i := range ps {
+		ps[i] = &c.Points[i]
+		ss[i] = &c.Scalars[i]
+	}
+	res, err := curve.MultiScalarMul(ps, ss)
+	if err != nil {
+		return err
+	}
+	curve.AssertIsEqual(res, &c.Expected)
+	return nil
+}
+
+func TestAlgebraCurveMultiScalarMul(t *testing.T) {
+	assert := test.NewAssert(t)
+	params := bandersnatch.GetEdwardsCurve()
+	var assignment algebraMSMCircuit
+	var expected bandersnatch.PointAffine
+	expected.X.SetZero()
+	expected.Y.SetOne()
+	for i := range assignment.Points {
+		s1, err := rand.Int(rand.Reader, &params.Order)
+		assert.NoError(err)
+		s2, err := rand.Int(rand.Reader, &params.Order)
+		assert.NoError(err)
+		var p, q bandersnatch.PointAffine
+		p.ScalarMultiplication(&params.Base, s1)
+		q.ScalarMultiplication(&p, s2)
+		expected.Add(&expected, &q)
+		assignment.Points[i] = Point{X: p.X.String(), Y: p.Y.String()}
+		assignment.Scalars[i] = emulated.ValueOf[emparams.BandersnatchFr](s2)
+	}
+	assignment.Expected = Point{X: expected.X.String(), Y: expected.Y.String()}
+	err := test.IsSolved(&algebraMSMCircuit{}, &assignment, ecc.BLS12_381.ScalarField())
+	assert.NoError(err)
+}
+
+type algebraSubgroupCircuit struct {
+	P Point
+}
+
+func (c *algebraSubgroupCircuit) Define(api frontend.API) error {
+	curve, err := NewAlgebraCurve[emparams.BandersnatchFr](api, twistededwards.BLS12_381_BANDERSNATCH)
+	if err != nil {
+		return err
+	}
+	curve.AssertIsInSubgroup(&c.P)
+	return nil
+}
+
+func TestAlgebraCurveAssertIsInSubgroup(t *testing.T) {
+	assert := test.NewAssert(t)
+	params := bandersnatch.GetEdwardsCurve()
+	s, err := rand.Int(rand.Reader, &params.Order)
+	assert.NoError(err)
+	var p, torsion, q bandersnatch.PointAffine
+	p.ScalarMultiplication(&params.Base, s)
+	// (0, -1) is the point of order 2
+	torsion.X.SetZero()
+	torsion.Y.SetOne()
+	torsion.Y.Neg(&torsion.Y)
+	q.Add(&p, &torsion)
+	assert.True(q.IsOnCurve())
+
+	assert.CheckCircuit(&algebraSubgroupCircuit{},
+		test.WithValidAssignment(&algebraSubgroupCircuit{P: Point{X: p.X.String(), Y: p.Y.String()}}),
+		test.WithInvalidAssignment(&algebraSubgroupCircuit{P: Point{X: q.X.String(), Y: q.Y.String()}}),
+		test.WithInvalidAssignment(&algebraSubgroupCircuit{P: Point{X: p.X.String(), Y: q.Y.String()}}),
+		test.WithCurves(ecc.BLS12_381))
+}
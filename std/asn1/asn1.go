@@ -0,0 +1,219 @@
+// Package asn1 implements in-circuit parsing of DER encoded ASN.1 structures.
+//
+// The input is a byte slice in which the elements are parsed at variable
+// positions. Every element is a TLV, consisting of an identifier octet, a
+// length and the contents. Only the tags of the low-tag-number form are
+// supported, and the lengths are bounded to 65535 bytes, which covers the
+// short form and the long forms on one and two bytes. The length encodings are
+// asserted to be minimal as required by DER.
+//
+// The parser does not check the structure of the elements against a schema.
+// The callers navigate the structure from a trusted starting position and
+// assert the tags of the elements they expect.
+package asn1
+
+import (
+	stdbits "math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Universal tags of the identifier octets.
+const (
+	TagBoolean           = 0x01
+	TagInteger           = 0x02
+	TagBitString         = 0x03
+	TagOctetString       = 0x04
+	TagNull              = 0x05
+	TagOID               = 0x06
+	TagUTF8String        = 0x0c
+	TagPrintableString   = 0x13
+	TagIA5String         = 0x16
+	TagUTCTime           = 0x17
+	TagGeneralizedTime   = 0x18
+	TagSequence          = 0x30
+	TagSet               = 0x31
+	ClassContextSpecific = 0x80
+	Constructed          = 0x20
+)
+
+// maxHeaderLen is the maximal length of the identifier octet and the length.
+const maxHeaderLen = 4
+
+// Element is a parsed TLV.
+type Element struct {
+	// Tag is the identifier octet.
+	Tag frontend.Variable
+	// Start is the position of the identifier octet.
+	Start frontend.Variable
+	// Offset is the position of the first byte of the contents.
+	Offset frontend.Variable
+	// Length is the number of bytes of the contents.
+	Length frontend.Variable
+	// End is the position after the last byte of the contents.
+	End frontend.Variable
+}
+
+// Parser parses DER elements in a byte slice.
+type Parser struct {
+	api    frontend.API
+	n      int
+	data   *logderivlookup.Table
+	formT  *logderivlookup.Table
+	highT  *logderivlookup.Table
+	nbBits int
+}
+
+// NewParser returns a new parser of the elements in data.
+func NewParser(api frontend.API, data []uints.U8) *Parser {
+	p := &Parser{api: api, n: len(data), nbBits: stdbits.Len(uint(len(data)))}
+	p.data = logderivlookup.New(api)
+	for i := range data {
+		p.data.Insert(data[i].Val)
+	}
+	// the positions of the headers and the contents may be past the end of
+	// the data.
+	for i := 0; i < maxHeaderLen; i++ {
+		p.data.Insert(0)
+	}
+	// formT is 1 for the valid first bytes of a length: the short form and
+	// the long forms on one and two bytes.
+	p.formT = logderivlookup.New(api)
+	p.highT = logderivlookup.New(api)
+	for b := 0; b < 256; b++ {
+		p.formT.Insert(utils.BoolToInt(b < 0x80 || b == 0x81 || b == 0x82))
+		p.highT.Insert(b >> 7)
+	}
+	return p
+}
+
+// Parse parses the element at position pos. It asserts that the length is
+// encoded in minimal form and that the element ends before the end of the
+// data.
+func (p *Parser) Parse(pos frontend.Variable) Element {
+	api := p.api
+	hdr := p.data.Lookup(pos, api.Add(pos, 1), api.Add(pos, 2), api.Add(pos, 3))
+	tag, b1, b2, b3 := hdr[0], hdr[1], hdr[2], hdr[3]
+	api.AssertIsEqual(p.formT.Lookup(b1)[0], 1)
+	long1 := api.IsZero(api.Sub(b1, 0x81))
+	long2 := api.IsZero(api.Sub(b1, 0x82))
+	short := api.Sub(1, long1, long2)
+	// the long forms are only used for lengths which do not fit the shorter
+	// forms.
+	api.AssertIsEqual(api.Mul(long1, api.Sub(1, p.highT.Lookup(b2)[0])), 0)
+	api.AssertIsEqual(api.Mul(long2, api.IsZero(b2)), 0)
+
+	length := api.Add(api.Mul(short, b1), api.Mul(long1, b2), api.Mul(long2, api.Add(api.Mul(b2, 256), b3)))
+	offset := api.Add(pos, 2, long1, api.Mul(long2, 2))
+	e := Element{Tag: tag, Start: pos, Offset: offset, Length: length, End: api.Add(offset, length)}
+	// the element ends before the end of the data.
+	bits.ToBinary(api, api.Sub(p.n, e.End), bits.WithNbDigits(p.nbBits))
+	return e
+}
+
+// At returns the byte at position pos.
+func (p *Parser) At(pos frontend.Variable) frontend.Variable {
+	return p.data.Lookup(pos)[0]
+}
+
+// Expect parses the element at position pos and asserts that its tag is tag.
+func (p *Parser) Expect(pos frontend.Variable, tag int) Element {
+	e := p.Parse(pos)
+	p.api.AssertIsEqual(e.Tag, tag)
+	return e
+}
+
+// Child parses the first element in the contents of the element e.
+func (p *Parser) Child(e Element) Element {
+	return p.Parse(e.Offset)
+}
+
+// Next parses the element following the element e.
+func (p *Parser) Next(e Element) Element {
+	return p.Parse(e.End)
+}
+
+// AssertIsWithin asserts that the element e ends before the end of the
+// element parent.
+func (p *Parser) AssertIsWithin(e, parent Element) {
+	bits.ToBinary(p.api, p.api.Sub(parent.End, e.End), bits.WithNbDigits(p.nbBits))
+}
+
+// Contents returns the contents of the element e, padded with zeros to
+// maxLen bytes. It asserts that the contents have at most maxLen bytes.
+func (p *Parser) Contents(e Element, maxLen int) []uints.U8 {
+	api := p.api
+	inContents := selector.PrefixMask(api, e.Length, maxLen)
+	res := make([]uints.U8, maxLen)
+	for i := range res {
+		// the positions after the end of the contents are replaced by the
+		// position of the first byte to stay in the table.
+		idx := api.Add(e.Offset, api.Mul(inContents[i], i))
+		res[i] = uints.U8{Val: api.Mul(p.data.Lookup(idx)[0], inContents[i])}
+	}
+	return res
+}
+
+// Integer returns the contents of the element e as the big-endian bytes of
+// an unsigned integer, padded on the left with zeros to maxLen bytes. It
+// asserts that e is an integer of at most maxLen bytes, not counting the
+// leading zero byte of the encoding of positive integers with the high bit
+// set, that it is not negative and that it is encoded in minimal form.
+func (p *Parser) Integer(e Element, maxLen int) []uints.U8 {
+	api := p.api
+	api.AssertIsEqual(e.Tag, TagInteger)
+	api.AssertIsDifferent(e.Length, 0)
+	// the first byte of the contents is not negative.
+	first := p.data.Lookup(e.Offset)[0]
+	api.AssertIsEqual(p.highT.Lookup(first)[0], 0)
+	// the leading zero byte is skipped. It is only allowed before a byte with
+	// the high bit set or for the integer zero.
+	skip := api.IsZero(first)
+	second := p.data.Lookup(api.Add(e.Offset, 1))[0]
+	hasSecond := api.Sub(1, api.IsZero(api.Sub(e.Length, 1)))
+	api.AssertIsEqual(api.Mul(skip, hasSecond, api.Sub(1, p.highT.Lookup(second)[0])), 0)
+	length := api.Sub(e.Length, skip)
+	inInteger := selector.PrefixMask(api, length, maxLen)
+	res := make([]uints.U8, maxLen)
+	for i := range res {
+		// the byte i from the right.
+		idx := api.Sub(e.End, api.Mul(inInteger[i], i+1), api.Sub(1, inInteger[i]))
+		res[maxLen-1-i] = uints.U8{Val: api.Mul(p.data.Lookup(idx)[0], inInteger[i])}
+	}
+	return res
+}
+
+// AssertContentsEqual asserts that the contents of the element e are equal to
+// the constant contents.
+func (p *Parser) AssertContentsEqual(e Element, contents []byte) {
+	api := p.api
+	api.AssertIsEqual(e.Length, len(contents))
+	for i := range contents {
+		api.AssertIsEqual(p.data.Lookup(api.Add(e.Offset, i))[0], contents[i])
+	}
+}
+
+// ObjectIdentifier returns the DER encoding of the contents of the object
+// identifier with the given components.
+func ObjectIdentifier(components ...uint64) []byte {
+	if len(components) < 2 {
+		panic("object identifier must have at least two components")
+	}
+	var res []byte
+	for _, c := range append([]uint64{components[0]*40 + components[1]}, components[2:]...) {
+		n := (stdbits.Len64(c) + 6) / 7
+		for j := max(n, 1) - 1; j >= 0; j-- {
+			b := byte(c>>(7*j)) & 0x7f
+			if j > 0 {
+				b |= 0x80
+			}
+			res = append(res, b)
+		}
+	}
+	return res
+}
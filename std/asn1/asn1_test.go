@@ -0,0 +1,146 @@
+package asn1
+
+import (
+	stdasn1 "encoding/asn1"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+func TestObjectIdentifier(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, oid := range []stdasn1.ObjectIdentifier{{1, 2, 840, 10045, 4, 3, 2}, {2, 5, 4, 3}, {1, 2, 840, 113549, 1, 1, 11}, {2, 999, 3}} {
+		der, err := stdasn1.Marshal(oid)
+		assert.NoError(err)
+		components := make([]uint64, len(oid))
+		for i := range oid {
+			components[i] = uint64(oid[i])
+		}
+		assert.Equal(der[2:], ObjectIdentifier(components...), oid.String())
+	}
+}
+
+type parseCircuit struct {
+	Data   [300]uints.U8
+	Tag    frontend.Variable
+	Offset frontend.Variable
+	Length frontend.Variable
+}
+
+func (c *parseCircuit) Define(api frontend.API) error {
+	e := NewParser(api, c.Data[:]).Parse(0)
+	api.AssertIsEqual(e.Tag, c.Tag)
+	api.AssertIsEqual(e.Offset, c.Offset)
+	api.AssertIsEqual(e.Length, c.Length)
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	assert := test.NewAssert(t)
+	testCases := []struct {
+		header         []byte
+		valid          bool
+		offset, length int
+	}{
+		{[]byte{0x04, 0x00}, true, 2, 0},
+		{[]byte{0x04, 0x7f}, true, 2, 127},
+		{[]byte{0x04, 0x81, 0x80}, true, 3, 128},
+		{[]byte{0x04, 0x82, 0x01, 0x00}, true, 4, 256},
+		{[]byte{0x04, 0x82, 0x01, 0x28}, true, 4, 296},
+		// not minimal
+		{[]byte{0x04, 0x81, 0x7f}, false, 3, 127},
+		{[]byte{0x04, 0x82, 0x00, 0x80}, false, 4, 128},
+		// unsupported forms
+		{[]byte{0x04, 0x80}, false, 2, 0},
+		{[]byte{0x04, 0x83, 0x00, 0x00, 0x01}, false, 5, 1},
+		// past the end of the data
+		{[]byte{0x04, 0x82, 0x01, 0x29}, false, 4, 297},
+	}
+	for _, tc := range testCases {
+		var assignment parseCircuit
+		data := make([]byte, len(assignment.Data))
+		copy(data, tc.header)
+		copy(assignment.Data[:], uints.NewU8Array(data))
+		assignment.Tag = tc.header[0]
+		assignment.Offset = tc.offset
+		assignment.Length = tc.length
+		err := test.IsSolved(&parseCircuit{}, &assignment, ecc.BN254.ScalarField())
+		if tc.valid {
+			assert.NoError(err, tc.header)
+		} else {
+			assert.Error(err, tc.header)
+		}
+	}
+}
+
+type integerCircuit struct {
+	Data     [32]uints.U8
+	Integers [3][4]uints.U8
+	Name     [4]uints.U8
+}
+
+func (c *integerCircuit) Define(api frontend.API) error {
+	p := NewParser(api, c.Data[:])
+	seq := p.Expect(0, TagSequence)
+	e := p.Child(seq)
+	for i := range c.Integers {
+		v := p.Integer(e, len(c.Integers[i]))
+		for j := range v {
+			api.AssertIsEqual(v[j].Val, c.Integers[i][j].Val)
+		}
+		e = p.Next(e)
+	}
+	api.AssertIsEqual(e.Tag, TagPrintableString)
+	p.AssertIsWithin(e, seq)
+	name := p.Contents(e, len(c.Name))
+	for j := range name {
+		api.AssertIsEqual(name[j].Val, c.Name[j].Val)
+	}
+	return nil
+}
+
+func TestInteger(t *testing.T) {
+	assert := test.NewAssert(t)
+	type value struct {
+		A, B, C int
+		Name    string `asn1:"printable"`
+	}
+	assign := func(v value) *integerCircuit {
+		var assignment integerCircuit
+		der, err := stdasn1.Marshal(v)
+		assert.NoError(err)
+		data := make([]byte, len(assignment.Data))
+		copy(data, der)
+		copy(assignment.Data[:], uints.NewU8Array(data))
+		for i, x := range []int{v.A, v.B, v.C} {
+			copy(assignment.Integers[i][:], uints.NewU8Array([]byte{byte(x >> 24), byte(x >> 16), byte(x >> 8), byte(x)}))
+		}
+		name := make([]byte, len(assignment.Name))
+		copy(name, v.Name)
+		copy(assignment.Name[:], uints.NewU8Array(name))
+		return &assignment
+	}
+	err := test.IsSolved(&integerCircuit{}, assign(value{0, 0x80, 0x123456, "abc"}), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// negative integers are not supported.
+	err = test.IsSolved(&integerCircuit{}, assign(value{-1, 0x80, 0x123456, "abc"}), ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// the integers are not encoded in minimal form.
+	for _, first := range [][]byte{{0x02, 0x02, 0x00, 0x01}, {0x02, 0x02, 0x00, 0x00}, {0x02, 0x02, 0xff, 0x80}} {
+		valid := assign(value{1, 0x80, 0x123456, "abc"})
+		der, err := stdasn1.Marshal(value{1, 0x80, 0x123456, "abc"})
+		assert.NoError(err)
+		// replace the integer 02 01 01 by the non-minimal encoding.
+		malformed := append(append([]byte{der[0], der[1] + byte(len(first)-3)}, first...), der[5:]...)
+		data := make([]byte, len(valid.Data))
+		copy(data, malformed)
+		copy(valid.Data[:], uints.NewU8Array(data))
+		err = test.IsSolved(&integerCircuit{}, valid, ecc.BN254.ScalarField())
+		assert.Error(err, first)
+	}
+}
@@ -0,0 +1,257 @@
+// Package aes implements the AES block cipher and the CTR and GCM modes of
+// operation in circuit.
+//
+// The S-box and its products by 2 and 3 in GF(2^8) are lookup tables, and the
+// XORs of the bytes are performed with the lookup tables of [uints.BinaryField].
+// A round of AES costs 48 table lookups for the S-box and 64 XOR lookups.
+//
+// The lengths of the plaintexts, the ciphertexts and the additional data of
+// the modes of operation are witnesses bounded by the lengths of the slices.
+//
+// See [FIPS 197] for the block cipher and [NIST SP 800-38D] for GCM.
+//
+// [FIPS 197]: https://csrc.nist.gov/pubs/fips/197/final
+// [NIST SP 800-38D]: https://csrc.nist.gov/pubs/sp/800/38/d/final
+package aes
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// BlockSize is the AES block size in bytes.
+const BlockSize = 16
+
+// AES performs the AES operations in circuit. The lookup tables are shared by
+// all the ciphers created from the same instance.
+type AES struct {
+	api frontend.API
+	bf  *uints.BinaryField[uints.U32]
+	// sbox, sbox2 and sbox3 are S(b), 2·S(b) and 3·S(b) in GF(2^8).
+	sbox, sbox2, sbox3 *logderivlookup.Table
+}
+
+// New returns a new AES instance.
+func New(api frontend.API) (*AES, error) {
+	bf, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, fmt.Errorf("new binary field: %w", err)
+	}
+	a := &AES{
+		api:   api,
+		bf:    bf,
+		sbox:  logderivlookup.New(api),
+		sbox2: logderivlookup.New(api),
+		sbox3: logderivlookup.New(api),
+	}
+	for b := 0; b < 256; b++ {
+		s := sbox[b]
+		a.sbox.Insert(s)
+		a.sbox2.Insert(xtime(s))
+		a.sbox3.Insert(xtime(s) ^ s)
+	}
+	return a, nil
+}
+
+// Cipher is an AES cipher with an expanded key.
+type Cipher struct {
+	a *AES
+	// roundKeys are the words of the round keys.
+	roundKeys []uints.U32
+}
+
+// NewCipher expands the key, which must have 16, 24 or 32 bytes for
+// AES-128, AES-192 and AES-256 respectively.
+func (a *AES) NewCipher(key []uints.U8) (*Cipher, error) {
+	nk := len(key) / 4
+	if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+		return nil, fmt.Errorf("invalid key size %d", len(key))
+	}
+	key = a.checkBytes(key)
+	nr := nk + 6
+	w := make([]uints.U32, 4*(nr+1))
+	for i := 0; i < nk; i++ {
+		copy(w[i][:], key[4*i:4*i+4])
+	}
+	rcon := byte(1)
+	for i := nk; i < len(w); i++ {
+		t := w[i-1]
+		switch {
+		case i%nk == 0:
+			t = a.subWord(uints.U32{t[1], t[2], t[3], t[0]})
+			t = a.bf.Xor(t, uints.U32{uints.NewU8(rcon), uints.NewU8(0), uints.NewU8(0), uints.NewU8(0)})
+			rcon = xtime(rcon)
+		case nk > 6 && i%nk == 4:
+			t = a.subWord(t)
+		}
+		w[i] = a.bf.Xor(w[i-nk], t)
+	}
+	return &Cipher{a: a, roundKeys: w}, nil
+}
+
+// Encrypt encrypts the block src.
+func (c *Cipher) Encrypt(src [BlockSize]uints.U8) [BlockSize]uints.U8 {
+	a := c.a
+	nr := len(c.roundKeys)/4 - 1
+	var state [4]uints.U32
+	for j := range state {
+		copy(state[j][:], src[4*j:4*j+4])
+		state[j] = a.bf.Xor(state[j], c.roundKeys[j])
+	}
+	for r := 1; r <= nr; r++ {
+		// the S-box values after ShiftRows, where s[j][i] is the byte of the
+		// row i of the column j.
+		var s, s2, s3 [4][4]uints.U8
+		for j := 0; j < 4; j++ {
+			for i := 0; i < 4; i++ {
+				v := state[(j+i)%4][i].Val
+				s[j][i] = uints.U8{Val: a.sbox.Lookup(v)[0]}
+				if r < nr {
+					s2[j][i] = uints.U8{Val: a.sbox2.Lookup(v)[0]}
+					s3[j][i] = uints.U8{Val: a.sbox3.Lookup(v)[0]}
+				}
+			}
+		}
+		for j := 0; j < 4; j++ {
+			k := c.roundKeys[4*r+j]
+			if r == nr {
+				state[j] = a.bf.Xor(uints.U32(s[j]), k)
+				continue
+			}
+			// MixColumns: the row i is 2·s_i ⊕ 3·s_{i+1} ⊕ s_{i+2} ⊕ s_{i+3}.
+			var m2, m3, m4, m5 uints.U32
+			for i := 0; i < 4; i++ {
+				m2[i] = s2[j][i]
+				m3[i] = s3[j][(i+1)%4]
+				m4[i] = s[j][(i+2)%4]
+				m5[i] = s[j][(i+3)%4]
+			}
+			state[j] = a.bf.Xor(m2, m3, m4, m5, k)
+		}
+	}
+	var res [BlockSize]uints.U8
+	for j := range state {
+		copy(res[4*j:4*j+4], state[j][:])
+	}
+	return res
+}
+
+// CTR encrypts or decrypts the first length bytes of src in counter mode with
+// the initial counter block iv, which is incremented as a 128-bit big-endian
+// integer. The bytes of the result after length are zero.
+func (c *Cipher) CTR(iv [BlockSize]uints.U8, src []uints.U8, length frontend.Variable) []uints.U8 {
+	iv = [BlockSize]uints.U8(c.a.checkBytes(iv[:]))
+	return c.ctr(iv, BlockSize, 0, c.a.checkBytes(src), length)
+}
+
+// ctr encrypts the first length bytes of src in counter mode where the block
+// i is encrypted with the counter block iv incremented by first+i modulo
+// 2^(8·width) in its last width bytes.
+func (c *Cipher) ctr(iv [BlockSize]uints.U8, width, first int, src []uints.U8, length frontend.Variable) []uints.U8 {
+	api := c.a.api
+	nbBlocks := (len(src) + BlockSize - 1) / BlockSize
+	keystream := make([]uints.U8, 0, nbBlocks*BlockSize)
+	for i := 0; i < nbBlocks; i++ {
+		block := iv
+		copy(block[BlockSize-width:], increment(api, iv[BlockSize-width:], first+i))
+		ks := c.Encrypt(block)
+		keystream = append(keystream, ks[:]...)
+	}
+	res := c.a.xor(src, keystream[:len(src)])
+	active := selector.PrefixMask(api, length, len(src))
+	for i := range res {
+		res[i] = uints.U8{Val: api.Mul(res[i].Val, active[i])}
+	}
+	return res
+}
+
+func (a *AES) subWord(w uints.U32) uints.U32 {
+	var res uints.U32
+	for i := range w {
+		res[i] = uints.U8{Val: a.sbox.Lookup(w[i].Val)[0]}
+	}
+	return res
+}
+
+// xor returns the bytewise XOR of x and y, which have the same length.
+func (a *AES) xor(x, y []uints.U8) []uints.U8 {
+	res := make([]uints.U8, len(x))
+	for i := 0; i < len(x); i += 4 {
+		var u, v uints.U32
+		for j := 0; j < 4; j++ {
+			u[j], v[j] = uints.NewU8(0), uints.NewU8(0)
+			if i+j < len(x) {
+				u[j], v[j] = x[i+j], y[i+j]
+			}
+		}
+		w := a.bf.Xor(u, v)
+		copy(res[i:], w[:min(4, len(x)-i)])
+	}
+	return res
+}
+
+// checkBytes returns the bytes of src, range checked.
+func (a *AES) checkBytes(src []uints.U8) []uints.U8 {
+	res := make([]uints.U8, len(src))
+	for i := range src {
+		res[i] = a.bf.ByteValueOf(src[i].Val)
+	}
+	return res
+}
+
+// increment returns the big-endian integer given by the bytes src incremented
+// by v, modulo 2^(8·len(src)).
+func increment(api frontend.API, src []uints.U8, v int) []uints.U8 {
+	res := make([]uints.U8, len(src))
+	var carry frontend.Variable = v
+	// the limbs of 8 bytes are processed from the least significant.
+	for end := len(src); end > 0; end -= 8 {
+		start := max(0, end-8)
+		var limb frontend.Variable = 0
+		for i := start; i < end; i++ {
+			limb = api.Add(api.Mul(limb, 256), src[i].Val)
+		}
+		nbBits := 8 * (end - start)
+		bs := bits.ToBinary(api, api.Add(limb, carry), bits.WithNbDigits(nbBits+1))
+		carry = bs[nbBits]
+		for i := start; i < end; i++ {
+			k := 8 * (end - 1 - i)
+			res[i] = uints.U8{Val: bits.FromBinary(api, bs[k:k+8], bits.WithUnconstrainedInputs())}
+		}
+	}
+	return res
+}
+
+// xtime returns the product of b by x in GF(2^8).
+func xtime(b byte) byte {
+	res := b << 1
+	if b&0x80 != 0 {
+		res ^= 0x1b
+	}
+	return res
+}
+
+// sbox is the AES S-box.
+var sbox = [256]byte{
+	0x63, 0x7c, 0x77, 0x7b, 0xf2, 0x6b, 0x6f, 0xc5, 0x30, 0x01, 0x67, 0x2b, 0xfe, 0xd7, 0xab, 0x76,
+	0xca, 0x82, 0xc9, 0x7d, 0xfa, 0x59, 0x47, 0xf0, 0xad, 0xd4, 0xa2, 0xaf, 0x9c, 0xa4, 0x72, 0xc0,
+	0xb7, 0xfd, 0x93, 0x26, 0x36, 0x3f, 0xf7, 0xcc, 0x34, 0xa5, 0xe5, 0xf1, 0x71, 0xd8, 0x31, 0x15,
+	0x04, 0xc7, 0x23, 0xc3, 0x18, 0x96, 0x05, 0x9a, 0x07, 0x12, 0x80, 0xe2, 0xeb, 0x27, 0xb2, 0x75,
+	0x09, 0x83, 0x2c, 0x1a, 0x1b, 0x6e, 0x5a, 0xa0, 0x52, 0x3b, 0xd6, 0xb3, 0x29, 0xe3, 0x2f, 0x84,
+	0x53, 0xd1, 0x00, 0xed, 0x20, 0xfc, 0xb1, 0x5b, 0x6a, 0xcb, 0xbe, 0x39, 0x4a, 0x4c, 0x58, 0xcf,
+	0xd0, 0xef, 0xaa, 0xfb, 0x43, 0x4d, 0x33, 0x85, 0x45, 0xf9, 0x02, 0x7f, 0x50, 0x3c, 0x9f, 0xa8,
+	0x51, 0xa3, 0x40, 0x8f, 0x92, 0x9d, 0x38, 0xf5, 0xbc, 0xb6, 0xda, 0x21, 0x10, 0xff, 0xf3, 0xd2,
+	0xcd, 0x0c, 0x13, 0xec, 0x5f, 0x97, 0x44, 0x17, 0xc4, 0xa7, 0x7e, 0x3d, 0x64, 0x5d, 0x19, 0x73,
+	0x60, 0x81, 0x4f, 0xdc, 0x22, 0x2a, 0x90, 0x88, 0x46, 0xee, 0xb8, 0x14, 0xde, 0x5e, 0x0b, 0xdb,
+	0xe0, 0x32, 0x3a, 0x0a, 0x49, 0x06, 0x24, 0x5c, 0xc2, 0xd3, 0xac, 0x62, 0x91, 0x95, 0xe4, 0x79,
+	0xe7, 0xc8, 0x37, 0x6d, 0x8d, 0xd5, 0x4e, 0xa9, 0x6c, 0x56, 0xf4, 0xea, 0x65, 0x7a, 0xae, 0x08,
+	0xba, 0x78, 0x25, 0x2e, 0x1c, 0xa6, 0xb4, 0xc6, 0xe8, 0xdd, 0x74, 0x1f, 0x4b, 0xbd, 0x8b, 0x8a,
+	0x70, 0x3e, 0xb5, 0x66, 0x48, 0x03, 0xf6, 0x0e, 0x61, 0x35, 0x57, 0xb9, 0x86, 0xc1, 0x1d, 0x9e,
+	0xe1, 0xf8, 0x98, 0x11, 0x69, 0xd9, 0x8e, 0x94, 0x9b, 0x1e, 0x87, 0xe9, 0xce, 0x55, 0x28, 0xdf,
+	0x8c, 0xa1, 0x89, 0x0d, 0xbf, 0xe6, 0x42, 0x68, 0x41, 0x99, 0x2d, 0x0f, 0xb0, 0x54, 0xbb, 0x16,
+}
@@ -0,0 +1,198 @@
+package aes
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type encryptCircuit struct {
+	Key      []uints.U8
+	In       [BlockSize]uints.U8
+	Expected [BlockSize]uints.U8
+}
+
+func (c *encryptCircuit) Define(api frontend.API) error {
+	a, err := New(api)
+	if err != nil {
+		return err
+	}
+	ciph, err := a.NewCipher(c.Key)
+	if err != nil {
+		return err
+	}
+	res := ciph.Encrypt(c.In)
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Expected[i].Val)
+	}
+	return nil
+}
+
+func TestEncrypt(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, keyLen := range []int{16, 24, 32} {
+		key := make([]byte, keyLen)
+		in := make([]byte, BlockSize)
+		rand.Read(key)
+		rand.Read(in)
+		block, err := aes.NewCipher(key)
+		assert.NoError(err)
+		expected := make([]byte, BlockSize)
+		block.Encrypt(expected, in)
+
+		assert.Run(func(assert *test.Assert) {
+			circuit := encryptCircuit{Key: make([]uints.U8, keyLen)}
+			witness := encryptCircuit{
+				Key:      uints.NewU8Array(key),
+				In:       [BlockSize]uints.U8(uints.NewU8Array(in)),
+				Expected: [BlockSize]uints.U8(uints.NewU8Array(expected)),
+			}
+			assert.CheckCircuit(&circuit, test.WithValidAssignment(&witness), test.WithCurves(ecc.BN254), test.NoProverChecks())
+		}, fmt.Sprintf("AES-%d", 8*keyLen))
+	}
+}
+
+type ctrCircuit struct {
+	Key      [16]uints.U8
+	IV       [BlockSize]uints.U8
+	In       []uints.U8
+	Length   frontend.Variable
+	Expected []uints.U8
+}
+
+func (c *ctrCircuit) Define(api frontend.API) error {
+	a, err := New(api)
+	if err != nil {
+		return err
+	}
+	ciph, err := a.NewCipher(c.Key[:])
+	if err != nil {
+		return err
+	}
+	res := ciph.CTR(c.IV, c.In, c.Length)
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Expected[i].Val)
+	}
+	return nil
+}
+
+func TestCTR(t *testing.T) {
+	assert := test.NewAssert(t)
+	const maxLen = 40
+	key := make([]byte, 16)
+	rand.Read(key)
+	block, err := aes.NewCipher(key)
+	assert.NoError(err)
+	// the counter overflows the low 64 bits.
+	iv := bytes.Repeat([]byte{0xff}, BlockSize)
+	iv[0] = 0x12
+	for _, length := range []int{0, 1, 16, 33, maxLen} {
+		in := make([]byte, maxLen)
+		rand.Read(in[:length])
+		expected := make([]byte, maxLen)
+		cipher.NewCTR(block, iv).XORKeyStream(expected[:length], in[:length])
+
+		circuit := ctrCircuit{In: make([]uints.U8, maxLen), Expected: make([]uints.U8, maxLen)}
+		witness := ctrCircuit{
+			Key:      [16]uints.U8(uints.NewU8Array(key)),
+			IV:       [BlockSize]uints.U8(uints.NewU8Array(iv)),
+			In:       uints.NewU8Array(in),
+			Length:   length,
+			Expected: uints.NewU8Array(expected),
+		}
+		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, length)
+	}
+}
+
+type gcmCircuit struct {
+	Key        [16]uints.U8
+	Nonce      [NonceSize]uints.U8
+	Plaintext  []uints.U8
+	Length     frontend.Variable
+	AAD        []uints.U8
+	AADLength  frontend.Variable
+	Ciphertext []uints.U8
+	Tag        [TagSize]uints.U8
+}
+
+func (c *gcmCircuit) Define(api frontend.API) error {
+	a, err := New(api)
+	if err != nil {
+		return err
+	}
+	ciph, err := a.NewCipher(c.Key[:])
+	if err != nil {
+		return err
+	}
+	gcm := ciph.NewGCM()
+	ciphertext, tag := gcm.Seal(c.Nonce, c.Plaintext, c.Length, c.AAD, c.AADLength)
+	for i := range ciphertext {
+		api.AssertIsEqual(ciphertext[i].Val, c.Ciphertext[i].Val)
+	}
+	for i := range tag {
+		api.AssertIsEqual(tag[i].Val, c.Tag[i].Val)
+	}
+	plaintext := gcm.Open(c.Nonce, c.Ciphertext, c.Length, c.AAD, c.AADLength, c.Tag)
+	for i := range plaintext {
+		api.AssertIsEqual(plaintext[i].Val, c.Plaintext[i].Val)
+	}
+	return nil
+}
+
+func TestGCM(t *testing.T) {
+	assert := test.NewAssert(t)
+	const maxLen, maxAADLen = 40, 20
+	key := make([]byte, 16)
+	nonce := make([]byte, NonceSize)
+	rand.Read(key)
+	rand.Read(nonce)
+	block, err := aes.NewCipher(key)
+	assert.NoError(err)
+	aead, err := cipher.NewGCM(block)
+	assert.NoError(err)
+	for _, tc := range []struct{ length, aadLength int }{
+		{0, 0}, {1, 13}, {16, 16}, {37, 0}, {maxLen, maxAADLen},
+	} {
+		plaintext := make([]byte, maxLen)
+		aad := make([]byte, maxAADLen)
+		rand.Read(plaintext[:tc.length])
+		rand.Read(aad[:tc.aadLength])
+		sealed := aead.Seal(nil, nonce, plaintext[:tc.length], aad[:tc.aadLength])
+		ciphertext := make([]byte, maxLen)
+		copy(ciphertext, sealed[:tc.length])
+		tag := sealed[tc.length:]
+
+		circuit := gcmCircuit{
+			Plaintext:  make([]uints.U8, maxLen),
+			AAD:        make([]uints.U8, maxAADLen),
+			Ciphertext: make([]uints.U8, maxLen),
+		}
+		witness := func(tag []byte) *gcmCircuit {
+			return &gcmCircuit{
+				Key:        [16]uints.U8(uints.NewU8Array(key)),
+				Nonce:      [NonceSize]uints.U8(uints.NewU8Array(nonce)),
+				Plaintext:  uints.NewU8Array(plaintext),
+				Length:     tc.length,
+				AAD:        uints.NewU8Array(aad),
+				AADLength:  tc.aadLength,
+				Ciphertext: uints.NewU8Array(ciphertext),
+				Tag:        [TagSize]uints.U8(uints.NewU8Array(tag)),
+			}
+		}
+		err := test.IsSolved(&circuit, witness(tag), ecc.BN254.ScalarField())
+		assert.NoError(err, tc)
+
+		tag[0] ^= 1
+		err = test.IsSolved(&circuit, witness(tag), ecc.BN254.ScalarField())
+		assert.Error(err, tc)
+	}
+}
@@ -0,0 +1,210 @@
+package aes
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+const (
+	// NonceSize is the size of the nonces of GCM in bytes. Only the standard
+	// nonces of 12 bytes are supported.
+	NonceSize = 12
+	// TagSize is the size of the authentication tags of GCM in bytes.
+	TagSize = 16
+)
+
+// GCM is the Galois/Counter Mode of an AES cipher.
+//
+// The elements of GF(2^128) are represented by the bits of their
+// coefficients, where the coefficient of x^i is the bit 7-i%8 of the byte
+// i/8. As the multiplications in GHASH are all by the same element H, they are
+// linear maps computed from the products of H by the powers of x.
+type GCM struct {
+	c *Cipher
+	// hx are the chunks of the packed coefficients of H·x^i. The coefficients
+	// are in slots of 8 bits so that sums of 128 of them do not overflow.
+	hx [128][]frontend.Variable
+	// slots is the number of coefficients per chunk.
+	slots int
+}
+
+// NewGCM returns the GCM mode of the cipher.
+func (c *Cipher) NewGCM() *GCM {
+	api := c.a.api
+	g := &GCM{c: c, slots: (api.Compiler().FieldBitLen() - 1) / 8}
+	var zero [BlockSize]uints.U8
+	for i := range zero {
+		zero[i] = uints.NewU8(0)
+	}
+	hk := c.Encrypt(zero)
+	h := g.toCoefficients(hk[:])
+	for i := range g.hx {
+		g.hx[i] = g.pack(h)
+		// multiplication by x modulo x^128 + x^7 + x^2 + x + 1.
+		o := h[127]
+		next := make([]frontend.Variable, 128)
+		copy(next[1:], h[:127])
+		next[0] = o
+		for _, k := range []int{1, 2, 7} {
+			next[k] = api.Xor(next[k], o)
+		}
+		h = next
+	}
+	return g
+}
+
+// Seal encrypts and authenticates the first length bytes of plaintext and
+// authenticates the first aadLength bytes of aad. It returns the ciphertext,
+// padded with zeros to the length of plaintext, and the authentication tag.
+func (g *GCM) Seal(nonce [NonceSize]uints.U8, plaintext []uints.U8, length frontend.Variable, aad []uints.U8, aadLength frontend.Variable) ([]uints.U8, [TagSize]uints.U8) {
+	j0 := g.counter(nonce)
+	ciphertext := g.c.ctr(j0, 4, 1, g.c.a.checkBytes(plaintext), length)
+	return ciphertext, g.tag(j0, ciphertext, length, aad, aadLength)
+}
+
+// Open decrypts the first length bytes of ciphertext and asserts that tag is
+// the authentication tag of the ciphertext and of the first aadLength bytes of
+// aad. It returns the plaintext, padded with zeros to the length of
+// ciphertext.
+func (g *GCM) Open(nonce [NonceSize]uints.U8, ciphertext []uints.U8, length frontend.Variable, aad []uints.U8, aadLength frontend.Variable, tag [TagSize]uints.U8) []uints.U8 {
+	api := g.c.a.api
+	j0 := g.counter(nonce)
+	ciphertext = g.c.a.checkBytes(ciphertext)
+	plaintext := g.c.ctr(j0, 4, 1, ciphertext, length)
+	// the ciphertext is authenticated without the bytes after the end.
+	active := selector.PrefixMask(api, length, len(ciphertext))
+	masked := make([]uints.U8, len(ciphertext))
+	for i := range ciphertext {
+		masked[i] = uints.U8{Val: api.Mul(ciphertext[i].Val, active[i])}
+	}
+	expected := g.tag(j0, masked, length, aad, aadLength)
+	for i := range tag {
+		api.AssertIsEqual(tag[i].Val, expected[i].Val)
+	}
+	return plaintext
+}
+
+// counter returns the pre-counter block J0 = nonce || 0^31 || 1.
+func (g *GCM) counter(nonce [NonceSize]uints.U8) [BlockSize]uints.U8 {
+	var j0 [BlockSize]uints.U8
+	copy(j0[:], g.c.a.checkBytes(nonce[:]))
+	for i := NonceSize; i < BlockSize-1; i++ {
+		j0[i] = uints.NewU8(0)
+	}
+	j0[BlockSize-1] = uints.NewU8(1)
+	return j0
+}
+
+// tag returns the authentication tag of the ciphertext, whose bytes after
+// length are zero, and of the first aadLength bytes of aad.
+func (g *GCM) tag(j0 [BlockSize]uints.U8, ciphertext []uints.U8, length frontend.Variable, aad []uints.U8, aadLength frontend.Variable) [TagSize]uints.U8 {
+	api := g.c.a.api
+	aad = g.c.a.checkBytes(aad)
+	active := selector.PrefixMask(api, aadLength, len(aad))
+	for i := range aad {
+		aad[i] = uints.U8{Val: api.Mul(aad[i].Val, active[i])}
+	}
+	y := make([]frontend.Variable, 128)
+	for i := range y {
+		y[i] = 0
+	}
+	y = g.ghash(y, aad, aadLength)
+	y = g.ghash(y, ciphertext, length)
+	// the lengths in bits as 64-bit big-endian integers.
+	x := make([]frontend.Variable, 0, 128)
+	for _, l := range []frontend.Variable{aadLength, length} {
+		bs := bits.ToBinary(api, api.Mul(l, 8), bits.WithNbDigits(64))
+		for k := 63; k >= 0; k-- {
+			x = append(x, bs[k])
+		}
+	}
+	y = g.mul(xorBits(api, y, x))
+
+	s := make([]uints.U8, BlockSize)
+	for i := range s {
+		bs := make([]frontend.Variable, 8)
+		for k := range bs {
+			bs[k] = y[8*i+7-k]
+		}
+		s[i] = uints.U8{Val: bits.FromBinary(api, bs, bits.WithUnconstrainedInputs())}
+	}
+	ek := g.c.Encrypt(j0)
+	return [TagSize]uints.U8(g.c.a.xor(ek[:], s))
+}
+
+// ghash absorbs in y the blocks of data which start before length. The bytes
+// of data after length are zero, so that the last block is padded with zeros.
+func (g *GCM) ghash(y []frontend.Variable, data []uints.U8, length frontend.Variable) []frontend.Variable {
+	api := g.c.a.api
+	nbBlocks := (len(data) + BlockSize - 1) / BlockSize
+	inBlocks := selector.PrefixMask(api, length, nbBlocks*BlockSize)
+	for i := 0; i < nbBlocks; i++ {
+		block := make([]uints.U8, BlockSize)
+		for j := range block {
+			block[j] = uints.NewU8(0)
+		}
+		copy(block, data[i*BlockSize:])
+		next := g.mul(xorBits(api, y, g.toCoefficients(block)))
+		for k := range y {
+			y[k] = api.Select(inBlocks[i*BlockSize], next[k], y[k])
+		}
+	}
+	return y
+}
+
+// mul returns the coefficients of y·H.
+func (g *GCM) mul(y []frontend.Variable) []frontend.Variable {
+	api := g.c.a.api
+	res := make([]frontend.Variable, 0, 128)
+	for k := range g.hx[0] {
+		var acc frontend.Variable = 0
+		for i := range y {
+			acc = api.Add(acc, api.Mul(y[i], g.hx[i][k]))
+		}
+		nbSlots := min(g.slots, 128-k*g.slots)
+		bs := bits.ToBinary(api, acc, bits.WithNbDigits(8*nbSlots))
+		// the coefficient is the parity of the sum in the slot.
+		for j := 0; j < nbSlots; j++ {
+			res = append(res, bs[8*j])
+		}
+	}
+	return res
+}
+
+// pack returns the coefficients c packed in chunks of slots of 8 bits.
+func (g *GCM) pack(c []frontend.Variable) []frontend.Variable {
+	api := g.c.a.api
+	var res []frontend.Variable
+	for start := 0; start < len(c); start += g.slots {
+		var acc frontend.Variable = 0
+		for j := min(start+g.slots, len(c)) - 1; j >= start; j-- {
+			acc = api.Add(api.Mul(acc, 256), c[j])
+		}
+		res = append(res, acc)
+	}
+	return res
+}
+
+// toCoefficients returns the coefficients of the element of GF(2^128) given
+// by the bytes of a block.
+func (g *GCM) toCoefficients(block []uints.U8) []frontend.Variable {
+	api := g.c.a.api
+	res := make([]frontend.Variable, 0, 128)
+	for i := range block {
+		bs := bits.ToBinary(api, block[i].Val, bits.WithNbDigits(8))
+		for k := 7; k >= 0; k-- {
+			res = append(res, bs[k])
+		}
+	}
+	return res
+}
+
+func xorBits(api frontend.API, a, b []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(a))
+	for i := range a {
+		res[i] = api.Xor(a[i], b[i])
+	}
+	return res
+}
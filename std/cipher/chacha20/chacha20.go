@@ -0,0 +1,134 @@
+// Package chacha20 implements the ChaCha20 stream cipher in circuit as
+// specified by RFC 8439.
+//
+// The words of the state are [uints.U32] and the quarter round uses the
+// addition, XOR and rotation of [uints.BinaryField]. The length of the input is
+// a witness bounded by the length of the slice.
+package chacha20
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+const (
+	// KeySize is the size of the key in bytes.
+	KeySize = 32
+	// NonceSize is the size of the nonce in bytes.
+	NonceSize = 12
+	// BlockSize is the size of a block of the key stream in bytes.
+	BlockSize = 64
+)
+
+// constants are the first words of the state, "expand 32-byte k".
+var constants = [4]uint32{0x61707865, 0x3320646e, 0x79622d32, 0x6b206574}
+
+// ChaCha20 performs the ChaCha20 operations in circuit.
+type ChaCha20 struct {
+	api frontend.API
+	bf  *uints.BinaryField[uints.U32]
+}
+
+// New returns a new ChaCha20 instance.
+func New(api frontend.API) (*ChaCha20, error) {
+	bf, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, fmt.Errorf("new binary field: %w", err)
+	}
+	return &ChaCha20{api: api, bf: bf}, nil
+}
+
+// Block returns the block of the key stream for the key, the block counter and
+// the nonce. The key and the nonce must be range checked, for instance with
+// [ChaCha20.XORKeyStream].
+func (c *ChaCha20) Block(key [KeySize]uints.U8, counter uint32, nonce [NonceSize]uints.U8) [BlockSize]uints.U8 {
+	bf := c.bf
+	var init [16]uints.U32
+	for i := range constants {
+		init[i] = uints.NewU32(constants[i])
+	}
+	for i := 0; i < 8; i++ {
+		init[4+i] = bf.PackLSB(key[4*i : 4*i+4]...)
+	}
+	init[12] = uints.NewU32(counter)
+	for i := 0; i < 3; i++ {
+		init[13+i] = bf.PackLSB(nonce[4*i : 4*i+4]...)
+	}
+	x := init
+	for i := 0; i < 10; i++ {
+		// the column round.
+		c.quarterRound(&x, 0, 4, 8, 12)
+		c.quarterRound(&x, 1, 5, 9, 13)
+		c.quarterRound(&x, 2, 6, 10, 14)
+		c.quarterRound(&x, 3, 7, 11, 15)
+		// the diagonal round.
+		c.quarterRound(&x, 0, 5, 10, 15)
+		c.quarterRound(&x, 1, 6, 11, 12)
+		c.quarterRound(&x, 2, 7, 8, 13)
+		c.quarterRound(&x, 3, 4, 9, 14)
+	}
+	var res [BlockSize]uints.U8
+	for i := range x {
+		copy(res[4*i:4*i+4], bf.UnpackLSB(bf.Add(x[i], init[i])))
+	}
+	return res
+}
+
+// XORKeyStream encrypts or decrypts the first length bytes of src with the key
+// stream for the key and the nonce, starting at the block counter. The bytes
+// of the result after length are zero. It returns an error if the block
+// counter overflows.
+func (c *ChaCha20) XORKeyStream(key [KeySize]uints.U8, nonce [NonceSize]uints.U8, counter uint32, src []uints.U8, length frontend.Variable) ([]uints.U8, error) {
+	api := c.api
+	nbBlocks := (len(src) + BlockSize - 1) / BlockSize
+	if uint64(counter)+uint64(nbBlocks) > math.MaxUint32+1 {
+		return nil, fmt.Errorf("block counter overflow")
+	}
+	key = [KeySize]uints.U8(c.checkBytes(key[:]))
+	nonce = [NonceSize]uints.U8(c.checkBytes(nonce[:]))
+	src = c.checkBytes(src)
+	active := selector.PrefixMask(api, length, len(src))
+	res := make([]uints.U8, len(src))
+	for i := 0; i < nbBlocks; i++ {
+		ks := c.Block(key, counter+uint32(i), nonce)
+		for j := i * BlockSize; j < len(src) && j < (i+1)*BlockSize; j += 4 {
+			var u, v uints.U32
+			for k := range u {
+				u[k], v[k] = uints.NewU8(0), ks[j-i*BlockSize+k]
+				if j+k < len(src) {
+					u[k] = src[j+k]
+				}
+			}
+			w := c.bf.Xor(u, v)
+			for k := 0; k < 4 && j+k < len(src); k++ {
+				res[j+k] = uints.U8{Val: api.Mul(w[k].Val, active[j+k])}
+			}
+		}
+	}
+	return res, nil
+}
+
+func (c *ChaCha20) quarterRound(x *[16]uints.U32, a, b, cc, d int) {
+	bf := c.bf
+	x[a] = bf.Add(x[a], x[b])
+	x[d] = bf.Lrot(bf.Xor(x[d], x[a]), 16)
+	x[cc] = bf.Add(x[cc], x[d])
+	x[b] = bf.Lrot(bf.Xor(x[b], x[cc]), 12)
+	x[a] = bf.Add(x[a], x[b])
+	x[d] = bf.Lrot(bf.Xor(x[d], x[a]), 8)
+	x[cc] = bf.Add(x[cc], x[d])
+	x[b] = bf.Lrot(bf.Xor(x[b], x[cc]), 7)
+}
+
+// checkBytes returns the bytes of src, range checked.
+func (c *ChaCha20) checkBytes(src []uints.U8) []uints.U8 {
+	res := make([]uints.U8, len(src))
+	for i := range src {
+		res[i] = c.bf.ByteValueOf(src[i].Val)
+	}
+	return res
+}
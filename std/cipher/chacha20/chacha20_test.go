@@ -0,0 +1,65 @@
+package chacha20
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/chacha20"
+)
+
+type xorKeyStreamCircuit struct {
+	Key      [KeySize]uints.U8
+	Nonce    [NonceSize]uints.U8
+	In       []uints.U8
+	Length   frontend.Variable
+	Expected []uints.U8
+	counter  uint32
+}
+
+func (c *xorKeyStreamCircuit) Define(api frontend.API) error {
+	cc, err := New(api)
+	if err != nil {
+		return err
+	}
+	res, err := cc.XORKeyStream(c.Key, c.Nonce, c.counter, c.In, c.Length)
+	if err != nil {
+		return err
+	}
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Expected[i].Val)
+	}
+	return nil
+}
+
+func TestXORKeyStream(t *testing.T) {
+	assert := test.NewAssert(t)
+	const maxLen, counter = 100, 7
+	key := make([]byte, KeySize)
+	nonce := make([]byte, NonceSize)
+	rand.Read(key)
+	rand.Read(nonce)
+	for _, length := range []int{0, 1, 64, 65, maxLen} {
+		in := make([]byte, maxLen)
+		rand.Read(in[:length])
+		expected := make([]byte, maxLen)
+		s, err := chacha20.NewUnauthenticatedCipher(key, nonce)
+		assert.NoError(err)
+		s.SetCounter(counter)
+		s.XORKeyStream(expected[:length], in[:length])
+
+		circuit := xorKeyStreamCircuit{In: make([]uints.U8, maxLen), Expected: make([]uints.U8, maxLen), counter: counter}
+		witness := xorKeyStreamCircuit{
+			Key:      [KeySize]uints.U8(uints.NewU8Array(key)),
+			Nonce:    [NonceSize]uints.U8(uints.NewU8Array(nonce)),
+			In:       uints.NewU8Array(in),
+			Length:   length,
+			Expected: uints.NewU8Array(expected),
+		}
+		err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, length)
+	}
+}
@@ -0,0 +1,108 @@
+// Package chacha20poly1305 implements the ChaCha20-Poly1305 AEAD in circuit as
+// specified by RFC 8439.
+//
+// The lengths of the plaintexts, the ciphertexts and the additional data are
+// witnesses bounded by the lengths of the slices, which allows for instance to
+// decrypt TLS records of variable lengths.
+package chacha20poly1305
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/cipher/chacha20"
+	"github.com/consensys/gnark/std/mac/poly1305"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// KeySize is the size of the key in bytes.
+	KeySize = chacha20.KeySize
+	// NonceSize is the size of the nonce in bytes.
+	NonceSize = chacha20.NonceSize
+	// TagSize is the size of the authentication tag in bytes.
+	TagSize = poly1305.TagSize
+)
+
+// AEAD performs the ChaCha20-Poly1305 operations in circuit.
+type AEAD struct {
+	api    frontend.API
+	cipher *chacha20.ChaCha20
+	mac    *poly1305.Poly1305
+}
+
+// New returns a new ChaCha20-Poly1305 instance.
+func New(api frontend.API) (*AEAD, error) {
+	c, err := chacha20.New(api)
+	if err != nil {
+		return nil, fmt.Errorf("new chacha20: %w", err)
+	}
+	m, err := poly1305.New(api)
+	if err != nil {
+		return nil, fmt.Errorf("new poly1305: %w", err)
+	}
+	return &AEAD{api: api, cipher: c, mac: m}, nil
+}
+
+// Seal encrypts and authenticates the first length bytes of plaintext and
+// authenticates the first aadLength bytes of aad. It returns the ciphertext,
+// padded with zeros to the length of plaintext, and the authentication tag.
+func (a *AEAD) Seal(key [KeySize]uints.U8, nonce [NonceSize]uints.U8, plaintext []uints.U8, length frontend.Variable, aad []uints.U8, aadLength frontend.Variable) ([]uints.U8, [TagSize]uints.U8, error) {
+	ciphertext, err := a.cipher.XORKeyStream(key, nonce, 1, plaintext, length)
+	if err != nil {
+		return nil, [TagSize]uints.U8{}, fmt.Errorf("encrypt: %w", err)
+	}
+	tag, err := a.tag(key, nonce, ciphertext, length, aad, aadLength)
+	if err != nil {
+		return nil, [TagSize]uints.U8{}, err
+	}
+	return ciphertext, tag, nil
+}
+
+// Open decrypts the first length bytes of ciphertext and asserts that tag is
+// the authentication tag of the ciphertext and of the first aadLength bytes of
+// aad. It returns the plaintext, padded with zeros to the length of
+// ciphertext.
+func (a *AEAD) Open(key [KeySize]uints.U8, nonce [NonceSize]uints.U8, ciphertext []uints.U8, length frontend.Variable, aad []uints.U8, aadLength frontend.Variable, tag [TagSize]uints.U8) ([]uints.U8, error) {
+	expected, err := a.tag(key, nonce, ciphertext, length, aad, aadLength)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tag {
+		a.api.AssertIsEqual(tag[i].Val, expected[i].Val)
+	}
+	plaintext, err := a.cipher.XORKeyStream(key, nonce, 1, ciphertext, length)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+// tag returns the authentication tag of the first length bytes of the
+// ciphertext and of the first aadLength bytes of aad.
+func (a *AEAD) tag(key [KeySize]uints.U8, nonce [NonceSize]uints.U8, ciphertext []uints.U8, length frontend.Variable, aad []uints.U8, aadLength frontend.Variable) ([TagSize]uints.U8, error) {
+	api := a.api
+	// the one-time key is the beginning of the block 0 of the key stream.
+	zeros := make([]uints.U8, poly1305.KeySize)
+	for i := range zeros {
+		zeros[i] = uints.NewU8(0)
+	}
+	macKey, err := a.cipher.XORKeyStream(key, nonce, 0, zeros, len(zeros))
+	if err != nil {
+		return [TagSize]uints.U8{}, fmt.Errorf("mac key: %w", err)
+	}
+	m := a.mac.NewMAC([poly1305.KeySize]uints.U8(macKey))
+	m.WritePadded(aad, aadLength)
+	m.WritePadded(ciphertext, length)
+	// the lengths as 64-bit little-endian integers.
+	lengths := make([]uints.U8, 0, 16)
+	for _, l := range []frontend.Variable{aadLength, length} {
+		bs := bits.ToBinary(api, l, bits.WithNbDigits(64))
+		for j := 0; j < 8; j++ {
+			lengths = append(lengths, uints.U8{Val: bits.FromBinary(api, bs[8*j:8*j+8], bits.WithUnconstrainedInputs())})
+		}
+	}
+	m.Write(lengths, len(lengths))
+	return m.Sum(), nil
+}
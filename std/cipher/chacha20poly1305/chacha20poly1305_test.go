@@ -0,0 +1,95 @@
+package chacha20poly1305
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+type aeadCircuit struct {
+	Key        [KeySize]uints.U8
+	Nonce      [NonceSize]uints.U8
+	Plaintext  []uints.U8
+	Length     frontend.Variable
+	AAD        []uints.U8
+	AADLength  frontend.Variable
+	Ciphertext []uints.U8
+	Tag        [TagSize]uints.U8
+}
+
+func (c *aeadCircuit) Define(api frontend.API) error {
+	a, err := New(api)
+	if err != nil {
+		return err
+	}
+	ciphertext, tag, err := a.Seal(c.Key, c.Nonce, c.Plaintext, c.Length, c.AAD, c.AADLength)
+	if err != nil {
+		return err
+	}
+	for i := range ciphertext {
+		api.AssertIsEqual(ciphertext[i].Val, c.Ciphertext[i].Val)
+	}
+	for i := range tag {
+		api.AssertIsEqual(tag[i].Val, c.Tag[i].Val)
+	}
+	plaintext, err := a.Open(c.Key, c.Nonce, c.Ciphertext, c.Length, c.AAD, c.AADLength, c.Tag)
+	if err != nil {
+		return err
+	}
+	for i := range plaintext {
+		api.AssertIsEqual(plaintext[i].Val, c.Plaintext[i].Val)
+	}
+	return nil
+}
+
+func TestAEAD(t *testing.T) {
+	assert := test.NewAssert(t)
+	const maxLen, maxAADLen = 70, 20
+	key := make([]byte, KeySize)
+	nonce := make([]byte, NonceSize)
+	rand.Read(key)
+	rand.Read(nonce)
+	aead, err := chacha20poly1305.New(key)
+	assert.NoError(err)
+	for _, tc := range []struct{ length, aadLength int }{
+		{0, 0}, {1, 13}, {16, 16}, {65, 0}, {maxLen, maxAADLen},
+	} {
+		plaintext := make([]byte, maxLen)
+		aad := make([]byte, maxAADLen)
+		rand.Read(plaintext[:tc.length])
+		rand.Read(aad[:tc.aadLength])
+		sealed := aead.Seal(nil, nonce, plaintext[:tc.length], aad[:tc.aadLength])
+		ciphertext := make([]byte, maxLen)
+		copy(ciphertext, sealed[:tc.length])
+		tag := sealed[tc.length:]
+
+		circuit := aeadCircuit{
+			Plaintext:  make([]uints.U8, maxLen),
+			AAD:        make([]uints.U8, maxAADLen),
+			Ciphertext: make([]uints.U8, maxLen),
+		}
+		witness := func(tag []byte) *aeadCircuit {
+			return &aeadCircuit{
+				Key:        [KeySize]uints.U8(uints.NewU8Array(key)),
+				Nonce:      [NonceSize]uints.U8(uints.NewU8Array(nonce)),
+				Plaintext:  uints.NewU8Array(plaintext),
+				Length:     tc.length,
+				AAD:        uints.NewU8Array(aad),
+				AADLength:  tc.aadLength,
+				Ciphertext: uints.NewU8Array(ciphertext),
+				Tag:        [TagSize]uints.U8(uints.NewU8Array(tag)),
+			}
+		}
+		err := test.IsSolved(&circuit, witness(tag), ecc.BN254.ScalarField())
+		assert.NoError(err, tc)
+
+		tag[0] ^= 1
+		err = test.IsSolved(&circuit, witness(tag), ecc.BN254.ScalarField())
+		assert.Error(err, tc)
+	}
+}
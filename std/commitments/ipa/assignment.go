@@ -0,0 +1,152 @@
+package ipa
+
+import (
+	"fmt"
+	"math/big"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	edbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	jubjub "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	edbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+	edbls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	babyjubjub "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	edbw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/twistededwards"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	edbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/twistededwards"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bw6761"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls24315"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// valueOfPoint returns the witness of the native point p. The type of p must
+// correspond to the type parameter G1El.
+func valueOfPoint[G1El algebra.G1ElementT](p any) (G1El, error) {
+	var ret G1El
+	switch s := any(&ret).(type) {
+	case *sw_bn254.G1Affine:
+		tP, ok := p.(bn254.G1Affine)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, p)
+		}
+		*s = sw_bn254.NewG1Affine(tP)
+	case *sw_bls12377.G1Affine:
+		tP, ok := p.(bls12377.G1Affine)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, p)
+		}
+		*s = sw_bls12377.NewG1Affine(tP)
+	case *sw_bls12381.G1Affine:
+		tP, ok := p.(bls12381.G1Affine)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, p)
+		}
+		*s = sw_bls12381.NewG1Affine(tP)
+	case *sw_bw6761.G1Affine:
+		tP, ok := p.(bw6761.G1Affine)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, p)
+		}
+		*s = sw_bw6761.NewG1Affine(tP)
+	case *sw_bls24315.G1Affine:
+		tP, ok := p.(bls24315.G1Affine)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, p)
+		}
+		*s = sw_bls24315.NewG1Affine(tP)
+	case *twistededwards.Point:
+		switch tP := p.(type) {
+		case bandersnatch.PointAffine:
+			*s = twistededwards.Point{X: tP.X.String(), Y: tP.Y.String()}
+		case jubjub.PointAffine:
+			*s = twistededwards.Point{X: tP.X.String(), Y: tP.Y.String()}
+		case babyjubjub.PointAffine:
+			*s = twistededwards.Point{X: tP.X.String(), Y: tP.Y.String()}
+		case edbls12377.PointAffine:
+			*s = twistededwards.Point{X: tP.X.String(), Y: tP.Y.String()}
+		case edbls24315.PointAffine:
+			*s = twistededwards.Point{X: tP.X.String(), Y: tP.Y.String()}
+		case edbls24317.PointAffine:
+			*s = twistededwards.Point{X: tP.X.String(), Y: tP.Y.String()}
+		case edbw6633.PointAffine:
+			*s = twistededwards.Point{X: tP.X.String(), Y: tP.Y.String()}
+		case edbw6761.PointAffine:
+			*s = twistededwards.Point{X: tP.X.String(), Y: tP.Y.String()}
+		default:
+			return ret, fmt.Errorf("mismatching types %T %T", ret, p)
+		}
+	default:
+		return ret, fmt.Errorf("unknown type parametrization")
+	}
+	return ret, nil
+}
+
+// ValueOfCommitment initializes an IPA commitment witness from the native
+// point. The type of cmt must correspond to the type parameter G1El. For
+// example, when G1El is [sw_bn254.G1Affine], then cmt must be
+// [bn254.G1Affine]. For the native twisted Edwards curves, the point must be
+// the PointAffine of the corresponding gnark-crypto package, for example
+// [bandersnatch.PointAffine] for Bandersnatch.
+func ValueOfCommitment[G1El algebra.G1ElementT](cmt any) (Commitment[G1El], error) {
+	p, err := valueOfPoint[G1El](cmt)
+	if err != nil {
+		return Commitment[G1El]{}, err
+	}
+	return Commitment[G1El]{G1El: p}, nil
+}
+
+// ValueOfVerifyingKey initializes an IPA verifying key witness from the native
+// bases and the inner product base u. The type P must correspond to the type
+// parameter G1El, see [ValueOfCommitment].
+func ValueOfVerifyingKey[G1El algebra.G1ElementT, P any](bases []P, u P) (VerifyingKey[G1El], error) {
+	var ret VerifyingKey[G1El]
+	ret.Bases = make([]G1El, len(bases))
+	for i := range bases {
+		p, err := valueOfPoint[G1El](bases[i])
+		if err != nil {
+			return ret, fmt.Errorf("base %d: %w", i, err)
+		}
+		ret.Bases[i] = p
+	}
+	p, err := valueOfPoint[G1El](u)
+	if err != nil {
+		return ret, fmt.Errorf("inner product base: %w", err)
+	}
+	ret.U = p
+	return ret, nil
+}
+
+// ValueOfOpeningProof initializes an IPA opening proof witness from the native
+// cross terms l and r of the rounds, the folded coefficient a and the claimed
+// value. The type P must correspond to the type parameter G1El, see
+// [ValueOfCommitment].
+func ValueOfOpeningProof[FR emulated.FieldParams, G1El algebra.G1ElementT, P any](l, r []P, a, claimedValue *big.Int) (OpeningProof[FR, G1El], error) {
+	var ret OpeningProof[FR, G1El]
+	if len(l) != len(r) {
+		return ret, fmt.Errorf("mismatching number of L %d and R %d", len(l), len(r))
+	}
+	ret.L = make([]G1El, len(l))
+	ret.R = make([]G1El, len(r))
+	for i := range l {
+		p, err := valueOfPoint[G1El](l[i])
+		if err != nil {
+			return ret, fmt.Errorf("L[%d]: %w", i, err)
+		}
+		ret.L[i] = p
+		if p, err = valueOfPoint[G1El](r[i]); err != nil {
+			return ret, fmt.Errorf("R[%d]: %w", i, err)
+		}
+		ret.R[i] = p
+	}
+	ret.A = emulated.ValueOf[FR](a)
+	ret.ClaimedValue = emulated.ValueOf[FR](claimedValue)
+	return ret, nil
+}
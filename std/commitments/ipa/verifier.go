@@ -0,0 +1,279 @@
+// Package ipa implements the inner product argument (IPA) polynomial
+// commitment verification.
+//
+// The IPA polynomial commitment is transparent: it does not require a trusted
+// setup, but only a vector of group elements without known discrete logarithm
+// relations. The polynomial f of degree less than n is committed in the
+// coefficient form as C = ∑ fᵢ Gᵢ, where Gᵢ are the bases of the verifying
+// key. The evaluation proof f(z) = v consists of log₂(n) pairs of group
+// elements and a scalar. The verification is linear in n, but the group
+// operations are performed in a single multi-scalar multiplication.
+//
+// The argument follows the Bulletproofs and Halo constructions without
+// blinding, so the commitments and proofs are not hiding. In every round the
+// prover folds the coefficients as a' = a_lo + x a_hi, the evaluation vector
+// as b' = b_lo + x⁻¹ b_hi and the bases as G' = G_lo + x⁻¹ G_hi and sends
+//
+//	L = ⟨a_hi, G_lo⟩ + ⟨a_hi, b_lo⟩ Q,
+//	R = ⟨a_lo, G_hi⟩ + ⟨a_lo, b_hi⟩ Q,
+//
+// where Q = w U is the rescaled inner product base and x is the challenge of
+// the round. The challenges are computed with the Fiat-Shamir transcript
+// returned by [recursion.NewTranscript], so that they can be computed out of
+// circuit with the gnark-crypto transcript and the [recursion.NewShort] hash.
+// The challenge w is bound to the verifying key (the bases and then U), the
+// commitment, the evaluation point and the claimed value, and the round
+// challenges "x0", "x1", ... are bound to the points L and R of the round, all
+// marshalled with the curve marshalling methods.
+//
+// The package supports any curve implementing [algebra.Curve], including the
+// emulated short Weierstrass curves and the native twisted Edwards curves
+// through
+// [github.com/consensys/gnark/std/algebra/native/twistededwards.AlgebraCurve].
+// The latter does not check the points, so the caller must assert that the
+// points of the verifying key and of the proof given as witness are in the
+// prime order subgroup with its AssertIsInSubgroup method.
+package ipa
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	fbits "github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/recursion"
+)
+
+// VerifyingKey is the public parameters of the IPA polynomial commitment. The
+// number of bases must be a power of two. Use [ValueOfVerifyingKey] to
+// initialize a witness from the native points.
+type VerifyingKey[G1El algebra.G1ElementT] struct {
+	// Bases are the bases for committing to the coefficients.
+	Bases []G1El
+	// U is the base for the inner product.
+	U G1El
+}
+
+// PlaceholderVerifyingKey returns a placeholder verifying key for n bases to be
+// used for compiling the circuit.
+func PlaceholderVerifyingKey[G1El algebra.G1ElementT](n int) VerifyingKey[G1El] {
+	return VerifyingKey[G1El]{Bases: make([]G1El, n)}
+}
+
+// Commitment is an IPA commitment to a polynomial. Use [ValueOfCommitment] to
+// initialize a witness from the native commitment.
+type Commitment[G1El algebra.G1ElementT] struct {
+	G1El G1El
+}
+
+// OpeningProof is the proof that the committed polynomial evaluated at a
+// point is equal to ClaimedValue. Use [ValueOfOpeningProof] to initialize a
+// witness from the native proof.
+type OpeningProof[FR emulated.FieldParams, G1El algebra.G1ElementT] struct {
+	// L and R are the cross terms of the rounds.
+	L, R []G1El
+	// A is the folded coefficient.
+	A emulated.Element[FR]
+	// ClaimedValue is the evaluation of the polynomial.
+	ClaimedValue emulated.Element[FR]
+}
+
+// PlaceholderOpeningProof returns a placeholder opening proof for a
+// polynomial with n coefficients to be used for compiling the circuit.
+func PlaceholderOpeningProof[FR emulated.FieldParams, G1El algebra.G1ElementT](n int) OpeningProof[FR, G1El] {
+	k := bits.Len(uint(n)) - 1
+	return OpeningProof[FR, G1El]{L: make([]G1El, k), R: make([]G1El, k)}
+}
+
+// Verifier allows verifying IPA opening proofs.
+type Verifier[FR emulated.FieldParams, G1El algebra.G1ElementT] struct {
+	api       frontend.API
+	scalarApi *emulated.Field[FR]
+	curve     algebra.Curve[FR, G1El]
+}
+
+// NewVerifier initializes a new Verifier instance.
+func NewVerifier[FR emulated.FieldParams, G1El algebra.G1ElementT](api frontend.API) (*Verifier[FR, G1El], error) {
+	curve, err := algebra.GetCurve[FR, G1El](api)
+	if err != nil {
+		return nil, err
+	}
+	scalarApi, err := emulated.NewField[FR](api)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier[FR, G1El]{
+		api:       api,
+		scalarApi: scalarApi,
+		curve:     curve,
+	}, nil
+}
+
+// CheckOpeningProof asserts the validity of the opening proof for the given
+// commitment at point.
+func (v *Verifier[FR, G1El]) CheckOpeningProof(commitment Commitment[G1El], proof OpeningProof[FR, G1El], point emulated.Element[FR], vk VerifyingKey[G1El]) error {
+	n := len(vk.Bases)
+	k := bits.Len(uint(n)) - 1
+	if n == 0 || n != 1<<k {
+		return fmt.Errorf("number of bases %d is not a power of two", n)
+	}
+	if len(proof.L) != k || len(proof.R) != k {
+		return fmt.Errorf("expected %d rounds, got %d L and %d R", k, len(proof.L), len(proof.R))
+	}
+	w, xs, err := v.deriveChallenges(vk, commitment, proof, point)
+	if err != nil {
+		return err
+	}
+	folding := v.NewFolding(w, xs)
+	f := v.scalarApi
+
+	// the folded evaluation vector is b₀ = ∏ⱼ (1 + xⱼ⁻¹ z^(2^(k-1-j))).
+	zPowers := make([]*emulated.Element[FR], k)
+	zPowers[k-1] = &point
+	for j := k - 2; j >= 0; j-- {
+		zPowers[j] = f.Mul(zPowers[j+1], zPowers[j+1])
+	}
+	b0 := f.One()
+	for j := 0; j < k; j++ {
+		b0 = f.Mul(b0, f.Add(f.One(), f.Mul(folding.XInv[j], zPowers[j])))
+	}
+	// the folded base G₀ = ∑ sᵢ Gᵢ is not computed, but its terms a⋅sᵢ⋅Gᵢ are
+	// merged into the final multi-scalar multiplication.
+	points, scalars := v.foldedTerms(proof, &vk.U, folding, b0)
+	for i := range vk.Bases {
+		points = append(points, &vk.Bases[i])
+		scalars = append(scalars, f.Mul(&proof.A, folding.S[i]))
+	}
+	res, err := v.curve.MultiScalarMul(points, scalars)
+	if err != nil {
+		return fmt.Errorf("multi-scalar multiplication: %w", err)
+	}
+	v.curve.AssertIsEqual(res, &commitment.G1El)
+	return nil
+}
+
+// Folding is the folding of the bases and the evaluation vector of an opening
+// proof given by its challenges. Use [Verifier.NewFolding] to compute it.
+type Folding[FR emulated.FieldParams] struct {
+	// W is the challenge for rescaling the inner product base.
+	W *emulated.Element[FR]
+	// X are the challenges of the rounds and XInv their inverses.
+	X, XInv []*emulated.Element[FR]
+	// S are the scalars of the folded base G₀ = ∑ sᵢ Gᵢ and of the folded
+	// evaluation vector b₀ = ∑ sᵢ bᵢ. The scalar sᵢ is the product of xⱼ⁻¹
+	// for the bits j of i set, the first round corresponding to the most
+	// significant bit.
+	S []*emulated.Element[FR]
+}
+
+// NewFolding returns the folding for the challenge w of the inner product base
+// and the challenges xs of the rounds.
+func (v *Verifier[FR, G1El]) NewFolding(w *emulated.Element[FR], xs []*emulated.Element[FR]) Folding[FR] {
+	f := v.scalarApi
+	res := Folding[FR]{W: w, X: xs, XInv: make([]*emulated.Element[FR], len(xs))}
+	res.S = []*emulated.Element[FR]{f.One()}
+	for j := range xs {
+		res.XInv[j] = f.Inverse(xs[j])
+		next := make([]*emulated.Element[FR], 2*len(res.S))
+		for i := range res.S {
+			next[2*i] = res.S[i]
+			next[2*i+1] = f.Mul(res.S[i], res.XInv[j])
+		}
+		res.S = next
+	}
+	return res
+}
+
+// FoldedCommitment returns
+//
+//	a G₀ + w (a b₀ - v) U - ∑ xⱼ Lⱼ - ∑ xⱼ⁻¹ Rⱼ,
+//
+// where G₀ and b₀ are the folded base and evaluation vector, a is the folded
+// coefficient and v the claimed value of the proof. The proof is valid if it
+// is equal to the commitment. It allows verifying proofs with other
+// transcripts, evaluation vectors or fixed bases, while
+// [Verifier.CheckOpeningProof] computes the same point for the coefficient
+// form and the transcript of this package without computing G₀ separately.
+func (v *Verifier[FR, G1El]) FoldedCommitment(proof OpeningProof[FR, G1El], u *G1El, folding Folding[FR], g0 *G1El, b0 *emulated.Element[FR]) (*G1El, error) {
+	k := len(folding.X)
+	if len(proof.L) != k || len(proof.R) != k {
+		return nil, fmt.Errorf("expected %d rounds, got %d L and %d R", k, len(proof.L), len(proof.R))
+	}
+	points, scalars := v.foldedTerms(proof, u, folding, b0)
+	points = append(points, g0)
+	scalars = append(scalars, &proof.A)
+	res, err := v.curve.MultiScalarMul(points, scalars)
+	if err != nil {
+		return nil, fmt.Errorf("multi-scalar multiplication: %w", err)
+	}
+	return res, nil
+}
+
+// foldedTerms returns the points and scalars of the terms
+// w (a b₀ - v) U - ∑ xⱼ Lⱼ - ∑ xⱼ⁻¹ Rⱼ of the folded commitment, which do not
+// depend on the bases.
+func (v *Verifier[FR, G1El]) foldedTerms(proof OpeningProof[FR, G1El], u *G1El, folding Folding[FR], b0 *emulated.Element[FR]) ([]*G1El, []*emulated.Element[FR]) {
+	f := v.scalarApi
+	k := len(folding.X)
+	points := make([]*G1El, 0, 1+2*k)
+	scalars := make([]*emulated.Element[FR], 0, 1+2*k)
+	points = append(points, u)
+	scalars = append(scalars, f.Mul(folding.W, f.Sub(f.Mul(&proof.A, b0), &proof.ClaimedValue)))
+	for j := 0; j < k; j++ {
+		points = append(points, &proof.L[j], &proof.R[j])
+		scalars = append(scalars, f.Neg(folding.X[j]), f.Neg(folding.XInv[j]))
+	}
+	return points, scalars
+}
+
+// deriveChallenges computes the challenge w for rescaling the inner product
+// base and the challenges of the rounds.
+func (v *Verifier[FR, G1El]) deriveChallenges(vk VerifyingKey[G1El], commitment Commitment[G1El], proof OpeningProof[FR, G1El], point emulated.Element[FR]) (*emulated.Element[FR], []*emulated.Element[FR], error) {
+	var fr FR
+	ids := make([]string, len(proof.L)+1)
+	ids[0] = "w"
+	for j := range proof.L {
+		ids[j+1] = fmt.Sprintf("x%d", j)
+	}
+	fs, err := recursion.NewTranscript(v.api, fr.Modulus(), ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("new transcript: %w", err)
+	}
+	for i := range vk.Bases {
+		if err := fs.Bind("w", v.curve.MarshalG1(vk.Bases[i])); err != nil {
+			return nil, nil, fmt.Errorf("bind base %d: %w", i, err)
+		}
+	}
+	if err := fs.Bind("w", v.curve.MarshalG1(vk.U)); err != nil {
+		return nil, nil, fmt.Errorf("bind U: %w", err)
+	}
+	if err := fs.Bind("w", v.curve.MarshalG1(commitment.G1El)); err != nil {
+		return nil, nil, fmt.Errorf("bind commitment: %w", err)
+	}
+	if err := fs.Bind("w", v.curve.MarshalScalar(point)); err != nil {
+		return nil, nil, fmt.Errorf("bind point: %w", err)
+	}
+	if err := fs.Bind("w", v.curve.MarshalScalar(proof.ClaimedValue)); err != nil {
+		return nil, nil, fmt.Errorf("bind claimed value: %w", err)
+	}
+	for j := range proof.L {
+		if err := fs.Bind(ids[j+1], v.curve.MarshalG1(proof.L[j])); err != nil {
+			return nil, nil, fmt.Errorf("bind L[%d]: %w", j, err)
+		}
+		if err := fs.Bind(ids[j+1], v.curve.MarshalG1(proof.R[j])); err != nil {
+			return nil, nil, fmt.Errorf("bind R[%d]: %w", j, err)
+		}
+	}
+	challenges := make([]*emulated.Element[FR], len(ids))
+	for i := range ids {
+		c, err := fs.ComputeChallenge(ids[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("compute challenge %s: %w", ids[i], err)
+		}
+		b := fbits.ToBinary(v.api, c, fbits.WithNbDigits(fr.Modulus().BitLen()))
+		challenges[i] = v.scalarApi.FromBits(b...)
+	}
+	return challenges[0], challenges[1:], nil
+}
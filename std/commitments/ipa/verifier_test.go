@@ -0,0 +1,280 @@
+package ipa
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	babyjubjub "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/recursion"
+	"github.com/consensys/gnark/test"
+)
+
+// group defines the native group operations used by the test prover.
+type group[P any] struct {
+	order   *big.Int
+	native  *big.Int
+	add     func(p, q P) P
+	mul     func(p P, s *big.Int) P
+	marshal func(p P) []byte
+}
+
+func (g group[P]) msm(ps []P, ss []*big.Int) P {
+	res := g.mul(ps[0], ss[0])
+	for i := 1; i < len(ps); i++ {
+		res = g.add(res, g.mul(ps[i], ss[i]))
+	}
+	return res
+}
+
+func (g group[P]) marshalScalar(s *big.Int) []byte {
+	res := make([]byte, (g.order.BitLen()+7)/8)
+	return s.FillBytes(res)
+}
+
+func (g group[P]) challenge(fs *fiatshamir.Transcript, id string) (*big.Int, error) {
+	b, err := fs.ComputeChallenge(id)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func innerProduct(a, b []*big.Int, m *big.Int) *big.Int {
+	res := new(big.Int)
+	for i := range a {
+		res.Add(res, new(big.Int).Mul(a[i], b[i]))
+	}
+	return res.Mod(res, m)
+}
+
+// open commits to the coefficients and computes the IPA opening proof at the
+// point z following the transcript of the verifier.
+func open[P any](g group[P], bases []P, u P, coeffs []*big.Int, z *big.Int) (cmt P, l, r []P, a, value *big.Int, err error) {
+	n := len(bases)
+	k := 0
+	for 1<<k < n {
+		k++
+	}
+	m := g.order
+	b := make([]*big.Int, n)
+	b[0] = big.NewInt(1)
+	for i := 1; i < n; i++ {
+		b[i] = new(big.Int).Mul(b[i-1], z)
+		b[i].Mod(b[i], m)
+	}
+	cmt = g.msm(bases, coeffs)
+	value = innerProduct(coeffs, b, m)
+
+	ids := make([]string, k+1)
+	ids[0] = "w"
+	for j := 0; j < k; j++ {
+		ids[j+1] = fmt.Sprintf("x%d", j)
+	}
+	h, err := recursion.NewShort(g.native, m)
+	if err != nil {
+		return
+	}
+	fs := fiatshamir.NewTranscript(h, ids...)
+	var bound [][]byte
+	for i := range bases {
+		bound = append(bound, g.marshal(bases[i]))
+	}
+	bound = append(bound, g.marshal(u), g.marshal(cmt), g.marshalScalar(z), g.marshalScalar(value))
+	for _, data := range bound {
+		if err = fs.Bind("w", data); err != nil {
+			return
+		}
+	}
+	w, err := g.challenge(fs, "w")
+	if err != nil {
+		return
+	}
+	q := g.mul(u, w)
+
+	as := append([]*big.Int{}, coeffs...)
+	gs := append([]P{}, bases...)
+	for j := 0; j < k; j++ {
+		half := len(as) / 2
+		aLo, aHi := as[:half], as[half:]
+		bLo, bHi := b[:half], b[half:]
+		gLo, gHi := gs[:half], gs[half:]
+		lj := g.add(g.msm(gLo, aHi), g.mul(q, innerProduct(aHi, bLo, m)))
+		rj := g.add(g.msm(gHi, aLo), g.mul(q, innerProduct(aLo, bHi, m)))
+		l = append(l, lj)
+		r = append(r, rj)
+		if err = fs.Bind(ids[j+1], g.marshal(lj)); err != nil {
+			return
+		}
+		if err = fs.Bind(ids[j+1], g.marshal(rj)); err != nil {
+			return
+		}
+		var x *big.Int
+		if x, err = g.challenge(fs, ids[j+1]); err != nil {
+			return
+		}
+		xInv := new(big.Int).ModInverse(x, m)
+		nextA := make([]*big.Int, half)
+		nextB := make([]*big.Int, half)
+		nextG := make([]P, half)
+		for i := 0; i < half; i++ {
+			nextA[i] = new(big.Int).Mul(x, aHi[i])
+			nextA[i].Add(nextA[i], aLo[i]).Mod(nextA[i], m)
+			nextB[i] = new(big.Int).Mul(xInv, bHi[i])
+			nextB[i].Add(nextB[i], bLo[i]).Mod(nextB[i], m)
+			nextG[i] = g.add(gLo[i], g.mul(gHi[i], xInv))
+		}
+		as, b, gs = nextA, nextB, nextG
+	}
+	a = as[0]
+	return
+}
+
+type OpeningProofCircuit[FR emulated.FieldParams, G1El algebra.G1ElementT] struct {
+	Vk         VerifyingKey[G1El]
+	Commitment Commitment[G1El]
+	Proof      OpeningProof[FR, G1El]
+	Point      emulated.Element[FR]
+}
+
+func (c *OpeningProofCircuit[FR, G1El]) Define(api frontend.API) error {
+	v, err := NewVerifier[FR, G1El](api)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	return v.CheckOpeningProof(c.Commitment, c.Proof, c.Point, c.Vk)
+}
+
+func testOpeningProof[FR emulated.FieldParams, G1El algebra.G1ElementT, P any](t *testing.T, g group[P], n int, field *big.Int, gen P) {
+	assert := test.NewAssert(t)
+	bases := make([]P, n)
+	coeffs := make([]*big.Int, n)
+	for i := range bases {
+		s, err := rand.Int(rand.Reader, g.order)
+		assert.NoError(err)
+		bases[i] = g.mul(gen, s)
+		coeffs[i], err = rand.Int(rand.Reader, g.order)
+		assert.NoError(err)
+	}
+	s, err := rand.Int(rand.Reader, g.order)
+	assert.NoError(err)
+	u := g.mul(gen, s)
+	z, err := rand.Int(rand.Reader, g.order)
+	assert.NoError(err)
+	cmt, l, r, a, value, err := open(g, bases, u, coeffs, z)
+	assert.NoError(err)
+
+	wVk, err := ValueOfVerifyingKey[G1El](bases, u)
+	assert.NoError(err)
+	wCmt, err := ValueOfCommitment[G1El](cmt)
+	assert.NoError(err)
+	wProof, err := ValueOfOpeningProof[FR, G1El](l, r, a, value)
+	assert.NoError(err)
+	circuit := OpeningProofCircuit[FR, G1El]{
+		Vk:    PlaceholderVerifyingKey[G1El](n),
+		Proof: PlaceholderOpeningProof[FR, G1El](n),
+	}
+	assignment := OpeningProofCircuit[FR, G1El]{
+		Vk:         wVk,
+		Commitment: wCmt,
+		Proof:      wProof,
+		Point:      emulated.ValueOf[FR](z),
+	}
+	err = test.IsSolved(&circuit, &assignment, field)
+	assert.NoError(err)
+
+	wrong := assignment
+	wrong.Proof.ClaimedValue = emulated.ValueOf[FR](new(big.Int).Add(value, big.NewInt(1)))
+	err = test.IsSolved(&circuit, &wrong, field)
+	assert.Error(err)
+
+	// the proof is bound to the verifying key
+	otherVk, err := ValueOfVerifyingKey[G1El](bases, g.add(u, gen))
+	assert.NoError(err)
+	wrong = assignment
+	wrong.Vk = otherVk
+	err = test.IsSolved(&circuit, &wrong, field)
+	assert.Error(err)
+}
+
+func TestOpeningProofBandersnatch(t *testing.T) {
+	curve := bandersnatch.GetEdwardsCurve()
+	g := group[bandersnatch.PointAffine]{
+		order:  &curve.Order,
+		native: ecc.BLS12_381.ScalarField(),
+		add: func(p, q bandersnatch.PointAffine) bandersnatch.PointAffine {
+			var res bandersnatch.PointAffine
+			res.Add(&p, &q)
+			return res
+		},
+		mul: func(p bandersnatch.PointAffine, s *big.Int) bandersnatch.PointAffine {
+			var res bandersnatch.PointAffine
+			res.ScalarMultiplication(&p, s)
+			return res
+		},
+		marshal: func(p bandersnatch.PointAffine) []byte {
+			x, y := p.X.Bytes(), p.Y.Bytes()
+			return append(x[:], y[:]...)
+		},
+	}
+	testOpeningProof[emparams.BandersnatchFr, twistededwards.Point](t, g, 8, ecc.BLS12_381.ScalarField(), curve.Base)
+}
+
+func TestOpeningProofBabyJubjub(t *testing.T) {
+	curve := babyjubjub.GetEdwardsCurve()
+	g := group[babyjubjub.PointAffine]{
+		order:  &curve.Order,
+		native: ecc.BN254.ScalarField(),
+		add: func(p, q babyjubjub.PointAffine) babyjubjub.PointAffine {
+			var res babyjubjub.PointAffine
+			res.Add(&p, &q)
+			return res
+		},
+		mul: func(p babyjubjub.PointAffine, s *big.Int) babyjubjub.PointAffine {
+			var res babyjubjub.PointAffine
+			res.ScalarMultiplication(&p, s)
+			return res
+		},
+		marshal: func(p babyjubjub.PointAffine) []byte {
+			x, y := p.X.Bytes(), p.Y.Bytes()
+			return append(x[:], y[:]...)
+		},
+	}
+	testOpeningProof[emparams.BabyJubjubFr, twistededwards.Point](t, g, 8, ecc.BN254.ScalarField(), curve.Base)
+}
+
+func TestOpeningProofBN254(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping emulated IPA verification in short mode")
+	}
+	_, _, gen, _ := bn254.Generators()
+	g := group[bn254.G1Affine]{
+		order:  ecc.BN254.ScalarField(),
+		native: ecc.BN254.ScalarField(),
+		add: func(p, q bn254.G1Affine) bn254.G1Affine {
+			var res bn254.G1Affine
+			res.Add(&p, &q)
+			return res
+		},
+		mul: func(p bn254.G1Affine, s *big.Int) bn254.G1Affine {
+			var res bn254.G1Affine
+			res.ScalarMultiplication(&p, s)
+			return res
+		},
+		marshal: func(p bn254.G1Affine) []byte {
+			return p.Marshal()
+		},
+	}
+	testOpeningProof[sw_bn254.ScalarField, sw_bn254.G1Affine](t, g, 4, ecc.BN254.ScalarField(), gen)
+}
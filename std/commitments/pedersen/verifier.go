@@ -54,17 +54,29 @@ func (v *Verifier[FR, G1El, G2El, GtEl]) FoldCommitments(commitments []Commitmen
 
 // AssertCommitment verifies the given commitment and knowledge proof against the given verifying key.
 func (v *Verifier[FR, G1El, G2El, GtEl]) AssertCommitment(commitment Commitment[G1El], knowledgeProof KnowledgeProof[G1El], vk VerifyingKey[G2El], opts ...VerifierOption) error {
+	P, Q, err := v.PairingArguments(commitment, knowledgeProof, vk, opts...)
+	if err != nil {
+		return err
+	}
+
+	v.pairing.PairingCheck(P, Q)
+	return nil
+}
+
+// PairingArguments returns the arguments of the pairing check which verifies
+// the given commitment and knowledge proof against the given verifying key.
+// The options are applied as in [Verifier.AssertCommitment]. It allows the
+// caller to merge the check with other pairing checks.
+func (v *Verifier[FR, G1El, G2El, GtEl]) PairingArguments(commitment Commitment[G1El], knowledgeProof KnowledgeProof[G1El], vk VerifyingKey[G2El], opts ...VerifierOption) ([]*G1El, []*G2El, error) {
 	cfg, err := newCfg(opts...)
 	if err != nil {
-		return fmt.Errorf("apply options: %w", err)
+		return nil, nil, fmt.Errorf("apply options: %w", err)
 	}
 	if cfg.subgroupCheck {
 		v.pairing.AssertIsOnG1(&commitment.G1El)
 		v.pairing.AssertIsOnG1(&knowledgeProof.G1El)
 	}
-
-	v.pairing.PairingCheck([]*G1El{&commitment.G1El, &knowledgeProof.G1El}, []*G2El{&vk.G, &vk.GRootSigmaNeg})
-	return nil
+	return []*G1El{&commitment.G1El, &knowledgeProof.G1El}, []*G2El{&vk.G, &vk.GRootSigmaNeg}, nil
 }
 
 // TODO: add asserting with switches between different keys
@@ -0,0 +1,268 @@
+// Package base64 implements in-circuit base64 encoding and decoding as
+// specified by RFC 4648.
+//
+// The inputs and the outputs are bytes with a variable length. The characters
+// are mapped to their values using lookup tables. The decoding is strict: the
+// padding must be present for the padded encodings and absent otherwise, the
+// unused bits of the last character must be zero and newline characters are
+// not ignored. Hence every byte string has exactly one accepted encoding.
+package base64
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+const (
+	stdAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	urlAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	padChar     = '='
+)
+
+// Encoding is a radix 64 encoding defined by an alphabet of 64 characters.
+type Encoding struct {
+	alphabet string
+	padded   bool
+}
+
+var (
+	// StdEncoding is the standard base64 encoding with padding.
+	StdEncoding = &Encoding{alphabet: stdAlphabet, padded: true}
+	// URLEncoding is the base64 encoding with padding using the URL and file
+	// name safe alphabet.
+	URLEncoding = &Encoding{alphabet: urlAlphabet, padded: true}
+	// RawStdEncoding is the standard base64 encoding without padding.
+	RawStdEncoding = &Encoding{alphabet: stdAlphabet}
+	// RawURLEncoding is the base64 encoding without padding using the URL and
+	// file name safe alphabet, used for instance in JWTs.
+	RawURLEncoding = &Encoding{alphabet: urlAlphabet}
+)
+
+// NewEncoding returns a new encoding defined by the alphabet, which must
+// consist of 64 distinct ASCII characters different from the padding character
+// and the newline characters.
+func NewEncoding(alphabet string, padded bool) (*Encoding, error) {
+	if len(alphabet) != 64 {
+		return nil, fmt.Errorf("alphabet must have 64 characters")
+	}
+	var seen [128]bool
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if c >= 128 || c == padChar || c == '\n' || c == '\r' {
+			return nil, fmt.Errorf("invalid character %q in the alphabet", c)
+		}
+		if seen[c] {
+			return nil, fmt.Errorf("duplicate character %q in the alphabet", c)
+		}
+		seen[c] = true
+	}
+	return &Encoding{alphabet: alphabet, padded: padded}, nil
+}
+
+// EncodedMaxLen returns the maximal length of the encoding of n bytes.
+func (enc *Encoding) EncodedMaxLen(n int) int {
+	if enc.padded {
+		return (n + 2) / 3 * 4
+	}
+	return (n*8 + 5) / 6
+}
+
+// DecodedMaxLen returns the maximal length of the bytes decoded from n
+// characters.
+func (enc *Encoding) DecodedMaxLen(n int) int {
+	return n/4*3 + (n%4)*3/4
+}
+
+// Codec encodes and decodes base64 in-circuit.
+type Codec struct {
+	api frontend.API
+	enc *Encoding
+}
+
+// NewCodec returns a new codec for the encoding enc.
+func NewCodec(api frontend.API, enc *Encoding) *Codec {
+	return &Codec{api: api, enc: enc}
+}
+
+// the values of the characters which are not in the alphabet.
+const (
+	valuePad     = 1 << 6
+	valueInvalid = 1 << 7
+)
+
+// Decode decodes the first length characters of src and returns the decoded
+// bytes, padded with zeros to [Encoding.DecodedMaxLen] bytes, and their
+// number. It asserts that the characters are a valid encoding.
+func (c *Codec) Decode(src []uints.U8, length frontend.Variable) ([]uints.U8, frontend.Variable, error) {
+	api := c.api
+	n := len(src)
+	active, eq := lengthFlags(api, length, n)
+
+	valueT := logderivlookup.New(api)
+	for b := 0; b < 256; b++ {
+		valueT.Insert(c.enc.value(b))
+	}
+	vals := make([]frontend.Variable, n)
+	for i := range src {
+		vals[i] = src[i].Val
+	}
+	values := valueT.Lookup(vals...)
+
+	// the characters after the end are zero, so that all the decoded bytes
+	// after the end are zero for a canonical encoding.
+	sextets := make([][]frontend.Variable, (n+3)/4*4)
+	pads := make([]frontend.Variable, n)
+	var nbPads frontend.Variable = 0
+	for i := range sextets {
+		if i >= n {
+			sextets[i] = []frontend.Variable{0, 0, 0, 0, 0, 0}
+			continue
+		}
+		bs := bits.ToBinary(api, api.Mul(values[i], active[i]), bits.WithNbDigits(8))
+		api.AssertIsEqual(bs[7], 0)
+		sextets[i] = bs[:6]
+		pads[i] = bs[6]
+		nbPads = api.Add(nbPads, pads[i])
+	}
+	// the padding characters are at the end.
+	for i := 0; i+1 < n; i++ {
+		api.AssertIsEqual(api.Mul(pads[i], active[i+1], api.Sub(1, pads[i+1])), 0)
+	}
+	api.AssertIsEqual(api.Mul(nbPads, api.Sub(nbPads, 1), api.Sub(nbPads, 2)), 0)
+
+	// the decoded length is computed from the length modulo 4.
+	var decodedLen frontend.Variable = 0
+	for i := range eq {
+		if c.enc.padded && i%4 != 0 {
+			api.AssertIsEqual(eq[i], 0)
+		}
+		if !c.enc.padded && i%4 == 1 {
+			api.AssertIsEqual(eq[i], 0)
+		}
+		decodedLen = api.Add(decodedLen, api.Mul(eq[i], i/4*3+(i%4)*3/4))
+	}
+	decodedLen = api.Sub(decodedLen, nbPads)
+
+	// all the bytes of the groups of four characters are checked, including
+	// the ones after the maximal length which hold the unused bits of the last
+	// character.
+	m := c.enc.DecodedMaxLen(n)
+	res := make([]uints.U8, (n+3)/4*3)
+	inDecoded := selector.PrefixMask(api, decodedLen, len(res))
+	for g := 0; 4*g < n; g++ {
+		var bs []frontend.Variable
+		for j := 0; j < 4; j++ {
+			// the sextets are big-endian, the bits are little-endian.
+			s := sextets[4*g+j]
+			for k := 5; k >= 0; k-- {
+				bs = append(bs, s[k])
+			}
+		}
+		for j := 0; j < 3; j++ {
+			byteBits := make([]frontend.Variable, 8)
+			for k := range byteBits {
+				byteBits[k] = bs[8*j+7-k]
+			}
+			v := bits.FromBinary(api, byteBits, bits.WithUnconstrainedInputs())
+			api.AssertIsEqual(api.Mul(v, api.Sub(1, inDecoded[3*g+j])), 0)
+			res[3*g+j] = uints.U8{Val: v}
+		}
+	}
+	return res[:m], decodedLen, nil
+}
+
+// Encode encodes the first length bytes of src and returns the characters of
+// the encoding, padded with zeros to [Encoding.EncodedMaxLen] characters, and
+// their number.
+func (c *Codec) Encode(src []uints.U8, length frontend.Variable) ([]uints.U8, frontend.Variable, error) {
+	api := c.api
+	n := len(src)
+	active, eq := lengthFlags(api, length, n)
+
+	// the numbers of characters without and with padding are computed from
+	// the length modulo 3.
+	var rawLen, paddedLen frontend.Variable = 0, 0
+	for i := range eq {
+		rawLen = api.Add(rawLen, api.Mul(eq[i], (i*8+5)/6))
+		paddedLen = api.Add(paddedLen, api.Mul(eq[i], (i+2)/3*4))
+	}
+	encodedLen := rawLen
+	if c.enc.padded {
+		encodedLen = paddedLen
+	}
+
+	alphabetT := logderivlookup.New(api)
+	for i := 0; i < len(c.enc.alphabet); i++ {
+		alphabetT.Insert(c.enc.alphabet[i])
+	}
+	var bs []frontend.Variable
+	for i := 0; i < (n+2)/3*3; i++ {
+		if i >= n {
+			bs = append(bs, 0, 0, 0, 0, 0, 0, 0, 0)
+			continue
+		}
+		// the bytes after the end are zero, so that the unused bits of the
+		// last character are zero.
+		b := bits.ToBinary(api, api.Mul(src[i].Val, active[i]), bits.WithNbDigits(8))
+		for k := 7; k >= 0; k-- {
+			bs = append(bs, b[k])
+		}
+	}
+	sextets := make([]frontend.Variable, len(bs)/6)
+	for i := range sextets {
+		s := make([]frontend.Variable, 6)
+		for k := range s {
+			s[k] = bs[6*i+5-k]
+		}
+		sextets[i] = bits.FromBinary(api, s, bits.WithUnconstrainedInputs())
+	}
+	chars := alphabetT.Lookup(sextets...)
+
+	m := c.enc.EncodedMaxLen(n)
+	inRaw := selector.PrefixMask(api, rawLen, len(chars))
+	inPadded := selector.PrefixMask(api, paddedLen, len(chars))
+	res := make([]uints.U8, m)
+	for i := range res {
+		v := api.Mul(chars[i], inRaw[i])
+		if c.enc.padded {
+			v = api.Add(v, api.Mul(api.Sub(inPadded[i], inRaw[i]), padChar))
+		}
+		res[i] = uints.U8{Val: v}
+	}
+	return res, encodedLen, nil
+}
+
+// value returns the value of the character b, which is valuePad for the
+// padding character of a padded encoding and valueInvalid for the characters
+// which are not in the alphabet.
+func (enc *Encoding) value(b int) int {
+	for i := 0; i < len(enc.alphabet); i++ {
+		if int(enc.alphabet[i]) == b {
+			return i
+		}
+	}
+	if enc.padded && b == padChar {
+		return valuePad
+	}
+	return valueInvalid
+}
+
+// lengthFlags returns the flags which are 1 for the first length indices of n
+// and the flags which are 1 for the index equal to length in [0, n]. It
+// asserts that length is at most n.
+func lengthFlags(api frontend.API, length frontend.Variable, n int) (active, eq []frontend.Variable) {
+	active = selector.PrefixMask(api, length, n)
+	eq = make([]frontend.Variable, n+1)
+	var prev frontend.Variable = 1
+	for i := 0; i < n; i++ {
+		eq[i] = api.Sub(prev, active[i])
+		prev = active[i]
+	}
+	eq[n] = prev
+	return active, eq
+}
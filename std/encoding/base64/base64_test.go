@@ -0,0 +1,125 @@
+package base64
+
+import (
+	stdbase64 "encoding/base64"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+var encodings = []struct {
+	enc    *Encoding
+	stdEnc *stdbase64.Encoding
+}{
+	{StdEncoding, stdbase64.StdEncoding},
+	{URLEncoding, stdbase64.URLEncoding},
+	{RawStdEncoding, stdbase64.RawStdEncoding},
+	{RawURLEncoding, stdbase64.RawURLEncoding},
+}
+
+const maxEncodedLen = 12
+
+type decodeCircuit struct {
+	enc        *Encoding
+	Data       [maxEncodedLen]uints.U8
+	Length     frontend.Variable
+	Decoded    [9]uints.U8
+	DecodedLen frontend.Variable
+}
+
+func (c *decodeCircuit) Define(api frontend.API) error {
+	res, length, err := NewCodec(api, c.enc).Decode(c.Data[:], c.Length)
+	if err != nil {
+		return err
+	}
+	if len(res) != len(c.Decoded) {
+		return fmt.Errorf("unexpected length %d", len(res))
+	}
+	api.AssertIsEqual(length, c.DecodedLen)
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Decoded[i].Val)
+	}
+	return nil
+}
+
+func TestDecode(t *testing.T) {
+	assert := test.NewAssert(t)
+	testCases := []string{
+		"", "QQ==", "QUI=", "QUJD", "QUJDRA==", "_-8=", "+/8=", "AAAAAAAAAAAA", "QQ", "QUI", "QUJDRA",
+		// invalid or not canonical
+		"Q", "QR==", "QUJ=", "Q===", "=QQQ", "QQ=A", "QQ=", "QUJD\n", "QU!D", "QUJDR", "QUJD====",
+	}
+	for _, e := range encodings {
+		for _, tc := range testCases {
+			expected, err := e.stdEnc.Strict().DecodeString(tc)
+			var assignment decodeCircuit
+			data := make([]byte, maxEncodedLen)
+			copy(data, tc)
+			copy(assignment.Data[:], uints.NewU8Array(data))
+			assignment.Length = len(tc)
+			decoded := make([]byte, len(assignment.Decoded))
+			copy(decoded, expected)
+			copy(assignment.Decoded[:], uints.NewU8Array(decoded))
+			assignment.DecodedLen = len(expected)
+			solveErr := test.IsSolved(&decodeCircuit{enc: e.enc}, &assignment, ecc.BN254.ScalarField())
+			// the newline characters are ignored by the standard library.
+			if err == nil && !strings.ContainsAny(tc, "\r\n") {
+				assert.NoError(solveErr, tc)
+			} else {
+				assert.Error(solveErr, tc)
+			}
+		}
+	}
+}
+
+const maxDecodedLen = 8
+
+type encodeCircuit struct {
+	enc        *Encoding
+	Data       [maxDecodedLen]uints.U8
+	Length     frontend.Variable
+	Encoded    [12]uints.U8
+	EncodedLen frontend.Variable
+}
+
+func (c *encodeCircuit) Define(api frontend.API) error {
+	res, length, err := NewCodec(api, c.enc).Encode(c.Data[:], c.Length)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(length, c.EncodedLen)
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Encoded[i].Val)
+	}
+	for i := len(res); i < len(c.Encoded); i++ {
+		api.AssertIsEqual(c.Encoded[i].Val, 0)
+	}
+	return nil
+}
+
+func TestEncode(t *testing.T) {
+	assert := test.NewAssert(t)
+	rng := rand.New(rand.NewSource(1)) //#nosec G404 -- test only
+	for _, e := range encodings {
+		for n := 0; n <= maxDecodedLen; n++ {
+			data := make([]byte, maxDecodedLen)
+			rng.Read(data)
+			expected := e.stdEnc.EncodeToString(data[:n])
+			var assignment encodeCircuit
+			copy(assignment.Data[:], uints.NewU8Array(data))
+			assignment.Length = n
+			encoded := make([]byte, len(assignment.Encoded))
+			copy(encoded, expected)
+			copy(assignment.Encoded[:], uints.NewU8Array(encoded))
+			assignment.EncodedLen = len(expected)
+			err := test.IsSolved(&encodeCircuit{enc: e.enc}, &assignment, ecc.BN254.ScalarField())
+			assert.NoError(err, expected)
+		}
+	}
+}
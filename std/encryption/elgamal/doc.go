@@ -0,0 +1,21 @@
+// Package elgamal implements exponential ElGamal encryption over twisted
+// Edwards elliptic curves available in gnark and gnark-crypto, such as
+// Baby-Jubjub on BN254.
+//
+// A message m is encrypted to the public key PK = [sk]G with the randomness r
+// as the pair of points ([r]G, [m]G + [r]PK). The scheme is additively
+// homomorphic and the ciphertexts can be re-randomized without the private key.
+// As the message is in the exponent, decryption only recovers small messages,
+// which suits for instance the tallies of votes.
+//
+// The circuit side asserts the relations between the keys, the ciphertexts, the
+// messages and the randomness. The [Native] type performs the same operations
+// outside of the circuit to compute the witnesses. The public keys and the
+// ciphertexts given as witnesses must be asserted to be on the curve by the
+// callers, for instance with [AssertIsOnCurve].
+//
+// The package depends on the [twistededwards] package for elliptic curve group
+// operations in twisted Edwards form using native arithmetic.
+//
+// [twistededwards]: https://pkg.go.dev/github.com/consensys/gnark/std/algebra/native/twistededwards
+package elgamal
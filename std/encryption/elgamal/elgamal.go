@@ -0,0 +1,92 @@
+package elgamal
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+)
+
+// PublicKey stores an ElGamal public key (to be used in gnark circuit)
+type PublicKey struct {
+	A twistededwards.Point
+}
+
+// Ciphertext stores an ElGamal ciphertext (to be used in gnark circuit)
+type Ciphertext struct {
+	C1, C2 twistededwards.Point
+}
+
+// Encrypt returns the encryption of the message m to the public key pk with
+// the randomness r.
+func Encrypt(curve twistededwards.Curve, pk PublicKey, m, r frontend.Variable) Ciphertext {
+	base := basePoint(curve)
+	return Ciphertext{
+		C1: curve.ScalarMul(base, r),
+		C2: curve.DoubleBaseScalarMul(base, pk.A, m, r),
+	}
+}
+
+// AssertEncrypts asserts that ct is the encryption of the message m to the
+// public key pk with the randomness r.
+func AssertEncrypts(curve twistededwards.Curve, pk PublicKey, ct Ciphertext, m, r frontend.Variable) {
+	expected := Encrypt(curve, pk, m, r)
+	assertIsEqual(curve.API(), ct.C1, expected.C1)
+	assertIsEqual(curve.API(), ct.C2, expected.C2)
+}
+
+// AssertDecrypts asserts that ct decrypts to the message m with the private
+// key sk, that is [m]G + [sk]C1 = C2.
+func AssertDecrypts(curve twistededwards.Curve, sk frontend.Variable, ct Ciphertext, m frontend.Variable) {
+	res := curve.DoubleBaseScalarMul(basePoint(curve), ct.C1, m, sk)
+	assertIsEqual(curve.API(), res, ct.C2)
+}
+
+// AssertPublicKey asserts that pk is the public key of the private key sk.
+func AssertPublicKey(curve twistededwards.Curve, sk frontend.Variable, pk PublicKey) {
+	assertIsEqual(curve.API(), curve.ScalarMul(basePoint(curve), sk), pk.A)
+}
+
+// Rerandomize returns the re-randomization of ct with the randomness r, which
+// is an encryption of the same message to the public key pk.
+func Rerandomize(curve twistededwards.Curve, pk PublicKey, ct Ciphertext, r frontend.Variable) Ciphertext {
+	zero := Encrypt(curve, pk, 0, r)
+	return Add(curve, ct, zero)
+}
+
+// AssertRerandomizes asserts that rerandomized is the re-randomization of ct
+// with the randomness r. This proves that both ciphertexts encrypt the same
+// message without revealing it.
+func AssertRerandomizes(curve twistededwards.Curve, pk PublicKey, ct, rerandomized Ciphertext, r frontend.Variable) {
+	expected := Rerandomize(curve, pk, ct, r)
+	assertIsEqual(curve.API(), rerandomized.C1, expected.C1)
+	assertIsEqual(curve.API(), rerandomized.C2, expected.C2)
+}
+
+// Add returns the encryption of the sum of the messages encrypted by a and b.
+func Add(curve twistededwards.Curve, a, b Ciphertext) Ciphertext {
+	return Ciphertext{
+		C1: curve.Add(a.C1, b.C1),
+		C2: curve.Add(a.C2, b.C2),
+	}
+}
+
+// AssertIsOnCurve asserts that the points of the public key and the
+// ciphertexts are on the curve.
+func AssertIsOnCurve(curve twistededwards.Curve, pk PublicKey, cts ...Ciphertext) {
+	curve.AssertIsOnCurve(pk.A)
+	for _, ct := range cts {
+		curve.AssertIsOnCurve(ct.C1)
+		curve.AssertIsOnCurve(ct.C2)
+	}
+}
+
+func basePoint(curve twistededwards.Curve) twistededwards.Point {
+	return twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+}
+
+func assertIsEqual(api frontend.API, p, q twistededwards.Point) {
+	api.AssertIsEqual(p.X, q.X)
+	api.AssertIsEqual(p.Y, q.Y)
+}
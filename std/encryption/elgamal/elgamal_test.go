@@ -0,0 +1,90 @@
+package elgamal
+
+import (
+	"math/big"
+	"testing"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+type elgamalCircuit struct {
+	curveID      tedwards.ID
+	PrivateKey   frontend.Variable
+	PublicKey    PublicKey `gnark:",public"`
+	Ciphertext   Ciphertext
+	Rerandomized Ciphertext `gnark:",public"`
+	Sum          Ciphertext `gnark:",public"`
+	Message      frontend.Variable
+	Other        frontend.Variable
+	Randomness   frontend.Variable
+	Rerandomness frontend.Variable
+}
+
+func (c *elgamalCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, c.curveID)
+	if err != nil {
+		return err
+	}
+	AssertIsOnCurve(curve, c.PublicKey, c.Ciphertext, c.Rerandomized, c.Sum)
+	AssertPublicKey(curve, c.PrivateKey, c.PublicKey)
+	AssertEncrypts(curve, c.PublicKey, c.Ciphertext, c.Message, c.Randomness)
+	AssertRerandomizes(curve, c.PublicKey, c.Ciphertext, c.Rerandomized, c.Rerandomness)
+	AssertDecrypts(curve, c.PrivateKey, c.Rerandomized, c.Message)
+	other := Encrypt(curve, c.PublicKey, c.Other, c.Rerandomness)
+	sum := Add(curve, c.Ciphertext, other)
+	AssertDecrypts(curve, c.PrivateKey, sum, api.Add(c.Message, c.Other))
+	assertIsEqual(api, sum.C1, c.Sum.C1)
+	assertIsEqual(api, sum.C2, c.Sum.C2)
+	return nil
+}
+
+func TestElGamal(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, id := range []tedwards.ID{tedwards.BN254, tedwards.BLS12_381} {
+		n, err := NewNative(id)
+		assert.NoError(err)
+		field, err := twistededwards.GetSnarkField(id)
+		assert.NoError(err)
+		sk, pk, err := n.GenerateKey(nil)
+		assert.NoError(err)
+		r, err := n.RandomScalar(nil)
+		assert.NoError(err)
+		r2, err := n.RandomScalar(nil)
+		assert.NoError(err)
+		m, other := big.NewInt(1234), big.NewInt(42)
+		ct := n.Encrypt(pk, m, r)
+		rerandomized := n.Rerandomize(pk, ct, r2)
+		sum := n.AddCiphertexts(ct, n.Encrypt(pk, other, r2))
+
+		dec, err := n.Decrypt(sk, rerandomized, 1<<16)
+		assert.NoError(err)
+		assert.Equal(uint64(1234), dec)
+		dec, err = n.Decrypt(sk, sum, 1<<16)
+		assert.NoError(err)
+		assert.Equal(uint64(1276), dec)
+		_, err = n.Decrypt(sk, sum, 1000)
+		assert.Error(err)
+
+		witness := func(m *big.Int) *elgamalCircuit {
+			return &elgamalCircuit{
+				PrivateKey:   sk,
+				PublicKey:    PublicKey{A: pk.Point()},
+				Ciphertext:   ct.Ciphertext(),
+				Rerandomized: rerandomized.Ciphertext(),
+				Sum:          sum.Ciphertext(),
+				Message:      m,
+				Other:        other,
+				Randomness:   r,
+				Rerandomness: r2,
+			}
+		}
+		circuit := elgamalCircuit{curveID: id}
+		err = test.IsSolved(&circuit, witness(m), field)
+		assert.NoError(err)
+		err = test.IsSolved(&circuit, witness(big.NewInt(1235)), field)
+		assert.Error(err)
+	}
+}
@@ -0,0 +1,272 @@
+package elgamal
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	tbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	tbls12381_bandersnatch "github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	tbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+	tbls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+	tbls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/twistededwards"
+	tbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tbw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/twistededwards"
+	tbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+)
+
+// NativePoint is a point of the curve outside of the circuit.
+type NativePoint struct {
+	X, Y *big.Int
+}
+
+// Point returns the assignment of the point.
+func (p NativePoint) Point() twistededwards.Point {
+	return twistededwards.Point{X: p.X, Y: p.Y}
+}
+
+// NativeCiphertext is a ciphertext outside of the circuit.
+type NativeCiphertext struct {
+	C1, C2 NativePoint
+}
+
+// Ciphertext returns the assignment of the ciphertext.
+func (ct NativeCiphertext) Ciphertext() Ciphertext {
+	return Ciphertext{C1: ct.C1.Point(), C2: ct.C2.Point()}
+}
+
+// Native performs the ElGamal operations outside of the circuit, for instance
+// to compute the witnesses of the circuits.
+type Native struct {
+	params *twistededwards.CurveParams
+	arith  arithmetic
+}
+
+// NewNative returns a new instance for the twisted Edwards curve id.
+func NewNative(id tedwards.ID) (*Native, error) {
+	params, err := twistededwards.GetCurveParams(id)
+	if err != nil {
+		return nil, fmt.Errorf("curve params: %w", err)
+	}
+	arith, err := newArithmetic(id)
+	if err != nil {
+		return nil, fmt.Errorf("arithmetic: %w", err)
+	}
+	return &Native{params: params, arith: arith}, nil
+}
+
+// Base returns the base point of the curve.
+func (n *Native) Base() NativePoint {
+	return NativePoint{X: new(big.Int).Set(n.params.Base[0]), Y: new(big.Int).Set(n.params.Base[1])}
+}
+
+// RandomScalar returns a random scalar modulo the order of the subgroup
+// generated by the base point. If rnd is nil, then crypto/rand is used.
+func (n *Native) RandomScalar(rnd io.Reader) (*big.Int, error) {
+	if rnd == nil {
+		rnd = rand.Reader
+	}
+	return rand.Int(rnd, n.params.Order)
+}
+
+// GenerateKey returns a new private key and the matching public key.
+func (n *Native) GenerateKey(rnd io.Reader) (*big.Int, NativePoint, error) {
+	sk, err := n.RandomScalar(rnd)
+	if err != nil {
+		return nil, NativePoint{}, fmt.Errorf("random scalar: %w", err)
+	}
+	return sk, n.ScalarMul(n.Base(), sk), nil
+}
+
+// Encrypt returns the encryption of the message m to the public key pk with
+// the randomness r.
+func (n *Native) Encrypt(pk NativePoint, m, r *big.Int) NativeCiphertext {
+	return NativeCiphertext{
+		C1: n.ScalarMul(n.Base(), r),
+		C2: n.Add(n.ScalarMul(n.Base(), m), n.ScalarMul(pk, r)),
+	}
+}
+
+// Rerandomize returns the re-randomization of ct with the randomness r.
+func (n *Native) Rerandomize(pk NativePoint, ct NativeCiphertext, r *big.Int) NativeCiphertext {
+	return n.AddCiphertexts(ct, n.Encrypt(pk, new(big.Int), r))
+}
+
+// AddCiphertexts returns the encryption of the sum of the messages encrypted by
+// a and b.
+func (n *Native) AddCiphertexts(a, b NativeCiphertext) NativeCiphertext {
+	return NativeCiphertext{C1: n.Add(a.C1, b.C1), C2: n.Add(a.C2, b.C2)}
+}
+
+// Decrypt returns the message encrypted by ct, which must be less than bound.
+// The discrete logarithm is computed with the baby-step giant-step algorithm
+// in O(sqrt(bound)) time and memory.
+func (n *Native) Decrypt(sk *big.Int, ct NativeCiphertext, bound uint64) (uint64, error) {
+	// [m]G = C2 - [sk]C1
+	target := n.Add(ct.C2, n.Neg(n.ScalarMul(ct.C1, sk)))
+	steps := uint64(1)
+	for steps*steps < bound {
+		steps++
+	}
+	baby := make(map[string]uint64, steps)
+	p := n.identity()
+	for j := uint64(0); j < steps; j++ {
+		if _, ok := baby[p.key()]; !ok {
+			baby[p.key()] = j
+		}
+		p = n.Add(p, n.Base())
+	}
+	giant := n.Neg(n.ScalarMul(n.Base(), new(big.Int).SetUint64(steps)))
+	for i := uint64(0); i*steps < bound; i++ {
+		if j, ok := baby[target.key()]; ok && i*steps+j < bound {
+			return i*steps + j, nil
+		}
+		target = n.Add(target, giant)
+	}
+	return 0, errors.New("message out of range")
+}
+
+// Add returns the sum of the points p and q.
+func (n *Native) Add(p, q NativePoint) NativePoint {
+	return n.arith.add(p, q)
+}
+
+// Neg returns the opposite of the point p.
+func (n *Native) Neg(p NativePoint) NativePoint {
+	return n.arith.neg(p)
+}
+
+// ScalarMul returns [s]p.
+func (n *Native) ScalarMul(p NativePoint, s *big.Int) NativePoint {
+	return n.arith.scalarMul(p, s)
+}
+
+func (n *Native) identity() NativePoint {
+	return NativePoint{X: new(big.Int), Y: big.NewInt(1)}
+}
+
+func (p NativePoint) key() string {
+	return p.X.String() + "," + p.Y.String()
+}
+
+// arithmetic implements the group law of a twisted Edwards curve on the
+// points given by their coordinates.
+type arithmetic interface {
+	add(p, q NativePoint) NativePoint
+	neg(p NativePoint) NativePoint
+	scalarMul(p NativePoint, s *big.Int) NativePoint
+}
+
+// affinePoint is the set of methods of the affine points of the twisted
+// Edwards curves of gnark-crypto used by [pointArithmetic].
+type affinePoint[T any] interface {
+	*T
+	Add(p1, p2 *T) *T
+	Neg(p1 *T) *T
+	ScalarMultiplication(p1 *T, scalar *big.Int) *T
+}
+
+// pointArithmetic implements [arithmetic] with the affine points T of
+// gnark-crypto, which are converted from and to [NativePoint] with set and
+// get.
+type pointArithmetic[T any, P affinePoint[T]] struct {
+	set func(p P, x, y *big.Int)
+	get func(p P) NativePoint
+}
+
+func newPointArithmetic[T any, P affinePoint[T]](set func(p P, x, y *big.Int), get func(p P) NativePoint) pointArithmetic[T, P] {
+	return pointArithmetic[T, P]{set: set, get: get}
+}
+
+func (a pointArithmetic[T, P]) point(p NativePoint) P {
+	res := P(new(T))
+	a.set(res, p.X, p.Y)
+	return res
+}
+
+func (a pointArithmetic[T, P]) add(p, q NativePoint) NativePoint {
+	res := a.point(p)
+	res.Add(res, a.point(q))
+	return a.get(res)
+}
+
+func (a pointArithmetic[T, P]) neg(p NativePoint) NativePoint {
+	res := a.point(p)
+	res.Neg(res)
+	return a.get(res)
+}
+
+func (a pointArithmetic[T, P]) scalarMul(p NativePoint, s *big.Int) NativePoint {
+	res := a.point(p)
+	res.ScalarMultiplication(res, s)
+	return a.get(res)
+}
+
+// newArithmetic returns the arithmetic of gnark-crypto for the twisted Edwards
+// curve id.
+func newArithmetic(id tedwards.ID) (arithmetic, error) {
+	switch id {
+	case tedwards.BN254:
+		return newPointArithmetic(
+			func(p *tbn254.PointAffine, x, y *big.Int) { p.X.SetBigInt(x); p.Y.SetBigInt(y) },
+			func(p *tbn254.PointAffine) NativePoint {
+				return NativePoint{X: p.X.BigInt(new(big.Int)), Y: p.Y.BigInt(new(big.Int))}
+			},
+		), nil
+	case tedwards.BLS12_377:
+		return newPointArithmetic(
+			func(p *tbls12377.PointAffine, x, y *big.Int) { p.X.SetBigInt(x); p.Y.SetBigInt(y) },
+			func(p *tbls12377.PointAffine) NativePoint {
+				return NativePoint{X: p.X.BigInt(new(big.Int)), Y: p.Y.BigInt(new(big.Int))}
+			},
+		), nil
+	case tedwards.BLS12_381:
+		return newPointArithmetic(
+			func(p *tbls12381.PointAffine, x, y *big.Int) { p.X.SetBigInt(x); p.Y.SetBigInt(y) },
+			func(p *tbls12381.PointAffine) NativePoint {
+				return NativePoint{X: p.X.BigInt(new(big.Int)), Y: p.Y.BigInt(new(big.Int))}
+			},
+		), nil
+	case tedwards.BLS12_381_BANDERSNATCH:
+		return newPointArithmetic(
+			func(p *tbls12381_bandersnatch.PointAffine, x, y *big.Int) { p.X.SetBigInt(x); p.Y.SetBigInt(y) },
+			func(p *tbls12381_bandersnatch.PointAffine) NativePoint {
+				return NativePoint{X: p.X.BigInt(new(big.Int)), Y: p.Y.BigInt(new(big.Int))}
+			},
+		), nil
+	case tedwards.BLS24_315:
+		return newPointArithmetic(
+			func(p *tbls24315.PointAffine, x, y *big.Int) { p.X.SetBigInt(x); p.Y.SetBigInt(y) },
+			func(p *tbls24315.PointAffine) NativePoint {
+				return NativePoint{X: p.X.BigInt(new(big.Int)), Y: p.Y.BigInt(new(big.Int))}
+			},
+		), nil
+	case tedwards.BLS24_317:
+		return newPointArithmetic(
+			func(p *tbls24317.PointAffine, x, y *big.Int) { p.X.SetBigInt(x); p.Y.SetBigInt(y) },
+			func(p *tbls24317.PointAffine) NativePoint {
+				return NativePoint{X: p.X.BigInt(new(big.Int)), Y: p.Y.BigInt(new(big.Int))}
+			},
+		), nil
+	case tedwards.BW6_761:
+		return newPointArithmetic(
+			func(p *tbw6761.PointAffine, x, y *big.Int) { p.X.SetBigInt(x); p.Y.SetBigInt(y) },
+			func(p *tbw6761.PointAffine) NativePoint {
+				return NativePoint{X: p.X.BigInt(new(big.Int)), Y: p.Y.BigInt(new(big.Int))}
+			},
+		), nil
+	case tedwards.BW6_633:
+		return newPointArithmetic(
+			func(p *tbw6633.PointAffine, x, y *big.Int) { p.X.SetBigInt(x); p.Y.SetBigInt(y) },
+			func(p *tbw6633.PointAffine) NativePoint {
+				return NativePoint{X: p.X.BigInt(new(big.Int)), Y: p.Y.BigInt(new(big.Int))}
+			},
+		), nil
+	default:
+		return nil, errors.New("unknown twisted edwards curve id")
+	}
+}
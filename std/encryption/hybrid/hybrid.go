@@ -0,0 +1,110 @@
+// Package hybrid implements hybrid encryption of field elements over twisted
+// Edwards elliptic curves available in gnark and gnark-crypto.
+//
+// The sender derives a shared secret S = [r]PK from an ephemeral randomness r
+// and the ElGamal public key PK of the recipient with the Diffie-Hellman key
+// exchange. The key stream is not squeezed from a sponge: it is derived from S
+// in counter mode, with one full call of the field hash function H per message
+// element, and the ciphertext of the message element m_i is
+//
+//	c_i = m_i + H(DomainKeyStream, S.X, S.Y, i).
+//
+// The ciphertexts are authenticated by the tag
+//
+//	H(DomainTag, S.X, S.Y, n, c_0, ..., c_{n-1})
+//
+// where n is the number of elements. The distinct domain tags ensure that the
+// absorbed prefix of the tag never matches a key stream block, so that the
+// intermediate hash state of the tag does not reveal the key stream. The
+// ciphertext consists of the ephemeral point R = [r]G, the encrypted elements
+// and the tag.
+//
+// The circuit side takes any [hash.FieldHasher] and the [Native] type takes
+// its native counterpart, for example gnark-crypto MiMC for [mimc.NewMiMC] or
+// the native Poseidon for [poseidon.NewPoseidon].
+//
+// [mimc.NewMiMC]: https://pkg.go.dev/github.com/consensys/gnark/std/hash/mimc#NewMiMC
+// [poseidon.NewPoseidon]: https://pkg.go.dev/github.com/consensys/gnark/std/hash/poseidon#NewPoseidon
+package hybrid
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/encryption/elgamal"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// Domain separation tags of the hashes of the key stream blocks and of the
+// authentication tag.
+const (
+	DomainKeyStream = 1
+	DomainTag       = 2
+)
+
+// Ciphertext stores a hybrid ciphertext (to be used in gnark circuit)
+type Ciphertext struct {
+	R   twistededwards.Point
+	C   []frontend.Variable
+	Tag frontend.Variable
+}
+
+// Encrypt returns the encryption of the elements of msg to the public key pk
+// with the randomness r.
+func Encrypt(curve twistededwards.Curve, h hash.FieldHasher, pk elgamal.PublicKey, r frontend.Variable, msg []frontend.Variable) Ciphertext {
+	base := twistededwards.Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}
+	s := curve.ScalarMul(pk.A, r)
+	api := curve.API()
+	ct := Ciphertext{R: curve.ScalarMul(base, r), C: make([]frontend.Variable, len(msg))}
+	for i := range msg {
+		ct.C[i] = api.Add(msg[i], keyStream(h, s, i))
+	}
+	ct.Tag = tag(h, s, ct.C)
+	return ct
+}
+
+// AssertEncrypts asserts that ct is the encryption of the elements of msg to
+// the public key pk with the randomness r. It returns an error if the number
+// of encrypted elements does not match the length of msg.
+func AssertEncrypts(curve twistededwards.Curve, h hash.FieldHasher, pk elgamal.PublicKey, ct Ciphertext, r frontend.Variable, msg []frontend.Variable) error {
+	api := curve.API()
+	if len(ct.C) != len(msg) {
+		return fmt.Errorf("ciphertext has %d elements, message has %d", len(ct.C), len(msg))
+	}
+	expected := Encrypt(curve, h, pk, r, msg)
+	api.AssertIsEqual(ct.R.X, expected.R.X)
+	api.AssertIsEqual(ct.R.Y, expected.R.Y)
+	for i := range ct.C {
+		api.AssertIsEqual(ct.C[i], expected.C[i])
+	}
+	api.AssertIsEqual(ct.Tag, expected.Tag)
+	return nil
+}
+
+// Decrypt returns the elements encrypted by ct with the private key sk. It
+// asserts that the tag of the ciphertext is valid. The point R must be
+// asserted to be on the curve by the caller.
+func Decrypt(curve twistededwards.Curve, h hash.FieldHasher, sk frontend.Variable, ct Ciphertext) []frontend.Variable {
+	api := curve.API()
+	s := curve.ScalarMul(ct.R, sk)
+	api.AssertIsEqual(ct.Tag, tag(h, s, ct.C))
+	res := make([]frontend.Variable, len(ct.C))
+	for i := range ct.C {
+		res[i] = api.Sub(ct.C[i], keyStream(h, s, i))
+	}
+	return res
+}
+
+func keyStream(h hash.FieldHasher, s twistededwards.Point, i int) frontend.Variable {
+	h.Reset()
+	h.Write(DomainKeyStream, s.X, s.Y, i)
+	return h.Sum()
+}
+
+func tag(h hash.FieldHasher, s twistededwards.Point, c []frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(DomainTag, s.X, s.Y, len(c))
+	h.Write(c...)
+	return h.Sum()
+}
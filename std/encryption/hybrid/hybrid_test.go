@@ -0,0 +1,138 @@
+package hybrid
+
+import (
+	stdhash "hash"
+	"math/big"
+	"testing"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/encryption/elgamal"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gnark/test"
+)
+
+type hybridCircuit struct {
+	curveID    tedwards.ID
+	poseidon   bool
+	PublicKey  elgamal.PublicKey `gnark:",public"`
+	Ciphertext Ciphertext        `gnark:",public"`
+	PrivateKey frontend.Variable
+	Randomness frontend.Variable
+	Message    []frontend.Variable
+}
+
+func (c *hybridCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, c.curveID)
+	if err != nil {
+		return err
+	}
+	var h hash.FieldHasher
+	if c.poseidon {
+		p, err := poseidon.NewPoseidon(api)
+		if err != nil {
+			return err
+		}
+		h = &p
+	} else {
+		m, err := mimc.NewMiMC(api)
+		if err != nil {
+			return err
+		}
+		h = &m
+	}
+	if err := AssertEncrypts(curve, h, c.PublicKey, c.Ciphertext, c.Randomness, c.Message); err != nil {
+		return err
+	}
+	curve.AssertIsOnCurve(c.Ciphertext.R)
+	msg := Decrypt(curve, h, c.PrivateKey, c.Ciphertext)
+	for i := range msg {
+		api.AssertIsEqual(msg[i], c.Message[i])
+	}
+	return nil
+}
+
+func TestHybrid(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, tc := range []struct {
+		id       tedwards.ID
+		h        stdhash.Hash
+		poseidon bool
+	}{
+		{tedwards.BN254, cryptohash.MIMC_BN254.New(), false},
+		{tedwards.BLS12_381, cryptohash.MIMC_BLS12_381.New(), false},
+		{tedwards.BN254, poseidon.NewNative(), true},
+	} {
+		id := tc.id
+		curve, err := elgamal.NewNative(id)
+		assert.NoError(err)
+		n, err := NewNative(id, tc.h)
+		assert.NoError(err)
+		field, err := twistededwards.GetSnarkField(id)
+		assert.NoError(err)
+		sk, pk, err := curve.GenerateKey(nil)
+		assert.NoError(err)
+		r, err := curve.RandomScalar(nil)
+		assert.NoError(err)
+		msg := []*big.Int{big.NewInt(1), big.NewInt(2), new(big.Int).Sub(field, big.NewInt(1))}
+		ct, err := n.Encrypt(pk, r, msg)
+		assert.NoError(err)
+
+		dec, err := n.Decrypt(sk, ct)
+		assert.NoError(err)
+		assert.Equal(msg, dec)
+		other, _, err := curve.GenerateKey(nil)
+		assert.NoError(err)
+		_, err = n.Decrypt(other, ct)
+		assert.Error(err)
+
+		witness := func(sk *big.Int) *hybridCircuit {
+			w := &hybridCircuit{
+				PublicKey:  elgamal.PublicKey{A: pk.Point()},
+				Ciphertext: ct.Ciphertext(),
+				PrivateKey: sk,
+				Randomness: r,
+				Message:    make([]frontend.Variable, len(msg)),
+			}
+			for i := range msg {
+				w.Message[i] = msg[i]
+			}
+			return w
+		}
+		circuit := hybridCircuit{
+			curveID:    id,
+			poseidon:   tc.poseidon,
+			Ciphertext: Ciphertext{C: make([]frontend.Variable, len(msg))},
+			Message:    make([]frontend.Variable, len(msg)),
+		}
+		err = test.IsSolved(&circuit, witness(sk), field)
+		assert.NoError(err)
+		err = test.IsSolved(&circuit, witness(other), field)
+		assert.Error(err)
+	}
+}
+
+func TestHybridLengthMismatch(t *testing.T) {
+	assert := test.NewAssert(t)
+	id := tedwards.BN254
+	field, err := twistededwards.GetSnarkField(id)
+	assert.NoError(err)
+	circuit := hybridCircuit{
+		curveID:    id,
+		Ciphertext: Ciphertext{C: make([]frontend.Variable, 2)},
+		Message:    make([]frontend.Variable, 3),
+	}
+	witness := hybridCircuit{
+		PublicKey:  elgamal.PublicKey{A: twistededwards.Point{X: 0, Y: 1}},
+		Ciphertext: Ciphertext{R: twistededwards.Point{X: 0, Y: 1}, C: []frontend.Variable{0, 0}, Tag: 0},
+		PrivateKey: 0,
+		Randomness: 0,
+		Message:    []frontend.Variable{0, 0, 0},
+	}
+	err = test.IsSolved(&circuit, &witness, field)
+	assert.ErrorContains(err, "ciphertext has 2 elements, message has 3")
+}
@@ -0,0 +1,114 @@
+package hybrid
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/encryption/elgamal"
+)
+
+// NativeCiphertext is a ciphertext outside of the circuit.
+type NativeCiphertext struct {
+	R   elgamal.NativePoint
+	C   []*big.Int
+	Tag *big.Int
+}
+
+// Ciphertext returns the assignment of the ciphertext.
+func (ct NativeCiphertext) Ciphertext() Ciphertext {
+	res := Ciphertext{R: ct.R.Point(), C: make([]frontend.Variable, len(ct.C)), Tag: ct.Tag}
+	for i := range ct.C {
+		res.C[i] = ct.C[i]
+	}
+	return res
+}
+
+// Native performs the hybrid encryption outside of the circuit with the
+// native counterpart of the in-circuit hash function, for example
+// gnark-crypto MiMC for [mimc.NewMiMC]. The field elements are written into
+// the hash function in big-endian form, padded to the size of the modulus.
+//
+// [mimc.NewMiMC]: https://pkg.go.dev/github.com/consensys/gnark/std/hash/mimc#NewMiMC
+type Native struct {
+	curve   *elgamal.Native
+	h       hash.Hash
+	modulus *big.Int
+}
+
+// NewNative returns a new instance for the twisted Edwards curve id and the
+// hash function h over the scalar field of the SNARK curve.
+func NewNative(id tedwards.ID, h hash.Hash) (*Native, error) {
+	curve, err := elgamal.NewNative(id)
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	modulus, err := twistededwards.GetSnarkField(id)
+	if err != nil {
+		return nil, fmt.Errorf("snark field: %w", err)
+	}
+	return &Native{curve: curve, h: h, modulus: modulus}, nil
+}
+
+// Encrypt returns the encryption of the elements of msg to the public key pk
+// with the randomness r.
+func (n *Native) Encrypt(pk elgamal.NativePoint, r *big.Int, msg []*big.Int) (NativeCiphertext, error) {
+	s := n.curve.ScalarMul(pk, r)
+	ct := NativeCiphertext{R: n.curve.ScalarMul(n.curve.Base(), r), C: make([]*big.Int, len(msg))}
+	for i := range msg {
+		k, err := n.hash(big.NewInt(DomainKeyStream), s.X, s.Y, big.NewInt(int64(i)))
+		if err != nil {
+			return NativeCiphertext{}, fmt.Errorf("key stream: %w", err)
+		}
+		ct.C[i] = k.Add(k, msg[i]).Mod(k, n.modulus)
+	}
+	t, err := n.tag(s, ct.C)
+	if err != nil {
+		return NativeCiphertext{}, fmt.Errorf("tag: %w", err)
+	}
+	ct.Tag = t
+	return ct, nil
+}
+
+// Decrypt returns the elements encrypted by ct with the private key sk. It
+// returns an error if the tag of the ciphertext is invalid.
+func (n *Native) Decrypt(sk *big.Int, ct NativeCiphertext) ([]*big.Int, error) {
+	s := n.curve.ScalarMul(ct.R, sk)
+	t, err := n.tag(s, ct.C)
+	if err != nil {
+		return nil, fmt.Errorf("tag: %w", err)
+	}
+	if ct.Tag == nil || t.Cmp(ct.Tag) != 0 {
+		return nil, errors.New("invalid tag")
+	}
+	res := make([]*big.Int, len(ct.C))
+	for i := range ct.C {
+		k, err := n.hash(big.NewInt(DomainKeyStream), s.X, s.Y, big.NewInt(int64(i)))
+		if err != nil {
+			return nil, fmt.Errorf("key stream: %w", err)
+		}
+		res[i] = k.Sub(ct.C[i], k).Mod(k, n.modulus)
+	}
+	return res, nil
+}
+
+func (n *Native) tag(s elgamal.NativePoint, c []*big.Int) (*big.Int, error) {
+	return n.hash(append([]*big.Int{big.NewInt(DomainTag), s.X, s.Y, big.NewInt(int64(len(c)))}, c...)...)
+}
+
+// hash returns the hash of the field elements inputs.
+func (n *Native) hash(inputs ...*big.Int) (*big.Int, error) {
+	n.h.Reset()
+	buf := make([]byte, (n.modulus.BitLen()+7)/8)
+	for _, x := range inputs {
+		new(big.Int).Mod(x, n.modulus).FillBytes(buf)
+		if _, err := n.h.Write(buf); err != nil {
+			return nil, err
+		}
+	}
+	return new(big.Int).SetBytes(n.h.Sum(nil)), nil
+}
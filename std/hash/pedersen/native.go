@@ -0,0 +1,212 @@
+package pedersen
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark/std/internal/babyjubjub"
+)
+
+const (
+	windowSize          = 4
+	nbWindowsPerSegment = 50
+	bitsPerSegment      = windowSize * nbWindowsPerSegment
+)
+
+var (
+	basesLock sync.Mutex
+	// bases[i] is the generator of the i-th segment in gnark coordinates
+	bases []edbn254.PointAffine
+)
+
+// basePoint returns the generator of the segment in gnark coordinates.
+func basePoint(segment int) edbn254.PointAffine {
+	basesLock.Lock()
+	defer basesLock.Unlock()
+	for len(bases) <= segment {
+		bases = append(bases, deriveBasePoint(len(bases)))
+	}
+	return bases[segment]
+}
+
+// deriveBasePoint derives the generator of the segment as circomlib. The
+// BLAKE-256 hash of "PedersenGenerator_<segment>_<try>", with the indices
+// padded to 32 digits, is decoded as a packed point for increasing tries until
+// it is on the curve, and the generator is the point multiplied by the
+// cofactor.
+func deriveBasePoint(segment int) edbn254.PointAffine {
+	params := edbn254.GetEdwardsCurve()
+	for try := 0; ; try++ {
+		h := blake256([]byte(fmt.Sprintf("PedersenGenerator_%032d_%032d", segment, try)))
+		h[31] &= 0xbf
+		p, ok := unpackPoint(h)
+		if !ok {
+			continue
+		}
+		p.ScalarMultiplication(&p, big.NewInt(8))
+		var check edbn254.PointAffine
+		check.ScalarMultiplication(&p, &params.Order)
+		if !check.IsZero() {
+			panic("base point not in subgroup")
+		}
+		return p
+	}
+}
+
+// unpackPoint decodes the circomlib packed point. The 255 low bits are the
+// little-endian y coordinate and the high bit is set when x is negative,
+// that is larger than (p-1)/2. It returns false if there is no such point.
+func unpackPoint(buf [32]byte) (edbn254.PointAffine, bool) {
+	var res edbn254.PointAffine
+	negative := buf[31]&0x80 != 0
+	buf[31] &= 0x7f
+	for i := 0; i < len(buf)/2; i++ {
+		buf[i], buf[len(buf)-1-i] = buf[len(buf)-1-i], buf[i]
+	}
+	y := new(big.Int).SetBytes(buf[:])
+	if y.Cmp(fr.Modulus()) >= 0 {
+		return res, false
+	}
+	// x² = (1 - y²) / (168700 - 168696y²)
+	var num, den, x fr.Element
+	res.Y.SetBigInt(y)
+	num.Square(&res.Y)
+	den.SetUint64(168696).Mul(&den, &num)
+	den.Sub(new(fr.Element).SetUint64(168700), &den)
+	num.Sub(new(fr.Element).SetOne(), &num)
+	if den.IsZero() {
+		return res, false
+	}
+	num.Div(&num, &den)
+	if x.Sqrt(&num) == nil {
+		return res, false
+	}
+	if isNegative(&x) != negative {
+		x.Neg(&x)
+	}
+	return babyjubjub.NativeFromCircomlib(&x, &res.Y), true
+}
+
+// isNegative returns true if x is larger than (p-1)/2.
+func isNegative(x *fr.Element) bool {
+	return x.LexicographicallyLargest()
+}
+
+// NativeHashBits returns the Pedersen hash of the bits outside of the circuit,
+// in the circomlib coordinates of Baby Jubjub.
+func NativeHashBits(bits []bool) (x, y *big.Int) {
+	params := edbn254.GetEdwardsCurve()
+	var res edbn254.PointAffine
+	res.X.SetZero()
+	res.Y.SetOne()
+	for s := 0; s*bitsPerSegment < len(bits); s++ {
+		segment := bits[s*bitsPerSegment : min(len(bits), (s+1)*bitsPerSegment)]
+		// the scalar is Σ_w ±(1 + b0 + 2b1 + 4b2)·2^(5w) where b3 is the sign
+		scalar := new(big.Int)
+		for w := 0; w*windowSize < len(segment); w++ {
+			acc := int64(1)
+			for b := 0; b < windowSize-1; b++ {
+				if o := w*windowSize + b; o < len(segment) && segment[o] {
+					acc += 1 << b
+				}
+			}
+			if o := w*windowSize + windowSize - 1; o < len(segment) && segment[o] {
+				acc = -acc
+			}
+			term := big.NewInt(acc)
+			scalar.Add(scalar, term.Lsh(term, uint(w*(windowSize+1))))
+		}
+		scalar.Mod(scalar, &params.Order)
+		var p edbn254.PointAffine
+		base := basePoint(s)
+		p.ScalarMultiplication(&base, scalar)
+		res.Add(&res, &p)
+	}
+	return babyjubjub.NativeToCircomlib(&res)
+}
+
+// NativeHash returns the Pedersen hash of the bytes msg outside of the circuit
+// as the circomlibjs pedersenHash.hash function. The bits of every byte are
+// hashed least significant bit first and the point is packed as in
+// circomlibjs, in 32 bytes of the little-endian y coordinate with the high bit
+// set when x is negative.
+func NativeHash(msg []byte) [32]byte {
+	bits := make([]bool, 8*len(msg))
+	for i := range bits {
+		bits[i] = (msg[i/8]>>(i%8))&1 == 1
+	}
+	x, y := NativeHashBits(bits)
+	var res [32]byte
+	y.FillBytes(res[:])
+	for i := 0; i < len(res)/2; i++ {
+		res[i], res[len(res)-1-i] = res[len(res)-1-i], res[i]
+	}
+	var xe fr.Element
+	xe.SetBigInt(x)
+	if isNegative(&xe) {
+		res[31] |= 0x80
+	}
+	return res
+}
+
+// NewNative returns the out-of-circuit counterpart of [Pedersen] as a
+// [hash.Hash]. Every block of [fr.Bytes] bytes written is a big-endian field
+// element, which must fit in nbBits bits, and Sum returns the x coordinate of
+// the hash of the elements written since the last call to Sum or Reset, which
+// are flushed.
+func NewNative(nbBits int) (hash.Hash, error) {
+	if nbBits <= 0 || nbBits > fr.Bits {
+		return nil, fmt.Errorf("invalid number of bits %d", nbBits)
+	}
+	return &digest{nbBits: nbBits}, nil
+}
+
+type digest struct {
+	nbBits int
+	bits   []bool
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	if len(p)%fr.Bytes != 0 {
+		return 0, errors.New("invalid input length: must represent a list of field elements")
+	}
+	for i := 0; i < len(p); i += fr.Bytes {
+		e, err := fr.BigEndian.Element((*[fr.Bytes]byte)(p[i : i+fr.Bytes]))
+		if err != nil {
+			return 0, err
+		}
+		v := e.BigInt(new(big.Int))
+		if v.BitLen() > d.nbBits {
+			return 0, fmt.Errorf("element does not fit in %d bits", d.nbBits)
+		}
+		for j := 0; j < d.nbBits; j++ {
+			d.bits = append(d.bits, v.Bit(j) == 1)
+		}
+	}
+	return len(p), nil
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	x, _ := NativeHashBits(d.bits)
+	d.bits = nil
+	var buf [fr.Bytes]byte
+	x.FillBytes(buf[:])
+	return append(b, buf[:]...)
+}
+
+func (d *digest) Reset() {
+	d.bits = nil
+}
+
+func (d *digest) Size() int {
+	return fr.Bytes
+}
+
+func (d *digest) BlockSize() int {
+	return fr.Bytes
+}
@@ -0,0 +1,141 @@
+// Package pedersen provides a ZKP-circuit function to compute the Pedersen
+// hash of circomlib over Baby Jubjub, the twisted Edwards curve of the BN254
+// scalar field.
+//
+// The input bits are split into segments of 200 bits, each with its own
+// generator G_s. A segment is split into windows of 4 bits b0, b1, b2, b3 and
+// the hash is the point
+//
+//	Σ_s [Σ_w (-1)^b3·(1 + b0 + 2b1 + 4b2)·2^(5w)]G_s.
+//
+// The generators are derived as in circomlib from the BLAKE-256 hashes of
+// "PedersenGenerator_<segment>_<try>" and the points are in the circomlib
+// coordinates of Baby Jubjub on 168700x² + y² = 1 + 168696x²y². In-circuit,
+// the windows select precomputed multiples of the generators with 2-bit
+// lookups and the points are added with the twisted Edwards arithmetic of
+// gnark.
+//
+// As a [hash.FieldHasher], every written element is decomposed into a fixed
+// number of bits, least significant bit first as the Num2Bits template of
+// circomlib, and the digest is the x coordinate of the hash of all the bits.
+// For instance the commitment of Tornado Cash is the digest of the nullifier
+// and the secret with 248 bits each.
+//
+// [hash.FieldHasher]: https://pkg.go.dev/github.com/consensys/gnark/std/hash#FieldHasher
+package pedersen
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/internal/babyjubjub"
+)
+
+// Pedersen computes the circomlib Pedersen hash of the written inputs.
+type Pedersen struct {
+	api    frontend.API
+	curve  twistededwards.Curve
+	nbBits int
+	data   []frontend.Variable
+}
+
+// NewPedersen returns a Pedersen instance, that can be used in a gnark circuit
+// over the BN254 scalar field, where every written element is decomposed into
+// nbBits bits.
+func NewPedersen(api frontend.API, nbBits int) (Pedersen, error) {
+	if nbBits <= 0 || nbBits > fr.Bits {
+		return Pedersen{}, fmt.Errorf("invalid number of bits %d", nbBits)
+	}
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return Pedersen{}, fmt.Errorf("new curve: %w", err)
+	}
+	return Pedersen{api: api, curve: curve, nbBits: nbBits}, nil
+}
+
+// Write adds more data to the running hash.
+func (h *Pedersen) Write(data ...frontend.Variable) {
+	h.data = append(h.data, data...)
+}
+
+// Reset resets the Hash to its initial state.
+func (h *Pedersen) Reset() {
+	h.data = nil
+}
+
+// Sum returns the x coordinate of the hash of the bits of all the inputs
+// written since the last call to Sum or Reset, which are flushed. It asserts
+// that the inputs fit in the number of bits.
+func (h *Pedersen) Sum() frontend.Variable {
+	var bits []frontend.Variable
+	for _, d := range h.data {
+		bits = append(bits, h.api.ToBinary(d, h.nbBits)...)
+	}
+	h.data = nil
+	return h.hashBits(bits).X
+}
+
+// HashBits returns the hash of the bits in the circomlib coordinates of Baby
+// Jubjub. It asserts that the bits are boolean.
+func (h *Pedersen) HashBits(bits []frontend.Variable) twistededwards.Point {
+	for i := range bits {
+		h.api.AssertIsBoolean(bits[i])
+	}
+	return h.hashBits(bits)
+}
+
+func (h *Pedersen) hashBits(bits []frontend.Variable) twistededwards.Point {
+	api := h.api
+	res := twistededwards.Point{X: 0, Y: 1}
+	for s := 0; s*bitsPerSegment < len(bits); s++ {
+		segment := bits[s*bitsPerSegment : min(len(bits), (s+1)*bitsPerSegment)]
+		base := basePoint(s)
+		for w := 0; w*windowSize < len(segment); w++ {
+			// the missing bits of the last window are zero.
+			var window [windowSize]frontend.Variable
+			for b := range window {
+				window[b] = 0
+				if o := w*windowSize + b; o < len(segment) {
+					window[b] = segment[o]
+				}
+			}
+			// table[k] = [k+1]2^(5w)G_s
+			var table [8]edbn254.PointAffine
+			table[0] = base
+			for k := 1; k < len(table); k++ {
+				table[k].Add(&table[k-1], &base)
+			}
+			var lo, hi [2]frontend.Variable
+			var coords [8][2]*big.Int
+			for k := range table {
+				coords[k][0] = table[k].X.BigInt(new(big.Int))
+				coords[k][1] = table[k].Y.BigInt(new(big.Int))
+			}
+			for c := range lo {
+				lo[c] = api.Lookup2(window[0], window[1], coords[0][c], coords[1][c], coords[2][c], coords[3][c])
+				hi[c] = api.Lookup2(window[0], window[1], coords[4][c], coords[5][c], coords[6][c], coords[7][c])
+			}
+			p := twistededwards.Point{
+				X: api.Select(window[2], hi[0], lo[0]),
+				Y: api.Select(window[2], hi[1], lo[1]),
+			}
+			// the last bit is the sign and -(x, y) = (-x, y).
+			p.X = api.Select(window[3], api.Neg(p.X), p.X)
+			if s == 0 && w == 0 {
+				res = p
+			} else {
+				res = h.curve.Add(res, p)
+			}
+			// the next window uses 2^5 times the generator.
+			for i := 0; i < windowSize+1; i++ {
+				base.Double(&base)
+			}
+		}
+	}
+	return babyjubjub.ToCircomlib(api, res)
+}
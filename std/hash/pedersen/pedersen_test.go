@@ -0,0 +1,163 @@
+package pedersen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/internal/babyjubjub"
+	"github.com/consensys/gnark/test"
+)
+
+func TestBlake256(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, v := range []struct {
+		msg      []byte
+		expected string
+	}{
+		{nil, "716f6e863f744b9ac22c97ec7b76ea5f5908bc5b2f67c61510bfc4751384ea7a"},
+		{[]byte{0}, "0ce8d4ef4dd7cd8d62dfded9d4edb0a774ae6a41929a74da23109e8f11139c87"},
+		{make([]byte, 72), "d419bad32d504fb7d44d460c42c5593fe544fa4c135dec31e21bd9abdcc22d41"},
+	} {
+		res := blake256(v.msg)
+		assert.Equal(v.expected, hex.EncodeToString(res[:]))
+	}
+}
+
+func TestBasePoints(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the generators hardcoded in the Pedersen template of circomlib.
+	expected := [][2]string{
+		{"10457101036533406547632367118273992217979173478358440826365724437999023779287", "19824078218392094440610104313265183977899662750282163392862422243483260492317"},
+		{"2671756056509184035029146175565761955751135805354291559563293617232983272177", "2663205510731142763556352975002641716101654201788071096152948830924149045094"},
+		{"5802099305472655231388284418920769829666717045250560929368476121199858275951", "5980429700218124965372158798884772646841287887664001482443826541541529227896"},
+		{"7107336197374528537877327281242680114152313102022415488494307685842428166594", "2857869773864086953506483169737724679646433914307247183624878062391496185654"},
+		{"20265828622013100949498132415626198973119240347465898028410217039057588424236", "1160461593266035632937973507065134938065359936056410650153315956301179689506"},
+		{"1487999857809287756929114517587739322941449154962237464737694709326309567994", "14017256862867289575056460215526364897734808720610101650676790868051368668003"},
+		{"14618644331049802168996997831720384953259095788558646464435263343433563860015", "13115243279999696210147231297848654998887864576952244320558158620692603342236"},
+		{"6814338563135591367010655964669793483652536871717891893032616415581401894627", "13660303521961041205824633772157003587453809761793065294055279768121314853695"},
+		{"3571615583211663069428808372184817973703476260057504149923239576077102575715", "11981351099832644138306422070127357074117642951423551606012551622164230222506"},
+		{"18597552580465440374022635246985743886550544261632147935254624835147509493269", "6753322320275422086923032033899357299485124665258735666995435957890214041481"},
+	}
+	for i := range expected {
+		base := basePoint(i)
+		x, y := babyjubjub.NativeToCircomlib(&base)
+		assert.Equal(expected[i][0], x.String(), i)
+		assert.Equal(expected[i][1], y.String(), i)
+	}
+}
+
+func TestNativeHash(t *testing.T) {
+	assert := test.NewAssert(t)
+	// a single window: [±(1 + b0 + 2b1 + 4b2)]G_0.
+	base := basePoint(0)
+	x, y := NativeHashBits([]bool{true, false, true, true})
+	p := base
+	p.ScalarMultiplication(&base, big.NewInt(6))
+	p.Neg(&p)
+	ex, ey := babyjubjub.NativeToCircomlib(&p)
+	assert.Equal(ex, x)
+	assert.Equal(ey, y)
+
+	// the packed point is the little-endian y with the sign of x.
+	msg := []byte("circomlib pedersen")
+	packed := NativeHash(msg)
+	p, ok := unpackPoint(packed)
+	assert.True(ok)
+	bits := make([]bool, 8*len(msg))
+	for i := range bits {
+		bits[i] = (msg[i/8]>>(i%8))&1 == 1
+	}
+	x, y = NativeHashBits(bits)
+	ex, ey = babyjubjub.NativeToCircomlib(&p)
+	assert.Equal(x, ex)
+	assert.Equal(y, ey)
+}
+
+type hashBitsCircuit struct {
+	Bits     []frontend.Variable
+	Expected [2]frontend.Variable `gnark:",public"`
+}
+
+func (c *hashBitsCircuit) Define(api frontend.API) error {
+	h, err := NewPedersen(api, 1)
+	if err != nil {
+		return err
+	}
+	res := h.HashBits(c.Bits)
+	api.AssertIsEqual(res.X, c.Expected[0])
+	api.AssertIsEqual(res.Y, c.Expected[1])
+	return nil
+}
+
+func TestHashBits(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, n := range []int{0, 1, 3, 4, 7, 200, 201, 450} {
+		bits := make([]bool, n)
+		circuit := hashBitsCircuit{Bits: make([]frontend.Variable, n)}
+		witness := hashBitsCircuit{Bits: make([]frontend.Variable, n)}
+		buf := make([]byte, n)
+		_, err := rand.Read(buf)
+		assert.NoError(err)
+		for i := range bits {
+			bits[i] = buf[i]&1 == 1
+			witness.Bits[i] = buf[i] & 1
+		}
+		x, y := NativeHashBits(bits)
+		witness.Expected = [2]frontend.Variable{x, y}
+		err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, n)
+		if n > 0 {
+			witness.Bits[n-1] = 1 - buf[n-1]&1
+			err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+			assert.Error(err, n)
+		}
+	}
+}
+
+type pedersenCircuit struct {
+	Inputs   [2]frontend.Variable
+	Expected frontend.Variable `gnark:",public"`
+}
+
+func (c *pedersenCircuit) Define(api frontend.API) error {
+	h, err := NewPedersen(api, 248)
+	if err != nil {
+		return err
+	}
+	h.Write(c.Inputs[:]...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	return nil
+}
+
+func TestPedersen(t *testing.T) {
+	assert := test.NewAssert(t)
+	h, err := NewNative(248)
+	assert.NoError(err)
+	var inputs [2]frontend.Variable
+	buf := make([]byte, fr.Bytes)
+	bound := new(big.Int).Lsh(big.NewInt(1), 248)
+	for i := range inputs {
+		v, err := rand.Int(rand.Reader, bound)
+		assert.NoError(err)
+		inputs[i] = v
+		v.FillBytes(buf)
+		_, err = h.Write(buf)
+		assert.NoError(err)
+	}
+	expected := new(big.Int).SetBytes(h.Sum(nil))
+	wrong := inputs
+	wrong[1] = new(big.Int).Add(inputs[1].(*big.Int), bound)
+	_, err = h.Write(wrong[1].(*big.Int).FillBytes(buf))
+	assert.Error(err)
+
+	assert.CheckCircuit(&pedersenCircuit{},
+		test.WithValidAssignment(&pedersenCircuit{Inputs: inputs, Expected: expected}),
+		test.WithInvalidAssignment(&pedersenCircuit{Inputs: [2]frontend.Variable{inputs[1], inputs[0]}, Expected: expected}),
+		test.WithInvalidAssignment(&pedersenCircuit{Inputs: wrong, Expected: expected}),
+		test.WithCurves(ecc.BN254), test.NoFuzzing())
+}
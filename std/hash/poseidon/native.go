@@ -0,0 +1,109 @@
+package poseidon
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// Hash returns the Poseidon hash of the inputs outside of the circuit. It
+// returns an error if there is no input, more than [MaxInputs] inputs or if an
+// input is not a reduced field element.
+func Hash(inputs ...*big.Int) (*big.Int, error) {
+	if len(inputs) == 0 || len(inputs) > MaxInputs {
+		return nil, fmt.Errorf("invalid number of inputs %d", len(inputs))
+	}
+	modulus := fr.Modulus()
+	state := make([]*big.Int, len(inputs)+1)
+	state[0] = new(big.Int)
+	for i := range inputs {
+		if inputs[i].Sign() < 0 || inputs[i].Cmp(modulus) >= 0 {
+			return nil, errors.New("input is not a reduced field element")
+		}
+		state[i+1] = new(big.Int).Set(inputs[i])
+	}
+	permute(state, modulus)
+	return state[0], nil
+}
+
+func permute(state []*big.Int, modulus *big.Int) {
+	t := len(state)
+	p := getParameters(t - 1)
+	five := big.NewInt(5)
+	tmp := make([]*big.Int, t)
+	for r := 0; r < fullRounds+p.nbPartialRounds; r++ {
+		for i := range state {
+			state[i].Add(state[i], p.roundConstants[r*t+i])
+		}
+		if r < fullRounds/2 || r >= fullRounds/2+p.nbPartialRounds {
+			for i := range state {
+				state[i].Exp(state[i], five, modulus)
+			}
+		} else {
+			state[0].Exp(state[0], five, modulus)
+		}
+		var term big.Int
+		for i := range tmp {
+			tmp[i] = new(big.Int)
+			for j := range state {
+				tmp[i].Add(tmp[i], term.Mul(p.mds[i][j], state[j]))
+			}
+			tmp[i].Mod(tmp[i], modulus)
+		}
+		copy(state, tmp)
+	}
+}
+
+// NewNative returns the out-of-circuit counterpart of [Poseidon] as a
+// [hash.Hash], for instance for building Merkle trees. Every block of
+// [fr.Bytes] bytes written is a big-endian field element and Sum returns the
+// hash of the elements written since the last call to Sum or Reset, which are
+// flushed. Sum panics if there is no element or more than [MaxInputs]
+// elements.
+func NewNative() hash.Hash {
+	return &digest{}
+}
+
+type digest struct {
+	data []*big.Int
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	if len(p)%fr.Bytes != 0 {
+		return 0, errors.New("invalid input length: must represent a list of field elements")
+	}
+	for i := 0; i < len(p); i += fr.Bytes {
+		e, err := fr.BigEndian.Element((*[fr.Bytes]byte)(p[i : i+fr.Bytes]))
+		if err != nil {
+			return 0, err
+		}
+		d.data = append(d.data, e.BigInt(new(big.Int)))
+	}
+	return len(p), nil
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	res, err := Hash(d.data...)
+	if err != nil {
+		panic(err)
+	}
+	d.data = nil
+	var buf [fr.Bytes]byte
+	res.FillBytes(buf[:])
+	return append(b, buf[:]...)
+}
+
+func (d *digest) Reset() {
+	d.data = nil
+}
+
+func (d *digest) Size() int {
+	return fr.Bytes
+}
+
+func (d *digest) BlockSize() int {
+	return fr.Bytes
+}
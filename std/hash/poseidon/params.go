@@ -0,0 +1,135 @@
+package poseidon
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// fullRounds is the number of full rounds, half of them before the partial
+// rounds and half of them after.
+const fullRounds = 8
+
+// partialRounds[t-2] is the number of partial rounds for the state width t, as
+// in circomlib.
+var partialRounds = [...]int{56, 57, 56, 60, 60, 63, 64, 63, 60, 66, 60, 65, 70, 60, 64, 68}
+
+// MaxInputs is the maximal number of inputs of the hash function.
+const MaxInputs = len(partialRounds)
+
+// parameters are the round constants and the MDS matrix of the permutation of
+// a given width.
+type parameters struct {
+	nbPartialRounds int
+	// roundConstants[r*t+i] is added to the i-th element of the state in round r
+	roundConstants []*big.Int
+	mds            [][]*big.Int
+}
+
+var (
+	paramsOnce [MaxInputs]sync.Once
+	params     [MaxInputs]parameters
+)
+
+// getParameters returns the parameters of the permutation for nbInputs inputs.
+// They are generated once with the Grain LFSR of the reference implementation
+// of Poseidon, with which the circomlib constants were obtained.
+func getParameters(nbInputs int) *parameters {
+	paramsOnce[nbInputs-1].Do(func() {
+		params[nbInputs-1] = generateParameters(nbInputs+1, partialRounds[nbInputs-1])
+	})
+	return &params[nbInputs-1]
+}
+
+func generateParameters(t, nbPartialRounds int) parameters {
+	modulus := ecc.BN254.ScalarField()
+	n := modulus.BitLen()
+	g := newGrain(n, t, fullRounds, nbPartialRounds)
+	res := parameters{
+		nbPartialRounds: nbPartialRounds,
+		roundConstants:  make([]*big.Int, (fullRounds+nbPartialRounds)*t),
+	}
+	// the round constants are sampled with rejection
+	for i := range res.roundConstants {
+		c := g.element(n)
+		for c.Cmp(modulus) >= 0 {
+			c = g.element(n)
+		}
+		res.roundConstants[i] = c
+	}
+	// the MDS matrix is the Cauchy matrix M[i][j] = 1/(x_i+y_j)
+	xs := make([]*big.Int, 2*t)
+	for i := range xs {
+		xs[i] = g.element(n)
+		xs[i].Mod(xs[i], modulus)
+	}
+	res.mds = make([][]*big.Int, t)
+	for i := range res.mds {
+		res.mds[i] = make([]*big.Int, t)
+		for j := range res.mds[i] {
+			e := new(big.Int).Add(xs[i], xs[t+j])
+			res.mds[i][j] = e.ModInverse(e.Mod(e, modulus), modulus)
+		}
+	}
+	return res
+}
+
+// grain is the self-shrinking Grain LFSR used for generating the parameters.
+type grain struct {
+	state [80]bool
+	// head is the position of the oldest bit of the state
+	head int
+}
+
+func newGrain(n, t, nbFullRounds, nbPartialRounds int) *grain {
+	var g grain
+	i := 0
+	push := func(v, nbBits int) {
+		for j := nbBits - 1; j >= 0; j-- {
+			g.state[i] = (v>>j)&1 == 1
+			i++
+		}
+	}
+	push(1, 2) // prime field
+	push(0, 4) // x^α s-box
+	push(n, 12)
+	push(t, 12)
+	push(nbFullRounds, 10)
+	push(nbPartialRounds, 10)
+	push(1<<30-1, 30)
+	for j := 0; j < 160; j++ {
+		g.next()
+	}
+	return &g
+}
+
+func (g *grain) next() bool {
+	at := func(i int) bool { return g.state[(g.head+i)%len(g.state)] }
+	b := at(62) != at(51) != at(38) != at(23) != at(13) != at(0)
+	g.state[g.head] = b
+	g.head = (g.head + 1) % len(g.state)
+	return b
+}
+
+// bit returns the next output bit. The bits are taken by pairs and the second
+// bit is output only if the first one is set.
+func (g *grain) bit() bool {
+	for !g.next() {
+		g.next()
+	}
+	return g.next()
+}
+
+// element returns the integer of the next nbBits output bits, most significant
+// bit first.
+func (g *grain) element(nbBits int) *big.Int {
+	res := new(big.Int)
+	for i := 0; i < nbBits; i++ {
+		res.Lsh(res, 1)
+		if g.bit() {
+			res.SetBit(res, 0, 1)
+		}
+	}
+	return res
+}
@@ -0,0 +1,96 @@
+// Package poseidon provides a ZKP-circuit function to compute the Poseidon
+// hash over the BN254 scalar field, compatible with circomlib.
+//
+// The hash of n inputs, for 1 ≤ n ≤ [MaxInputs], applies the Poseidon
+// permutation of width n+1 with the x^5 s-box, 8 full rounds and the circomlib
+// number of partial rounds to the state (0, in_1, ..., in_n) and returns the
+// first element of the state. The round constants and MDS matrices are the
+// ones of circomlib, generated with the Grain LFSR of the reference
+// implementation.
+//
+// This is the hash function used by Semaphore, zk-kit and other circom based
+// protocols. It is not the Poseidon2 permutation of gnark-crypto.
+package poseidon
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// Poseidon computes the circomlib Poseidon hash of the written inputs.
+type Poseidon struct {
+	api  frontend.API
+	data []frontend.Variable
+}
+
+// NewPoseidon returns a Poseidon instance, that can be used in a gnark circuit
+// over the BN254 scalar field.
+func NewPoseidon(api frontend.API) (Poseidon, error) {
+	if utils.FieldToCurve(api.Compiler().Field()) != ecc.BN254 {
+		return Poseidon{}, errors.New("poseidon is only defined over the BN254 scalar field")
+	}
+	return Poseidon{api: api}, nil
+}
+
+// Write adds more data to the running hash.
+func (h *Poseidon) Write(data ...frontend.Variable) {
+	h.data = append(h.data, data...)
+}
+
+// Reset resets the Hash to its initial state.
+func (h *Poseidon) Reset() {
+	h.data = nil
+}
+
+// Sum returns the hash of all the inputs written since the last call to Sum or
+// Reset, which are flushed. It panics if there is no input or more than
+// [MaxInputs] inputs.
+func (h *Poseidon) Sum() frontend.Variable {
+	if len(h.data) == 0 || len(h.data) > MaxInputs {
+		panic(fmt.Sprintf("poseidon: invalid number of inputs %d", len(h.data)))
+	}
+	state := make([]frontend.Variable, len(h.data)+1)
+	state[0] = 0
+	copy(state[1:], h.data)
+	h.data = nil
+	h.permute(state)
+	return state[0]
+}
+
+// permute applies the Poseidon permutation to the state in place.
+func (h *Poseidon) permute(state []frontend.Variable) {
+	t := len(state)
+	p := getParameters(t - 1)
+	tmp := make([]frontend.Variable, t)
+	for r := 0; r < fullRounds+p.nbPartialRounds; r++ {
+		for i := range state {
+			state[i] = h.api.Add(state[i], p.roundConstants[r*t+i])
+		}
+		if r < fullRounds/2 || r >= fullRounds/2+p.nbPartialRounds {
+			for i := range state {
+				state[i] = h.sbox(state[i])
+			}
+		} else {
+			state[0] = h.sbox(state[0])
+		}
+		for i := range tmp {
+			terms := make([]frontend.Variable, t)
+			for j := range state {
+				terms[j] = h.api.Mul(p.mds[i][j], state[j])
+			}
+			tmp[i] = h.api.Add(terms[0], terms[1], terms[2:]...)
+		}
+		copy(state, tmp)
+	}
+}
+
+// sbox returns x^5.
+func (h *Poseidon) sbox(x frontend.Variable) frontend.Variable {
+	x2 := h.api.Mul(x, x)
+	x4 := h.api.Mul(x2, x2)
+	return h.api.Mul(x4, x)
+}
@@ -0,0 +1,100 @@
+package poseidon
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// test vectors of circomlibjs
+var vectors = []struct {
+	inputs   []int64
+	expected string
+}{
+	{[]int64{1}, "0x29176100eaa962bdc1fe6c654d6a3c130e96a4d1168b33848b897dc502820133"},
+	{[]int64{1, 2}, "0x115cc0f5e7d690413df64c6b9662e9cf2a3617f2743245519e19607a4417189a"},
+	{[]int64{0, 0}, "0x2098f5fb9e239eab3ceac3f27b81e481dc3124d55ffed523a839ee8446b64864"},
+	{[]int64{1, 2, 3, 4}, "0x299c867db6c1fdd79dcefa40e4510b9837e60ebb1ce0663dbaa525df65250465"},
+}
+
+func TestNative(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, v := range vectors {
+		inputs := make([]*big.Int, len(v.inputs))
+		for i := range inputs {
+			inputs[i] = big.NewInt(v.inputs[i])
+		}
+		res, err := Hash(inputs...)
+		assert.NoError(err)
+		expected, _ := new(big.Int).SetString(v.expected, 0)
+		assert.Equal(expected, res)
+
+		h := NewNative()
+		buf := make([]byte, fr.Bytes)
+		for i := range inputs {
+			inputs[i].FillBytes(buf)
+			_, err = h.Write(buf)
+			assert.NoError(err)
+		}
+		assert.Equal(expected, new(big.Int).SetBytes(h.Sum(nil)))
+	}
+	_, err := Hash()
+	assert.Error(err)
+	_, err = Hash(make([]*big.Int, MaxInputs+1)...)
+	assert.Error(err)
+	_, err = Hash(fr.Modulus())
+	assert.Error(err)
+}
+
+type poseidonCircuit struct {
+	Inputs   []frontend.Variable
+	Expected frontend.Variable `gnark:",public"`
+}
+
+func (c *poseidonCircuit) Define(api frontend.API) error {
+	h, err := NewPoseidon(api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.Inputs...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	return nil
+}
+
+func TestPoseidon(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, v := range vectors {
+		circuit := poseidonCircuit{Inputs: make([]frontend.Variable, len(v.inputs))}
+		witness := poseidonCircuit{Inputs: make([]frontend.Variable, len(v.inputs)), Expected: v.expected}
+		for i := range v.inputs {
+			witness.Inputs[i] = v.inputs[i]
+		}
+		assert.CheckCircuit(&circuit, test.WithValidAssignment(&witness), test.WithCurves(ecc.BN254))
+	}
+}
+
+func TestPoseidonWidths(t *testing.T) {
+	assert := test.NewAssert(t)
+	for n := 1; n <= MaxInputs; n++ {
+		inputs := make([]*big.Int, n)
+		circuit := poseidonCircuit{Inputs: make([]frontend.Variable, n)}
+		witness := poseidonCircuit{Inputs: make([]frontend.Variable, n)}
+		for i := range inputs {
+			inputs[i], _ = rand.Int(rand.Reader, fr.Modulus())
+			witness.Inputs[i] = inputs[i]
+		}
+		expected, err := Hash(inputs...)
+		assert.NoError(err)
+		witness.Expected = expected
+		err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, n)
+		witness.Expected = new(big.Int).Add(expected, big.NewInt(1))
+		err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.Error(err, n)
+	}
+}
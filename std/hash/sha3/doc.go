@@ -2,4 +2,6 @@
 // Keccak f-[1600] permutation function.
 //
 // Instances correspond golang.org/x/crypto/sha3, except SHA224, which is not x64 compatible.
+// The returned hashers also implement [hash.BinaryFixedLengthHasher] for hashing a prefix of
+// variable length of the written data.
 package sha3
@@ -1,11 +1,16 @@
 package sha3
 
 import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/cmp"
 	"github.com/consensys/gnark/std/math/uints"
 	"github.com/consensys/gnark/std/permutation/keccakf"
 )
 
 type digest struct {
+	api       frontend.API
 	uapi      *uints.BinaryField[uints.U64]
 	state     [25]uints.U64 // 1600 bits state: 25 x 64
 	in        []uints.U8    // input to be digested
@@ -32,6 +37,61 @@ func (d *digest) Sum() []uints.U8 {
 	return d.squeezeBlocks()
 }
 
+// FixedLengthSum computes the digest of the first length bytes of the
+// written data. The length must be at most the number of written bytes.
+func (d *digest) FixedLengthSum(length frontend.Variable) []uints.U8 {
+	comparator := cmp.NewBoundedComparator(d.api, big.NewInt(int64(len(d.in)+1)), false)
+	comparator.AssertIsLessEq(length, len(d.in))
+
+	// the padding starts at most at the end of the written data, so there is
+	// one more block than the number of full blocks of the written data.
+	nbBlocks := len(d.in)/d.rate + 1
+	padded := make([]uints.U8, nbBlocks*d.rate)
+	copy(padded, d.in)
+	for i := len(d.in); i < len(padded); i++ {
+		padded[i] = uints.NewU8(0)
+	}
+
+	// isLast[b] is 1 if the padding ends in the block b, i.e. if length is in
+	// the range of the block. The bytes after the length are zeroed, the
+	// domain separation byte is set at the length and the last byte of the
+	// last block is OR-ed with 0x80.
+	isLast := make([]frontend.Variable, nbBlocks)
+	inData := frontend.Variable(1)
+	for b := 0; b < nbBlocks; b++ {
+		isLast[b] = 0
+		for j := 0; j < d.rate; j++ {
+			i := b*d.rate + j
+			isEnd := d.api.IsZero(d.api.Sub(i, length))
+			isLast[b] = d.api.Add(isLast[b], isEnd)
+			inData = d.api.Sub(inData, isEnd)
+			padded[i].Val = d.api.Add(d.api.Mul(inData, padded[i].Val), d.api.Mul(isEnd, d.dsbyte))
+		}
+		last := b*d.rate + d.rate - 1
+		padded[last].Val = d.api.Add(padded[last].Val, d.api.Mul(isLast[b], 0x80))
+	}
+
+	blocks := d.composeBlocks(padded)
+	state := d.state
+	result := make([]uints.U8, d.outputLen)
+	for i := range result {
+		result[i] = uints.NewU8(0)
+	}
+	for b, block := range blocks {
+		for i := range block {
+			state[i] = d.uapi.Xor(state[i], block[i])
+		}
+		state = keccakf.Permute(d.uapi, state)
+		for i := 0; i < d.outputLen/8; i++ {
+			lane := d.uapi.UnpackLSB(state[i])
+			for j := range lane {
+				result[i*8+j].Val = d.api.Select(isLast[b], lane[j].Val, result[i*8+j].Val)
+			}
+		}
+	}
+	return result
+}
+
 func (d *digest) padding() []uints.U8 {
 	padded := make([]uints.U8, len(d.in))
 	copy(padded[:], d.in[:])
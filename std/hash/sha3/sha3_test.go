@@ -88,3 +88,68 @@ func TestSHA3(t *testing.T) {
 		}, name)
 	}
 }
+
+type sha3FixedLengthCircuit struct {
+	In       []uints.U8
+	Length   frontend.Variable
+	Expected []uints.U8
+
+	hasher string
+}
+
+func (c *sha3FixedLengthCircuit) Define(api frontend.API) error {
+	newHasher, ok := testCases[c.hasher]
+	if !ok {
+		return fmt.Errorf("hash function unknown: %s", c.hasher)
+	}
+	bh, err := newHasher.zk(api)
+	if err != nil {
+		return err
+	}
+	h, ok := bh.(zkhash.BinaryFixedLengthHasher)
+	if !ok {
+		return fmt.Errorf("hash function %s does not support fixed length sums", c.hasher)
+	}
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return err
+	}
+
+	h.Write(c.In)
+	res := h.FixedLengthSum(c.Length)
+
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestSHA3FixedLengthSum(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := make([]byte, 310)
+	_, err := rand.Reader.Read(in)
+	assert.NoError(err)
+
+	for _, name := range []string{"SHA3-256", "Keccak-256"} {
+		for _, length := range []int{0, 135, 136, 200, 310} {
+			name, length := name, length
+			assert.Run(func(assert *test.Assert) {
+				h := testCases[name].native()
+				h.Write(in[:length])
+				expected := h.Sum(nil)
+
+				circuit := &sha3FixedLengthCircuit{
+					In:       make([]uints.U8, len(in)),
+					Expected: make([]uints.U8, len(expected)),
+					hasher:   name,
+				}
+				witness := &sha3FixedLengthCircuit{
+					In:       uints.NewU8Array(in),
+					Length:   length,
+					Expected: uints.NewU8Array(expected),
+				}
+				assert.CheckCircuit(circuit, test.WithValidAssignment(witness), test.WithCurves(ecc.BN254), test.NoProverChecks(), test.NoFuzzing())
+			}, name, fmt.Sprintf("length=%d", length))
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Package babyjubjub converts the points of the Baby Jubjub curve between the
+// circomlib and the gnark coordinates.
+//
+// circomlib uses the curve 168700x² + y² = 1 + 168696x²y², while gnark uses
+// the isomorphic curve -x² + y² = 1 + dx²y². The point (x, y) in the circomlib
+// coordinates is (s⋅x, y) in the gnark coordinates, where s is a square root
+// of -168700.
+package babyjubjub
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+)
+
+var (
+	xScale, _     = new(big.Int).SetString("15527681003928902128179717624703512672403908117992798440346960750464748824729", 10)
+	xScaleInverse = new(big.Int).ModInverse(xScale, fr.Modulus())
+)
+
+// ToCircomlib returns the point p in the circomlib coordinates.
+func ToCircomlib(api frontend.API, p twistededwards.Point) twistededwards.Point {
+	return twistededwards.Point{X: api.Mul(p.X, xScaleInverse), Y: p.Y}
+}
+
+// NativeToCircomlib returns the circomlib coordinates of p.
+func NativeToCircomlib(p *edbn254.PointAffine) (x, y *big.Int) {
+	x = p.X.BigInt(new(big.Int))
+	x.Mul(x, xScaleInverse).Mod(x, fr.Modulus())
+	return x, p.Y.BigInt(new(big.Int))
+}
+
+// NativeFromCircomlib returns the point with the circomlib coordinates (x, y).
+func NativeFromCircomlib(x, y *fr.Element) edbn254.PointAffine {
+	var res edbn254.PointAffine
+	res.X.Mul(x, new(fr.Element).SetBigInt(xScale))
+	res.Y.Set(y)
+	return res
+}
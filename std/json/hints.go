@@ -0,0 +1,45 @@
+package json
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		compactHint,
+	}
+}
+
+// compactHint returns the concatenation of the bytes emitted at every
+// position, padded with zeros. The inputs are the tuples (count, b0, b1, b2)
+// of the number of the emitted bytes and the emitted bytes.
+func compactHint(_ *big.Int, inputs, outputs []*big.Int) error {
+	if len(inputs)%4 != 0 {
+		return fmt.Errorf("expected tuples of four inputs")
+	}
+	k := 0
+	for i := 0; i < len(inputs); i += 4 {
+		if !inputs[i].IsUint64() || inputs[i].Uint64() > 3 {
+			return fmt.Errorf("invalid number of emitted bytes")
+		}
+		for j := 0; j < int(inputs[i].Uint64()); j++ {
+			if k >= len(outputs) {
+				return fmt.Errorf("value is longer than %d bytes", len(outputs))
+			}
+			outputs[k].Set(inputs[i+1+j])
+			k++
+		}
+	}
+	for ; k < len(outputs); k++ {
+		outputs[k].SetUint64(0)
+	}
+	return nil
+}
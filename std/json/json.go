@@ -0,0 +1,370 @@
+// Package json implements in-circuit validation of JSON texts and extraction
+// of values by their key paths.
+//
+// The parser is a finite state machine whose transitions are given by
+// lookup tables. The bytes of the input are classified using a lookup table
+// and the state after every byte is looked up from the state before, the class
+// of the byte and whether the innermost container is an array, an object or
+// the root. The stack of the open containers is kept as a bit string bounded
+// by the maximum nesting depth.
+//
+// The parser follows RFC 8259, with the exception that it does not check that
+// the strings are valid UTF-8. The keys of the paths are compared with the
+// encoded bytes of the keys, so a key containing escapes only matches a path
+// element containing the same escapes.
+package json
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Query is a key path whose value is extracted from a JSON text.
+type Query struct {
+	// Path is the sequence of the keys of the nested objects from the root to
+	// the value. It must not be empty.
+	Path []string
+	// MaxLen is the maximum length of the extracted value.
+	MaxLen int
+}
+
+// Value is a value extracted from a JSON text.
+type Value struct {
+	// Bytes are the bytes of the value. If the value is a string, then these
+	// are the unescaped bytes of the string without the quotes. Otherwise they
+	// are the encoded bytes of the value, including the whitespace inside of
+	// objects and arrays. Only the first Length bytes are part of the value
+	// and the remaining bytes are zero.
+	Bytes []uints.U8
+	// Length is the length of the value.
+	Length frontend.Variable
+	// IsString is 1 if the value is a string and 0 otherwise.
+	IsString frontend.Variable
+}
+
+// Parser validates JSON texts and extracts values from them in circuit.
+type Parser struct {
+	api      frontend.API
+	maxDepth int
+}
+
+// NewParser returns a new parser for JSON texts whose objects and arrays are
+// nested at most maxDepth times.
+func NewParser(api frontend.API, maxDepth int) (*Parser, error) {
+	if maxDepth < 0 || maxDepth+2 >= api.Compiler().FieldBitLen() {
+		return nil, fmt.Errorf("invalid maximum depth %d", maxDepth)
+	}
+	return &Parser{api: api, maxDepth: maxDepth}, nil
+}
+
+// Validate asserts that the first length bytes of data are a JSON text with the
+// nesting depth at most the maximum depth of the parser. The remaining bytes
+// of data are ignored.
+func (p *Parser) Validate(data []uints.U8, length frontend.Variable) error {
+	_, err := p.parse(data, length, nil)
+	return err
+}
+
+// Extract asserts that the first length bytes of data are a JSON text as in
+// [Parser.Validate] and returns the values of the queries. It asserts that
+// every key path occurs exactly once in the text and that the extracted values
+// are at most of their maximum lengths.
+//
+// The unicode escapes of the extracted strings are decoded to UTF-8. The
+// escapes of UTF-16 surrogates are not supported and their extraction fails.
+func (p *Parser) Extract(data []uints.U8, length frontend.Variable, queries ...Query) ([]Value, error) {
+	for i, q := range queries {
+		if len(q.Path) == 0 {
+			return nil, fmt.Errorf("query %d: empty path", i)
+		}
+		if q.MaxLen <= 0 {
+			return nil, fmt.Errorf("query %d: invalid maximum length %d", i, q.MaxLen)
+		}
+	}
+	return p.parse(data, length, queries)
+}
+
+// step is the parsing of a single byte, shared by the queries.
+type step struct {
+	// b is the byte and cls its class.
+	b, cls frontend.Variable
+	// idx is the index of the state before the byte and the class in the
+	// tables of the flags.
+	idx frontend.Variable
+	// depth is the nesting depth before the byte and depthAfter after.
+	depth, depthAfter frontend.Variable
+	pop, pushObject   frontend.Variable
+
+	// the flags of the byte used for the extraction.
+	valueStart, valueEnd, numberEnd frontend.Variable
+	keyStart, keyChar, keyEnd       frontend.Variable
+	strChar, escape, escChar        frontend.Variable
+	hexLast                         frontend.Variable
+	// utf8 is the UTF-8 encoding of the code point if the byte is the last
+	// digit of a unicode escape. The flags utf8Long and utf8Three are set if
+	// the encoding has at least two and three bytes.
+	utf8                [3]frontend.Variable
+	utf8Long, utf8Three frontend.Variable
+	// surrogate is set if the code point is a UTF-16 surrogate.
+	surrogate frontend.Variable
+}
+
+func (p *Parser) parse(data []uints.U8, length frontend.Variable, queries []Query) ([]Value, error) {
+	api := p.api
+	n := len(data)
+	classT := logderivlookup.New(api)
+	for b := 0; b < 256; b++ {
+		classT.Insert(byteClass(b))
+	}
+	transT := logderivlookup.New(api)
+	for st := 0; st < nbStates; st++ {
+		for cls := 0; cls < nbClasses; cls++ {
+			for ctx := 0; ctx < nbContexts; ctx++ {
+				transT.Insert(transition(st, cls, ctx))
+			}
+		}
+	}
+	pushT := p.flagTable(func(f flags) int { return f.push })
+	pushObjectT := p.flagTable(func(f flags) int { return f.pushObject })
+	popT := p.flagTable(func(f flags) int { return f.pop })
+
+	bytes := make([]frontend.Variable, n+1)
+	for i := range data {
+		bytes[i] = data[i].Val
+	}
+	// the virtual position after the data is always after the end of the
+	// input.
+	bytes[n] = 0
+	active := append(selector.PrefixMask(api, length, n), 0)
+	classes := classT.Lookup(bytes...)
+
+	steps := make([]step, n+1)
+	var state frontend.Variable = stValue
+	// the stack of the open containers is 1 followed by a bit for every
+	// container, which is 1 for objects and 0 for arrays.
+	var stack frontend.Variable = 1
+	var depth frontend.Variable = 0
+	for i := range steps {
+		cls := api.Select(active[i], classes[i], clsEnd)
+		sb := bits.ToBinary(api, stack, bits.WithNbDigits(p.maxDepth+1))
+		rest := bits.FromBinary(api, sb[1:], bits.WithUnconstrainedInputs())
+		ctx := api.Add(sb[0], api.IsZero(api.Sub(stack, 1)))
+		idx := api.Add(api.Mul(state, nbClasses), cls)
+		next := transT.Lookup(api.Add(api.Mul(idx, nbContexts), ctx))[0]
+		push := pushT.Lookup(idx)[0]
+		pushObject := pushObjectT.Lookup(idx)[0]
+		pop := popT.Lookup(idx)[0]
+		stack = api.Add(stack, api.Mul(push, api.Add(stack, pushObject)), api.Mul(pop, api.Sub(rest, stack)))
+		depthAfter := api.Sub(api.Add(depth, push), pop)
+		steps[i] = step{
+			b:          bytes[i],
+			cls:        cls,
+			idx:        idx,
+			depth:      depth,
+			depthAfter: depthAfter,
+			pop:        pop,
+			pushObject: pushObject,
+		}
+		state, depth = next, depthAfter
+	}
+	api.AssertIsEqual(state, stDone)
+
+	if len(queries) == 0 {
+		return nil, nil
+	}
+	p.decodeSteps(steps)
+	res := make([]Value, len(queries))
+	for i, q := range queries {
+		var err error
+		if res[i], err = p.extract(steps, q); err != nil {
+			return nil, fmt.Errorf("query %d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
+// decodeSteps sets the flags of the steps used for the extraction and decodes
+// the unicode escapes.
+func (p *Parser) decodeSteps(steps []step) {
+	api := p.api
+	hexT := logderivlookup.New(api)
+	for b := 0; b < 256; b++ {
+		hexT.Insert(hexValue(b))
+	}
+	valueStartT := p.flagTable(func(f flags) int { return f.valueStart })
+	valueEndT := p.flagTable(func(f flags) int { return f.valueEnd })
+	numberEndT := p.flagTable(func(f flags) int { return f.numberEnd })
+	keyStartT := p.flagTable(func(f flags) int { return f.keyStart })
+	keyCharT := p.flagTable(func(f flags) int { return f.keyChar })
+	keyEndT := p.flagTable(func(f flags) int { return f.keyEnd })
+	strCharT := p.flagTable(func(f flags) int { return f.strChar })
+	escapeT := p.flagTable(func(f flags) int { return f.escape })
+	escCharT := p.flagTable(func(f flags) int { return f.escChar })
+	hexDigitT := p.flagTable(func(f flags) int { return f.hexDigit })
+	hexLastT := p.flagTable(func(f flags) int { return f.hexLast })
+
+	var acc frontend.Variable = 0
+	for i := range steps {
+		s := &steps[i]
+		lookup := func(tbl *logderivlookup.Table) frontend.Variable { return tbl.Lookup(s.idx)[0] }
+		s.valueStart, s.valueEnd, s.numberEnd = lookup(valueStartT), lookup(valueEndT), lookup(numberEndT)
+		s.keyStart, s.keyChar, s.keyEnd = lookup(keyStartT), lookup(keyCharT), lookup(keyEndT)
+		s.strChar, s.escape, s.escChar = lookup(strCharT), lookup(escapeT), lookup(escCharT)
+		s.hexLast = lookup(hexLastT)
+
+		// the code point of a unicode escape is 16*acc plus the last digit,
+		// where acc is given by the first three digits.
+		hexVal := hexT.Lookup(s.b)[0]
+		ab := bits.ToBinary(api, acc, bits.WithNbDigits(12))
+		codePoint := api.Add(api.Mul(acc, 16), hexVal)
+		is1 := api.IsZero(bits.FromBinary(api, ab[3:], bits.WithUnconstrainedInputs()))
+		hi7 := bits.FromBinary(api, ab[7:], bits.WithUnconstrainedInputs())
+		is12 := api.IsZero(hi7)
+		is2, is3 := api.Sub(is12, is1), api.Sub(1, is12)
+		low6 := api.Add(api.Mul(api.Add(ab[0], api.Mul(ab[1], 2)), 16), hexVal)
+		mid6 := bits.FromBinary(api, ab[2:8], bits.WithUnconstrainedInputs())
+		s.utf8[0] = api.Add(
+			api.Mul(is1, codePoint),
+			api.Mul(is2, api.Add(0xc0, bits.FromBinary(api, ab[2:], bits.WithUnconstrainedInputs()))),
+			api.Mul(is3, api.Add(0xe0, bits.FromBinary(api, ab[8:], bits.WithUnconstrainedInputs()))),
+		)
+		s.utf8[1] = api.Add(api.Mul(is2, api.Add(0x80, low6)), api.Mul(is3, api.Add(0x80, mid6)))
+		s.utf8[2] = api.Mul(is3, api.Add(0x80, low6))
+		s.utf8Long, s.utf8Three = api.Add(is2, is3), is3
+		s.surrogate = api.IsZero(api.Sub(hi7, 0x1b))
+		acc = api.Mul(lookup(hexDigitT), codePoint)
+	}
+}
+
+// extract returns the value of the query. The value is found by tracking the
+// number m of the keys of the path which are matched by the keys of the
+// enclosing objects. The key m of the path is compared with the keys of the
+// object at depth m+1, and the value of the path is at depth m when all the
+// keys are matched.
+func (p *Parser) extract(steps []step, q Query) (Value, error) {
+	api := p.api
+	nbKeys := len(q.Path)
+	// the keys of the path, each followed by a value which is not a byte.
+	pathT := logderivlookup.New(api)
+	offT := logderivlookup.New(api)
+	lenT := logderivlookup.New(api)
+	off := 0
+	for _, k := range q.Path {
+		offT.Insert(off)
+		lenT.Insert(len(k))
+		for j := 0; j < len(k); j++ {
+			pathT.Insert(k[j])
+		}
+		pathT.Insert(256)
+		off += len(k) + 1
+	}
+	offT.Insert(0)
+	lenT.Insert(0)
+
+	var m, pending, keyMatch, keyPos, isString, found, count frontend.Variable = 0, 0, 0, 0, 0, 0, 0
+	emitted := make([]frontend.Variable, 0, 4*len(steps))
+	type emission struct {
+		pos    frontend.Variable
+		flags  [3]frontend.Variable
+		values [3]frontend.Variable
+	}
+	emissions := make([]emission, len(steps))
+	for i, s := range steps {
+		// compare the key with the key m of the path if we are in the object
+		// at depth m+1.
+		cmpKey := api.Mul(api.IsZero(api.Sub(s.depth, api.Add(m, 1))), api.Sub(1, api.IsZero(api.Sub(m, nbKeys))))
+		expected := pathT.Lookup(api.Add(offT.Lookup(m)[0], keyPos))[0]
+		isExpected := api.IsZero(api.Sub(s.b, expected))
+		keyFull := api.Mul(s.keyEnd, keyMatch, cmpKey, api.IsZero(api.Sub(keyPos, lenT.Lookup(m)[0])))
+		// the position in the key is reset outside of the keys and on a
+		// mismatch, so that it stays in the key of the path.
+		keyMatch = api.Add(s.keyStart, api.Mul(s.keyChar, keyMatch, isExpected, cmpKey))
+		keyPos = api.Mul(s.keyChar, keyMatch, api.Add(keyPos, 1))
+
+		// the value of a matched key starts. We descend into the value if it
+		// is the value of the path or an object.
+		isLast := api.IsZero(api.Sub(api.Add(m, 1), nbKeys))
+		start := api.Mul(pending, s.valueStart)
+		targetStart := api.Mul(start, isLast)
+		found = api.Add(found, targetStart)
+		pending = api.Add(keyFull, api.Mul(api.Sub(1, s.keyEnd), api.Sub(1, s.valueStart), pending))
+		m = api.Add(m, api.Mul(start, api.Add(isLast, api.Mul(api.Sub(1, isLast), s.pushObject))))
+		isString = api.Select(targetStart, api.IsZero(api.Sub(s.cls, clsQuote)), isString)
+
+		// the byte is part of the value of the path, unless it follows a
+		// number value.
+		atTarget := api.IsZero(api.Sub(m, nbKeys))
+		depthIsTarget := api.IsZero(api.Sub(s.depth, nbKeys))
+		inTarget := api.Mul(atTarget, api.Sub(1, api.Mul(s.numberEnd, depthIsTarget)))
+		// the value of the path ends, and then the enclosing object may end.
+		m = api.Sub(m, api.Mul(atTarget, api.Add(
+			api.Mul(s.valueEnd, api.IsZero(api.Sub(s.depthAfter, nbKeys))),
+			api.Mul(s.numberEnd, depthIsTarget),
+		)))
+		m = api.Sub(m, api.Mul(s.pop, api.IsZero(api.Sub(s.depth, api.Add(m, 1))), api.Sub(1, api.IsZero(m))))
+
+		// the bytes emitted for the value. Strings are unescaped and the other
+		// values are emitted as is.
+		inString := api.Mul(inTarget, isString)
+		inRaw := api.Sub(inTarget, inString)
+		unicode := api.Mul(inString, s.hexLast)
+		api.AssertIsEqual(api.Mul(unicode, s.surrogate), 0)
+		e := emission{pos: count}
+		e.flags[0] = api.Add(inRaw, api.Mul(inString, api.Add(s.strChar, s.escape)), unicode)
+		e.flags[1] = api.Mul(unicode, s.utf8Long)
+		e.flags[2] = api.Mul(unicode, s.utf8Three)
+		e.values[0] = api.Add(
+			api.Mul(inRaw, s.b),
+			api.Mul(inString, api.Add(api.Mul(s.strChar, s.b), s.escChar)),
+			api.Mul(unicode, s.utf8[0]),
+		)
+		e.values[1] = api.Mul(unicode, s.utf8[1])
+		e.values[2] = api.Mul(unicode, s.utf8[2])
+		nbEmitted := api.Add(e.flags[0], e.flags[1], e.flags[2])
+		emitted = append(emitted, nbEmitted, e.values[0], e.values[1], e.values[2])
+		count = api.Add(count, nbEmitted)
+		emissions[i] = e
+	}
+	api.AssertIsEqual(found, 1)
+
+	out, err := api.Compiler().NewHint(compactHint, q.MaxLen, emitted...)
+	if err != nil {
+		return Value{}, fmt.Errorf("compact hint: %w", err)
+	}
+	// every emitted byte is at its position in the output and the output is
+	// zero after the length.
+	outT := logderivlookup.New(api)
+	for i := range out {
+		outT.Insert(out[i])
+	}
+	for _, e := range emissions {
+		for j := range e.flags {
+			v := outT.Lookup(api.Mul(e.flags[j], api.Add(e.pos, j)))[0]
+			api.AssertIsEqual(api.Mul(e.flags[j], v), e.values[j])
+		}
+	}
+	inValue := selector.PrefixMask(api, count, q.MaxLen)
+	res := make([]uints.U8, q.MaxLen)
+	for i := range out {
+		api.AssertIsEqual(api.Mul(api.Sub(1, inValue[i]), out[i]), 0)
+		res[i] = uints.U8{Val: out[i]}
+	}
+	return Value{Bytes: res, Length: count, IsString: isString}, nil
+}
+
+// flagTable returns the table of the flag given by fn indexed by the state and
+// the class of the byte.
+func (p *Parser) flagTable(fn func(f flags) int) *logderivlookup.Table {
+	t := logderivlookup.New(p.api)
+	for st := 0; st < nbStates; st++ {
+		for cls := 0; cls < nbClasses; cls++ {
+			t.Insert(fn(byteFlags(st, cls)))
+		}
+	}
+	return t
+}
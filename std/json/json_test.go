@@ -0,0 +1,156 @@
+package json
+
+import (
+	stdjson "encoding/json"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+const maxDepth = 3
+
+type validateCircuit struct {
+	Data   [48]uints.U8
+	Length frontend.Variable
+}
+
+func (c *validateCircuit) Define(api frontend.API) error {
+	p, err := NewParser(api, maxDepth)
+	if err != nil {
+		return err
+	}
+	return p.Validate(c.Data[:], c.Length)
+}
+
+func depth(s string) int {
+	var res, d int
+	inString := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case inString && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			inString = !inString
+		case !inString && (s[i] == '{' || s[i] == '['):
+			d++
+			res = max(res, d)
+		case !inString && (s[i] == '}' || s[i] == ']'):
+			d--
+		}
+	}
+	return res
+}
+
+func TestValidate(t *testing.T) {
+	assert := test.NewAssert(t)
+	testCases := []string{
+		// valid
+		`{}`, `[]`, `0`, `-0`, `-1.5e+10`, `12E-3`, `0.25`, `"aé\n\"\\\/"`, `true`, `false`, `null`,
+		` {"a" : [1, 2, {"b": null}], "c": false} `, "[[[]]]\n", `[{"":""},"x"]`, `"€"`,
+		// invalid
+		``, ` `, `{`, `[1,]`, `{"a":1,}`, `01`, `1.`, `1e`, `.5`, `+1`, `-`, `tru`, `nul`, `"a`, `"\x"`,
+		`"\u12g4"`, `[[[[]]]]`, `{"a" 1}`, `{"a":1 "b":2}`, `1 2`, `}`, "\"\t\"", `[1}`, `{"a":1]`,
+		`{1:2}`, `[1]]`, `TRUE`,
+	}
+	for _, tc := range testCases {
+		valid := stdjson.Valid([]byte(tc)) && depth(tc) <= maxDepth
+		var assignment validateCircuit
+		data := make([]byte, len(assignment.Data))
+		copy(data, tc)
+		copy(assignment.Data[:], uints.NewU8Array(data))
+		assignment.Length = len(tc)
+		err := test.IsSolved(&validateCircuit{}, &assignment, ecc.BN254.ScalarField())
+		if valid {
+			assert.NoError(err, tc)
+		} else {
+			assert.Error(err, tc)
+		}
+	}
+}
+
+const maxValueLen = 24
+
+var queries = []Query{
+	{Path: []string{"iss"}, MaxLen: maxValueLen},
+	{Path: []string{"sub"}, MaxLen: maxValueLen},
+	{Path: []string{"aud"}, MaxLen: maxValueLen},
+	{Path: []string{"profile", "name"}, MaxLen: maxValueLen},
+	{Path: []string{"profile", "n", "v"}, MaxLen: maxValueLen},
+	{Path: []string{"verified"}, MaxLen: maxValueLen},
+}
+
+type extractCircuit struct {
+	Data     [200]uints.U8
+	Length   frontend.Variable
+	Values   [6][maxValueLen]uints.U8
+	Lengths  [6]frontend.Variable
+	IsString [6]frontend.Variable
+}
+
+func (c *extractCircuit) Define(api frontend.API) error {
+	p, err := NewParser(api, maxDepth)
+	if err != nil {
+		return err
+	}
+	values, err := p.Extract(c.Data[:], c.Length, queries...)
+	if err != nil {
+		return err
+	}
+	for i, v := range values {
+		api.AssertIsEqual(v.Length, c.Lengths[i])
+		api.AssertIsEqual(v.IsString, c.IsString[i])
+		for j := range v.Bytes {
+			api.AssertIsEqual(v.Bytes[j].Val, c.Values[i][j].Val)
+		}
+	}
+	return nil
+}
+
+func extractAssignment(doc string, values []string, isString []int) *extractCircuit {
+	var assignment extractCircuit
+	data := make([]byte, len(assignment.Data))
+	copy(data, doc)
+	copy(assignment.Data[:], uints.NewU8Array(data))
+	assignment.Length = len(doc)
+	for i := range values {
+		v := make([]byte, maxValueLen)
+		copy(v, values[i])
+		copy(assignment.Values[i][:], uints.NewU8Array(v))
+		assignment.Lengths[i] = len(values[i])
+		assignment.IsString[i] = isString[i]
+	}
+	return &assignment
+}
+
+func TestExtract(t *testing.T) {
+	assert := test.NewAssert(t)
+	doc := `{"sub" : "12\"34\\5", "aud":["x", "y"],"profile":{"name":"J\u00e9r\u00F4me \u20ac\u0021",` +
+		`"n":{"v":-12.5e3},"iss":0},"iss":"https://example.com","verified":true}`
+	values := []string{"https://example.com", `12"34\5`, `["x", "y"]`, "Jérôme €!", "-12.5e3", "true"}
+	isString := []int{1, 1, 0, 1, 0, 0}
+	err := test.IsSolved(&extractCircuit{}, extractAssignment(doc, values, isString), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the key of the path occurs twice.
+	wrong := `{"iss":"a","sub":"","aud":1,"iss":"b","profile":{"name":"","n":{"v":0}},"verified":true}`
+	err = test.IsSolved(&extractCircuit{}, extractAssignment(wrong, []string{"a", "", "1", "", "0", "true"}, []int{1, 1, 0, 1, 0, 0}), ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// the key of the path is in a nested object.
+	wrong = `{"sub":"","aud":1,"profile":{"iss":"a","name":"","n":{"v":0}},"verified":true}`
+	err = test.IsSolved(&extractCircuit{}, extractAssignment(wrong, []string{"a", "", "1", "", "0", "true"}, []int{1, 1, 0, 1, 0, 0}), ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// the value is too long.
+	wrong = `{"iss":"0123456789012345678901234","sub":"","aud":1,"profile":{"name":"","n":{"v":0}},"verified":true}`
+	err = test.IsSolved(&extractCircuit{}, extractAssignment(wrong, []string{"012345678901234567890123", "", "1", "", "0", "true"}, []int{1, 1, 0, 1, 0, 0}), ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// the surrogates are not supported.
+	wrong = `{"iss":"\ud83d\ude00","sub":"","aud":1,"profile":{"name":"","n":{"v":0}},"verified":true}`
+	err = test.IsSolved(&extractCircuit{}, extractAssignment(wrong, []string{"", "", "1", "", "0", "true"}, []int{1, 1, 0, 1, 0, 0}), ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -0,0 +1,425 @@
+package json
+
+// the states of the parser. The state stError is absorbing and the parsing
+// succeeds if the state after the end of the input is stDone.
+const (
+	stError    = iota
+	stValue    // expecting a value
+	stArrFirst // expecting the first value of an array or its end
+	stObjFirst // expecting the first key of an object or its end
+	stKey      // expecting a key
+	stKeyStr   // inside of a key
+	stKeyEsc   // after a backslash in a key
+	stKeyU1    // expecting the first hex digit of a unicode escape in a key
+	stKeyU2
+	stKeyU3
+	stKeyU4
+	stColon // expecting a colon after a key
+	stStr   // inside of a string value
+	stEsc   // after a backslash in a string value
+	stU1    // expecting the first hex digit of a unicode escape
+	stU2
+	stU3
+	stU4
+	stMinus   // after the minus sign of a number
+	stZero    // after the leading zero of a number
+	stInt     // in the integer part of a number
+	stDot     // after the decimal point of a number
+	stFrac    // in the fractional part of a number
+	stExpE    // after the exponent marker of a number
+	stExpSign // after the sign of the exponent of a number
+	stExp     // in the exponent of a number
+	stTrue1   // after t, expecting r
+	stTrue2   // expecting u
+	stTrue3   // expecting e
+	stFalse1  // after f, expecting a
+	stFalse2  // expecting l
+	stFalse3  // expecting s
+	stFalse4  // expecting e
+	stNull1   // after n, expecting u
+	stNull2   // expecting l
+	stNull3   // expecting l
+	stAfter   // after a value
+	stDone    // after the end of the input
+	nbStates
+)
+
+// the classes of the input bytes. The class clsEnd is used for the positions
+// after the end of the input.
+const (
+	clsOther      = iota // bytes which are only allowed in strings
+	clsControl           // control characters which are not allowed
+	clsSpace             // the space character
+	clsWhitespace        // tab, line feed and carriage return
+	clsQuote             // "
+	clsBackslash
+	clsLBrace
+	clsRBrace
+	clsLBracket
+	clsRBracket
+	clsColon
+	clsComma
+	clsMinus
+	clsPlus
+	clsDot
+	clsZero
+	clsDigit // 1-9
+	clsA     // a
+	clsB     // b
+	clsCD    // c, d
+	clsE     // e
+	clsF     // f
+	clsUpper // A-D, F
+	clsUpperE
+	clsL
+	clsN
+	clsR
+	clsS
+	clsT
+	clsU
+	clsSlash
+	clsEnd
+	nbClasses
+)
+
+// the contexts of the parser given by the innermost container.
+const (
+	ctxArray = iota
+	ctxObject
+	ctxRoot
+	nbContexts
+)
+
+// byteClass returns the class of the byte b.
+func byteClass(b int) int {
+	switch {
+	case b == ' ':
+		return clsSpace
+	case b == '\t' || b == '\n' || b == '\r':
+		return clsWhitespace
+	case b < 0x20:
+		return clsControl
+	case b >= '1' && b <= '9':
+		return clsDigit
+	case b >= 'A' && b <= 'F' && b != 'E':
+		return clsUpper
+	}
+	switch b {
+	case '"':
+		return clsQuote
+	case '\\':
+		return clsBackslash
+	case '{':
+		return clsLBrace
+	case '}':
+		return clsRBrace
+	case '[':
+		return clsLBracket
+	case ']':
+		return clsRBracket
+	case ':':
+		return clsColon
+	case ',':
+		return clsComma
+	case '-':
+		return clsMinus
+	case '+':
+		return clsPlus
+	case '.':
+		return clsDot
+	case '0':
+		return clsZero
+	case 'a':
+		return clsA
+	case 'b':
+		return clsB
+	case 'c', 'd':
+		return clsCD
+	case 'e':
+		return clsE
+	case 'f':
+		return clsF
+	case 'E':
+		return clsUpperE
+	case 'l':
+		return clsL
+	case 'n':
+		return clsN
+	case 'r':
+		return clsR
+	case 's':
+		return clsS
+	case 't':
+		return clsT
+	case 'u':
+		return clsU
+	case '/':
+		return clsSlash
+	}
+	return clsOther
+}
+
+// hexValue returns the value of the hexadecimal digit b and 0 for the other
+// bytes.
+func hexValue(b int) int {
+	switch {
+	case b >= '0' && b <= '9':
+		return b - '0'
+	case b >= 'a' && b <= 'f':
+		return b - 'a' + 10
+	case b >= 'A' && b <= 'F':
+		return b - 'A' + 10
+	}
+	return 0
+}
+
+func isHex(cls int) bool {
+	switch cls {
+	case clsZero, clsDigit, clsA, clsB, clsCD, clsE, clsF, clsUpper, clsUpperE:
+		return true
+	}
+	return false
+}
+
+func isSpace(cls int) bool {
+	return cls == clsSpace || cls == clsWhitespace
+}
+
+func isDigit(cls int) bool {
+	return cls == clsZero || cls == clsDigit
+}
+
+// escaped returns the byte given by the single character escape of the class
+// and 0 if the class is not a single character escape.
+func escaped(cls int) int {
+	switch cls {
+	case clsQuote:
+		return '"'
+	case clsBackslash:
+		return '\\'
+	case clsSlash:
+		return '/'
+	case clsB:
+		return '\b'
+	case clsF:
+		return '\f'
+	case clsN:
+		return '\n'
+	case clsR:
+		return '\r'
+	case clsT:
+		return '\t'
+	}
+	return 0
+}
+
+func expectsValue(st int) bool {
+	return st == stValue || st == stArrFirst
+}
+
+func isNumberEnd(st int) bool {
+	return st == stZero || st == stInt || st == stFrac || st == stExp
+}
+
+// transition returns the state after reading a byte of class cls in state st
+// and context ctx.
+func transition(st, cls, ctx int) int {
+	if expectsValue(st) {
+		if isSpace(cls) {
+			return st
+		}
+		switch cls {
+		case clsQuote:
+			return stStr
+		case clsLBrace:
+			return stObjFirst
+		case clsLBracket:
+			return stArrFirst
+		case clsMinus:
+			return stMinus
+		case clsZero:
+			return stZero
+		case clsDigit:
+			return stInt
+		case clsT:
+			return stTrue1
+		case clsF:
+			return stFalse1
+		case clsN:
+			return stNull1
+		case clsRBracket:
+			if st == stArrFirst {
+				return stAfter
+			}
+		}
+		return stError
+	}
+	if isNumberEnd(st) {
+		switch {
+		case isDigit(cls) && st != stZero:
+			return st
+		case cls == clsDot && (st == stZero || st == stInt):
+			return stDot
+		case (cls == clsE || cls == clsUpperE) && st != stExp:
+			return stExpE
+		}
+		// the number ends and the byte is read after the value.
+		return transition(stAfter, cls, ctx)
+	}
+	switch st {
+	case stObjFirst, stKey:
+		switch {
+		case isSpace(cls):
+			return st
+		case cls == clsQuote:
+			return stKeyStr
+		case cls == clsRBrace && st == stObjFirst:
+			return stAfter
+		}
+	case stKeyStr, stStr:
+		switch cls {
+		case clsQuote:
+			if st == stKeyStr {
+				return stColon
+			}
+			return stAfter
+		case clsBackslash:
+			return st + 1
+		case clsControl, clsWhitespace, clsEnd:
+			// the control characters must be escaped in strings.
+		default:
+			return st
+		}
+	case stKeyEsc, stEsc:
+		if cls == clsU {
+			return st + 1
+		}
+		if escaped(cls) != 0 {
+			return st - 1
+		}
+	case stKeyU1, stKeyU2, stKeyU3, stU1, stU2, stU3:
+		if isHex(cls) {
+			return st + 1
+		}
+	case stKeyU4:
+		if isHex(cls) {
+			return stKeyStr
+		}
+	case stU4:
+		if isHex(cls) {
+			return stStr
+		}
+	case stColon:
+		switch {
+		case isSpace(cls):
+			return stColon
+		case cls == clsColon:
+			return stValue
+		}
+	case stMinus:
+		switch cls {
+		case clsZero:
+			return stZero
+		case clsDigit:
+			return stInt
+		}
+	case stDot:
+		if isDigit(cls) {
+			return stFrac
+		}
+	case stExpE:
+		if cls == clsPlus || cls == clsMinus {
+			return stExpSign
+		}
+		if isDigit(cls) {
+			return stExp
+		}
+	case stExpSign:
+		if isDigit(cls) {
+			return stExp
+		}
+	case stTrue1, stTrue2, stFalse1, stFalse2, stFalse3, stNull1, stNull2:
+		if cls == literalNext[st] {
+			return st + 1
+		}
+	case stTrue3, stFalse4, stNull3:
+		if cls == literalNext[st] {
+			return stAfter
+		}
+	case stAfter:
+		switch {
+		case isSpace(cls):
+			return stAfter
+		case cls == clsComma && ctx == ctxObject:
+			return stKey
+		case cls == clsComma && ctx == ctxArray:
+			return stValue
+		case cls == clsRBrace && ctx == ctxObject, cls == clsRBracket && ctx == ctxArray:
+			return stAfter
+		case cls == clsEnd && ctx == ctxRoot:
+			return stDone
+		}
+	case stDone:
+		if cls == clsEnd {
+			return stDone
+		}
+	}
+	return stError
+}
+
+// literalNext is the class of the next byte of the literals true, false and
+// null.
+var literalNext = map[int]int{
+	stTrue1: clsR, stTrue2: clsU, stTrue3: clsE,
+	stFalse1: clsA, stFalse2: clsL, stFalse3: clsS, stFalse4: clsE,
+	stNull1: clsU, stNull2: clsL, stNull3: clsL,
+}
+
+// the flags of the byte of class cls read in state st, used for tracking the
+// nesting and the values.
+type flags struct {
+	push, pushObject int // the byte opens a container
+	pop              int // the byte closes a container
+	valueStart       int // the byte is the first byte of a value
+	valueEnd         int // the byte is the last byte of a value
+	numberEnd        int // the byte follows the last byte of a number
+	keyStart         int // the byte is the opening quote of a key
+	keyChar          int // the byte is part of the encoding of a key
+	keyEnd           int // the byte is the closing quote of a key
+	strChar          int // the byte is an unescaped character of a string
+	escape           int // the byte is a single character escape
+	escChar          int // the byte given by the single character escape
+	hexDigit         int // the byte is one of the first three hex digits of a unicode escape
+	hexLast          int // the byte is the last hex digit of a unicode escape
+}
+
+// byteFlags returns the flags of the byte of class cls read in state st. The
+// flags are only meaningful when the transition does not fail.
+func byteFlags(st, cls int) flags {
+	var f flags
+	b := func(v bool) int {
+		if v {
+			return 1
+		}
+		return 0
+	}
+	canClose := st == stAfter || isNumberEnd(st)
+	f.push = b(expectsValue(st) && (cls == clsLBrace || cls == clsLBracket))
+	f.pushObject = b(expectsValue(st) && cls == clsLBrace)
+	f.pop = b((cls == clsRBrace && (canClose || st == stObjFirst)) || (cls == clsRBracket && (canClose || st == stArrFirst)))
+	f.valueStart = b(expectsValue(st) && !isSpace(cls) && cls != clsRBracket && transition(st, cls, ctxRoot) != stError)
+	f.numberEnd = b(isNumberEnd(st) && (isSpace(cls) || cls == clsComma || cls == clsRBrace || cls == clsRBracket || cls == clsEnd))
+	f.valueEnd = b((st == stStr && cls == clsQuote) || ((st == stTrue3 || st == stFalse4 || st == stNull3) && cls == literalNext[st]) || f.pop == 1)
+	f.keyStart = b((st == stObjFirst || st == stKey) && cls == clsQuote)
+	f.keyEnd = b(st == stKeyStr && cls == clsQuote)
+	keyNext := transition(st, cls, ctxRoot)
+	f.keyChar = b(st >= stKeyStr && st <= stKeyU4 && keyNext >= stKeyStr && keyNext <= stKeyU4)
+	f.strChar = b(st == stStr && cls != clsQuote && cls != clsBackslash && transition(st, cls, ctxRoot) == stStr)
+	if st == stEsc {
+		f.escape = b(escaped(cls) != 0)
+		f.escChar = escaped(cls)
+	}
+	f.hexDigit = b((st == stU1 || st == stU2 || st == stU3) && isHex(cls))
+	f.hexLast = b(st == stU4 && isHex(cls))
+	return f
+}
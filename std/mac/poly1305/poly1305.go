@@ -0,0 +1,179 @@
+// Package poly1305 implements the Poly1305 one-time authenticator in circuit as
+// specified by RFC 8439.
+//
+// The accumulator is an element of the field modulo 2^130-5, which is emulated
+// with [emparams.Poly1305]. The lengths of the messages are witnesses bounded
+// by the lengths of the slices.
+//
+// A key must only be used to authenticate a single message.
+package poly1305
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+const (
+	// KeySize is the size of the key in bytes.
+	KeySize = 32
+	// TagSize is the size of the authenticator in bytes.
+	TagSize = 16
+	// blockSize is the size of the blocks of the message in bytes.
+	blockSize = 16
+)
+
+// Poly1305 computes Poly1305 authenticators in circuit.
+type Poly1305 struct {
+	api frontend.API
+	f   *emulated.Field[emparams.Poly1305]
+	bf  *uints.BinaryField[uints.U32]
+}
+
+// New returns a new Poly1305 instance.
+func New(api frontend.API) (*Poly1305, error) {
+	f, err := emulated.NewField[emparams.Poly1305](api)
+	if err != nil {
+		return nil, fmt.Errorf("new field: %w", err)
+	}
+	bf, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, fmt.Errorf("new binary field: %w", err)
+	}
+	return &Poly1305{api: api, f: f, bf: bf}, nil
+}
+
+// Sum returns the authenticator of the first length bytes of msg.
+func (p *Poly1305) Sum(key [KeySize]uints.U8, msg []uints.U8, length frontend.Variable) [TagSize]uints.U8 {
+	m := p.NewMAC(key)
+	m.Write(msg, length)
+	return m.Sum()
+}
+
+// MAC is an authenticator in progress. It allows to authenticate messages
+// built from several parts of variable lengths, such as the inputs of the AEAD
+// construction of ChaCha20-Poly1305.
+type MAC struct {
+	p   *Poly1305
+	r   *emulated.Element[emparams.Poly1305]
+	s   [blockSize]uints.U8
+	acc *emulated.Element[emparams.Poly1305]
+}
+
+// NewMAC returns a new authenticator for the key.
+func (p *Poly1305) NewMAC(key [KeySize]uints.U8) *MAC {
+	api := p.api
+	var r [blockSize]frontend.Variable
+	var s [blockSize]uints.U8
+	for i := 0; i < blockSize; i++ {
+		r[i] = p.bf.ByteValueOf(key[i].Val).Val
+		s[i] = p.bf.ByteValueOf(key[blockSize+i].Val)
+	}
+	// r is clamped by clearing the four high bits of the bytes 3, 7, 11 and 15
+	// and the two low bits of the bytes 4, 8 and 12.
+	for _, i := range []int{3, 7, 11, 15} {
+		bs := bits.ToBinary(api, r[i], bits.WithNbDigits(8))
+		r[i] = bits.FromBinary(api, bs[:4], bits.WithUnconstrainedInputs())
+	}
+	for _, i := range []int{4, 8, 12} {
+		bs := bits.ToBinary(api, r[i], bits.WithNbDigits(8))
+		r[i] = api.Mul(bits.FromBinary(api, bs[2:], bits.WithUnconstrainedInputs()), 4)
+	}
+	return &MAC{
+		p:   p,
+		r:   p.f.NewElement([]frontend.Variable{p.pack(r[:8]), p.pack(r[8:]), 0}),
+		s:   s,
+		acc: p.f.Zero(),
+	}
+}
+
+// Write absorbs the first length bytes of msg. The last block is padded as
+// specified by Poly1305 if it is partial, hence all the writes but the last
+// must have lengths which are multiple of 16 bytes.
+func (m *MAC) Write(msg []uints.U8, length frontend.Variable) {
+	m.write(msg, length, false)
+}
+
+// WritePadded absorbs the first length bytes of msg padded with zeros to a
+// multiple of 16 bytes, as in the AEAD construction of ChaCha20-Poly1305.
+func (m *MAC) WritePadded(msg []uints.U8, length frontend.Variable) {
+	m.write(msg, length, true)
+}
+
+func (m *MAC) write(msg []uints.U8, length frontend.Variable, padded bool) {
+	p := m.p
+	api := p.api
+	nbBlocks := (len(msg) + blockSize - 1) / blockSize
+	inMsg := selector.PrefixMask(api, length, len(msg))
+	b := make([]frontend.Variable, nbBlocks*blockSize)
+	for i := range b {
+		b[i] = 0
+		if i < len(msg) {
+			b[i] = api.Mul(p.bf.ByteValueOf(msg[i].Val).Val, inMsg[i])
+		} else {
+			inMsg = append(inMsg, 0)
+		}
+	}
+	for i := 0; i < nbBlocks; i++ {
+		block := b[i*blockSize : (i+1)*blockSize]
+		flags := inMsg[i*blockSize : (i+1)*blockSize]
+		// the block is followed by the byte 1 at the position of its length.
+		var pad [blockSize + 1]frontend.Variable
+		for j := range pad {
+			pad[j] = 0
+		}
+		if padded {
+			pad[blockSize] = flags[0]
+		} else {
+			for j := 1; j < blockSize; j++ {
+				pad[j] = api.Sub(flags[j-1], flags[j])
+			}
+			pad[blockSize] = flags[blockSize-1]
+		}
+		limbs := []frontend.Variable{
+			api.Add(p.pack(block[:8]), p.pack(pad[:8])),
+			api.Add(p.pack(block[8:]), p.pack(pad[8:blockSize])),
+			pad[blockSize],
+		}
+		n := p.f.NewElement(limbs)
+		next := p.f.Mul(p.f.Add(m.acc, n), m.r)
+		m.acc = p.f.Select(flags[0], next, m.acc)
+	}
+}
+
+// Sum returns the authenticator of the absorbed messages.
+func (m *MAC) Sum() [TagSize]uints.U8 {
+	p := m.p
+	api := p.api
+	acc := p.f.Reduce(m.acc)
+	p.f.AssertIsInRange(acc)
+	// the authenticator is acc + s modulo 2^128.
+	var res [TagSize]uints.U8
+	var carry frontend.Variable = 0
+	for k := 0; k < 2; k++ {
+		s := make([]frontend.Variable, 8)
+		for j := range s {
+			s[j] = m.s[8*k+j].Val
+		}
+		bs := bits.ToBinary(api, api.Add(acc.Limbs[k], p.pack(s), carry), bits.WithNbDigits(65))
+		carry = bs[64]
+		for j := 0; j < 8; j++ {
+			res[8*k+j] = uints.U8{Val: bits.FromBinary(api, bs[8*j:8*j+8], bits.WithUnconstrainedInputs())}
+		}
+	}
+	return res
+}
+
+// pack returns the little-endian integer given by the bytes bs.
+func (p *Poly1305) pack(bs []frontend.Variable) frontend.Variable {
+	var res frontend.Variable = 0
+	for i := len(bs) - 1; i >= 0; i-- {
+		res = p.api.Add(p.api.Mul(res, 256), bs[i])
+	}
+	return res
+}
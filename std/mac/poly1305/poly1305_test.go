@@ -0,0 +1,56 @@
+package poly1305
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/poly1305" //nolint:staticcheck // reference implementation
+)
+
+type sumCircuit struct {
+	Key      [KeySize]uints.U8
+	Msg      []uints.U8
+	Length   frontend.Variable
+	Expected [TagSize]uints.U8
+}
+
+func (c *sumCircuit) Define(api frontend.API) error {
+	p, err := New(api)
+	if err != nil {
+		return err
+	}
+	res := p.Sum(c.Key, c.Msg, c.Length)
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Expected[i].Val)
+	}
+	return nil
+}
+
+func TestSum(t *testing.T) {
+	assert := test.NewAssert(t)
+	const maxLen = 50
+	key := make([]byte, KeySize)
+	rand.Read(key)
+	// the accumulator is close to the modulus.
+	key[16], key[17] = 0xff, 0xff
+	for _, length := range []int{0, 1, 15, 16, 17, 32, maxLen} {
+		msg := make([]byte, maxLen)
+		rand.Read(msg[:length])
+		var expected [TagSize]byte
+		poly1305.Sum(&expected, msg[:length], (*[KeySize]byte)(key))
+
+		circuit := sumCircuit{Msg: make([]uints.U8, maxLen)}
+		witness := sumCircuit{
+			Key:      [KeySize]uints.U8(uints.NewU8Array(key)),
+			Msg:      uints.NewU8Array(msg),
+			Length:   length,
+			Expected: [TagSize]uints.U8(uints.NewU8Array(expected[:])),
+		}
+		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, length)
+	}
+}
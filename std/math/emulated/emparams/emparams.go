@@ -170,6 +170,150 @@ type BLS12381Fr struct{ fourLimbPrimeField }
 
 func (fp BLS12381Fr) Modulus() *big.Int { return ecc.BLS12_381.ScalarField() }
 
+// BandersnatchFr provides type parametrization for field emulation:
+//   - limbs: 4
+//   - limb width: 64 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0x1cfb69d4ca675f520cce760202687600ff8f87007419047174fd06b52876e7e1 (base 16)
+//	13108968793781547619861935127046491459309155893440570251786403306729687672801 (base 10)
+//
+// This is the scalar field of the Bandersnatch curve, defined over the scalar
+// field of the BLS12-381 curve.
+type BandersnatchFr struct{ fourLimbPrimeField }
+
+func (fr BandersnatchFr) Modulus() *big.Int {
+	val, _ := new(big.Int).SetString("1cfb69d4ca675f520cce760202687600ff8f87007419047174fd06b52876e7e1", 16)
+	return val
+}
+
+// BabyJubjubFr provides type parametrization for field emulation:
+//   - limbs: 4
+//   - limb width: 64 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0x60c89ce5c263405370a08b6d0302b0bab3eedb83920ee0a677297dc392126f1 (base 16)
+//	2736030358979909402780800718157159386076813972158567259200215660948447373041 (base 10)
+//
+// This is the scalar field of the Baby Jubjub curve, the twisted Edwards curve
+// defined over the scalar field of the BN254 curve.
+type BabyJubjubFr struct{ fourLimbPrimeField }
+
+func (fr BabyJubjubFr) Modulus() *big.Int {
+	val, _ := new(big.Int).SetString("60c89ce5c263405370a08b6d0302b0bab3eedb83920ee0a677297dc392126f1", 16)
+	return val
+}
+
+// JubjubFr provides type parametrization for field emulation:
+//   - limbs: 4
+//   - limb width: 64 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0xe7db4ea6533afa906673b0101343b00a6682093ccc81082d0970e5ed6f72cb7 (base 16)
+//	6554484396890773809930967563523245729705921265872317281365359162392183254199 (base 10)
+//
+// This is the scalar field of the Jubjub curve, the twisted Edwards curve defined
+// over the scalar field of the BLS12-381 curve.
+type JubjubFr struct{ fourLimbPrimeField }
+
+func (fr JubjubFr) Modulus() *big.Int {
+	val, _ := new(big.Int).SetString("e7db4ea6533afa906673b0101343b00a6682093ccc81082d0970e5ed6f72cb7", 16)
+	return val
+}
+
+// EdBLS12377Fr provides type parametrization for field emulation:
+//   - limbs: 4
+//   - limb width: 64 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0x4aad957a68b2955982d1347970dec005293a3afc43c8afeb95aee9ac33fd9ff (base 16)
+//	2111115437357092606062206234695386632838870926408408195193685246394721360383 (base 10)
+//
+// This is the scalar field of the twisted Edwards curve defined over the scalar
+// field of the BLS12-377 curve.
+type EdBLS12377Fr struct{ fourLimbPrimeField }
+
+func (fr EdBLS12377Fr) Modulus() *big.Int {
+	val, _ := new(big.Int).SetString("4aad957a68b2955982d1347970dec005293a3afc43c8afeb95aee9ac33fd9ff", 16)
+	return val
+}
+
+// EdBLS24315Fr provides type parametrization for field emulation:
+//   - limbs: 4
+//   - limb width: 64 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0x32dbd584953b42564bf8fd939f24f53138f389f67beda7e5558abe965b8f281 (base 16)
+//	1437753473921907580703509300571927811987591765799164617677716990775193563777 (base 10)
+//
+// This is the scalar field of the twisted Edwards curve defined over the scalar
+// field of the BLS24-315 curve.
+type EdBLS24315Fr struct{ fourLimbPrimeField }
+
+func (fr EdBLS24315Fr) Modulus() *big.Int {
+	val, _ := new(big.Int).SetString("32dbd584953b42564bf8fd939f24f53138f389f67beda7e5558abe965b8f281", 16)
+	return val
+}
+
+// EdBLS24317Fr provides type parametrization for field emulation:
+//   - limbs: 4
+//   - limb width: 64 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0x887f22fd4d1b5f85a1612fe51b079a91ffa0e80551b97b458b17ab6a69cb571 (base 16)
+//	3858698654557105525567273719690987823069521430163883173133245580997415449969 (base 10)
+//
+// This is the scalar field of the twisted Edwards curve defined over the scalar
+// field of the BLS24-317 curve.
+type EdBLS24317Fr struct{ fourLimbPrimeField }
+
+func (fr EdBLS24317Fr) Modulus() *big.Int {
+	val, _ := new(big.Int).SetString("887f22fd4d1b5f85a1612fe51b079a91ffa0e80551b97b458b17ab6a69cb571", 16)
+	return val
+}
+
+// EdBW6633Fr provides type parametrization for field emulation:
+//   - limbs: 5
+//   - limb width: 64 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0x98474056b0daca1a7ee9317d2f8bd5fbd83a035540306f0f328ceee31a83eb95587428a793f7af (base 16)
+//	4963142838689179791878211236301121218116687802119716497817028544854034649070444389864454748079 (base 10)
+//
+// This is the scalar field of the twisted Edwards curve defined over the scalar
+// field of the BW6-633 curve.
+type EdBW6633Fr struct{ fiveLimbPrimeField }
+
+func (fr EdBW6633Fr) Modulus() *big.Int {
+	val, _ := new(big.Int).SetString("98474056b0daca1a7ee9317d2f8bd5fbd83a035540306f0f328ceee31a83eb95587428a793f7af", 16)
+	return val
+}
+
+// EdBW6761Fr provides type parametrization for field emulation:
+//   - limbs: 6
+//   - limb width: 64 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0x35c748c2f8a21d58c760b80d94292763445b3e601ea271e1d75fe7d6eeb84234066d10f5d893814103486497d95295 (base 16)
+//	32333053251621136751331591711861691692049189094364332567435817881934511297123972799646723302813083835942624121493 (base 10)
+//
+// This is the scalar field of the twisted Edwards curve defined over the scalar
+// field of the BW6-761 curve.
+type EdBW6761Fr struct{ sixLimbPrimeField }
+
+func (fr EdBW6761Fr) Modulus() *big.Int {
+	val, _ := new(big.Int).SetString("35c748c2f8a21d58c760b80d94292763445b3e601ea271e1d75fe7d6eeb84234066d10f5d893814103486497d95295", 16)
+	return val
+}
+
 // P256Fp provides type parametrization for field emulation:
 //   - limbs: 4
 //   - limb width: 64 bits
@@ -282,6 +426,26 @@ type BLS24315Fr struct{ fourLimbPrimeField }
 
 func (fr BLS24315Fr) Modulus() *big.Int { return ecc.BLS24_315.ScalarField() }
 
+// Poly1305 provides type parametrization for field emulation:
+//   - limbs: 3
+//   - limb width: 64 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0x3fffffffffffffffffffffffffffffffb (base 16)
+//	1361129467683753853853498429727072845819 (base 10)
+//
+// This is the field of the Poly1305 one-time authenticator, 2^130-5.
+type Poly1305 struct{}
+
+func (Poly1305) NbLimbs() uint     { return 3 }
+func (Poly1305) BitsPerLimb() uint { return 64 }
+func (Poly1305) IsPrime() bool     { return true }
+func (Poly1305) Modulus() *big.Int {
+	val := new(big.Int).Lsh(big.NewInt(1), 130)
+	return val.Sub(val, big.NewInt(5))
+}
+
 // Mod1e4096 provides type parametrization for emulated aritmetic:
 //   - limbs: 64
 //   - limb width: 64 bits
@@ -301,6 +465,25 @@ func (Mod1e4096) Modulus() *big.Int {
 	return val
 }
 
+// Mod1e2048 provides type parametrization for emulated aritmetic:
+//   - limbs: 32
+//   - limb width: 64 bits
+//
+// The modulus for type parametrisation is 2^2048-1.
+//
+// This is non-prime modulus. It is mainly targeted for using variable-modulus
+// operations (ModAdd, ModMul, ModExp, ModAssertIsEqual) for variable modulus
+// arithmetic, such as RSA-2048.
+type Mod1e2048 struct{}
+
+func (Mod1e2048) NbLimbs() uint     { return 32 }
+func (Mod1e2048) BitsPerLimb() uint { return 64 }
+func (Mod1e2048) IsPrime() bool     { return false }
+func (Mod1e2048) Modulus() *big.Int {
+	val := new(big.Int).Lsh(big.NewInt(1), 2048)
+	return val.Sub(val, big.NewInt(1))
+}
+
 // Mod1e512 provides type parametrization for emulated aritmetic:
 //   - limbs: 8
 //   - limb width: 64 bits
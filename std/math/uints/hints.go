@@ -40,8 +40,8 @@ func toBytes(m *big.Int, inputs []*big.Int, outputs []*big.Int) error {
 	if len(outputs) != nbLimbs {
 		return fmt.Errorf("output must be 8 elements")
 	}
-	if !inputs[1].IsUint64() {
-		return fmt.Errorf("input must be 64 bits")
+	if inputs[1].Sign() < 0 {
+		return fmt.Errorf("input must be non-negative")
 	}
 	base := new(big.Int).Lsh(big.NewInt(1), uint(8))
 	tmp := new(big.Int).Set(inputs[1])
@@ -24,6 +24,8 @@ package uints
 
 import (
 	"fmt"
+	"math/big"
+	"math/bits"
 
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/internal/logderivprecomp"
@@ -173,10 +175,10 @@ func (bf *BinaryField[T]) ValueOf(a frontend.Variable) T {
 	if err != nil {
 		panic(err)
 	}
-	// TODO: add constraint which ensures that map back to
 	for i := range bts {
 		r[i] = bf.ByteValueOf(bts[i])
 	}
+	bf.api.AssertIsEqual(bf.ToValue(r), a)
 	return r
 }
 
@@ -260,8 +262,19 @@ func (bf *BinaryField[T]) Add(a ...T) T {
 		va[i] = bf.ToValue(a[i])
 	}
 	vres := bf.api.Add(va[0], va[1], va[2:]...)
-	res := bf.ValueOf(vres)
-	// TODO: should also check the that carry we omitted is correct.
+	// the sum is decomposed into the bytes of the result and the carry, which
+	// is less than the number of summands.
+	var res T
+	bts, err := bf.api.Compiler().NewHint(toBytes, len(res)+1, len(res)+1, vres)
+	if err != nil {
+		panic(err)
+	}
+	for i := 0; i < len(res); i++ {
+		res[i] = bf.ByteValueOf(bts[i])
+	}
+	carry := bts[len(res)]
+	bf.rchecker.Check(carry, bits.Len(uint(len(a)-1)))
+	bf.api.AssertIsEqual(bf.api.Add(bf.ToValue(res), bf.api.Mul(carry, new(big.Int).Lsh(big.NewInt(1), uint(8*len(res))))), vres)
 	return res
 }
 
@@ -1,11 +1,14 @@
 package uints
 
 import (
+	"math/big"
 	"math/bits"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/test"
 )
 
@@ -79,3 +82,96 @@ func TestRshift(t *testing.T) {
 	err = test.IsSolved(&rshiftCircuit{Shift: 11}, &rshiftCircuit{Shift: 11, In: NewU32(0x12345678), Expected: NewU32(0x12345678 >> 11)}, ecc.BN254.ScalarField())
 	assert.NoError(err)
 }
+
+type valueOfCircuit struct {
+	In       frontend.Variable
+	Expected U32
+}
+
+func (c *valueOfCircuit) Define(api frontend.API) error {
+	uapi, err := New[U32](api)
+	if err != nil {
+		return err
+	}
+	uapi.AssertEq(uapi.ValueOf(c.In), c.Expected)
+	return nil
+}
+
+type addCircuit struct {
+	A, B, Expected U32
+}
+
+func (c *addCircuit) Define(api frontend.API) error {
+	uapi, err := New[U32](api)
+	if err != nil {
+		return err
+	}
+	uapi.AssertEq(uapi.Add(c.A, c.B), c.Expected)
+	return nil
+}
+
+// isSolvedWithHint compiles the circuit and solves it with the byte
+// decomposition hint replaced by a malicious one.
+func isSolvedWithHint(circuit, assignment frontend.Circuit, hint solver.Hint) error {
+	field := ecc.BN254.ScalarField()
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, circuit)
+	if err != nil {
+		return err
+	}
+	w, err := frontend.NewWitness(assignment, field)
+	if err != nil {
+		return err
+	}
+	return ccs.IsSolved(w, solver.OverrideHint(solver.GetHintID(toBytes), hint))
+}
+
+func TestValueOf(t *testing.T) {
+	assert := test.NewAssert(t)
+	err := test.IsSolved(&valueOfCircuit{}, &valueOfCircuit{In: 0x12345678, Expected: NewU32(0x12345678)}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	err = test.IsSolved(&valueOfCircuit{}, &valueOfCircuit{In: 1 << 32, Expected: NewU32(0)}, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// a decomposition hint returning the bytes of another value must not be
+	// accepted: the bytes are recomposed and compared to the input.
+	shifted := func(field *big.Int, inputs, outputs []*big.Int) error {
+		v := new(big.Int).Add(inputs[1], big.NewInt(1))
+		return toBytes(field, []*big.Int{inputs[0], v}, outputs)
+	}
+	err = isSolvedWithHint(&valueOfCircuit{}, &valueOfCircuit{In: 0x12345678, Expected: NewU32(0x12345679)}, shifted)
+	assert.Error(err)
+	err = isSolvedWithHint(&valueOfCircuit{}, &valueOfCircuit{In: 0x12345678, Expected: NewU32(0x12345678)}, toBytes)
+	assert.NoError(err)
+}
+
+func TestAdd(t *testing.T) {
+	assert := test.NewAssert(t)
+	err := test.IsSolved(&addCircuit{}, &addCircuit{A: NewU32(0xffffffff), B: NewU32(2), Expected: NewU32(1)}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	err = test.IsSolved(&addCircuit{}, &addCircuit{A: NewU32(0x12345678), B: NewU32(0x11111111), Expected: NewU32(0x23456789)}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	err = test.IsSolved(&addCircuit{}, &addCircuit{A: NewU32(0xffffffff), B: NewU32(2), Expected: NewU32(0)}, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// a hint returning arbitrary result bytes with the carry which makes the
+	// recomposition hold modulo the field must not be accepted: the carry is
+	// range checked.
+	const forged = 0xdeadbeef
+	forgedCarry := func(field *big.Int, inputs, outputs []*big.Int) error {
+		if err := toBytes(field, []*big.Int{big.NewInt(4), big.NewInt(forged)}, outputs[:4]); err != nil {
+			return err
+		}
+		if len(outputs) == 4 {
+			return nil
+		}
+		// carry = (sum - forged) / 2^32 mod p
+		inv := new(big.Int).ModInverse(new(big.Int).Lsh(big.NewInt(1), 32), field)
+		outputs[4].Sub(inputs[1], big.NewInt(forged))
+		outputs[4].Mul(outputs[4], inv).Mod(outputs[4], field)
+		return nil
+	}
+	err = isSolvedWithHint(&addCircuit{}, &addCircuit{A: NewU32(0xffffffff), B: NewU32(2), Expected: NewU32(forged)}, forgedCarry)
+	assert.Error(err)
+	err = isSolvedWithHint(&addCircuit{}, &addCircuit{A: NewU32(0xffffffff), B: NewU32(2), Expected: NewU32(1)}, toBytes)
+	assert.NoError(err)
+}
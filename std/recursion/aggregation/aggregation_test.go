@@ -0,0 +1,155 @@
+package aggregation
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	native_plonk "github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/recursion/plonk"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gnark/test/unsafekzg"
+)
+
+type leafCircuit struct {
+	P, Q frontend.Variable
+	N    frontend.Variable `gnark:",public"`
+}
+
+func (c *leafCircuit) Define(api frontend.API) error {
+	res := api.Mul(c.P, c.Q)
+	api.AssertIsEqual(res, c.N)
+	return nil
+}
+
+func unsafeSRS(ccs constraint.ConstraintSystem) (kzg.SRS, kzg.SRS, error) {
+	return unsafekzg.NewSRS(ccs)
+}
+
+func TestNbLevels(t *testing.T) {
+	assert := test.NewAssert(t)
+	nb, err := nbLevels(2, 8)
+	assert.NoError(err)
+	assert.Equal(3, nb)
+	nb, err = nbLevels(4, 4)
+	assert.NoError(err)
+	assert.Equal(1, nb)
+	_, err = nbLevels(2, 6)
+	assert.Error(err)
+	_, err = nbLevels(1, 4)
+	assert.Error(err)
+	_, err = nbLevels(4, 2)
+	assert.Error(err)
+}
+
+func TestAggregateTree(t *testing.T) {
+	assert := test.NewAssert(t)
+	leaves := make([]string, 9)
+	for i := range leaves {
+		leaves[i] = fmt.Sprintf("%d", i)
+	}
+	var nbCalls atomic.Int32
+	root, err := aggregateTree(4, 3, leaves, func(level int, children []string) (string, error) {
+		nbCalls.Add(1)
+		return fmt.Sprintf("%d(%s)", level, strings.Join(children, ",")), nil
+	})
+	assert.NoError(err)
+	assert.Equal("1(0(0,1,2),0(3,4,5),0(6,7,8))", root)
+	assert.Equal(int32(4), nbCalls.Load())
+
+	_, err = aggregateTree(4, 3, leaves, func(level int, children []string) (string, error) {
+		if level == 1 {
+			return "", fmt.Errorf("failing")
+		}
+		return "", nil
+	})
+	assert.Error(err)
+}
+
+func TestNodeCircuit(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	fanIn := 2
+
+	ccs, err := frontend.Compile(field, scs.NewBuilder, &leafCircuit{})
+	assert.NoError(err)
+	pk, vk, err := func() (native_plonk.ProvingKey, native_plonk.VerifyingKey, error) {
+		srs, srsLagrange, err := unsafeSRS(ccs)
+		if err != nil {
+			return nil, nil, err
+		}
+		return native_plonk.Setup(ccs, srs, srsLagrange)
+	}()
+	assert.NoError(err)
+
+	proofs := make([]native_plonk.Proof, fanIn)
+	witnesses := make([]witness.Witness, fanIn)
+	for i := range proofs {
+		w, err := frontend.NewWitness(&leafCircuit{P: 3, Q: 5 + i, N: 3 * (5 + i)}, field)
+		assert.NoError(err)
+		proofs[i], err = native_plonk.Prove(ccs, pk, w, plonk.GetNativeProverOptions(field, field))
+		assert.NoError(err)
+		witnesses[i], err = w.Public()
+		assert.NoError(err)
+	}
+	digest, err := Digest(field, witnesses...)
+	assert.NoError(err)
+
+	circuit, err := PlaceholderNodeCircuit[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl](ccs, vk, fanIn, plonk.WithCompleteArithmetic())
+	assert.NoError(err)
+	assignment := &NodeCircuit[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl]{
+		Proofs:    make([]plonk.Proof[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine], fanIn),
+		Witnesses: make([]plonk.Witness[sw_bn254.ScalarField], fanIn),
+		Digest:    digest,
+	}
+	for i := range proofs {
+		assignment.Proofs[i], err = plonk.ValueOfProof[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine](proofs[i])
+		assert.NoError(err)
+		assignment.Witnesses[i], err = plonk.ValueOfWitness[sw_bn254.ScalarField](witnesses[i])
+		assert.NoError(err)
+	}
+	err = test.IsSolved(circuit, assignment, field)
+	assert.NoError(err)
+
+	// the digest must commit to the public inputs of the children
+	assignment.Digest = 0
+	err = test.IsSolved(circuit, assignment, field)
+	assert.Error(err)
+}
+
+func TestAggregator(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping full aggregation in short mode")
+	}
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	fanIn, nbLeaves := 2, 4
+
+	agg, err := New[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl](&leafCircuit{}, fanIn, nbLeaves, unsafeSRS,
+		WithNbTasks(1), WithVerifierOptions(plonk.WithCompleteArithmetic()))
+	assert.NoError(err)
+	proofs := make([]native_plonk.Proof, nbLeaves)
+	witnesses := make([]witness.Witness, nbLeaves)
+	for i := range proofs {
+		proofs[i], witnesses[i], err = agg.ProveLeaf(&leafCircuit{P: 3, Q: 5 + i, N: 3 * (5 + i)})
+		assert.NoError(err)
+	}
+	rootProof, rootWitness, err := agg.Aggregate(proofs, witnesses)
+	assert.NoError(err)
+	err = native_plonk.Verify(rootProof, agg.VerifyingKey(), rootWitness)
+	assert.NoError(err)
+
+	expected, err := RootDigest(field, fanIn, witnesses)
+	assert.NoError(err)
+	computed, err := publicValues(rootWitness)
+	assert.NoError(err)
+	assert.Equal(0, expected.Cmp(computed[0]))
+}
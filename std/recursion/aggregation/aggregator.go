@@ -0,0 +1,229 @@
+package aggregation
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	native_plonk "github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/recursion/plonk"
+	"golang.org/x/sync/errgroup"
+)
+
+// SRSProvider returns the KZG SRS in canonical and Lagrange form suitable for
+// the PLONK setup of the constraint system ccs. For testing, the package
+// [github.com/consensys/gnark/test/unsafekzg] can be used.
+type SRSProvider func(ccs constraint.ConstraintSystem) (canonical, lagrange kzg.SRS, err error)
+
+type circuitKeys struct {
+	ccs constraint.ConstraintSystem
+	pk  native_plonk.ProvingKey
+	vk  native_plonk.VerifyingKey
+}
+
+// treeNode is a proof in the aggregation tree with the corresponding public
+// witness.
+type treeNode struct {
+	proof  native_plonk.Proof
+	public witness.Witness
+}
+
+// Aggregator holds the compiled leaf and node circuits of the aggregation tree
+// and their PLONK keys. Use [New] for initializing it.
+type Aggregator[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	field    *big.Int
+	fanIn    int
+	nbLeaves int
+	cfg      *config
+
+	leaf  circuitKeys
+	nodes []circuitKeys // nodes[0] verifies leaves, last element is the root
+}
+
+// New compiles the leaf circuit and the node circuits of the aggregation tree
+// with nbLeaves leaves where every node has fanIn children. It generates the
+// PLONK keys of all the circuits using the SRS returned by srs. The number of
+// leaves must be a power of fan-in.
+//
+// All the circuits are defined over the scalar field of the curve defined by
+// the type parameters.
+func New[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](leaf frontend.Circuit, fanIn, nbLeaves int, srs SRSProvider, opts ...Option) (*Aggregator[FR, G1El, G2El, GtEl], error) {
+	var fr FR
+	cfg, err := newCfg(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("apply options: %w", err)
+	}
+	nbNodeLevels, err := nbLevels(fanIn, nbLeaves)
+	if err != nil {
+		return nil, err
+	}
+	a := &Aggregator[FR, G1El, G2El, GtEl]{
+		field:    fr.Modulus(),
+		fanIn:    fanIn,
+		nbLeaves: nbLeaves,
+		cfg:      cfg,
+	}
+	if a.leaf, err = setup(a.field, leaf, srs); err != nil {
+		return nil, fmt.Errorf("leaf: %w", err)
+	}
+	child := a.leaf
+	for i := 0; i < nbNodeLevels; i++ {
+		node, err := PlaceholderNodeCircuit[FR, G1El, G2El, GtEl](child.ccs, child.vk, fanIn, cfg.verifierOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("node circuit %d: %w", i, err)
+		}
+		keys, err := setup(a.field, node, srs)
+		if err != nil {
+			return nil, fmt.Errorf("node level %d: %w", i, err)
+		}
+		a.nodes = append(a.nodes, keys)
+		child = keys
+	}
+	return a, nil
+}
+
+// ProveLeaf computes the proof of the leaf circuit for the given assignment.
+// It returns the proof and the public witness of the leaf. The proof is
+// computed with the options suitable for in-circuit verification.
+func (a *Aggregator[FR, G1El, G2El, GtEl]) ProveLeaf(assignment frontend.Circuit) (native_plonk.Proof, witness.Witness, error) {
+	w, err := frontend.NewWitness(assignment, a.field)
+	if err != nil {
+		return nil, nil, fmt.Errorf("new witness: %w", err)
+	}
+	proof, err := native_plonk.Prove(a.leaf.ccs, a.leaf.pk, w, plonk.GetNativeProverOptions(a.field, a.field))
+	if err != nil {
+		return nil, nil, fmt.Errorf("prove: %w", err)
+	}
+	pubw, err := w.Public()
+	if err != nil {
+		return nil, nil, fmt.Errorf("public witness: %w", err)
+	}
+	return proof, pubw, nil
+}
+
+// Aggregate aggregates the leaf proofs into a single root proof. The proofs of
+// every level are computed in parallel using a pool of workers. It returns the
+// root proof and its public witness, which consists of [RootDigest] of the
+// leaf public witnesses.
+//
+// The leaf proofs must be computed with the options suitable for in-circuit
+// verification, see [Aggregator.ProveLeaf]. The root proof is computed with
+// the default prover options and can be verified natively with the default
+// verifier options against [Aggregator.VerifyingKey].
+func (a *Aggregator[FR, G1El, G2El, GtEl]) Aggregate(proofs []native_plonk.Proof, publicWitnesses []witness.Witness) (native_plonk.Proof, witness.Witness, error) {
+	if len(proofs) != len(publicWitnesses) {
+		return nil, nil, fmt.Errorf("proofs and witnesses length mismatch")
+	}
+	if len(proofs) != a.nbLeaves {
+		return nil, nil, fmt.Errorf("expected %d leaf proofs, got %d", a.nbLeaves, len(proofs))
+	}
+	leaves := make([]treeNode, len(proofs))
+	for i := range proofs {
+		leaves[i] = treeNode{proof: proofs[i], public: publicWitnesses[i]}
+	}
+	root, err := aggregateTree(a.cfg.nbTasks, a.fanIn, leaves, a.proveNode)
+	if err != nil {
+		return nil, nil, err
+	}
+	return root.proof, root.public, nil
+}
+
+// VerifyingKey returns the verifying key of the root circuit.
+func (a *Aggregator[FR, G1El, G2El, GtEl]) VerifyingKey() native_plonk.VerifyingKey {
+	return a.nodes[len(a.nodes)-1].vk
+}
+
+func (a *Aggregator[FR, G1El, G2El, GtEl]) proveNode(level int, children []treeNode) (treeNode, error) {
+	var err error
+	assignment := &NodeCircuit[FR, G1El, G2El, GtEl]{
+		Proofs:    make([]plonk.Proof[FR, G1El, G2El], len(children)),
+		Witnesses: make([]plonk.Witness[FR], len(children)),
+	}
+	publics := make([]witness.Witness, len(children))
+	for i := range children {
+		if assignment.Proofs[i], err = plonk.ValueOfProof[FR, G1El, G2El](children[i].proof); err != nil {
+			return treeNode{}, fmt.Errorf("value of proof %d: %w", i, err)
+		}
+		if assignment.Witnesses[i], err = plonk.ValueOfWitness[FR](children[i].public); err != nil {
+			return treeNode{}, fmt.Errorf("value of witness %d: %w", i, err)
+		}
+		publics[i] = children[i].public
+	}
+	if assignment.Digest, err = Digest(a.field, publics...); err != nil {
+		return treeNode{}, fmt.Errorf("digest: %w", err)
+	}
+	w, err := frontend.NewWitness(assignment, a.field)
+	if err != nil {
+		return treeNode{}, fmt.Errorf("new witness: %w", err)
+	}
+	var proverOpts []backend.ProverOption
+	if level < len(a.nodes)-1 {
+		// only the proofs which are verified in-circuit need recursion
+		// friendly hash functions.
+		proverOpts = append(proverOpts, plonk.GetNativeProverOptions(a.field, a.field))
+	}
+	proof, err := native_plonk.Prove(a.nodes[level].ccs, a.nodes[level].pk, w, proverOpts...)
+	if err != nil {
+		return treeNode{}, fmt.Errorf("prove: %w", err)
+	}
+	pubw, err := w.Public()
+	if err != nil {
+		return treeNode{}, fmt.Errorf("public witness: %w", err)
+	}
+	return treeNode{proof: proof, public: pubw}, nil
+}
+
+// aggregateTree combines the leaves bottom-up, fanIn children at a time, until
+// a single root remains. The combinations of a level are computed in parallel
+// by at most nbTasks workers.
+func aggregateTree[T any](nbTasks, fanIn int, leaves []T, combine func(level int, children []T) (T, error)) (T, error) {
+	var zero T
+	if _, err := nbLevels(fanIn, len(leaves)); err != nil {
+		return zero, err
+	}
+	current := leaves
+	for level := 0; len(current) > 1; level++ {
+		next := make([]T, len(current)/fanIn)
+		var eg errgroup.Group
+		eg.SetLimit(nbTasks)
+		for i := range next {
+			i, level, children := i, level, current[i*fanIn:(i+1)*fanIn]
+			eg.Go(func() error {
+				res, err := combine(level, children)
+				if err != nil {
+					return fmt.Errorf("level %d node %d: %w", level, i, err)
+				}
+				next[i] = res
+				return nil
+			})
+		}
+		if err := eg.Wait(); err != nil {
+			return zero, err
+		}
+		current = next
+	}
+	return current[0], nil
+}
+
+func setup(field *big.Int, circuit frontend.Circuit, srs SRSProvider) (circuitKeys, error) {
+	ccs, err := frontend.Compile(field, scs.NewBuilder, circuit)
+	if err != nil {
+		return circuitKeys{}, fmt.Errorf("compile: %w", err)
+	}
+	canonical, lagrange, err := srs(ccs)
+	if err != nil {
+		return circuitKeys{}, fmt.Errorf("srs: %w", err)
+	}
+	pk, vk, err := native_plonk.Setup(ccs, canonical, lagrange)
+	if err != nil {
+		return circuitKeys{}, fmt.Errorf("setup: %w", err)
+	}
+	return circuitKeys{ccs: ccs, pk: pk, vk: vk}, nil
+}
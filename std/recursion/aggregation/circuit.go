@@ -0,0 +1,90 @@
+package aggregation
+
+import (
+	"fmt"
+
+	native_plonk "github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/recursion"
+	"github.com/consensys/gnark/std/recursion/plonk"
+)
+
+// NodeCircuit is the circuit of an inner node in the aggregation tree. It
+// verifies the proofs of the children and asserts that the public input Digest
+// is the hash of the public inputs of the children.
+//
+// The verifying key of the children is embedded in the circuit as a constant.
+// Use [PlaceholderNodeCircuit] for creating the circuit for compilation.
+type NodeCircuit[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	VerifyingKey plonk.VerifyingKey[FR, G1El, G2El] `gnark:"-"`
+	Proofs       []plonk.Proof[FR, G1El, G2El]
+	Witnesses    []plonk.Witness[FR]
+
+	// Digest is the hash of the public inputs of the children.
+	Digest frontend.Variable `gnark:",public"`
+
+	verifierOpts []plonk.VerifierOption
+}
+
+// PlaceholderNodeCircuit returns a node circuit for compilation which verifies
+// fanIn proofs of the constraint system ccs with the verifying key vk.
+func PlaceholderNodeCircuit[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](ccs constraint.ConstraintSystem, vk native_plonk.VerifyingKey, fanIn int, opts ...plonk.VerifierOption) (*NodeCircuit[FR, G1El, G2El, GtEl], error) {
+	if fanIn < 2 {
+		return nil, fmt.Errorf("fan-in must be at least 2, got %d", fanIn)
+	}
+	circuitVk, err := plonk.ValueOfVerifyingKey[FR, G1El, G2El](vk)
+	if err != nil {
+		return nil, fmt.Errorf("value of verifying key: %w", err)
+	}
+	c := &NodeCircuit[FR, G1El, G2El, GtEl]{
+		VerifyingKey: circuitVk,
+		Proofs:       make([]plonk.Proof[FR, G1El, G2El], fanIn),
+		Witnesses:    make([]plonk.Witness[FR], fanIn),
+		verifierOpts: opts,
+	}
+	for i := 0; i < fanIn; i++ {
+		c.Proofs[i] = plonk.PlaceholderProof[FR, G1El, G2El](ccs)
+		c.Witnesses[i] = plonk.PlaceholderWitness[FR](ccs)
+	}
+	return c, nil
+}
+
+// Define implements [frontend.Circuit].
+func (c *NodeCircuit[FR, G1El, G2El, GtEl]) Define(api frontend.API) error {
+	v, err := plonk.NewVerifier[FR, G1El, G2El, GtEl](api)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	if err = v.AssertSameProofs(c.VerifyingKey, c.Proofs, c.Witnesses, c.verifierOpts...); err != nil {
+		return fmt.Errorf("assert proofs: %w", err)
+	}
+	digest, err := witnessDigest[FR, G1El](api, c.Witnesses)
+	if err != nil {
+		return fmt.Errorf("witness digest: %w", err)
+	}
+	api.AssertIsEqual(digest, c.Digest)
+	return nil
+}
+
+// witnessDigest computes in-circuit the hash of the public inputs of all the
+// witnesses. It corresponds to the out-of-circuit [Digest].
+func witnessDigest[FR emulated.FieldParams, G1El algebra.G1ElementT](api frontend.API, witnesses []plonk.Witness[FR]) (frontend.Variable, error) {
+	var fr FR
+	h, err := recursion.NewHash(api, fr.Modulus(), true)
+	if err != nil {
+		return nil, fmt.Errorf("new hash: %w", err)
+	}
+	curve, err := algebra.GetCurve[FR, G1El](api)
+	if err != nil {
+		return nil, fmt.Errorf("get curve: %w", err)
+	}
+	for i := range witnesses {
+		for j := range witnesses[i].Public {
+			h.Write(curve.MarshalScalar(witnesses[i].Public[j])...)
+		}
+	}
+	return h.Sum(), nil
+}
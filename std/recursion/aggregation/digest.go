@@ -0,0 +1,111 @@
+package aggregation
+
+import (
+	"fmt"
+	"math/big"
+
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/std/recursion"
+)
+
+// Digest computes out-of-circuit the public input of the node circuit which
+// verifies the proofs with the given public witnesses.
+func Digest(field *big.Int, publicWitnesses ...witness.Witness) (*big.Int, error) {
+	publics := make([][]*big.Int, len(publicWitnesses))
+	for i := range publicWitnesses {
+		var err error
+		if publics[i], err = publicValues(publicWitnesses[i]); err != nil {
+			return nil, fmt.Errorf("witness %d: %w", i, err)
+		}
+	}
+	return digestValues(field, publics)
+}
+
+// RootDigest computes out-of-circuit the public input of the root proof of the
+// aggregation tree with the given fan-in for the leaf proofs with the public
+// witnesses leaves. The number of leaves must be a power of the fan-in.
+func RootDigest(field *big.Int, fanIn int, leaves []witness.Witness) (*big.Int, error) {
+	if _, err := nbLevels(fanIn, len(leaves)); err != nil {
+		return nil, err
+	}
+	level := make([][]*big.Int, len(leaves))
+	for i := range leaves {
+		var err error
+		if level[i], err = publicValues(leaves[i]); err != nil {
+			return nil, fmt.Errorf("leaf %d: %w", i, err)
+		}
+	}
+	for len(level) > 1 {
+		next := make([][]*big.Int, len(level)/fanIn)
+		for i := range next {
+			d, err := digestValues(field, level[i*fanIn:(i+1)*fanIn])
+			if err != nil {
+				return nil, err
+			}
+			next[i] = []*big.Int{d}
+		}
+		level = next
+	}
+	return level[0][0], nil
+}
+
+// nbLevels returns the number of node levels in the tree with nbLeaves leaves.
+func nbLevels(fanIn, nbLeaves int) (int, error) {
+	if fanIn < 2 {
+		return 0, fmt.Errorf("fan-in must be at least 2, got %d", fanIn)
+	}
+	if nbLeaves < fanIn {
+		return 0, fmt.Errorf("number of leaves %d less than fan-in %d", nbLeaves, fanIn)
+	}
+	nb := 0
+	for n := nbLeaves; n > 1; n /= fanIn {
+		if n%fanIn != 0 {
+			return 0, fmt.Errorf("number of leaves %d is not a power of fan-in %d", nbLeaves, fanIn)
+		}
+		nb++
+	}
+	return nb, nil
+}
+
+func digestValues(field *big.Int, publics [][]*big.Int) (*big.Int, error) {
+	h, err := recursion.NewShort(field, field)
+	if err != nil {
+		return nil, fmt.Errorf("new hash: %w", err)
+	}
+	buf := make([]byte, (field.BitLen()+7)/8)
+	for i := range publics {
+		for j := range publics[i] {
+			publics[i][j].FillBytes(buf)
+			h.Write(buf)
+		}
+	}
+	return new(big.Int).SetBytes(h.Sum(nil)), nil
+}
+
+func publicValues(w witness.Witness) ([]*big.Int, error) {
+	pubw, err := w.Public()
+	if err != nil {
+		return nil, fmt.Errorf("get public witness: %w", err)
+	}
+	var ret []*big.Int
+	switch vect := pubw.Vector().(type) {
+	case fr_bn254.Vector:
+		for i := range vect {
+			ret = append(ret, vect[i].BigInt(new(big.Int)))
+		}
+	case fr_bls12381.Vector:
+		for i := range vect {
+			ret = append(ret, vect[i].BigInt(new(big.Int)))
+		}
+	case fr_bw6761.Vector:
+		for i := range vect {
+			ret = append(ret, vect[i].BigInt(new(big.Int)))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported witness vector %T", vect)
+	}
+	return ret, nil
+}
@@ -0,0 +1,15 @@
+// Package aggregation implements tree-based aggregation of PLONK proofs.
+//
+// The aggregation tree has proofs of a user-defined leaf circuit at the bottom
+// and node proofs above them. Every node circuit verifies fan-in proofs of the
+// level below in a single batched KZG check and exposes a single public input
+// -- the hash of the public inputs of the verified proofs. Thus, the public
+// input of the root proof is a commitment to the public inputs of all the leaf
+// proofs. Use [RootDigest] for computing it out-of-circuit.
+//
+// As the node circuits verify proofs defined over the same field they are
+// themselves defined over, the in-circuit verification uses field emulation.
+// The package supports aggregating proofs over BN254, BLS12-381 and BW6-761.
+// Every level of the tree has a separate node circuit as the verifying key of
+// the children is embedded in the node circuit as a constant.
+package aggregation
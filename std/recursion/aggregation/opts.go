@@ -0,0 +1,53 @@
+package aggregation
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/consensys/gnark/std/recursion/plonk"
+)
+
+type config struct {
+	nbTasks      int
+	verifierOpts []plonk.VerifierOption
+}
+
+// Option allows to modify the behaviour of the [Aggregator].
+type Option func(cfg *config) error
+
+// WithNbTasks sets the number of proofs which are computed in parallel. If not
+// set, then the number of workers is set to runtime.NumCPU(). As the PLONK
+// prover is itself parallel, then the option is mostly useful for limiting the
+// memory usage.
+func WithNbTasks(nbTasks int) Option {
+	return func(cfg *config) error {
+		if nbTasks <= 0 {
+			return fmt.Errorf("invalid number of tasks: %d", nbTasks)
+		}
+		cfg.nbTasks = nbTasks
+		return nil
+	}
+}
+
+// WithVerifierOptions sets the options for the in-circuit PLONK verifier used
+// in the node circuits. See [plonk.WithCompleteArithmetic] which is necessary
+// for simple leaf circuits whose selector polynomials may have exceptional
+// cases.
+func WithVerifierOptions(opts ...plonk.VerifierOption) Option {
+	return func(cfg *config) error {
+		cfg.verifierOpts = append(cfg.verifierOpts, opts...)
+		return nil
+	}
+}
+
+func newCfg(opts ...Option) (*config, error) {
+	cfg := &config{
+		nbTasks: runtime.NumCPU(),
+	}
+	for i := range opts {
+		if err := opts[i](cfg); err != nil {
+			return nil, fmt.Errorf("option %d: %w", i, err)
+		}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,474 @@
+package groth16
+
+import (
+	"fmt"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16backend_bls12377 "github.com/consensys/gnark/backend/groth16/bls12-377"
+	groth16backend_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381"
+	groth16backend_bls24315 "github.com/consensys/gnark/backend/groth16/bls24-315"
+	groth16backend_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	groth16backend_bw6761 "github.com/consensys/gnark/backend/groth16/bw6-761"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bw6761"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls24315"
+	"github.com/consensys/gnark/std/commitments/pedersen"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/recursion"
+)
+
+// BatchVerifyingKey is a typed Groth16 verifying key for checking several SNARK
+// proofs at once using [Verifier.AssertProofBatch]. For witness creation use
+// the method [ValueOfBatchVerifyingKey] and for stub placeholder use
+// [PlaceholderBatchVerifyingKey].
+//
+// Differently from [VerifyingKey], it stores the points α and β instead of the
+// precomputed pairing e(α, β), which is folded into the batched pairing check.
+// As the pairing is not stored, there is nothing to keep consistent with α and
+// β when the key is given as a witness.
+type BatchVerifyingKey[G1El algebra.G1ElementT, G2El algebra.G2ElementT] struct {
+	G1 struct {
+		Alpha G1El
+		K     []G1El
+	}
+	G2 struct {
+		Beta, GammaNeg, DeltaNeg G2El
+	}
+	CommitmentKey                pedersen.VerifyingKey[G2El]
+	PublicAndCommitmentCommitted [][]int
+}
+
+// PlaceholderBatchVerifyingKey returns an empty batch verifying key for a given
+// compiled constraint system. See [PlaceholderVerifyingKey].
+func PlaceholderBatchVerifyingKey[G1El algebra.G1ElementT, G2El algebra.G2ElementT](ccs constraint.ConstraintSystem) BatchVerifyingKey[G1El, G2El] {
+	commitments := ccs.GetCommitments().(constraint.Groth16Commitments)
+	commitmentWires := commitments.CommitmentIndexes()
+
+	var ret BatchVerifyingKey[G1El, G2El]
+	ret.G1.K = make([]G1El, ccs.GetNbPublicVariables()+len(commitments))
+	ret.PublicAndCommitmentCommitted = commitments.GetPublicAndCommitmentCommitted(commitmentWires, ccs.GetNbPublicVariables())
+	return ret
+}
+
+// ValueOfBatchVerifyingKey initializes witness from the given Groth16 verifying
+// key. It returns an error if there is a mismatch between the type parameters
+// and the provided native verifying key.
+func ValueOfBatchVerifyingKey[G1El algebra.G1ElementT, G2El algebra.G2ElementT](vk groth16.VerifyingKey) (BatchVerifyingKey[G1El, G2El], error) {
+	return valueOfBatchVerifyingKey[G1El, G2El](vk, false)
+}
+
+// ValueOfBatchVerifyingKeyFixed initializes witness from the given Groth16
+// verifying key and precomputes the Miller loop lines of the G2 elements. The
+// key must be embedded in the circuit at compile time. It returns an error if
+// there is a mismatch between the type parameters and the provided native
+// verifying key.
+func ValueOfBatchVerifyingKeyFixed[G1El algebra.G1ElementT, G2El algebra.G2ElementT](vk groth16.VerifyingKey) (BatchVerifyingKey[G1El, G2El], error) {
+	return valueOfBatchVerifyingKey[G1El, G2El](vk, true)
+}
+
+func valueOfBatchVerifyingKey[G1El algebra.G1ElementT, G2El algebra.G2ElementT](vk groth16.VerifyingKey, fixed bool) (BatchVerifyingKey[G1El, G2El], error) {
+	var ret BatchVerifyingKey[G1El, G2El]
+	var err error
+	switch s := any(&ret).(type) {
+	case *BatchVerifyingKey[sw_bn254.G1Affine, sw_bn254.G2Affine]:
+		tVk, ok := vk.(*groth16backend_bn254.VerifyingKey)
+		if !ok {
+			return ret, fmt.Errorf("expected bn254.VerifyingKey, got %T", vk)
+		}
+		newG2 := sw_bn254.NewG2Affine
+		newCommitmentKey := pedersen.ValueOfVerifyingKey[sw_bn254.G2Affine]
+		if fixed {
+			newG2 = sw_bn254.NewG2AffineFixed
+			newCommitmentKey = pedersen.ValueOfVerifyingKeyFixed[sw_bn254.G2Affine]
+		}
+		s.G1.Alpha = sw_bn254.NewG1Affine(tVk.G1.Alpha)
+		s.G1.K = make([]sw_bn254.G1Affine, len(tVk.G1.K))
+		for i := range s.G1.K {
+			s.G1.K[i] = sw_bn254.NewG1Affine(tVk.G1.K[i])
+		}
+		var deltaNeg, gammaNeg bn254.G2Affine
+		deltaNeg.Neg(&tVk.G2.Delta)
+		gammaNeg.Neg(&tVk.G2.Gamma)
+		s.G2.Beta = newG2(tVk.G2.Beta)
+		s.G2.DeltaNeg = newG2(deltaNeg)
+		s.G2.GammaNeg = newG2(gammaNeg)
+		s.CommitmentKey, err = newCommitmentKey(&tVk.CommitmentKey)
+		if err != nil {
+			return ret, fmt.Errorf("commitment key: %w", err)
+		}
+	case *BatchVerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine]:
+		tVk, ok := vk.(*groth16backend_bls12377.VerifyingKey)
+		if !ok {
+			return ret, fmt.Errorf("expected bls12377.VerifyingKey, got %T", vk)
+		}
+		newG2 := sw_bls12377.NewG2Affine
+		newCommitmentKey := pedersen.ValueOfVerifyingKey[sw_bls12377.G2Affine]
+		if fixed {
+			newG2 = sw_bls12377.NewG2AffineFixed
+			newCommitmentKey = pedersen.ValueOfVerifyingKeyFixed[sw_bls12377.G2Affine]
+		}
+		s.G1.Alpha = sw_bls12377.NewG1Affine(tVk.G1.Alpha)
+		s.G1.K = make([]sw_bls12377.G1Affine, len(tVk.G1.K))
+		for i := range s.G1.K {
+			s.G1.K[i] = sw_bls12377.NewG1Affine(tVk.G1.K[i])
+		}
+		var deltaNeg, gammaNeg bls12377.G2Affine
+		deltaNeg.Neg(&tVk.G2.Delta)
+		gammaNeg.Neg(&tVk.G2.Gamma)
+		s.G2.Beta = newG2(tVk.G2.Beta)
+		s.G2.DeltaNeg = newG2(deltaNeg)
+		s.G2.GammaNeg = newG2(gammaNeg)
+		s.CommitmentKey, err = newCommitmentKey(&tVk.CommitmentKey)
+		if err != nil {
+			return ret, fmt.Errorf("commitment key: %w", err)
+		}
+	case *BatchVerifyingKey[sw_bls12381.G1Affine, sw_bls12381.G2Affine]:
+		tVk, ok := vk.(*groth16backend_bls12381.VerifyingKey)
+		if !ok {
+			return ret, fmt.Errorf("expected bls12381.VerifyingKey, got %T", vk)
+		}
+		newG2 := sw_bls12381.NewG2Affine
+		newCommitmentKey := pedersen.ValueOfVerifyingKey[sw_bls12381.G2Affine]
+		if fixed {
+			newG2 = sw_bls12381.NewG2AffineFixed
+			newCommitmentKey = pedersen.ValueOfVerifyingKeyFixed[sw_bls12381.G2Affine]
+		}
+		s.G1.Alpha = sw_bls12381.NewG1Affine(tVk.G1.Alpha)
+		s.G1.K = make([]sw_bls12381.G1Affine, len(tVk.G1.K))
+		for i := range s.G1.K {
+			s.G1.K[i] = sw_bls12381.NewG1Affine(tVk.G1.K[i])
+		}
+		var deltaNeg, gammaNeg bls12381.G2Affine
+		deltaNeg.Neg(&tVk.G2.Delta)
+		gammaNeg.Neg(&tVk.G2.Gamma)
+		s.G2.Beta = newG2(tVk.G2.Beta)
+		s.G2.DeltaNeg = newG2(deltaNeg)
+		s.G2.GammaNeg = newG2(gammaNeg)
+		s.CommitmentKey, err = newCommitmentKey(&tVk.CommitmentKey)
+		if err != nil {
+			return ret, fmt.Errorf("commitment key: %w", err)
+		}
+	case *BatchVerifyingKey[sw_bls24315.G1Affine, sw_bls24315.G2Affine]:
+		tVk, ok := vk.(*groth16backend_bls24315.VerifyingKey)
+		if !ok {
+			return ret, fmt.Errorf("expected bls24315.VerifyingKey, got %T", vk)
+		}
+		newG2 := sw_bls24315.NewG2Affine
+		newCommitmentKey := pedersen.ValueOfVerifyingKey[sw_bls24315.G2Affine]
+		if fixed {
+			newG2 = sw_bls24315.NewG2AffineFixed
+			newCommitmentKey = pedersen.ValueOfVerifyingKeyFixed[sw_bls24315.G2Affine]
+		}
+		s.G1.Alpha = sw_bls24315.NewG1Affine(tVk.G1.Alpha)
+		s.G1.K = make([]sw_bls24315.G1Affine, len(tVk.G1.K))
+		for i := range s.G1.K {
+			s.G1.K[i] = sw_bls24315.NewG1Affine(tVk.G1.K[i])
+		}
+		var deltaNeg, gammaNeg bls24315.G2Affine
+		deltaNeg.Neg(&tVk.G2.Delta)
+		gammaNeg.Neg(&tVk.G2.Gamma)
+		s.G2.Beta = newG2(tVk.G2.Beta)
+		s.G2.DeltaNeg = newG2(deltaNeg)
+		s.G2.GammaNeg = newG2(gammaNeg)
+		s.CommitmentKey, err = newCommitmentKey(&tVk.CommitmentKey)
+		if err != nil {
+			return ret, fmt.Errorf("commitment key: %w", err)
+		}
+	case *BatchVerifyingKey[sw_bw6761.G1Affine, sw_bw6761.G2Affine]:
+		tVk, ok := vk.(*groth16backend_bw6761.VerifyingKey)
+		if !ok {
+			return ret, fmt.Errorf("expected bw6761.VerifyingKey, got %T", vk)
+		}
+		newG2 := sw_bw6761.NewG2Affine
+		newCommitmentKey := pedersen.ValueOfVerifyingKey[sw_bw6761.G2Affine]
+		if fixed {
+			newG2 = sw_bw6761.NewG2AffineFixed
+			newCommitmentKey = pedersen.ValueOfVerifyingKeyFixed[sw_bw6761.G2Affine]
+		}
+		s.G1.Alpha = sw_bw6761.NewG1Affine(tVk.G1.Alpha)
+		s.G1.K = make([]sw_bw6761.G1Affine, len(tVk.G1.K))
+		for i := range s.G1.K {
+			s.G1.K[i] = sw_bw6761.NewG1Affine(tVk.G1.K[i])
+		}
+		var deltaNeg, gammaNeg bw6761.G2Affine
+		deltaNeg.Neg(&tVk.G2.Delta)
+		gammaNeg.Neg(&tVk.G2.Gamma)
+		s.G2.Beta = newG2(tVk.G2.Beta)
+		s.G2.DeltaNeg = newG2(deltaNeg)
+		s.G2.GammaNeg = newG2(gammaNeg)
+		s.CommitmentKey, err = newCommitmentKey(&tVk.CommitmentKey)
+		if err != nil {
+			return ret, fmt.Errorf("commitment key: %w", err)
+		}
+	default:
+		return ret, fmt.Errorf("unknown parametric type combination")
+	}
+	return ret, nil
+}
+
+// AssertProofBatch asserts that all the SNARK proofs hold for the
+// corresponding witnesses and the common verifying key.
+//
+// Instead of checking every proof individually, the verification equations are
+// combined using random scalars derived in-circuit from the verifying key, the
+// proofs and the witnesses. The combined equation, including the term
+// e(α, β), is then checked using a single [algebra.Pairing.PairingCheck],
+// amortizing the cost of the final exponentiation and of the pairings with the
+// fixed arguments of the verifying key over the whole batch.
+func (v *Verifier[FR, G1El, G2El, GtEl]) AssertProofBatch(vk BatchVerifyingKey[G1El, G2El], proofs []Proof[G1El, G2El], witnesses []Witness[FR], opts ...VerifierOption) error {
+	if len(proofs) != len(witnesses) {
+		return fmt.Errorf("proofs and witnesses length mismatch")
+	}
+	if len(proofs) == 0 {
+		return fmt.Errorf("no proofs to check")
+	}
+	opt, err := newCfg(opts...)
+	if err != nil {
+		return fmt.Errorf("apply options: %w", err)
+	}
+	nbProofs := len(proofs)
+	nbCommitments := len(vk.PublicAndCommitmentCommitted)
+	inS := make([][]*emulated.Element[FR], nbProofs)
+	for i := range proofs {
+		if len(proofs[i].Commitments) != nbCommitments {
+			return fmt.Errorf("proof %d: invalid number of commitments", i)
+		}
+		if inS[i], err = v.publicInputs(vk.G1.K, vk.PublicAndCommitmentCommitted, proofs[i], witnesses[i]); err != nil {
+			return fmt.Errorf("proof %d: %w", i, err)
+		}
+	}
+
+	// the scalars for combining the Groth16 equations are rᵢ = γⁱ. The
+	// Pedersen knowledge proofs are combined with γⁿ⁺ⁱ, so that all the checks
+	// are bound to distinct powers of γ. As r₀ = 1, the terms of the first
+	// proof are added without scalar multiplication.
+	gamma, err := v.deriveBatchChallenge(vk, proofs, inS)
+	if err != nil {
+		return fmt.Errorf("derive challenge: %w", err)
+	}
+	gammas := make([]*emulated.Element[FR], 2*nbProofs)
+	gammas[0] = v.scalarApi.One()
+	for i := 1; i < len(gammas); i++ {
+		gammas[i] = v.scalarApi.Mul(gammas[i-1], gamma)
+	}
+	rs := gammas[:nbProofs]
+	rSum := v.scalarApi.Sum(rs...)
+
+	if opt.forceSubgroupCheck {
+		for i := range proofs {
+			v.pairing.AssertIsOnG1(&proofs[i].Ar)
+			v.pairing.AssertIsOnG1(&proofs[i].Krs)
+			v.pairing.AssertIsOnG2(&proofs[i].Bs)
+		}
+	}
+
+	// ∑ᵢ rᵢ⋅kSumᵢ = (∑ᵢ rᵢ)⋅K₀ + ∑ⱼ (∑ᵢ rᵢ⋅sᵢⱼ)⋅Kⱼ + ∑ᵢ rᵢ⋅Dᵢ
+	inP := make([]*G1El, 0, len(vk.G1.K)+(nbProofs-1)*nbCommitments)
+	scalars := make([]*emulated.Element[FR], 0, cap(inP))
+	inP = append(inP, &vk.G1.K[0])
+	scalars = append(scalars, rSum)
+	for j := 1; j < len(vk.G1.K); j++ {
+		terms := make([]*emulated.Element[FR], nbProofs)
+		terms[0] = inS[0][j-1]
+		for i := 1; i < nbProofs; i++ {
+			terms[i] = v.scalarApi.Mul(rs[i], inS[i][j-1])
+		}
+		inP = append(inP, &vk.G1.K[j])
+		scalars = append(scalars, v.scalarApi.Sum(terms...))
+	}
+	for i := 1; i < nbProofs; i++ {
+		for j := range proofs[i].Commitments {
+			inP = append(inP, &proofs[i].Commitments[j].G1El)
+			scalars = append(scalars, rs[i])
+		}
+	}
+	kSum, err := v.curve.MultiScalarMul(inP, scalars, opt.algopt...)
+	if err != nil {
+		return fmt.Errorf("multi scalar mul public inputs: %w", err)
+	}
+	for j := range proofs[0].Commitments {
+		kSum = v.curve.Add(kSum, &proofs[0].Commitments[j].G1El)
+	}
+
+	// ∑ᵢ rᵢ⋅Krsᵢ
+	krsSum := &proofs[0].Krs
+	if nbProofs > 1 {
+		krs := make([]*G1El, nbProofs-1)
+		for i := range krs {
+			krs[i] = &proofs[i+1].Krs
+		}
+		krsTail, err := v.curve.MultiScalarMul(krs, rs[1:], opt.algopt...)
+		if err != nil {
+			return fmt.Errorf("multi scalar mul krs: %w", err)
+		}
+		krsSum = v.curve.Add(krsSum, krsTail)
+	}
+
+	// -(∑ᵢ rᵢ)⋅α, so that e(α, β)^(∑ᵢ rᵢ) is moved to the left-hand side
+	var alphaSum *G1El
+	if nbProofs == 1 {
+		alphaSum = v.curve.Neg(&vk.G1.Alpha)
+	} else {
+		alphaSum = v.curve.ScalarMul(&vk.G1.Alpha, v.scalarApi.Neg(rSum), opt.algopt...)
+	}
+
+	pairP := make([]*G1El, 0, nbProofs+5)
+	pairQ := make([]*G2El, 0, nbProofs+5)
+	pairP = append(pairP, &proofs[0].Ar)
+	pairQ = append(pairQ, &proofs[0].Bs)
+	for i := 1; i < nbProofs; i++ {
+		pairP = append(pairP, v.curve.ScalarMul(&proofs[i].Ar, rs[i], opt.algopt...))
+		pairQ = append(pairQ, &proofs[i].Bs)
+	}
+	pairP = append(pairP, kSum, krsSum, alphaSum)
+	pairQ = append(pairQ, &vk.G2.GammaNeg, &vk.G2.DeltaNeg, &vk.G2.Beta)
+
+	if nbCommitments > 0 {
+		// the commitments are folded within a proof by the Pedersen verifier
+		// and then across the proofs using the powers γⁿ⁺ⁱ. The Pedersen
+		// verifier options are applied to every proof when getting the pairing
+		// arguments.
+		cmts := make([]*G1El, nbProofs)
+		poks := make([]*G1El, nbProofs)
+		for i := range proofs {
+			folded, err := v.commitment.FoldCommitments(proofs[i].Commitments, inS[i][len(inS[i])-nbCommitments:]...)
+			if err != nil {
+				return fmt.Errorf("fold commitments %d: %w", i, err)
+			}
+			P, _, err := v.commitment.PairingArguments(folded, proofs[i].CommitmentPok, vk.CommitmentKey, opt.pedopt...)
+			if err != nil {
+				return fmt.Errorf("commitment %d: %w", i, err)
+			}
+			cmts[i], poks[i] = P[0], P[1]
+		}
+		cmtSum, err := v.curve.MultiScalarMul(cmts, gammas[nbProofs:], opt.algopt...)
+		if err != nil {
+			return fmt.Errorf("multi scalar mul commitments: %w", err)
+		}
+		pokSum, err := v.curve.MultiScalarMul(poks, gammas[nbProofs:], opt.algopt...)
+		if err != nil {
+			return fmt.Errorf("multi scalar mul knowledge proofs: %w", err)
+		}
+		pairP = append(pairP, cmtSum, pokSum)
+		pairQ = append(pairQ, &vk.CommitmentKey.G, &vk.CommitmentKey.GRootSigmaNeg)
+	}
+
+	if err := v.pairing.PairingCheck(pairP, pairQ); err != nil {
+		return fmt.Errorf("pairing check: %w", err)
+	}
+	return nil
+}
+
+// deriveBatchChallenge derives the challenge for combining the verification
+// equations of the proofs. The challenge is bound to the verifying key, to all
+// the proof elements and to the public inputs.
+func (v *Verifier[FR, G1El, G2El, GtEl]) deriveBatchChallenge(vk BatchVerifyingKey[G1El, G2El], proofs []Proof[G1El, G2El], publicInputs [][]*emulated.Element[FR]) (*emulated.Element[FR], error) {
+	var fr FR
+	h, err := recursion.NewHash(v.api, fr.Modulus(), false)
+	if err != nil {
+		return nil, fmt.Errorf("new hash: %w", err)
+	}
+	vars, err := verifyingKeyVariables(vk)
+	if err != nil {
+		return nil, fmt.Errorf("verifying key: %w", err)
+	}
+	h.Write(vars...)
+	for i := range proofs {
+		vars, err := proofVariables(proofs[i])
+		if err != nil {
+			return nil, fmt.Errorf("proof %d: %w", i, err)
+		}
+		h.Write(vars...)
+		for j := range publicInputs[i] {
+			h.Write(publicInputs[i][j].Limbs...)
+		}
+	}
+	return v.scalarApi.FromBits(v.api.ToBinary(h.Sum())...), nil
+}
+
+// verifyingKeyVariables returns the native variables of the group elements of
+// the verifying key used in the batched pairing check.
+func verifyingKeyVariables[G1El algebra.G1ElementT, G2El algebra.G2ElementT](vk BatchVerifyingKey[G1El, G2El]) ([]frontend.Variable, error) {
+	g1s := []*G1El{&vk.G1.Alpha}
+	for i := range vk.G1.K {
+		g1s = append(g1s, &vk.G1.K[i])
+	}
+	g2s := []*G2El{&vk.G2.Beta, &vk.G2.GammaNeg, &vk.G2.DeltaNeg}
+	if len(vk.PublicAndCommitmentCommitted) > 0 {
+		g2s = append(g2s, &vk.CommitmentKey.G, &vk.CommitmentKey.GRootSigmaNeg)
+	}
+	return pointsVariables(g1s, g2s)
+}
+
+// proofVariables returns the native variables of all the elements of the
+// proof, including the G2 element Bs.
+func proofVariables[G1El algebra.G1ElementT, G2El algebra.G2ElementT](proof Proof[G1El, G2El]) ([]frontend.Variable, error) {
+	g1s := []*G1El{&proof.Ar, &proof.Krs}
+	for i := range proof.Commitments {
+		g1s = append(g1s, &proof.Commitments[i].G1El)
+	}
+	if len(proof.Commitments) > 0 {
+		g1s = append(g1s, &proof.CommitmentPok.G1El)
+	}
+	return pointsVariables(g1s, []*G2El{&proof.Bs})
+}
+
+// pointsVariables returns the native variables of the coordinates of the given
+// points. It returns an error if the point types do not correspond to any of
+// the supported curves.
+func pointsVariables[G1El algebra.G1ElementT, G2El algebra.G2ElementT](g1s []*G1El, g2s []*G2El) ([]frontend.Variable, error) {
+	var ret []frontend.Variable
+	for i := range g1s {
+		switch p := any(g1s[i]).(type) {
+		case *sw_bn254.G1Affine:
+			ret = append(ret, emulatedVariables(&p.X, &p.Y)...)
+		case *sw_bls12381.G1Affine:
+			ret = append(ret, emulatedVariables(&p.X, &p.Y)...)
+		case *sw_bw6761.G1Affine:
+			ret = append(ret, emulatedVariables(&p.X, &p.Y)...)
+		case *sw_bls12377.G1Affine:
+			ret = append(ret, p.X, p.Y)
+		case *sw_bls24315.G1Affine:
+			ret = append(ret, p.X, p.Y)
+		default:
+			return nil, fmt.Errorf("unknown parametric type combination %T", p)
+		}
+	}
+	for i := range g2s {
+		switch q := any(g2s[i]).(type) {
+		case *sw_bn254.G2Affine:
+			ret = append(ret, emulatedVariables(&q.P.X.A0, &q.P.X.A1, &q.P.Y.A0, &q.P.Y.A1)...)
+		case *sw_bls12381.G2Affine:
+			ret = append(ret, emulatedVariables(&q.P.X.A0, &q.P.X.A1, &q.P.Y.A0, &q.P.Y.A1)...)
+		case *sw_bw6761.G2Affine:
+			ret = append(ret, emulatedVariables(&q.P.X, &q.P.Y)...)
+		case *sw_bls12377.G2Affine:
+			ret = append(ret, q.P.X.A0, q.P.X.A1, q.P.Y.A0, q.P.Y.A1)
+		case *sw_bls24315.G2Affine:
+			ret = append(ret,
+				q.P.X.B0.A0, q.P.X.B0.A1, q.P.X.B1.A0, q.P.X.B1.A1,
+				q.P.Y.B0.A0, q.P.Y.B0.A1, q.P.Y.B1.A0, q.P.Y.B1.A1)
+		default:
+			return nil, fmt.Errorf("unknown parametric type combination %T", q)
+		}
+	}
+	return ret, nil
+}
+
+func emulatedVariables[T emulated.FieldParams](els ...*emulated.Element[T]) []frontend.Variable {
+	var ret []frontend.Variable
+	for i := range els {
+		ret = append(ret, els[i].Limbs...)
+	}
+	return ret
+}
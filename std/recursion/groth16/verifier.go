@@ -567,52 +567,21 @@ func NewVerifier[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.
 // AssertProof asserts that the SNARK proof holds for the given witness and
 // verifying key.
 func (v *Verifier[FR, G1El, G2El, GtEl]) AssertProof(vk VerifyingKey[G1El, G2El, GtEl], proof Proof[G1El, G2El], witness Witness[FR], opts ...VerifierOption) error {
-	var fr FR
-	nbPublicVars := len(vk.G1.K) - len(vk.PublicAndCommitmentCommitted)
-	if len(witness.Public) != nbPublicVars-1 {
-		return fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(witness.Public), len(vk.G1.K)-1)
-	}
-
-	inP := make([]*G1El, len(vk.G1.K)-1) // first is for the one wire, we add it manually after MSM
-	for i := range inP {
-		inP[i] = &vk.G1.K[i+1]
-	}
-	inS := make([]*emulated.Element[FR], len(witness.Public)+len(vk.PublicAndCommitmentCommitted))
-	for i := range witness.Public {
-		inS[i] = &witness.Public[i]
-	}
-
 	opt, err := newCfg(opts...)
 	if err != nil {
 		return fmt.Errorf("apply options: %w", err)
 	}
-	hashToField, err := recursion.NewHash(v.api, fr.Modulus(), true)
+	inS, err := v.publicInputs(vk.G1.K, vk.PublicAndCommitmentCommitted, proof, witness)
 	if err != nil {
-		return fmt.Errorf("hash to field: %w", err)
-	}
-
-	maxNbPublicCommitted := 0
-	for _, s := range vk.PublicAndCommitmentCommitted { // iterate over commitments
-		maxNbPublicCommitted = utils.Max(maxNbPublicCommitted, len(s))
+		return err
 	}
-
-	commitmentAuxData := make([]*emulated.Element[FR], len(vk.PublicAndCommitmentCommitted))
-	for i := range vk.PublicAndCommitmentCommitted { // solveCommitmentWire
-		hashToField.Write(v.curve.MarshalG1(proof.Commitments[i].G1El)...)
-		for j := range vk.PublicAndCommitmentCommitted[i] {
-			hashToField.Write(v.curve.MarshalScalar(*inS[vk.PublicAndCommitmentCommitted[i][j]-1])...)
-		}
-
-		h := hashToField.Sum()
-		hashToField.Reset()
-
-		res := v.scalarApi.FromBits(v.api.ToBinary(h)...)
-
-		inS[nbPublicVars-1+i] = res
-		commitmentAuxData[i] = res
+	inP := make([]*G1El, len(vk.G1.K)-1) // first is for the one wire, we add it manually after MSM
+	for i := range inP {
+		inP[i] = &vk.G1.K[i+1]
 	}
 
 	if len(vk.PublicAndCommitmentCommitted) > 0 {
+		commitmentAuxData := inS[len(inS)-len(vk.PublicAndCommitmentCommitted):]
 		folded, err := v.commitment.FoldCommitments(proof.Commitments, commitmentAuxData...)
 		if err != nil {
 			return fmt.Errorf("fold commitments: %w", err)
@@ -645,3 +614,155 @@ func (v *Verifier[FR, G1El, G2El, GtEl]) AssertProof(vk VerifyingKey[G1El, G2El,
 	v.pairing.AssertIsEqual(pairing, &vk.E)
 	return nil
 }
+
+// publicInputs returns the scalars for the public part K of the verifying key
+// for the proof. It consists of the public witness (without the constant one
+// wire) followed by the commitment wires.
+func (v *Verifier[FR, G1El, G2El, GtEl]) publicInputs(k []G1El, publicAndCommitmentCommitted [][]int, proof Proof[G1El, G2El], witness Witness[FR]) ([]*emulated.Element[FR], error) {
+	var fr FR
+	nbPublicVars := len(k) - len(publicAndCommitmentCommitted)
+	if len(witness.Public) != nbPublicVars-1 {
+		return nil, fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(witness.Public), nbPublicVars-1)
+	}
+
+	inS := make([]*emulated.Element[FR], len(witness.Public)+len(publicAndCommitmentCommitted))
+	for i := range witness.Public {
+		inS[i] = &witness.Public[i]
+	}
+
+	hashToField, err := recursion.NewHash(v.api, fr.Modulus(), true)
+	if err != nil {
+		return nil, fmt.Errorf("hash to field: %w", err)
+	}
+
+	maxNbPublicCommitted := 0
+	for _, s := range publicAndCommitmentCommitted { // iterate over commitments
+		maxNbPublicCommitted = utils.Max(maxNbPublicCommitted, len(s))
+	}
+
+	for i := range publicAndCommitmentCommitted { // solveCommitmentWire
+		hashToField.Write(v.curve.MarshalG1(proof.Commitments[i].G1El)...)
+		for j := range publicAndCommitmentCommitted[i] {
+			hashToField.Write(v.curve.MarshalScalar(*inS[publicAndCommitmentCommitted[i][j]-1])...)
+		}
+
+		h := hashToField.Sum()
+		hashToField.Reset()
+
+		res := v.scalarApi.FromBits(v.api.ToBinary(h)...)
+
+		inS[nbPublicVars-1+i] = res
+	}
+	return inS, nil
+}
+
+// SwitchVerificationKey returns the verifying key vks[idx]. All the verifying
+// keys must correspond to circuits with the same number of public inputs and
+// the same commitment layout. The index idx may be a witness variable, allowing
+// to verify proofs of different circuits in a single circuit.
+//
+// Only the fields used by [Verifier.AssertProof] are selected. If all the
+// verifying keys have precomputed lines (see [ValueOfVerifyingKeyFixed]), then
+// the precomputations are also selected. Otherwise the lines are computed
+// in-circuit during the pairing computation.
+func (v *Verifier[FR, G1El, G2El, GtEl]) SwitchVerificationKey(idx frontend.Variable, vks []VerifyingKey[G1El, G2El, GtEl]) (VerifyingKey[G1El, G2El, GtEl], error) {
+	var ret VerifyingKey[G1El, G2El, GtEl]
+	if len(vks) == 0 {
+		return ret, fmt.Errorf("no verification keys given")
+	}
+	if len(vks) == 1 {
+		v.api.AssertIsEqual(idx, 0)
+		return vks[0], nil
+	}
+	muxer, ok := v.pairing.(algebra.PairingMuxer[G2El, GtEl])
+	if !ok {
+		return ret, fmt.Errorf("pairing does not implement algebra.PairingMuxer")
+	}
+	nbIns := len(vks)
+	nbK := len(vks[0].G1.K)
+	for i := range vks {
+		if len(vks[i].G1.K) != nbK {
+			return ret, fmt.Errorf("mismatching number of public inputs")
+		}
+		if !equalCommitted(vks[i].PublicAndCommitmentCommitted, vks[0].PublicAndCommitmentCommitted) {
+			return ret, fmt.Errorf("mismatching commitment layout")
+		}
+	}
+	eEls := make([]*GtEl, nbIns)
+	gammaNegEls := make([]*G2El, nbIns)
+	deltaNegEls := make([]*G2El, nbIns)
+	cmtGEls := make([]*G2El, nbIns)
+	cmtGRootSigmaNegEls := make([]*G2El, nbIns)
+	kEls := make([][]*G1El, nbK)
+	for j := range kEls {
+		kEls[j] = make([]*G1El, nbIns)
+	}
+	for i := range vks {
+		eEls[i] = &vks[i].E
+		gammaNegEls[i] = &vks[i].G2.GammaNeg
+		deltaNegEls[i] = &vks[i].G2.DeltaNeg
+		cmtGEls[i] = &vks[i].CommitmentKey.G
+		cmtGRootSigmaNegEls[i] = &vks[i].CommitmentKey.GRootSigmaNeg
+		for j := range kEls {
+			kEls[j][i] = &vks[i].G1.K[j]
+		}
+	}
+	ret.E = *muxer.MuxGt(idx, eEls...)
+	ret.G1.K = make([]G1El, nbK)
+	for j := range ret.G1.K {
+		ret.G1.K[j] = *v.curve.Mux(idx, kEls[j]...)
+	}
+	ret.G2.GammaNeg = *muxer.MuxG2(idx, gammaNegEls...)
+	ret.G2.DeltaNeg = *muxer.MuxG2(idx, deltaNegEls...)
+	// the commitment key is only initialized when the circuits use commitments
+	if len(vks[0].PublicAndCommitmentCommitted) > 0 {
+		ret.CommitmentKey.G = *muxer.MuxG2(idx, cmtGEls...)
+		ret.CommitmentKey.GRootSigmaNeg = *muxer.MuxG2(idx, cmtGRootSigmaNegEls...)
+	}
+	ret.PublicAndCommitmentCommitted = vks[0].PublicAndCommitmentCommitted
+	return ret, nil
+}
+
+// AssertDifferentProofs asserts the validity of different proofs for different
+// circuits. The selector which verification key in vks to use is given in
+// slice switches. The proofs and witnesses are given in the arguments and must
+// correspond to each other. See [Verifier.SwitchVerificationKey] for the
+// requirements on the verifying keys.
+func (v *Verifier[FR, G1El, G2El, GtEl]) AssertDifferentProofs(vks []VerifyingKey[G1El, G2El, GtEl],
+	switches []frontend.Variable, proofs []Proof[G1El, G2El], witnesses []Witness[FR], opts ...VerifierOption) error {
+	if len(proofs) != len(witnesses) || len(proofs) != len(switches) {
+		return fmt.Errorf("input lengths mismatch")
+	}
+	if len(proofs) == 0 {
+		return fmt.Errorf("no proofs to check")
+	}
+	for i := range proofs {
+		vk, err := v.SwitchVerificationKey(switches[i], vks)
+		if err != nil {
+			return fmt.Errorf("switch verification key: %w", err)
+		}
+		if err := v.AssertProof(vk, proofs[i], witnesses[i], opts...); err != nil {
+			return fmt.Errorf("assert proof %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// equalCommitted returns true if the public and commitment committed indices
+// of the verifying keys are equal.
+func equalCommitted(a, b [][]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
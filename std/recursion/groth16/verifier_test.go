@@ -453,3 +453,212 @@ func TestBW6InBN254Commitment(t *testing.T) {
 	err = test.IsSolved(outerCircuit, outerAssignment, ecc.BN254.ScalarField())
 	assert.NoError(err)
 }
+
+// tests with variable verifying keys
+
+type InnerCircuitOther struct {
+	P, Q frontend.Variable
+	N    frontend.Variable `gnark:",public"`
+}
+
+func (c *InnerCircuitOther) Define(api frontend.API) error {
+	res := api.Add(c.P, c.Q)
+	res = api.Mul(res, c.Q)
+	api.AssertIsEqual(res, c.N)
+	return nil
+}
+
+func getInnerOther(assert *test.Assert, field *big.Int) (constraint.ConstraintSystem, groth16.VerifyingKey, witness.Witness, groth16.Proof) {
+	innerCcs, err := frontend.Compile(field, r1cs.NewBuilder, &InnerCircuitOther{})
+	assert.NoError(err)
+	innerPK, innerVK, err := groth16.Setup(innerCcs)
+	assert.NoError(err)
+
+	// inner proof
+	innerAssignment := &InnerCircuitOther{
+		P: 3,
+		Q: 5,
+		N: 40,
+	}
+	innerWitness, err := frontend.NewWitness(innerAssignment, field)
+	assert.NoError(err)
+	innerProof, err := groth16.Prove(innerCcs, innerPK, innerWitness)
+	assert.NoError(err)
+	innerPubWitness, err := innerWitness.Public()
+	assert.NoError(err)
+	err = groth16.Verify(innerProof, innerVK, innerPubWitness)
+	assert.NoError(err)
+	return innerCcs, innerVK, innerPubWitness, innerProof
+}
+
+type OuterCircuitSwitch[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	Switches      []frontend.Variable
+	Proofs        []Proof[G1El, G2El]
+	InnerWitness  []Witness[FR]
+	VerifyingKeys []VerifyingKey[G1El, G2El, GtEl] `gnark:"-"`
+}
+
+func (c *OuterCircuitSwitch[FR, G1El, G2El, GtEl]) Define(api frontend.API) error {
+	verifier, err := NewVerifier[FR, G1El, G2El, GtEl](api)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	return verifier.AssertDifferentProofs(c.VerifyingKeys, c.Switches, c.Proofs, c.InnerWitness)
+}
+
+func TestBLS12InBW6Switch(t *testing.T) {
+	assert := test.NewAssert(t)
+	innerCcs1, innerVK1, innerWitness1, innerProof1 := getInner(assert, ecc.BLS12_377.ScalarField())
+	innerCcs2, innerVK2, innerWitness2, innerProof2 := getInnerOther(assert, ecc.BLS12_377.ScalarField())
+
+	// outer proof
+	circuitVk1, err := ValueOfVerifyingKeyFixed[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](innerVK1)
+	assert.NoError(err)
+	circuitVk2, err := ValueOfVerifyingKeyFixed[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](innerVK2)
+	assert.NoError(err)
+	circuitWitness1, err := ValueOfWitness[sw_bls12377.ScalarField](innerWitness1)
+	assert.NoError(err)
+	circuitWitness2, err := ValueOfWitness[sw_bls12377.ScalarField](innerWitness2)
+	assert.NoError(err)
+	circuitProof1, err := ValueOfProof[sw_bls12377.G1Affine, sw_bls12377.G2Affine](innerProof1)
+	assert.NoError(err)
+	circuitProof2, err := ValueOfProof[sw_bls12377.G1Affine, sw_bls12377.G2Affine](innerProof2)
+	assert.NoError(err)
+
+	outerCircuit := &OuterCircuitSwitch[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		Switches:      make([]frontend.Variable, 2),
+		Proofs:        []Proof[sw_bls12377.G1Affine, sw_bls12377.G2Affine]{PlaceholderProof[sw_bls12377.G1Affine, sw_bls12377.G2Affine](innerCcs1), PlaceholderProof[sw_bls12377.G1Affine, sw_bls12377.G2Affine](innerCcs2)},
+		InnerWitness:  []Witness[sw_bls12377.ScalarField]{PlaceholderWitness[sw_bls12377.ScalarField](innerCcs1), PlaceholderWitness[sw_bls12377.ScalarField](innerCcs2)},
+		VerifyingKeys: []VerifyingKey[sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{circuitVk1, circuitVk2},
+	}
+	outerAssignment := &OuterCircuitSwitch[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		Switches:     []frontend.Variable{1, 0},
+		Proofs:       []Proof[sw_bls12377.G1Affine, sw_bls12377.G2Affine]{circuitProof2, circuitProof1},
+		InnerWitness: []Witness[sw_bls12377.ScalarField]{circuitWitness2, circuitWitness1},
+	}
+	err = test.IsSolved(outerCircuit, outerAssignment, ecc.BW6_761.ScalarField())
+	assert.NoError(err)
+
+	// the proofs do not verify against the wrong verifying keys
+	outerAssignment.Switches = []frontend.Variable{0, 1}
+	err = test.IsSolved(outerCircuit, outerAssignment, ecc.BW6_761.ScalarField())
+	assert.Error(err)
+}
+
+// tests with batch verification
+
+func getInnerBatch(assert *test.Assert, field, outer *big.Int, withCommitment bool, nbProofs int) (constraint.ConstraintSystem, groth16.VerifyingKey, []witness.Witness, []groth16.Proof) {
+	newCircuit := func(p, q, n frontend.Variable) frontend.Circuit {
+		if withCommitment {
+			return &InnerCircuitCommitment{P: p, Q: q, N: n}
+		}
+		return &InnerCircuit{P: p, Q: q, N: n}
+	}
+	innerCcs, err := frontend.Compile(field, r1cs.NewBuilder, newCircuit(nil, nil, nil))
+	assert.NoError(err)
+	innerPK, innerVK, err := groth16.Setup(innerCcs)
+	assert.NoError(err)
+
+	// inner proofs
+	innerPubWitnesses := make([]witness.Witness, nbProofs)
+	innerProofs := make([]groth16.Proof, nbProofs)
+	for i := 0; i < nbProofs; i++ {
+		innerWitness, err := frontend.NewWitness(newCircuit(3, 5+i, 3*(5+i)), field)
+		assert.NoError(err)
+		innerProofs[i], err = groth16.Prove(innerCcs, innerPK, innerWitness, GetNativeProverOptions(outer, field))
+		assert.NoError(err)
+		innerPubWitnesses[i], err = innerWitness.Public()
+		assert.NoError(err)
+		err = groth16.Verify(innerProofs[i], innerVK, innerPubWitnesses[i], GetNativeVerifierOptions(outer, field))
+		assert.NoError(err)
+	}
+	return innerCcs, innerVK, innerPubWitnesses, innerProofs
+}
+
+type OuterCircuitBatch[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	Proofs         []Proof[G1El, G2El]
+	VerifyingKey   BatchVerifyingKey[G1El, G2El]
+	InnerWitnesses []Witness[FR]
+
+	opts []VerifierOption `gnark:"-"`
+}
+
+func (c *OuterCircuitBatch[FR, G1El, G2El, GtEl]) Define(api frontend.API) error {
+	verifier, err := NewVerifier[FR, G1El, G2El, GtEl](api)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	return verifier.AssertProofBatch(c.VerifyingKey, c.Proofs, c.InnerWitnesses, c.opts...)
+}
+
+func testBatch[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](assert *test.Assert, inner, outer *big.Int, withCommitment bool, nbProofs int, opts ...VerifierOption) {
+	innerCcs, innerVK, innerWitnesses, innerProofs := getInnerBatch(assert, inner, outer, withCommitment, nbProofs)
+
+	// outer proof
+	circuitVk, err := ValueOfBatchVerifyingKey[G1El, G2El](innerVK)
+	assert.NoError(err)
+	newOuterCircuit := func() *OuterCircuitBatch[FR, G1El, G2El, GtEl] {
+		c := &OuterCircuitBatch[FR, G1El, G2El, GtEl]{
+			Proofs:         make([]Proof[G1El, G2El], nbProofs),
+			InnerWitnesses: make([]Witness[FR], nbProofs),
+			VerifyingKey:   PlaceholderBatchVerifyingKey[G1El, G2El](innerCcs),
+			opts:           opts,
+		}
+		for i := 0; i < nbProofs; i++ {
+			c.Proofs[i] = PlaceholderProof[G1El, G2El](innerCcs)
+			c.InnerWitnesses[i] = PlaceholderWitness[FR](innerCcs)
+		}
+		return c
+	}
+	outerAssignment := &OuterCircuitBatch[FR, G1El, G2El, GtEl]{
+		Proofs:         make([]Proof[G1El, G2El], nbProofs),
+		InnerWitnesses: make([]Witness[FR], nbProofs),
+		VerifyingKey:   circuitVk,
+	}
+	for i := 0; i < nbProofs; i++ {
+		outerAssignment.Proofs[i], err = ValueOfProof[G1El, G2El](innerProofs[i])
+		assert.NoError(err)
+		outerAssignment.InnerWitnesses[i], err = ValueOfWitness[FR](innerWitnesses[i])
+		assert.NoError(err)
+	}
+	err = test.IsSolved(newOuterCircuit(), outerAssignment, outer)
+	assert.NoError(err)
+
+	// swapping the witnesses invalidates the batch
+	if nbProofs > 1 {
+		outerAssignment.InnerWitnesses[0], outerAssignment.InnerWitnesses[1] = outerAssignment.InnerWitnesses[1], outerAssignment.InnerWitnesses[0]
+		err = test.IsSolved(newOuterCircuit(), outerAssignment, outer)
+		assert.Error(err)
+		outerAssignment.InnerWitnesses[0], outerAssignment.InnerWitnesses[1] = outerAssignment.InnerWitnesses[1], outerAssignment.InnerWitnesses[0]
+	}
+
+	// the points α and β must be consistent with the proofs
+	wrongVk := circuitVk
+	wrongVk.G1.Alpha = circuitVk.G1.K[0]
+	outerAssignment.VerifyingKey = wrongVk
+	err = test.IsSolved(newOuterCircuit(), outerAssignment, outer)
+	assert.Error(err)
+}
+
+func TestBLS12InBW6Batch(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		testBatch[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](assert, ecc.BLS12_377.ScalarField(), ecc.BW6_761.ScalarField(), false, 3)
+	}, "nocommitment")
+	assert.Run(func(assert *test.Assert) {
+		testBatch[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](assert, ecc.BLS12_377.ScalarField(), ecc.BW6_761.ScalarField(), true, 3, WithSubgroupCheck())
+	}, "commitment")
+	assert.Run(func(assert *test.Assert) {
+		testBatch[sw_bls12377.ScalarField, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](assert, ecc.BLS12_377.ScalarField(), ecc.BW6_761.ScalarField(), true, 1)
+	}, "single")
+}
+
+func TestBLS24InBW6Batch(t *testing.T) {
+	assert := test.NewAssert(t)
+	testBatch[sw_bls24315.ScalarField, sw_bls24315.G1Affine, sw_bls24315.G2Affine, sw_bls24315.GT](assert, ecc.BLS24_315.ScalarField(), ecc.BW6_633.ScalarField(), true, 3)
+}
+
+func TestBN254InBN254Batch(t *testing.T) {
+	assert := test.NewAssert(t)
+	testBatch[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl](assert, ecc.BN254.ScalarField(), ecc.BN254.ScalarField(), true, 3, WithSubgroupCheck())
+}
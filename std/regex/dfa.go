@@ -0,0 +1,226 @@
+package regex
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxStates is the maximal number of states of the deterministic automaton.
+const maxStates = 1 << 12
+
+// the states of the deterministic automaton. The state deadState is absorbing
+// and not accepting.
+const (
+	deadState = iota
+	startState
+)
+
+// dfa is a deterministic automaton over the pairs (class, mask) of the class
+// of the byte and the set of the revealed groups enclosing the byte.
+type dfa struct {
+	nbStates  int
+	nbClasses int
+	nbMasks   int
+	// transitions is indexed by (state*nbClasses+class)*nbMasks+mask.
+	transitions []int
+	accepting   []bool
+}
+
+func (d *dfa) next(state, class, mask int) int {
+	return d.transitions[(state*d.nbClasses+class)*d.nbMasks+mask]
+}
+
+// closure returns the sorted states of the non-deterministic automaton
+// reachable from states by epsilon transitions. The states of kind kindBegin
+// are followed only if atStart and the states of kind kindEnd only if atEnd,
+// otherwise the latter are kept.
+func (re *Regexp) closure(states []int, atStart, atEnd bool) []int {
+	seen := make(map[int]bool)
+	var res []int
+	var visit func(s int)
+	visit = func(s int) {
+		if seen[s] {
+			return
+		}
+		seen[s] = true
+		st := &re.states[s]
+		switch {
+		case st.kind == kindSplit,
+			st.kind == kindBegin && atStart,
+			st.kind == kindEnd && atEnd:
+			for _, o := range st.out {
+				visit(o)
+			}
+		case st.kind == kindBegin:
+		default:
+			res = append(res, s)
+		}
+	}
+	for _, s := range states {
+		visit(s)
+	}
+	sort.Ints(res)
+	return res
+}
+
+// dfa builds the deterministic automaton with the capture groups groups
+// revealed, using the subset construction.
+func (re *Regexp) dfa(groups []int) (*dfa, error) {
+	d := &dfa{nbClasses: re.nbClasses, nbMasks: 1 << len(groups)}
+	// masks are the masks of the states of the non-deterministic automaton.
+	masks := make([]int, len(re.states))
+	for s := range re.states {
+		for _, g := range re.states[s].groups {
+			for j := range groups {
+				if groups[j] == g {
+					masks[s] |= 1 << j
+				}
+			}
+		}
+	}
+	// representatives are the bytes of every class.
+	representatives := make([]int, re.nbClasses)
+	for c := 255; c >= 0; c-- {
+		representatives[re.classes[c]] = c
+	}
+
+	key := func(set []int) string {
+		var sb strings.Builder
+		for _, s := range set {
+			sb.WriteString(strconv.Itoa(s))
+			sb.WriteByte(',')
+		}
+		return sb.String()
+	}
+	index := make(map[string]int)
+	sets := [][]int{nil}
+	add := func(set []int) (int, error) {
+		if len(set) == 0 {
+			return deadState, nil
+		}
+		k := key(set)
+		if i, ok := index[k]; ok {
+			return i, nil
+		}
+		if len(sets) == maxStates {
+			return 0, fmt.Errorf("automaton has more than %d states", maxStates)
+		}
+		index[k] = len(sets)
+		sets = append(sets, set)
+		return len(sets) - 1, nil
+	}
+	// the start state is kept distinct from the other states as it is the
+	// only one following the beginning of the text assertions.
+	sets = append(sets, re.closure([]int{re.start}, true, false))
+
+	for i := 0; i < len(sets); i++ {
+		for cls := 0; cls < re.nbClasses; cls++ {
+			for mask := 0; mask < d.nbMasks; mask++ {
+				var next []int
+				for _, s := range sets[i] {
+					st := &re.states[s]
+					if st.kind == kindByte && st.bytes[representatives[cls]] && masks[s] == mask {
+						next = append(next, st.out...)
+					}
+				}
+				j, err := add(re.closure(next, false, false))
+				if err != nil {
+					return nil, err
+				}
+				d.transitions = append(d.transitions, j)
+			}
+		}
+		accepting := false
+		for _, s := range re.closure(sets[i], i == startState, true) {
+			accepting = accepting || re.states[s].kind == kindMatch
+		}
+		d.accepting = append(d.accepting, accepting)
+	}
+	d.nbStates = len(sets)
+	return d, nil
+}
+
+// spans returns the spans [start, end) of the revealed groups for which the
+// automaton accepts the bytes of the given classes. It returns false if there
+// are no such spans.
+func (d *dfa) spans(classes []int) ([][2]int, bool) {
+	nbGroups := 0
+	for 1<<nbGroups < d.nbMasks {
+		nbGroups++
+	}
+	// the phase of every group is 0 before the group, 1 inside of the group
+	// and 2 after the group. The search is over the pairs of the state and the
+	// phases of all the groups.
+	nbPhases := 1
+	for i := 0; i < nbGroups; i++ {
+		nbPhases *= 3
+	}
+	advance := func(phases, mask int) (int, bool) {
+		res, pow := 0, 1
+		for j := 0; j < nbGroups; j++ {
+			ph, in := phases/pow%3, mask>>j&1 == 1
+			switch {
+			case in && ph == 2:
+				return 0, false
+			case in:
+				ph = 1
+			case ph == 1:
+				ph = 2
+			}
+			res += ph * pow
+			pow *= 3
+		}
+		return res, true
+	}
+
+	type pred struct{ prev, mask int }
+	layers := make([][]int, len(classes)+1)
+	preds := make([]map[int]pred, len(classes)+1)
+	layers[0] = []int{startState * nbPhases}
+	preds[0] = map[int]pred{startState * nbPhases: {}}
+	for i, cls := range classes {
+		preds[i+1] = make(map[int]pred)
+		for _, p := range layers[i] {
+			for mask := 0; mask < d.nbMasks; mask++ {
+				phases, ok := advance(p%nbPhases, mask)
+				if !ok {
+					continue
+				}
+				state := d.next(p/nbPhases, cls, mask)
+				if state == deadState {
+					continue
+				}
+				np := state*nbPhases + phases
+				if _, ok := preds[i+1][np]; !ok {
+					preds[i+1][np] = pred{p, mask}
+					layers[i+1] = append(layers[i+1], np)
+				}
+			}
+		}
+	}
+	for _, p := range layers[len(classes)] {
+		if !d.accepting[p/nbPhases] {
+			continue
+		}
+		res := make([][2]int, nbGroups)
+		found := make([]bool, nbGroups)
+		for i := len(classes); i > 0; i-- {
+			pr := preds[i][p]
+			for j := 0; j < nbGroups; j++ {
+				if pr.mask>>j&1 == 0 {
+					continue
+				}
+				if !found[j] {
+					found[j] = true
+					res[j][1] = i
+				}
+				res[j][0] = i - 1
+			}
+			p = pr.prev
+		}
+		return res, true
+	}
+	return nil, false
+}
@@ -0,0 +1,88 @@
+package regex
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		spansHint,
+	}
+}
+
+// spansHint returns the starts and the ends of the spans of the revealed
+// groups. The inputs are the number of states, classes and masks of the
+// automaton, its transitions and accepting states, the classes of all bytes,
+// the length of the input and the input bytes.
+func spansHint(_ *big.Int, inputs, outputs []*big.Int) error {
+	next := func(n int) ([]int, error) {
+		if len(inputs) < n {
+			return nil, fmt.Errorf("not enough inputs")
+		}
+		res := make([]int, n)
+		for i := range res {
+			if !inputs[i].IsUint64() || inputs[i].Uint64() >= 1<<31 {
+				return nil, fmt.Errorf("input %d out of range", i)
+			}
+			res[i] = int(inputs[i].Uint64())
+		}
+		inputs = inputs[n:]
+		return res, nil
+	}
+	header, err := next(3)
+	if err != nil {
+		return err
+	}
+	d := &dfa{nbStates: header[0], nbClasses: header[1], nbMasks: header[2]}
+	if len(outputs)%2 != 0 || len(outputs) > 2*maxGroups || 1<<(len(outputs)/2) != d.nbMasks {
+		return fmt.Errorf("expected two outputs per group")
+	}
+	if d.transitions, err = next(d.nbStates * d.nbClasses * d.nbMasks); err != nil {
+		return err
+	}
+	accepting, err := next(d.nbStates)
+	if err != nil {
+		return err
+	}
+	for _, a := range accepting {
+		d.accepting = append(d.accepting, a == 1)
+	}
+	classes, err := next(256)
+	if err != nil {
+		return err
+	}
+	length, err := next(1)
+	if err != nil {
+		return err
+	}
+	if length[0] > len(inputs) {
+		return fmt.Errorf("length larger than the input")
+	}
+	data, err := next(length[0])
+	if err != nil {
+		return err
+	}
+	for i := range data {
+		if data[i] >= 256 {
+			return fmt.Errorf("input byte %d out of range", i)
+		}
+		data[i] = classes[data[i]]
+	}
+	spans, ok := d.spans(data)
+	if !ok {
+		return fmt.Errorf("input does not match")
+	}
+	for j := range spans {
+		outputs[2*j].SetInt64(int64(spans[j][0]))
+		outputs[2*j+1].SetInt64(int64(spans[j][1]))
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package regex
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// maxGroups is the maximal number of revealed groups. The size of the
+// transition table is exponential in the number of revealed groups.
+const maxGroups = 4
+
+// Matcher checks in-circuit that inputs match a regular expression.
+type Matcher struct {
+	api frontend.API
+	re  *Regexp
+}
+
+// Capture is a revealed capture group.
+type Capture struct {
+	// Bytes are the bytes of the group padded with zeros.
+	Bytes []uints.U8
+	// Start is the index of the first byte of the group in the input and
+	// Length the number of bytes of the group. If the group does not
+	// participate in the match or is empty, then Length is zero and Start
+	// is arbitrary.
+	Start, Length frontend.Variable
+}
+
+// NewMatcher returns a new matcher for the regular expression re.
+func NewMatcher(api frontend.API, re *Regexp) *Matcher {
+	return &Matcher{api: api, re: re}
+}
+
+// Match asserts that the first length bytes of data match the regular
+// expression. The remaining bytes of data are ignored.
+func (m *Matcher) Match(data []uints.U8, length frontend.Variable) error {
+	_, err := m.Submatch(data, length, 0)
+	return err
+}
+
+// Submatch asserts that the first length bytes of data match the regular
+// expression and returns the bytes of the capture groups of the given
+// indices, padded to maxLen bytes. It asserts that every revealed group has at
+// most maxLen bytes.
+func (m *Matcher) Submatch(data []uints.U8, length frontend.Variable, maxLen int, groups ...int) ([]Capture, error) {
+	api := m.api
+	if len(groups) > maxGroups {
+		return nil, fmt.Errorf("at most %d groups can be revealed", maxGroups)
+	}
+	for i, g := range groups {
+		if g < 1 || g > m.re.nbGroups {
+			return nil, fmt.Errorf("group %d out of range [1, %d]", g, m.re.nbGroups)
+		}
+		for j := 0; j < i; j++ {
+			if groups[j] == g {
+				return nil, fmt.Errorf("duplicate group %d", g)
+			}
+		}
+	}
+	if maxLen < 0 {
+		return nil, fmt.Errorf("negative maximal length")
+	}
+	d, err := m.re.dfa(groups)
+	if err != nil {
+		return nil, fmt.Errorf("compile %q: %w", m.re.expr, err)
+	}
+	n := len(data)
+	active := selector.PrefixMask(api, length, n)
+
+	classT := logderivlookup.New(api)
+	for c := range m.re.classes {
+		classT.Insert(m.re.classes[c])
+	}
+	vals := make([]frontend.Variable, n)
+	for i := range data {
+		vals[i] = data[i].Val
+	}
+	classes := classT.Lookup(vals...)
+
+	// masks are the sets of the revealed groups enclosing every byte.
+	masks := make([]frontend.Variable, n)
+	for i := range masks {
+		masks[i] = 0
+	}
+	res := make([]Capture, len(groups))
+	if len(groups) > 0 {
+		inputs := []frontend.Variable{d.nbStates, d.nbClasses, d.nbMasks}
+		for _, t := range d.transitions {
+			inputs = append(inputs, t)
+		}
+		for _, a := range d.accepting {
+			inputs = append(inputs, utils.BoolToInt(a))
+		}
+		for _, c := range m.re.classes {
+			inputs = append(inputs, c)
+		}
+		inputs = append(inputs, length)
+		inputs = append(inputs, vals...)
+		spans, err := api.Compiler().NewHint(spansHint, 2*len(groups), inputs...)
+		if err != nil {
+			return nil, fmt.Errorf("new hint: %w", err)
+		}
+		dataT := logderivlookup.New(api)
+		for i := range data {
+			dataT.Insert(data[i].Val)
+		}
+		for i := 0; i < maxLen; i++ {
+			dataT.Insert(0)
+		}
+		for j := range groups {
+			start, end := spans[2*j], spans[2*j+1]
+			// the byte i is in the group if start <= i < end. The flags are
+			// boolean only if start <= end, unless the group is empty.
+			beforeEnd := selector.PrefixMask(api, end, n)
+			beforeStart := selector.PrefixMask(api, start, n)
+			for i := range masks {
+				in := api.Sub(beforeEnd[i], beforeStart[i])
+				api.AssertIsBoolean(in)
+				api.AssertIsEqual(api.Mul(in, api.Sub(1, active[i])), 0)
+				masks[i] = api.Add(masks[i], api.Mul(in, 1<<j))
+			}
+			capLen := api.Sub(end, start)
+			inCapture := selector.PrefixMask(api, capLen, maxLen)
+			indices := make([]frontend.Variable, maxLen)
+			for k := range indices {
+				indices[k] = api.Add(start, k)
+			}
+			bs := dataT.Lookup(indices...)
+			res[j].Bytes = make([]uints.U8, maxLen)
+			for k := range bs {
+				res[j].Bytes[k] = uints.U8{Val: api.Mul(bs[k], inCapture[k])}
+			}
+			res[j].Start = start
+			res[j].Length = capLen
+		}
+	}
+
+	transT := logderivlookup.New(api)
+	for _, t := range d.transitions {
+		transT.Insert(t)
+	}
+	acceptT := logderivlookup.New(api)
+	for _, a := range d.accepting {
+		acceptT.Insert(utils.BoolToInt(a))
+	}
+	var state frontend.Variable = startState
+	for i := 0; i < n; i++ {
+		idx := api.Add(api.Mul(api.Add(api.Mul(state, d.nbClasses), classes[i]), d.nbMasks), masks[i])
+		next := transT.Lookup(idx)[0]
+		state = api.Select(active[i], next, state)
+	}
+	api.AssertIsEqual(acceptT.Lookup(state)[0], 1)
+	return res, nil
+}
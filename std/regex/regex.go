@@ -0,0 +1,244 @@
+// Package regex implements in-circuit regular expression matching.
+//
+// A regular expression in the syntax of the Go regexp package is compiled at
+// circuit definition time into a deterministic finite automaton (DFA) whose
+// transition function is encoded in a lookup table. The circuit then checks
+// that the input, given as bytes with a variable length, is accepted by the
+// automaton.
+//
+// The regular expression must match the whole input, as if it was surrounded
+// by ^(?: and )$. The input is matched byte by byte: literals with non-ASCII
+// characters match their UTF-8 encoding, and character classes containing all
+// non-ASCII characters, such as . and [^a], match a single byte in the range
+// 0x80-0xff. Other character classes with non-ASCII characters are not
+// supported. Case folding applies only to ASCII letters. The empty-width
+// assertions other than ^ and $ at the beginning and the end of the text are
+// not supported.
+//
+// The bytes of capture groups can be revealed. For that, the transitions of the
+// automaton are additionally labelled by the set of the revealed groups which
+// enclose the byte, and the prover provides the span of every group. If the
+// regular expression is ambiguous, then any spans for which the input matches
+// are accepted. The bytes of a group must be contiguous, so a group in a
+// repetition can only be revealed if it matches consecutive bytes, in which
+// case all the repetitions are revealed.
+package regex
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"unicode/utf8"
+)
+
+// the kinds of the states of the non-deterministic automaton.
+const (
+	kindByte  = iota // consumes a byte in the set of bytes
+	kindSplit        // epsilon transitions to all outputs
+	kindBegin        // epsilon transition only at the beginning of the input
+	kindEnd          // epsilon transition only at the end of the input
+	kindMatch        // accepting state
+)
+
+type nfaState struct {
+	kind  int
+	bytes [256]bool
+	// groups are the indices of the capture groups enclosing the byte.
+	groups []int
+	out    []int
+}
+
+// Regexp is a compiled regular expression.
+type Regexp struct {
+	expr     string
+	nbGroups int
+	states   []nfaState
+	start    int
+	// classes are the classes of bytes which are equivalent for all the states
+	// of the automaton.
+	classes   [256]int
+	nbClasses int
+}
+
+// Compile parses the regular expression and compiles it into an automaton.
+func Compile(expr string) (*Regexp, error) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	res := &Regexp{expr: expr, nbGroups: re.MaxCap()}
+	match := res.add(nfaState{kind: kindMatch})
+	if res.start, err = res.compile(re.Simplify(), match, nil); err != nil {
+		return nil, err
+	}
+	res.computeClasses()
+	return res, nil
+}
+
+// MustCompile is like [Compile] but panics if the regular expression cannot be
+// compiled.
+func MustCompile(expr string) *Regexp {
+	re, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+// String returns the source text of the regular expression.
+func (re *Regexp) String() string {
+	return re.expr
+}
+
+// NumSubexp returns the number of capture groups.
+func (re *Regexp) NumSubexp() int {
+	return re.nbGroups
+}
+
+func (re *Regexp) add(s nfaState) int {
+	re.states = append(re.states, s)
+	return len(re.states) - 1
+}
+
+// compile adds the states matching r followed by the state next and returns
+// the first state.
+func (re *Regexp) compile(r *syntax.Regexp, next int, groups []int) (int, error) {
+	switch r.Op {
+	case syntax.OpNoMatch:
+		return re.add(nfaState{kind: kindByte, groups: groups}), nil
+	case syntax.OpEmptyMatch:
+		return next, nil
+	case syntax.OpLiteral:
+		var bs []byte
+		for _, c := range r.Rune {
+			bs = utf8.AppendRune(bs, c)
+		}
+		for i := len(bs) - 1; i >= 0; i-- {
+			s := nfaState{kind: kindByte, groups: groups, out: []int{next}}
+			s.bytes[bs[i]] = true
+			if r.Flags&syntax.FoldCase != 0 {
+				if c := bs[i] | 0x20; c >= 'a' && c <= 'z' {
+					s.bytes[c], s.bytes[c-0x20] = true, true
+				}
+			}
+			next = re.add(s)
+		}
+		return next, nil
+	case syntax.OpCharClass:
+		s := nfaState{kind: kindByte, groups: groups, out: []int{next}}
+		// the number of non-ASCII characters in the class.
+		nonASCII := 0
+		for i := 0; i < len(r.Rune); i += 2 {
+			lo, hi := r.Rune[i], r.Rune[i+1]
+			for c := lo; c <= hi && c < utf8.RuneSelf; c++ {
+				s.bytes[c] = true
+			}
+			if hi >= utf8.RuneSelf {
+				nonASCII += int(hi - max(lo, utf8.RuneSelf) + 1)
+			}
+		}
+		switch {
+		case nonASCII == utf8.MaxRune-utf8.RuneSelf+1:
+			for c := utf8.RuneSelf; c < 256; c++ {
+				s.bytes[c] = true
+			}
+		case nonASCII > 0 && r.Flags&syntax.FoldCase == 0:
+			return 0, fmt.Errorf("unsupported character class with non-ASCII characters: %s", r)
+		}
+		return re.add(s), nil
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		s := nfaState{kind: kindByte, groups: groups, out: []int{next}}
+		for c := range s.bytes {
+			s.bytes[c] = c != '\n' || r.Op == syntax.OpAnyChar
+		}
+		return re.add(s), nil
+	case syntax.OpBeginText:
+		return re.add(nfaState{kind: kindBegin, out: []int{next}}), nil
+	case syntax.OpEndText:
+		return re.add(nfaState{kind: kindEnd, out: []int{next}}), nil
+	case syntax.OpCapture:
+		inner := make([]int, len(groups), len(groups)+1)
+		copy(inner, groups)
+		return re.compile(r.Sub[0], next, append(inner, r.Cap))
+	case syntax.OpStar, syntax.OpPlus:
+		split := re.add(nfaState{kind: kindSplit})
+		body, err := re.compile(r.Sub[0], split, groups)
+		if err != nil {
+			return 0, err
+		}
+		re.states[split].out = []int{body, next}
+		if r.Op == syntax.OpPlus {
+			return body, nil
+		}
+		return split, nil
+	case syntax.OpQuest:
+		body, err := re.compile(r.Sub[0], next, groups)
+		if err != nil {
+			return 0, err
+		}
+		return re.add(nfaState{kind: kindSplit, out: []int{body, next}}), nil
+	case syntax.OpRepeat:
+		// x{n,m} is x repeated n times followed by m-n optional x, and x{n,}
+		// is x repeated n times followed by x*.
+		var err error
+		if r.Max == -1 {
+			next, err = re.compile(&syntax.Regexp{Op: syntax.OpStar, Sub: r.Sub}, next, groups)
+			if err != nil {
+				return 0, err
+			}
+		}
+		for i := r.Min; i < r.Max; i++ {
+			body, err := re.compile(r.Sub[0], next, groups)
+			if err != nil {
+				return 0, err
+			}
+			next = re.add(nfaState{kind: kindSplit, out: []int{body, next}})
+		}
+		for i := 0; i < r.Min; i++ {
+			if next, err = re.compile(r.Sub[0], next, groups); err != nil {
+				return 0, err
+			}
+		}
+		return next, nil
+	case syntax.OpConcat:
+		var err error
+		for i := len(r.Sub) - 1; i >= 0; i-- {
+			if next, err = re.compile(r.Sub[i], next, groups); err != nil {
+				return 0, err
+			}
+		}
+		return next, nil
+	case syntax.OpAlternate:
+		out := make([]int, len(r.Sub))
+		for i := range r.Sub {
+			var err error
+			if out[i], err = re.compile(r.Sub[i], next, groups); err != nil {
+				return 0, err
+			}
+		}
+		return re.add(nfaState{kind: kindSplit, out: out}), nil
+	}
+	return 0, fmt.Errorf("unsupported operator %s", r)
+}
+
+// computeClasses computes the classes of bytes which are accepted by the same
+// states.
+func (re *Regexp) computeClasses() {
+	classes := make(map[string]int)
+	for c := 0; c < 256; c++ {
+		sig := make([]byte, 0, len(re.states))
+		for i := range re.states {
+			if re.states[i].kind == kindByte && re.states[i].bytes[c] {
+				sig = append(sig, 1)
+			} else {
+				sig = append(sig, 0)
+			}
+		}
+		cls, ok := classes[string(sig)]
+		if !ok {
+			cls = len(classes)
+			classes[string(sig)] = cls
+		}
+		re.classes[c] = cls
+	}
+	re.nbClasses = len(classes)
+}
@@ -0,0 +1,197 @@
+package regex
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+var patterns = []string{
+	`a(b|c)*d`, `[0-9]{2,4}-[a-f]+`, `(?i)hello`, `x?y+z{3}`, `.*@example\.com`,
+	`^From: [^\r\n]+$`, `(ab|a)(bc|c)?`, `a{2,}b{0,2}`, `()|a*`, `(a*)*b`,
+}
+
+func TestCompile(t *testing.T) {
+	assert := test.NewAssert(t)
+	rng := rand.New(rand.NewSource(42)) //#nosec G404 -- test only
+	const alphabet = "abcdxyz09-@.eEhlo\n"
+	for _, p := range patterns {
+		re, err := Compile(p)
+		assert.NoError(err, p)
+		d, err := re.dfa(nil)
+		assert.NoError(err, p)
+		expected := regexp.MustCompile(`^(?:` + p + `)$`)
+		for i := 0; i < 2000; i++ {
+			b := make([]byte, rng.Intn(8))
+			for j := range b {
+				b[j] = alphabet[rng.Intn(len(alphabet))]
+			}
+			classes := make([]int, len(b))
+			for j := range b {
+				classes[j] = re.classes[b[j]]
+			}
+			_, ok := d.spans(classes)
+			assert.Equal(expected.Match(b), ok, "%s %q", p, b)
+		}
+	}
+	for _, p := range []string{`\bx`, `(?m)^x`, `[é-ü]`} {
+		_, err := Compile(p)
+		assert.Error(err, p)
+	}
+}
+
+type matchCircuit struct {
+	pattern string
+	Data    [16]uints.U8
+	Length  frontend.Variable
+}
+
+func (c *matchCircuit) Define(api frontend.API) error {
+	re, err := Compile(c.pattern)
+	if err != nil {
+		return err
+	}
+	return NewMatcher(api, re).Match(c.Data[:], c.Length)
+}
+
+func matchAssignment(s string) *matchCircuit {
+	var assignment matchCircuit
+	data := make([]byte, len(assignment.Data))
+	copy(data, s)
+	copy(assignment.Data[:], uints.NewU8Array(data))
+	assignment.Length = len(s)
+	return &assignment
+}
+
+func TestMatch(t *testing.T) {
+	assert := test.NewAssert(t)
+	testCases := []struct {
+		pattern string
+		inputs  []string
+	}{
+		{`a(b|c)*d`, []string{"ad", "abcbd", "abd", "abc", "xad", "adx", ""}},
+		{`[0-9]{2,4}-[a-f]+`, []string{"12-ab", "1234-f", "1-a", "12345-a", "12-", "12-abg"}},
+		{`(?i)hello`, []string{"hello", "HeLLo", "hell", "hello!"}},
+		{`^.*@example\.com$`, []string{"a@example.com", "@example.com", "a@exampleXcom", "a\n@example.com"}},
+		{`é+`, []string{"é", "éé", "e", "\xc3"}},
+		{`[^a]b`, []string{"xb", "\xffb", "ab", "b"}},
+	}
+	for _, tc := range testCases {
+		expected := regexp.MustCompile(`^(?:` + tc.pattern + `)$`)
+		for _, in := range tc.inputs {
+			err := test.IsSolved(&matchCircuit{pattern: tc.pattern}, matchAssignment(in), ecc.BN254.ScalarField())
+			if expected.MatchString(in) || (tc.pattern == `[^a]b` && in == "\xffb") {
+				assert.NoError(err, "%s %q", tc.pattern, in)
+			} else {
+				assert.Error(err, "%s %q", tc.pattern, in)
+			}
+		}
+	}
+}
+
+const (
+	fromPattern = `From: ([A-Za-z ]+) <([a-z.]+)@([a-z.]+)>\r\n`
+	maxCapLen   = 16
+)
+
+type submatchCircuit struct {
+	Data    [48]uints.U8
+	Length  frontend.Variable
+	Names   [2][maxCapLen]uints.U8
+	Starts  [2]frontend.Variable
+	Lengths [2]frontend.Variable
+}
+
+func (c *submatchCircuit) Define(api frontend.API) error {
+	re, err := Compile(fromPattern)
+	if err != nil {
+		return err
+	}
+	captures, err := NewMatcher(api, re).Submatch(c.Data[:], c.Length, maxCapLen, 1, 3)
+	if err != nil {
+		return err
+	}
+	for i := range captures {
+		api.AssertIsEqual(captures[i].Start, c.Starts[i])
+		api.AssertIsEqual(captures[i].Length, c.Lengths[i])
+		for j := range captures[i].Bytes {
+			api.AssertIsEqual(captures[i].Bytes[j].Val, c.Names[i][j].Val)
+		}
+	}
+	return nil
+}
+
+func TestSubmatch(t *testing.T) {
+	assert := test.NewAssert(t)
+	assign := func(in string, names []string, starts []int) *submatchCircuit {
+		var assignment submatchCircuit
+		data := make([]byte, len(assignment.Data))
+		copy(data, in)
+		copy(assignment.Data[:], uints.NewU8Array(data))
+		assignment.Length = len(in)
+		for i := range names {
+			b := make([]byte, maxCapLen)
+			copy(b, names[i])
+			copy(assignment.Names[i][:], uints.NewU8Array(b))
+			assignment.Starts[i] = starts[i]
+			assignment.Lengths[i] = len(names[i])
+		}
+		return &assignment
+	}
+	in := "From: Alice Smith <alice@example.com>\r\n"
+	m := regexp.MustCompile(`^` + fromPattern + `$`).FindStringSubmatchIndex(in)
+	names := []string{in[m[2]:m[3]], in[m[6]:m[7]]}
+	assert.Equal([]string{"Alice Smith", "example.com"}, names)
+	err := test.IsSolved(&submatchCircuit{}, assign(in, names, []int{m[2], m[6]}), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// wrong revealed bytes.
+	err = test.IsSolved(&submatchCircuit{}, assign(in, []string{"Alice Smit", "example.com"}, []int{m[2], m[6]}), ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// the input does not match.
+	in = "From: Alice <alice@example.com>\n"
+	err = test.IsSolved(&submatchCircuit{}, assign(in, []string{"Alice", "example.com"}, []int{6, 19}), ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type optionalCircuit struct {
+	Data   [8]uints.U8
+	Length frontend.Variable
+	Group  [4]uints.U8
+	GLen   frontend.Variable
+}
+
+func (c *optionalCircuit) Define(api frontend.API) error {
+	re := MustCompile(`x(?:(ab+)|c)y`)
+	captures, err := NewMatcher(api, re).Submatch(c.Data[:], c.Length, len(c.Group), 1)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(captures[0].Length, c.GLen)
+	for i := range c.Group {
+		api.AssertIsEqual(captures[0].Bytes[i].Val, c.Group[i].Val)
+	}
+	return nil
+}
+
+func TestSubmatchOptional(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, tc := range []struct{ in, group string }{{"xabby", "abb"}, {"xcy", ""}} {
+		var assignment optionalCircuit
+		data := make([]byte, len(assignment.Data))
+		copy(data, tc.in)
+		copy(assignment.Data[:], uints.NewU8Array(data))
+		assignment.Length = len(tc.in)
+		g := make([]byte, len(assignment.Group))
+		copy(g, tc.group)
+		copy(assignment.Group[:], uints.NewU8Array(g))
+		assignment.GLen = len(tc.group)
+		assert.NoError(test.IsSolved(&optionalCircuit{}, &assignment, ecc.BN254.ScalarField()), tc.in)
+	}
+}
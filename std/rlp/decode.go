@@ -0,0 +1,137 @@
+// Package rlp implements in-circuit decoding of Recursive Length Prefix
+// (RLP) encoded data as used in Ethereum.
+//
+// The decoder works over bytes represented as [uints.U8] and decodes items at
+// variable offsets, so the structure of the decoded data does not have to be
+// known at circuit compile time. The decoder does not enforce the canonical
+// encoding of the items (for example a single byte encoded as a short string
+// or lengths with leading zeros). When the decoded data is authenticated, for
+// example by a hash, then this is not an issue.
+//
+// See https://ethereum.org/en/developers/docs/data-structures-and-encoding/rlp/
+// for the description of the encoding.
+package rlp
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Item is a decoded RLP item.
+type Item struct {
+	// Offset is the position of the payload of the item in the data.
+	Offset frontend.Variable
+	// Length is the length of the payload of the item.
+	Length frontend.Variable
+	// IsList is 1 if the item is a list and 0 if it is a string.
+	IsList frontend.Variable
+	// End is the position of the first byte after the item, i.e. Offset+Length.
+	End frontend.Variable
+}
+
+// Decoder decodes RLP items from data at variable positions.
+type Decoder struct {
+	api         frontend.API
+	table       *logderivlookup.Table
+	maxLenOfLen int
+}
+
+// NewDecoder returns a new decoder over data. The bytes are looked up at
+// variable positions and looking up a byte outside of data fails at solving
+// time. If the decoded items are close to the end of data, then data should be
+// padded with zero bytes.
+//
+// The length of the length of long items is bounded by the number of bytes
+// needed to represent len(data), as longer items cannot fit into the data.
+func NewDecoder(api frontend.API, data []uints.U8) *Decoder {
+	table := logderivlookup.New(api)
+	for i := range data {
+		table.Insert(data[i].Val)
+	}
+	maxLenOfLen := 1
+	for l := len(data) >> 8; l > 0; l >>= 8 {
+		maxLenOfLen++
+	}
+	return &Decoder{
+		api:         api,
+		table:       table,
+		maxLenOfLen: maxLenOfLen,
+	}
+}
+
+// Decode decodes the item starting at position pos. A single byte in the range
+// [0x00, 0x7f] is its own payload and the corresponding item has Offset equal
+// to pos and Length 1.
+func (d *Decoder) Decode(pos frontend.Variable) Item {
+	api := d.api
+	inds := make([]frontend.Variable, d.maxLenOfLen+1)
+	for i := range inds {
+		inds[i] = api.Add(pos, i)
+	}
+	bs := d.table.Lookup(inds...)
+
+	// the prefix is
+	//   - 0b0xxxxxxx for single bytes,
+	//   - 0b10xxxxxx for strings,
+	//   - 0b11xxxxxx for lists,
+	// where the lowest six bits give the length of the payload if it is at
+	// most 55 and the length of the length plus 55 otherwise.
+	bits := api.ToBinary(bs[0], 8)
+	isSingle := api.Sub(1, bits[7])
+	isList := api.Mul(bits[7], bits[6])
+	isLong := api.Mul(bits[7], bits[5], bits[4], bits[3])
+	lowVal := api.FromBinary(bits[:6]...)
+
+	lenOfLen := api.Mul(isLong, api.Sub(lowVal, 55))
+	longLen := frontend.Variable(0)
+	active := frontend.Variable(1)
+	for i := 0; i < d.maxLenOfLen; i++ {
+		active = api.Sub(active, api.IsZero(api.Sub(lenOfLen, i)))
+		longLen = api.Select(active, api.Add(api.Mul(longLen, 256), bs[i+1]), longLen)
+	}
+	// the length of the length must be at most maxLenOfLen. As the active
+	// flag is one for all lengths of length larger than maxLenOfLen, then we
+	// check that it is one only when the length of the length is exactly
+	// maxLenOfLen.
+	api.AssertIsEqual(active, api.IsZero(api.Sub(lenOfLen, d.maxLenOfLen)))
+
+	shortLen := api.Mul(api.Sub(1, isLong), lowVal)
+	length := api.Select(isSingle, 1, api.Select(isLong, longLen, shortLen))
+	offset := api.Select(isSingle, pos, api.Add(pos, 1, lenOfLen))
+	return Item{
+		Offset: offset,
+		Length: length,
+		IsList: isList,
+		End:    api.Add(offset, length),
+	}
+}
+
+// Items decodes the first n items in the payload of the list item. The items
+// after the end of the list are decoded from the bytes following the list and
+// the caller should check that the list has the expected number of items by
+// comparing the End fields of the items and the list.
+func (d *Decoder) Items(list Item, n int) []Item {
+	res := make([]Item, n)
+	pos := list.Offset
+	for i := range res {
+		res[i] = d.Decode(pos)
+		pos = res[i].End
+	}
+	return res
+}
+
+// Bytes returns n bytes starting at position offset. It does not check that
+// the bytes belong to a single item.
+func (d *Decoder) Bytes(offset frontend.Variable, n int) []uints.U8 {
+	inds := make([]frontend.Variable, n)
+	for i := range inds {
+		inds[i] = d.api.Add(offset, i)
+	}
+	vals := d.table.Lookup(inds...)
+	res := make([]uints.U8, n)
+	for i := range res {
+		res[i] = uints.U8{Val: vals[i]}
+	}
+	return res
+}
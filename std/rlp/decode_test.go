@@ -0,0 +1,107 @@
+package rlp
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type decodeCircuit struct {
+	Data   []uints.U8
+	Pos    frontend.Variable
+	Offset frontend.Variable
+	Length frontend.Variable
+	IsList frontend.Variable
+}
+
+func (c *decodeCircuit) Define(api frontend.API) error {
+	d := NewDecoder(api, c.Data)
+	item := d.Decode(c.Pos)
+	api.AssertIsEqual(item.Offset, c.Offset)
+	api.AssertIsEqual(item.Length, c.Length)
+	api.AssertIsEqual(item.IsList, c.IsList)
+	api.AssertIsEqual(item.End, api.Add(c.Offset, c.Length))
+	return nil
+}
+
+func TestDecode(t *testing.T) {
+	assert := test.NewAssert(t)
+	long := strings.Repeat("ab", 60)
+	for _, tc := range []struct {
+		name   string
+		data   string
+		pos    int
+		offset int
+		length int
+		isList int
+	}{
+		{"single", "0f", 0, 0, 1, 0},
+		{"empty", "80", 0, 1, 0, 0},
+		{"string", "83646f67", 0, 1, 3, 0},
+		{"integer", "820400", 0, 1, 2, 0},
+		{"empty list", "c0", 0, 1, 0, 1},
+		{"list", "c88363617483646f67", 0, 1, 8, 1},
+		{"list item", "c88363617483646f67", 5, 6, 3, 0},
+		{"long string", "b83c" + long, 0, 2, 60, 0},
+		{"long list", "f83c" + long, 0, 2, 60, 1},
+		{"long length", "b90100" + strings.Repeat("00", 256), 0, 3, 256, 0},
+	} {
+		tc := tc
+		assert.Run(func(assert *test.Assert) {
+			bts, err := hex.DecodeString(tc.data)
+			assert.NoError(err)
+			// pad the data so that the length bytes can be looked up.
+			bts = append(bts, make([]byte, 4)...)
+			assignment := decodeCircuit{
+				Data:   uints.NewU8Array(bts),
+				Pos:    tc.pos,
+				Offset: tc.offset,
+				Length: tc.length,
+				IsList: tc.isList,
+			}
+			err = test.IsSolved(&decodeCircuit{Data: make([]uints.U8, len(bts))}, &assignment, ecc.BN254.ScalarField())
+			assert.NoError(err)
+		}, tc.name)
+	}
+}
+
+type itemsCircuit struct {
+	Data     []uints.U8
+	Expected [][]uints.U8
+}
+
+func (c *itemsCircuit) Define(api frontend.API) error {
+	d := NewDecoder(api, c.Data)
+	list := d.Decode(0)
+	api.AssertIsEqual(list.IsList, 1)
+	items := d.Items(list, len(c.Expected))
+	api.AssertIsEqual(items[len(items)-1].End, list.End)
+	for i := range items {
+		api.AssertIsEqual(items[i].Length, len(c.Expected[i]))
+		bts := d.Bytes(items[i].Offset, len(c.Expected[i]))
+		for j := range bts {
+			api.AssertIsEqual(bts[j].Val, c.Expected[i][j].Val)
+		}
+	}
+	return nil
+}
+
+func TestItems(t *testing.T) {
+	assert := test.NewAssert(t)
+	// ["cat", "dog", 0x0f, ""]
+	bts, err := hex.DecodeString("ca8363617483646f670f80" + "00000000")
+	assert.NoError(err)
+	expected := [][]byte{[]byte("cat"), []byte("dog"), {0x0f}, {}}
+	circuit := itemsCircuit{Data: make([]uints.U8, len(bts)), Expected: make([][]uints.U8, len(expected))}
+	assignment := itemsCircuit{Data: uints.NewU8Array(bts), Expected: make([][]uints.U8, len(expected))}
+	for i := range expected {
+		circuit.Expected[i] = make([]uints.U8, len(expected[i]))
+		assignment.Expected[i] = uints.NewU8Array(expected[i])
+	}
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&assignment), test.WithCurves(ecc.BN254), test.NoProverChecks(), test.NoFuzzing())
+}
@@ -0,0 +1,209 @@
+package rlp
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Encoded is a byte string of variable length, such as an RLP encoding. Only
+// the first Length bytes of Bytes are part of the string and the remaining
+// bytes are zero. The length of Bytes is the maximum length of the string.
+type Encoded struct {
+	Bytes  []uints.U8
+	Length frontend.Variable
+}
+
+// Encoder encodes byte strings, integers and nested lists into RLP in
+// circuit. The lengths of the encoded values are variables bounded by the
+// sizes of the inputs, so that the circuit can encode values whose lengths
+// are only known at solving time.
+type Encoder struct {
+	api frontend.API
+}
+
+// NewEncoder returns a new encoder.
+func NewEncoder(api frontend.API) *Encoder {
+	return &Encoder{api: api}
+}
+
+// String returns the encoding of the byte string given by the first length
+// bytes of b. It asserts that length is at most len(b).
+func (e *Encoder) String(b []uints.U8, length frontend.Variable) Encoded {
+	payload := e.truncate(b, length)
+	// a single byte in the range [0x00, 0x7f] is its own encoding.
+	var isSingle frontend.Variable = 0
+	if len(b) > 0 {
+		b0 := bits.ToBinary(e.api, payload.Bytes[0].Val, bits.WithNbDigits(8))
+		isSingle = e.api.Mul(e.api.IsZero(e.api.Sub(length, 1)), e.api.Sub(1, b0[7]))
+	}
+	return e.Concat(e.header(payload, 0x80, isSingle), payload)
+}
+
+// Uint returns the encoding of the unsigned integer v of at most nbBytes
+// bytes, as the string of its big-endian representation without leading
+// zeros. It asserts that v fits in nbBytes bytes.
+func (e *Encoder) Uint(v frontend.Variable, nbBytes int) Encoded {
+	vb := bits.ToBinary(e.api, v, bits.WithNbDigits(8*nbBytes))
+	b := make([]uints.U8, nbBytes)
+	for i := range b {
+		b[nbBytes-1-i] = uints.U8{Val: bits.FromBinary(e.api, vb[8*i:8*i+8], bits.WithUnconstrainedInputs())}
+	}
+	return e.BigEndian(b)
+}
+
+// BigEndian returns the encoding of the unsigned integer given by its
+// big-endian representation b, as the string of b without the leading zeros.
+// It is used for integers which do not fit into a native field element, such
+// as 256-bit values.
+func (e *Encoder) BigEndian(b []uints.U8) Encoded {
+	api := e.api
+	// the number of leading zero bytes.
+	var nbZeros frontend.Variable = 0
+	var isLeading frontend.Variable = 1
+	for i := range b {
+		isLeading = api.Mul(isLeading, api.IsZero(b[i].Val))
+		nbZeros = api.Add(nbZeros, isLeading)
+	}
+	length := api.Sub(len(b), nbZeros)
+	return e.String(e.shiftLeft(b, nbZeros, len(b)), length)
+}
+
+// List returns the encoding of the list of the encoded items.
+func (e *Encoder) List(items ...Encoded) Encoded {
+	payload := e.Concat(items...)
+	return e.Concat(e.header(payload, 0xc0, 0), payload)
+}
+
+// Concat returns the concatenation of the byte strings. The maximum length of
+// the result is the sum of the maximum lengths of the inputs.
+func (e *Encoder) Concat(items ...Encoded) Encoded {
+	if len(items) == 0 {
+		return Encoded{Length: 0}
+	}
+	res := items[0]
+	for i := 1; i < len(items); i++ {
+		res = e.concat(res, items[i])
+	}
+	return res
+}
+
+// concat returns the concatenation of a and b. The bytes of b are looked up at
+// the positions shifted by the length of a.
+func (e *Encoder) concat(a, b Encoded) Encoded {
+	api := e.api
+	if len(b.Bytes) == 0 {
+		return a
+	}
+	n := len(a.Bytes) + len(b.Bytes)
+	table := logderivlookup.New(api)
+	for i := range b.Bytes {
+		table.Insert(b.Bytes[i].Val)
+	}
+	length := api.Add(a.Length, b.Length)
+	// inA is 1 for the positions before the length of a and inRes for the
+	// positions before the length of the result.
+	inA := selector.PrefixMask(api, a.Length, n)
+	inRes := selector.PrefixMask(api, length, n)
+	inds := make([]frontend.Variable, n)
+	for k := range inds {
+		inB := api.Sub(inRes[k], inA[k])
+		inds[k] = api.Mul(inB, api.Sub(k, a.Length))
+	}
+	vals := table.Lookup(inds...)
+	res := make([]uints.U8, n)
+	for k := range res {
+		inB := api.Sub(inRes[k], inA[k])
+		v := api.Mul(inB, vals[k])
+		if k < len(a.Bytes) {
+			v = api.Add(v, api.Mul(inA[k], a.Bytes[k].Val))
+		}
+		res[k] = uints.U8{Val: v}
+	}
+	return Encoded{Bytes: res, Length: length}
+}
+
+// header returns the header of the string or list payload, with the given
+// offset 0x80 for strings and 0xc0 for lists. If omit is 1, then the header
+// is empty.
+func (e *Encoder) header(payload Encoded, offset int, omit frontend.Variable) Encoded {
+	api := e.api
+	// the number of bytes of the length of the payload.
+	m := 1
+	for l := len(payload.Bytes) >> 8; l > 0; l >>= 8 {
+		m++
+	}
+	lb := bits.ToBinary(api, payload.Length, bits.WithNbDigits(8*m))
+	// isLong is 1 if the length is larger than 55.
+	cb := bits.ToBinary(api, api.Add(payload.Length, (1<<(8*m))-56), bits.WithNbDigits(8*m+1))
+	isLong := cb[8*m]
+	lenBytes := make([]uints.U8, m)
+	for i := range lenBytes {
+		lenBytes[m-1-i] = uints.U8{Val: bits.FromBinary(api, lb[8*i:8*i+8], bits.WithUnconstrainedInputs())}
+	}
+	var nbZeros frontend.Variable = 0
+	var isLeading frontend.Variable = 1
+	for i := range lenBytes {
+		isLeading = api.Mul(isLeading, api.IsZero(lenBytes[i].Val))
+		nbZeros = api.Add(nbZeros, isLeading)
+	}
+	lenOfLen := api.Sub(m, nbZeros)
+	shifted := e.shiftLeft(lenBytes, nbZeros, m)
+
+	prefix := api.Add(offset, api.Select(isLong, api.Add(55, lenOfLen), payload.Length))
+	res := make([]uints.U8, m+1)
+	res[0] = uints.U8{Val: api.Mul(api.Sub(1, omit), prefix)}
+	for i := range shifted {
+		res[i+1] = uints.U8{Val: api.Mul(isLong, shifted[i].Val)}
+	}
+	length := api.Mul(api.Sub(1, omit), api.Add(1, api.Mul(isLong, lenOfLen)))
+	return Encoded{Bytes: res, Length: length}
+}
+
+// truncate returns the first length bytes of b with the remaining bytes set
+// to zero. It asserts that length is at most len(b).
+func (e *Encoder) truncate(b []uints.U8, length frontend.Variable) Encoded {
+	flags := selector.PrefixMask(e.api, length, len(b))
+	res := make([]uints.U8, len(b))
+	for i := range b {
+		res[i] = uints.U8{Val: e.api.Mul(flags[i], b[i].Val)}
+	}
+	return Encoded{Bytes: res, Length: length}
+}
+
+// shiftLeft returns the bytes of b starting at position s, padded with zeros.
+// The shift s must be at most maxShift.
+func (e *Encoder) shiftLeft(b []uints.U8, s frontend.Variable, maxShift int) []uints.U8 {
+	table := logderivlookup.New(e.api)
+	for i := range b {
+		table.Insert(b[i].Val)
+	}
+	for i := 0; i < maxShift; i++ {
+		table.Insert(0)
+	}
+	inds := make([]frontend.Variable, len(b))
+	for i := range inds {
+		inds[i] = e.api.Add(s, i)
+	}
+	vals := table.Lookup(inds...)
+	res := make([]uints.U8, len(b))
+	for i := range res {
+		res[i] = uints.U8{Val: vals[i]}
+	}
+	return res
+}
+
+// ValueOfEncoded returns the assignment of the byte string b padded with zeros
+// to maxLen bytes.
+func ValueOfEncoded(b []byte, maxLen int) (Encoded, error) {
+	if len(b) > maxLen {
+		return Encoded{}, fmt.Errorf("string has %d bytes, maximum is %d", len(b), maxLen)
+	}
+	res := make([]byte, maxLen)
+	copy(res, b)
+	return Encoded{Bytes: uints.NewU8Array(res), Length: len(b)}, nil
+}
@@ -0,0 +1,104 @@
+package rlp
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+// encodeNative returns the RLP encoding of v, which is a byte string or a
+// list of values.
+func encodeNative(v any) []byte {
+	var payload []byte
+	var offset byte
+	switch vv := v.(type) {
+	case []byte:
+		if len(vv) == 1 && vv[0] < 0x80 {
+			return vv
+		}
+		payload, offset = vv, 0x80
+	case []any:
+		for _, item := range vv {
+			payload = append(payload, encodeNative(item)...)
+		}
+		offset = 0xc0
+	default:
+		panic("unsupported type")
+	}
+	if len(payload) <= 55 {
+		return append([]byte{offset + byte(len(payload))}, payload...)
+	}
+	l := big.NewInt(int64(len(payload))).Bytes()
+	res := append([]byte{offset + 55 + byte(len(l))}, l...)
+	return append(res, payload...)
+}
+
+type encodeCircuit struct {
+	Strings  [4][]uints.U8
+	Lengths  [4]frontend.Variable
+	Int      frontend.Variable
+	Expected Encoded
+}
+
+func (c *encodeCircuit) Define(api frontend.API) error {
+	e := NewEncoder(api)
+	items := make([]Encoded, len(c.Strings))
+	for i := range c.Strings {
+		items[i] = e.String(c.Strings[i], c.Lengths[i])
+	}
+	res := e.List(e.List(items[:2]...), items[2], e.Uint(c.Int, 4), items[3])
+	api.AssertIsEqual(res.Length, c.Expected.Length)
+	for i := range res.Bytes {
+		if i < len(c.Expected.Bytes) {
+			api.AssertIsEqual(res.Bytes[i].Val, c.Expected.Bytes[i].Val)
+		} else {
+			api.AssertIsEqual(res.Bytes[i].Val, 0)
+		}
+	}
+	return nil
+}
+
+func TestEncode(t *testing.T) {
+	assert := test.NewAssert(t)
+	long := make([]byte, 300)
+	for i := range long {
+		long[i] = byte(i)
+	}
+	maxLens := [4]int{1, 60, 300, 2}
+	testCases := []struct {
+		strings [4][]byte
+		v       uint64
+	}{
+		{[4][]byte{{0x7f}, []byte("dog"), nil, {0x00}}, 0},
+		{[4][]byte{{0x80}, long[:55], long[:56], {0x01, 0x02}}, 127},
+		{[4][]byte{nil, long[:56], long[:256], {0x81}}, 128},
+		{[4][]byte{{0x00}, long[:60], long, nil}, 0xffffffff},
+	}
+	const maxLen = 400
+	for i, tc := range testCases {
+		vb := new(big.Int).SetUint64(tc.v).Bytes()
+		expected := encodeNative([]any{[]any{tc.strings[0], tc.strings[1]}, tc.strings[2], vb, tc.strings[3]})
+		var assignment encodeCircuit
+		for j := range tc.strings {
+			s := make([]byte, maxLens[j])
+			copy(s, tc.strings[j])
+			assignment.Strings[j] = uints.NewU8Array(s)
+			assignment.Lengths[j] = len(tc.strings[j])
+		}
+		assignment.Int = tc.v
+		var err error
+		assignment.Expected, err = ValueOfEncoded(expected, maxLen)
+		assert.NoError(err)
+		var circuit encodeCircuit
+		for j := range circuit.Strings {
+			circuit.Strings[j] = make([]uints.U8, maxLens[j])
+		}
+		circuit.Expected.Bytes = make([]uints.U8, maxLen)
+		err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+		assert.NoError(err, "test case %d", i)
+	}
+}
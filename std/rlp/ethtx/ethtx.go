@@ -0,0 +1,300 @@
+// Package ethtx implements the computation of the signing hashes of Ethereum
+// transactions and the recovery of their senders.
+//
+// The transactions are RLP-encoded in circuit from their fields using
+// [rlp.Encoder], so that the lengths of the variable-size fields, such as the
+// call data and the access list, are witness variables bounded by the sizes of
+// the placeholder fields. The supported transaction types are the legacy
+// transactions with EIP-155 replay protection, the EIP-2930 access list
+// transactions and the EIP-1559 dynamic fee transactions.
+//
+// The integer fields which may not fit into the native field, such as the
+// value and the gas prices, are given as 32-byte big-endian strings. The other
+// integer fields are 64-bit native variables.
+package ethtx
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/evmprecompiles"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/sha3"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rlp"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// The transaction types as defined by EIP-2718.
+const (
+	AccessListTxType = 0x01
+	DynamicFeeTxType = 0x02
+)
+
+// AccessTuple is an entry of an access list.
+type AccessTuple struct {
+	Address [20]uints.U8
+	// StorageKeys are the storage keys. The keys after NbStorageKeys are
+	// ignored.
+	StorageKeys   [][32]uints.U8
+	NbStorageKeys frontend.Variable
+}
+
+// AccessList is an EIP-2930 access list.
+type AccessList struct {
+	// Tuples are the entries of the list. The entries after NbTuples are
+	// ignored.
+	Tuples   []AccessTuple
+	NbTuples frontend.Variable
+}
+
+// PlaceholderAccessList returns a placeholder access list of at most
+// maxTuples entries of at most maxKeys storage keys each for compiling the
+// circuit.
+func PlaceholderAccessList(maxTuples, maxKeys int) AccessList {
+	al := AccessList{Tuples: make([]AccessTuple, maxTuples)}
+	for i := range al.Tuples {
+		al.Tuples[i].StorageKeys = make([][32]uints.U8, maxKeys)
+	}
+	return al
+}
+
+// LegacyTx is a legacy transaction.
+type LegacyTx struct {
+	Nonce    frontend.Variable
+	GasPrice [32]uints.U8
+	Gas      frontend.Variable
+	// To is the recipient. It is ignored if IsCreate is 1.
+	To       [20]uints.U8
+	IsCreate frontend.Variable
+	Value    [32]uints.U8
+	// Data is the call data. The bytes after DataLength are ignored.
+	Data       []uints.U8
+	DataLength frontend.Variable
+}
+
+// PlaceholderLegacyTx returns a placeholder legacy transaction with call data
+// of at most maxDataLen bytes for compiling the circuit.
+func PlaceholderLegacyTx(maxDataLen int) LegacyTx {
+	return LegacyTx{Data: make([]uints.U8, maxDataLen)}
+}
+
+// AccessListTx is an EIP-2930 access list transaction.
+type AccessListTx struct {
+	ChainID    frontend.Variable
+	Nonce      frontend.Variable
+	GasPrice   [32]uints.U8
+	Gas        frontend.Variable
+	To         [20]uints.U8
+	IsCreate   frontend.Variable
+	Value      [32]uints.U8
+	Data       []uints.U8
+	DataLength frontend.Variable
+	AccessList AccessList
+}
+
+// PlaceholderAccessListTx returns a placeholder access list transaction for
+// compiling the circuit. See [PlaceholderLegacyTx] and
+// [PlaceholderAccessList].
+func PlaceholderAccessListTx(maxDataLen, maxTuples, maxKeys int) AccessListTx {
+	return AccessListTx{
+		Data:       make([]uints.U8, maxDataLen),
+		AccessList: PlaceholderAccessList(maxTuples, maxKeys),
+	}
+}
+
+// DynamicFeeTx is an EIP-1559 dynamic fee transaction.
+type DynamicFeeTx struct {
+	ChainID    frontend.Variable
+	Nonce      frontend.Variable
+	GasTipCap  [32]uints.U8
+	GasFeeCap  [32]uints.U8
+	Gas        frontend.Variable
+	To         [20]uints.U8
+	IsCreate   frontend.Variable
+	Value      [32]uints.U8
+	Data       []uints.U8
+	DataLength frontend.Variable
+	AccessList AccessList
+}
+
+// PlaceholderDynamicFeeTx returns a placeholder dynamic fee transaction for
+// compiling the circuit. See [PlaceholderLegacyTx] and
+// [PlaceholderAccessList].
+func PlaceholderDynamicFeeTx(maxDataLen, maxTuples, maxKeys int) DynamicFeeTx {
+	return DynamicFeeTx{
+		Data:       make([]uints.U8, maxDataLen),
+		AccessList: PlaceholderAccessList(maxTuples, maxKeys),
+	}
+}
+
+// LegacySigningHash returns the EIP-155 signing hash of the legacy transaction
+// for the chain chainID, the Keccak-256 hash of
+//
+//	rlp([nonce, gasPrice, gas, to, value, data, chainID, 0, 0]).
+func LegacySigningHash(api frontend.API, tx *LegacyTx, chainID frontend.Variable) ([]uints.U8, error) {
+	e := rlp.NewEncoder(api)
+	enc := e.List(
+		e.Uint(tx.Nonce, 8),
+		e.BigEndian(tx.GasPrice[:]),
+		e.Uint(tx.Gas, 8),
+		encodeTo(api, e, tx.To, tx.IsCreate),
+		e.BigEndian(tx.Value[:]),
+		e.String(tx.Data, tx.DataLength),
+		e.Uint(chainID, 8),
+		e.Uint(0, 1),
+		e.Uint(0, 1),
+	)
+	return keccak(api, enc)
+}
+
+// AccessListSigningHash returns the signing hash of the EIP-2930 transaction,
+// the Keccak-256 hash of
+//
+//	0x01 || rlp([chainID, nonce, gasPrice, gas, to, value, data, accessList]).
+func AccessListSigningHash(api frontend.API, tx *AccessListTx) ([]uints.U8, error) {
+	e := rlp.NewEncoder(api)
+	enc := e.List(
+		e.Uint(tx.ChainID, 8),
+		e.Uint(tx.Nonce, 8),
+		e.BigEndian(tx.GasPrice[:]),
+		e.Uint(tx.Gas, 8),
+		encodeTo(api, e, tx.To, tx.IsCreate),
+		e.BigEndian(tx.Value[:]),
+		e.String(tx.Data, tx.DataLength),
+		encodeAccessList(api, e, &tx.AccessList),
+	)
+	return keccak(api, e.Concat(typePrefix(AccessListTxType), enc))
+}
+
+// DynamicFeeSigningHash returns the signing hash of the EIP-1559 transaction,
+// the Keccak-256 hash of
+//
+//	0x02 || rlp([chainID, nonce, gasTipCap, gasFeeCap, gas, to, value, data, accessList]).
+func DynamicFeeSigningHash(api frontend.API, tx *DynamicFeeTx) ([]uints.U8, error) {
+	e := rlp.NewEncoder(api)
+	enc := e.List(
+		e.Uint(tx.ChainID, 8),
+		e.Uint(tx.Nonce, 8),
+		e.BigEndian(tx.GasTipCap[:]),
+		e.BigEndian(tx.GasFeeCap[:]),
+		e.Uint(tx.Gas, 8),
+		encodeTo(api, e, tx.To, tx.IsCreate),
+		e.BigEndian(tx.Value[:]),
+		e.String(tx.Data, tx.DataLength),
+		encodeAccessList(api, e, &tx.AccessList),
+	)
+	return keccak(api, e.Concat(typePrefix(DynamicFeeTxType), enc))
+}
+
+// Signature is a secp256k1 transaction signature.
+type Signature struct {
+	// YParity is the parity of the y-coordinate of the signature point, 0 or
+	// 1. For the legacy EIP-155 transactions it is v - 35 - 2 chainID.
+	YParity frontend.Variable
+	R, S    emulated.Element[emulated.Secp256k1Fr]
+}
+
+// RecoverSender returns the address of the sender of the transaction with the
+// signing hash and the signature. It asserts that the signature is valid, with
+// s in the lower half of the scalar field as required for transactions.
+func RecoverSender(api frontend.API, hash []uints.U8, sig *Signature) ([20]uints.U8, error) {
+	var res [20]uints.U8
+	if len(hash) != 32 {
+		return res, fmt.Errorf("hash must be 32 bytes, got %d", len(hash))
+	}
+	fr, err := emulated.NewField[emulated.Secp256k1Fr](api)
+	if err != nil {
+		return res, fmt.Errorf("new scalar field: %w", err)
+	}
+	fp, err := emulated.NewField[emulated.Secp256k1Fp](api)
+	if err != nil {
+		return res, fmt.Errorf("new base field: %w", err)
+	}
+	msgBits := make([]frontend.Variable, 0, 256)
+	for i := range hash {
+		msgBits = append(msgBits, bits.ToBinary(api, hash[len(hash)-1-i].Val, bits.WithNbDigits(8))...)
+	}
+	msg := fr.FromBits(msgBits...)
+	pk := evmprecompiles.ECRecover(api, *msg, api.Add(sig.YParity, 27), sig.R, sig.S, 1, 0)
+
+	// the address is the last 20 bytes of the hash of the canonical
+	// big-endian coordinates of the public key.
+	var pkBytes []uints.U8
+	for _, c := range []*emulated.Element[emulated.Secp256k1Fp]{&pk.X, &pk.Y} {
+		cr := fp.Reduce(c)
+		fp.AssertIsInRange(cr)
+		cb := fp.ToBits(cr)
+		for i := 31; i >= 0; i-- {
+			pkBytes = append(pkBytes, uints.U8{Val: bits.FromBinary(api, cb[8*i:8*i+8], bits.WithUnconstrainedInputs())})
+		}
+	}
+	h, err := sha3.NewLegacyKeccak256(api)
+	if err != nil {
+		return res, fmt.Errorf("new keccak: %w", err)
+	}
+	h.Write(pkBytes)
+	copy(res[:], h.Sum()[12:])
+	return res, nil
+}
+
+// encodeTo encodes the recipient, which is the empty string for contract
+// creations.
+func encodeTo(api frontend.API, e *rlp.Encoder, to [20]uints.U8, isCreate frontend.Variable) rlp.Encoded {
+	api.AssertIsBoolean(isCreate)
+	return e.String(to[:], api.Mul(api.Sub(1, isCreate), 20))
+}
+
+// encodeAccessList encodes the access list as the list of the lists
+// [address, [storageKeys...]].
+func encodeAccessList(api frontend.API, e *rlp.Encoder, al *AccessList) rlp.Encoded {
+	tuples := make([]rlp.Encoded, len(al.Tuples))
+	for i := range al.Tuples {
+		t := &al.Tuples[i]
+		keys := make([]rlp.Encoded, len(t.StorageKeys))
+		for j := range t.StorageKeys {
+			keys[j] = e.String(t.StorageKeys[j][:], 32)
+		}
+		tuples[i] = e.List(
+			e.String(t.Address[:], 20),
+			e.List(truncate(api, keys, t.NbStorageKeys)...),
+		)
+	}
+	return e.List(truncate(api, tuples, al.NbTuples)...)
+}
+
+// truncate returns the items with the items after the first n emptied. It
+// asserts that n is at most len(items).
+func truncate(api frontend.API, items []rlp.Encoded, n frontend.Variable) []rlp.Encoded {
+	mask := selector.PrefixMask(api, n, len(items))
+	res := make([]rlp.Encoded, len(items))
+	for i := range items {
+		bs := make([]uints.U8, len(items[i].Bytes))
+		for j := range bs {
+			bs[j] = uints.U8{Val: api.Mul(mask[i], items[i].Bytes[j].Val)}
+		}
+		res[i] = rlp.Encoded{Bytes: bs, Length: api.Mul(mask[i], items[i].Length)}
+	}
+	return res
+}
+
+// typePrefix returns the EIP-2718 transaction type prefix.
+func typePrefix(txType uint8) rlp.Encoded {
+	return rlp.Encoded{Bytes: []uints.U8{uints.NewU8(txType)}, Length: 1}
+}
+
+// keccak returns the Keccak-256 hash of the encoded string.
+func keccak(api frontend.API, enc rlp.Encoded) ([]uints.U8, error) {
+	bh, err := sha3.NewLegacyKeccak256(api)
+	if err != nil {
+		return nil, fmt.Errorf("new keccak: %w", err)
+	}
+	h, ok := bh.(hash.BinaryFixedLengthHasher)
+	if !ok {
+		return nil, fmt.Errorf("keccak does not support variable length inputs")
+	}
+	h.Write(enc.Bytes)
+	return h.FixedLengthSum(enc.Length), nil
+}
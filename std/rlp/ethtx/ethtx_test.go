@@ -0,0 +1,264 @@
+package ethtx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/ecdsa"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/sha3"
+)
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func bytes32(v *big.Int) (res [32]uints.U8) {
+	copy(res[:], uints.NewU8Array(v.FillBytes(make([]byte, 32))))
+	return
+}
+
+func address(b []byte) (res [20]uints.U8) {
+	copy(res[:], uints.NewU8Array(b))
+	return
+}
+
+func keccakNative(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// encodeNative returns the RLP encoding of v, which is a byte string or a
+// list of values.
+func encodeNative(v any) []byte {
+	var payload []byte
+	var offset byte
+	switch vv := v.(type) {
+	case []byte:
+		if len(vv) == 1 && vv[0] < 0x80 {
+			return vv
+		}
+		payload, offset = vv, 0x80
+	case []any:
+		for _, item := range vv {
+			payload = append(payload, encodeNative(item)...)
+		}
+		offset = 0xc0
+	default:
+		panic("unsupported type")
+	}
+	if len(payload) <= 55 {
+		return append([]byte{offset + byte(len(payload))}, payload...)
+	}
+	l := big.NewInt(int64(len(payload))).Bytes()
+	res := append([]byte{offset + 55 + byte(len(l))}, l...)
+	return append(res, payload...)
+}
+
+type legacyCircuit struct {
+	Tx        LegacyTx
+	ChainID   frontend.Variable
+	Signature Signature
+	Hash      [32]uints.U8
+	Sender    [20]uints.U8
+}
+
+func (c *legacyCircuit) Define(api frontend.API) error {
+	h, err := LegacySigningHash(api, &c.Tx, c.ChainID)
+	if err != nil {
+		return err
+	}
+	for i := range h {
+		api.AssertIsEqual(h[i].Val, c.Hash[i].Val)
+	}
+	sender, err := RecoverSender(api, h, &c.Signature)
+	if err != nil {
+		return err
+	}
+	for i := range sender {
+		api.AssertIsEqual(sender[i].Val, c.Sender[i].Val)
+	}
+	return nil
+}
+
+func TestLegacyEIP155(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the example of EIP-155 signed with the private key 0x4646...46.
+	r, _ := new(big.Int).SetString("18515461264373351373200002665853028612451056578545711640558177340181847433846", 10)
+	s, _ := new(big.Int).SetString("46948507304638947509940763649030358759909902576025900602547168820602576006531", 10)
+	circuit := legacyCircuit{Tx: PlaceholderLegacyTx(4)}
+	assignment := legacyCircuit{
+		Tx: LegacyTx{
+			Nonce:      9,
+			GasPrice:   bytes32(big.NewInt(20_000_000_000)),
+			Gas:        21000,
+			To:         address(mustHex("3535353535353535353535353535353535353535")),
+			IsCreate:   0,
+			Value:      bytes32(big.NewInt(1_000_000_000_000_000_000)),
+			Data:       uints.NewU8Array(make([]byte, 4)),
+			DataLength: 0,
+		},
+		ChainID: 1,
+		Signature: Signature{
+			YParity: 0, // v = 37 = 35 + 2*1 + 0
+			R:       emulated.ValueOf[emulated.Secp256k1Fr](r),
+			S:       emulated.ValueOf[emulated.Secp256k1Fr](s),
+		},
+		Sender: address(mustHex("9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f")),
+	}
+	copy(assignment.Hash[:], uints.NewU8Array(mustHex("daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53")))
+	err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+type dynamicFeeCircuit struct {
+	Tx        DynamicFeeTx
+	Signature Signature
+	Sender    [20]uints.U8
+}
+
+func (c *dynamicFeeCircuit) Define(api frontend.API) error {
+	h, err := DynamicFeeSigningHash(api, &c.Tx)
+	if err != nil {
+		return err
+	}
+	sender, err := RecoverSender(api, h, &c.Signature)
+	if err != nil {
+		return err
+	}
+	for i := range sender {
+		api.AssertIsEqual(sender[i].Val, c.Sender[i].Val)
+	}
+	return nil
+}
+
+type accessListCircuit struct {
+	Tx   AccessListTx
+	Hash [32]uints.U8
+}
+
+func (c *accessListCircuit) Define(api frontend.API) error {
+	h, err := AccessListSigningHash(api, &c.Tx)
+	if err != nil {
+		return err
+	}
+	for i := range h {
+		api.AssertIsEqual(h[i].Val, c.Hash[i].Val)
+	}
+	return nil
+}
+
+func TestTypedTransactions(t *testing.T) {
+	assert := test.NewAssert(t)
+	const maxData, maxTuples, maxKeys = 80, 2, 2
+	to := mustHex("00000000219ab540356cbb839cbe05303d7705fa")
+	data := make([]byte, 68)
+	for i := range data {
+		data[i] = byte(3 * i)
+	}
+	addr := mustHex("dac17f958d2ee523a2206206994597c13d831ec7")
+	key := keccakNative([]byte("slot"))
+	nativeAccessList := []any{[]any{addr, []any{key}}}
+	accessList := PlaceholderAccessList(maxTuples, maxKeys)
+	accessList.NbTuples = 1
+	for i := range accessList.Tuples {
+		accessList.Tuples[i].Address = address(addr)
+		accessList.Tuples[i].NbStorageKeys = 0
+		for j := range accessList.Tuples[i].StorageKeys {
+			copy(accessList.Tuples[i].StorageKeys[j][:], uints.NewU8Array(key))
+		}
+	}
+	accessList.Tuples[0].NbStorageKeys = 1
+	value, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	paddedData := uints.NewU8Array(append(append([]byte{}, data...), make([]byte, maxData-len(data))...))
+
+	// EIP-1559 transaction creating a contract, with the sender recovered from
+	// the signature.
+	chainID := big.NewInt(1)
+	enc := encodeNative([]any{
+		chainID.Bytes(), big.NewInt(7).Bytes(), big.NewInt(1_000_000_000).Bytes(), big.NewInt(30_000_000_000).Bytes(),
+		big.NewInt(100_000).Bytes(), []byte{}, value.Bytes(), data, nativeAccessList,
+	})
+	hash := keccakNative([]byte{DynamicFeeTxType}, enc)
+	sk, err := ecdsa.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	halfFr := new(big.Int).Rsh(fr.Modulus(), 1)
+	var v uint
+	var r, s *big.Int
+	for {
+		v, r, s, err = sk.SignForRecover(hash, nil)
+		assert.NoError(err)
+		if s.Cmp(halfFr) <= 0 {
+			break
+		}
+	}
+	pkx, pky := sk.PublicKey.A.X.Bytes(), sk.PublicKey.A.Y.Bytes()
+	sender := keccakNative(pkx[:], pky[:])[12:]
+	dfCircuit := dynamicFeeCircuit{Tx: PlaceholderDynamicFeeTx(maxData, maxTuples, maxKeys)}
+	dfAssignment := dynamicFeeCircuit{
+		Tx: DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      7,
+			GasTipCap:  bytes32(big.NewInt(1_000_000_000)),
+			GasFeeCap:  bytes32(big.NewInt(30_000_000_000)),
+			Gas:        100_000,
+			To:         address(to),
+			IsCreate:   1,
+			Value:      bytes32(value),
+			Data:       paddedData,
+			DataLength: len(data),
+			AccessList: accessList,
+		},
+		Signature: Signature{
+			YParity: v,
+			R:       emulated.ValueOf[emulated.Secp256k1Fr](r),
+			S:       emulated.ValueOf[emulated.Secp256k1Fr](s),
+		},
+		Sender: address(sender),
+	}
+	err = test.IsSolved(&dfCircuit, &dfAssignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	wrong := dfAssignment
+	wrong.Tx.Nonce = 8
+	err = test.IsSolved(&dfCircuit, &wrong, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// EIP-2930 transaction.
+	enc = encodeNative([]any{
+		big.NewInt(5).Bytes(), []byte{}, big.NewInt(20_000_000_000).Bytes(),
+		big.NewInt(50_000).Bytes(), to, []byte{}, data[:3], nativeAccessList,
+	})
+	alCircuit := accessListCircuit{Tx: PlaceholderAccessListTx(maxData, maxTuples, maxKeys)}
+	alAssignment := accessListCircuit{
+		Tx: AccessListTx{
+			ChainID:    5,
+			Nonce:      0,
+			GasPrice:   bytes32(big.NewInt(20_000_000_000)),
+			Gas:        50_000,
+			To:         address(to),
+			IsCreate:   0,
+			Value:      bytes32(big.NewInt(0)),
+			Data:       paddedData,
+			DataLength: 3,
+			AccessList: accessList,
+		},
+	}
+	copy(alAssignment.Hash[:], uints.NewU8Array(keccakNative([]byte{AccessListTxType}, enc)))
+	err = test.IsSolved(&alCircuit, &alAssignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
@@ -0,0 +1,44 @@
+package secretsharing
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/algopts"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// nbIndexBits is the maximal bit length of the indices of the parties in
+// [AssertFeldman].
+const nbIndexBits = 32
+
+// AssertFeldman asserts that the share of the party of the given index is
+// consistent with the Feldman commitments to the coefficients of the
+// polynomial, in increasing degree order. That is
+//
+//	[share]G = Σ_j [index^j]C_j.
+//
+// The index must be positive and less than 2^32, which is asserted. The
+// options are passed to the scalar multiplications of the curve, for instance
+// to use complete arithmetic.
+func AssertFeldman[FR emulated.FieldParams, G1El algebra.G1ElementT](api frontend.API, curve algebra.Curve[FR, G1El], commitments []*G1El, index frontend.Variable, share *emulated.Element[FR], opts ...algopts.AlgebraOption) error {
+	if len(commitments) == 0 {
+		return fmt.Errorf("no commitments")
+	}
+	f, err := emulated.NewField[FR](api)
+	if err != nil {
+		return fmt.Errorf("new scalar field: %w", err)
+	}
+	api.AssertIsDifferent(index, 0)
+	i := f.FromBits(bits.ToBinary(api, index, bits.WithNbDigits(nbIndexBits))...)
+	// the sum is computed with the Horner scheme.
+	msmOpts := append([]algopts.AlgebraOption{algopts.WithFoldingScalarMul(), algopts.WithNbScalarBits(nbIndexBits)}, opts...)
+	expected, err := curve.MultiScalarMul(commitments, []*emulated.Element[FR]{i}, msmOpts...)
+	if err != nil {
+		return fmt.Errorf("multi scalar mul: %w", err)
+	}
+	curve.AssertIsEqual(curve.ScalarMulBase(share, opts...), expected)
+	return nil
+}
@@ -0,0 +1,80 @@
+// Package secretsharing implements in-circuit verification of Shamir secret
+// sharing and of Feldman verifiable secret sharing.
+//
+// In Shamir secret sharing with threshold t, the dealer samples a polynomial P
+// of degree t-1 whose constant term is the secret and gives the share P(i) to
+// the party of index i. Any t shares determine the secret by Lagrange
+// interpolation at zero, while fewer shares reveal nothing about it.
+//
+// The shares of [AssertShares] and [Reconstruct] are in the native field. For
+// Feldman verifiable secret sharing, the dealer publishes the commitments
+// [a_j]G to the coefficients of P in a group of prime order and the parties
+// check their shares with [AssertFeldman]. There, the shares are scalars of the
+// group, which is any curve implementing [algebra.Curve], such as the twisted
+// Edwards curves of [twistededwards.NewAlgebraCurve] or the short Weierstrass
+// curves of [sw_emulated.New]. All the twisted Edwards curves of gnark are
+// supported, with the matching scalar fields of the emparams package, for
+// instance [emparams.BabyJubjubFr] or [emparams.BandersnatchFr].
+//
+// [twistededwards.NewAlgebraCurve]: https://pkg.go.dev/github.com/consensys/gnark/std/algebra/native/twistededwards#NewAlgebraCurve
+// [sw_emulated.New]: https://pkg.go.dev/github.com/consensys/gnark/std/algebra/emulated/sw_emulated#New
+// [emparams.BabyJubjubFr]: https://pkg.go.dev/github.com/consensys/gnark/std/math/emulated/emparams#BabyJubjubFr
+// [emparams.BandersnatchFr]: https://pkg.go.dev/github.com/consensys/gnark/std/math/emulated/emparams#BandersnatchFr
+package secretsharing
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/polynomial"
+)
+
+// AssertShares asserts that the shares are the evaluations of the polynomial
+// with the given coefficients, in increasing degree order, at the indices.
+func AssertShares(api frontend.API, coefficients polynomial.Polynomial, indices, shares []frontend.Variable) error {
+	if len(indices) != len(shares) {
+		return fmt.Errorf("mismatching indices and shares lengths")
+	}
+	for i := range shares {
+		api.AssertIsEqual(shares[i], coefficients.Eval(api, indices[i]))
+	}
+	return nil
+}
+
+// LagrangeCoefficients returns the Lagrange coefficients at zero of the
+// indices, such that the secret is the sum of the products of the shares by
+// the coefficients. It asserts that the indices are distinct.
+func LagrangeCoefficients(api frontend.API, indices []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(indices))
+	for i := range indices {
+		// λ_i = Π_{j≠i} x_j / (x_j - x_i)
+		var num, den frontend.Variable = 1, 1
+		for j := range indices {
+			if j == i {
+				continue
+			}
+			num = api.Mul(num, indices[j])
+			den = api.Mul(den, api.Sub(indices[j], indices[i]))
+		}
+		// the division asserts that the denominator is not zero.
+		res[i] = api.Div(num, den)
+	}
+	return res
+}
+
+// Reconstruct returns the secret shared by the shares at the indices, which
+// must be at least the threshold. It asserts that the indices are distinct.
+func Reconstruct(api frontend.API, indices, shares []frontend.Variable) (frontend.Variable, error) {
+	if len(indices) != len(shares) {
+		return nil, fmt.Errorf("mismatching indices and shares lengths")
+	}
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("no shares")
+	}
+	lambdas := LagrangeCoefficients(api, indices)
+	var res frontend.Variable = 0
+	for i := range shares {
+		res = api.Add(res, api.Mul(lambdas[i], shares[i]))
+	}
+	return res, nil
+}
@@ -0,0 +1,195 @@
+package secretsharing
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/encryption/elgamal"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/test"
+)
+
+// deal returns the coefficients of a random polynomial of degree t-1 modulo
+// the modulus and the shares of the parties 1 to n.
+func deal(modulus *big.Int, t, n int) (coefficients, shares []*big.Int) {
+	coefficients = make([]*big.Int, t)
+	for i := range coefficients {
+		coefficients[i], _ = rand.Int(rand.Reader, modulus)
+	}
+	shares = make([]*big.Int, n)
+	for i := range shares {
+		// Horner evaluation at i+1.
+		s := new(big.Int)
+		for j := t - 1; j >= 0; j-- {
+			s.Mul(s, big.NewInt(int64(i+1))).Add(s, coefficients[j]).Mod(s, modulus)
+		}
+		shares[i] = s
+	}
+	return coefficients, shares
+}
+
+type shamirCircuit struct {
+	Coefficients []frontend.Variable
+	Indices      []frontend.Variable `gnark:",public"`
+	Shares       []frontend.Variable `gnark:",public"`
+	Subset       []frontend.Variable
+	SubsetShares []frontend.Variable
+}
+
+func (c *shamirCircuit) Define(api frontend.API) error {
+	if err := AssertShares(api, c.Coefficients, c.Indices, c.Shares); err != nil {
+		return err
+	}
+	secret, err := Reconstruct(api, c.Subset, c.SubsetShares)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(secret, c.Coefficients[0])
+	return nil
+}
+
+func TestShamir(t *testing.T) {
+	assert := test.NewAssert(t)
+	const threshold, n = 3, 5
+	coefficients, shares := deal(ecc.BN254.ScalarField(), threshold, n)
+	subset := []int{4, 1, 5}
+	witness := func(subset []int) *shamirCircuit {
+		w := &shamirCircuit{}
+		for i := range coefficients {
+			w.Coefficients = append(w.Coefficients, coefficients[i])
+		}
+		for i := range shares {
+			w.Indices = append(w.Indices, i+1)
+			w.Shares = append(w.Shares, shares[i])
+		}
+		for _, i := range subset {
+			w.Subset = append(w.Subset, i)
+			w.SubsetShares = append(w.SubsetShares, shares[i-1])
+		}
+		return w
+	}
+	circuit := shamirCircuit{
+		Coefficients: make([]frontend.Variable, threshold),
+		Indices:      make([]frontend.Variable, n),
+		Shares:       make([]frontend.Variable, n),
+		Subset:       make([]frontend.Variable, threshold),
+		SubsetShares: make([]frontend.Variable, threshold),
+	}
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(witness(subset)), test.WithCurves(ecc.BN254))
+
+	// the indices must be distinct.
+	err := test.IsSolved(&circuit, witness([]int{4, 4, 5}), ecc.BN254.ScalarField())
+	assert.Error(err)
+	// the shares must be the evaluations of the polynomial.
+	bad := witness(subset)
+	bad.Shares[2] = new(big.Int).Add(shares[2], big.NewInt(1))
+	err = test.IsSolved(&circuit, bad, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type feldmanTwistedEdwardsCircuit[S emulated.FieldParams] struct {
+	curveID     tedwards.ID
+	Commitments []twistededwards.Point
+	Index       frontend.Variable
+	Share       emulated.Element[S]
+}
+
+func (c *feldmanTwistedEdwardsCircuit[S]) Define(api frontend.API) error {
+	curve, err := twistededwards.NewAlgebraCurve[S](api, c.curveID)
+	if err != nil {
+		return err
+	}
+	commitments := make([]*twistededwards.Point, len(c.Commitments))
+	for i := range commitments {
+		commitments[i] = &c.Commitments[i]
+	}
+	return AssertFeldman(api, curve, commitments, c.Index, &c.Share)
+}
+
+func testFeldmanTwistedEdwards[S emulated.FieldParams](assert *test.Assert, id tedwards.ID, field *big.Int) {
+	const threshold, n = 3, 4
+	params, err := twistededwards.GetCurveParams(id)
+	assert.NoError(err)
+	curve, err := elgamal.NewNative(id)
+	assert.NoError(err)
+	coefficients, shares := deal(params.Order, threshold, n)
+	commitments := make([]twistededwards.Point, threshold)
+	for j := range coefficients {
+		commitments[j] = curve.ScalarMul(curve.Base(), coefficients[j]).Point()
+	}
+	circuit := feldmanTwistedEdwardsCircuit[S]{curveID: id, Commitments: make([]twistededwards.Point, threshold)}
+	for i := range shares {
+		witness := feldmanTwistedEdwardsCircuit[S]{Commitments: commitments, Index: i + 1, Share: emulated.ValueOf[S](shares[i])}
+		err := test.IsSolved(&circuit, &witness, field)
+		assert.NoError(err, i)
+		witness.Index = (i+1)%n + 1
+		err = test.IsSolved(&circuit, &witness, field)
+		assert.Error(err, i)
+		witness.Index = i + 1
+		witness.Share = emulated.ValueOf[S](shares[(i+1)%n])
+		err = test.IsSolved(&circuit, &witness, field)
+		assert.Error(err, i)
+	}
+	// the index zero would reveal the secret.
+	witness := feldmanTwistedEdwardsCircuit[S]{Commitments: commitments, Index: 0, Share: emulated.ValueOf[S](coefficients[0])}
+	err = test.IsSolved(&circuit, &witness, field)
+	assert.Error(err)
+}
+
+func TestFeldmanTwistedEdwards(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		testFeldmanTwistedEdwards[emparams.BandersnatchFr](assert, tedwards.BLS12_381_BANDERSNATCH, ecc.BLS12_381.ScalarField())
+	}, "bandersnatch")
+	assert.Run(func(assert *test.Assert) {
+		testFeldmanTwistedEdwards[emparams.BabyJubjubFr](assert, tedwards.BN254, ecc.BN254.ScalarField())
+	}, "babyjubjub")
+}
+
+type feldmanWeierstrassCircuit struct {
+	Commitments []sw_emulated.AffinePoint[emulated.Secp256k1Fp]
+	Index       frontend.Variable
+	Share       emulated.Element[emulated.Secp256k1Fr]
+}
+
+func (c *feldmanWeierstrassCircuit) Define(api frontend.API) error {
+	curve, err := sw_emulated.New[emulated.Secp256k1Fp, emulated.Secp256k1Fr](api, sw_emulated.GetSecp256k1Params())
+	if err != nil {
+		return err
+	}
+	commitments := make([]*sw_emulated.AffinePoint[emulated.Secp256k1Fp], len(c.Commitments))
+	for i := range commitments {
+		commitments[i] = &c.Commitments[i]
+	}
+	return AssertFeldman(api, curve, commitments, c.Index, &c.Share)
+}
+
+func TestFeldmanWeierstrass(t *testing.T) {
+	assert := test.NewAssert(t)
+	const threshold, n = 3, 3
+	_, g := secp256k1.Generators()
+	coefficients, shares := deal(ecc.SECP256K1.ScalarField(), threshold, n)
+	commitments := make([]sw_emulated.AffinePoint[emulated.Secp256k1Fp], threshold)
+	for j := range coefficients {
+		var p secp256k1.G1Affine
+		p.ScalarMultiplication(&g, coefficients[j])
+		commitments[j] = sw_emulated.AffinePoint[emulated.Secp256k1Fp]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](p.X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](p.Y),
+		}
+	}
+	for i := range shares {
+		circuit := feldmanWeierstrassCircuit{Commitments: make([]sw_emulated.AffinePoint[emulated.Secp256k1Fp], threshold)}
+		witness := feldmanWeierstrassCircuit{Commitments: commitments, Index: i + 1, Share: emulated.ValueOf[emulated.Secp256k1Fr](shares[i])}
+		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, i)
+	}
+}
@@ -56,6 +56,28 @@ func Partition(api frontend.API, pivotPosition frontend.Variable, rightSide bool
 	return
 }
 
+// PrefixMask returns a mask of length n whose first length elements are equal
+// to 1 and the remaining elements are equal to 0. More precisely, for each i we
+// have:
+//
+//	if i < length
+//	    out[i] = 1
+//	else
+//	    out[i] = 0
+//
+// We must have length >= 0 and length <= n, otherwise a proof cannot be generated.
+func PrefixMask(api frontend.API, length frontend.Variable, n int) []frontend.Variable {
+	switch n {
+	case 0:
+		api.AssertIsEqual(length, 0)
+		return nil
+	case 1:
+		api.AssertIsBoolean(length)
+		return []frontend.Variable{length}
+	}
+	return stepMask(api, n, length, 1, 0)
+}
+
 // stepMask generates a step like function into an output array of a given length.
 // The output is an array of length outputLen,
 // such that its first stepPosition elements are equal to startValue and the remaining elements are equal to
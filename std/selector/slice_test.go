@@ -203,3 +203,59 @@ func TestSlice(t *testing.T) {
 		WantSlice: [7]frontend.Variable{0, 1, 0, 0, 0, 0, 0},
 	})
 }
+
+type prefixMaskCircuit struct {
+	Length   frontend.Variable    `gnark:",public"`
+	WantMask [5]frontend.Variable `gnark:",public"`
+	Single   frontend.Variable    `gnark:",public"`
+}
+
+func (c *prefixMaskCircuit) Define(api frontend.API) error {
+	gotMask := selector.PrefixMask(api, c.Length, len(c.WantMask))
+	for i, want := range c.WantMask {
+		api.AssertIsEqual(gotMask[i], want)
+	}
+	api.AssertIsEqual(selector.PrefixMask(api, c.Single, 1)[0], c.Single)
+	_ = selector.PrefixMask(api, 0, 0)
+	return nil
+}
+
+func TestPrefixMask(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	assert.ProverSucceeded(&prefixMaskCircuit{}, &prefixMaskCircuit{
+		Length:   3,
+		WantMask: [5]frontend.Variable{1, 1, 1, 0, 0},
+		Single:   1,
+	})
+
+	assert.ProverSucceeded(&prefixMaskCircuit{}, &prefixMaskCircuit{
+		Length:   0,
+		WantMask: [5]frontend.Variable{0, 0, 0, 0, 0},
+		Single:   0,
+	})
+
+	assert.ProverSucceeded(&prefixMaskCircuit{}, &prefixMaskCircuit{
+		Length:   5,
+		WantMask: [5]frontend.Variable{1, 1, 1, 1, 1},
+		Single:   1,
+	})
+
+	assert.ProverFailed(&prefixMaskCircuit{}, &prefixMaskCircuit{
+		Length:   6,
+		WantMask: [5]frontend.Variable{1, 1, 1, 1, 1},
+		Single:   1,
+	})
+
+	assert.ProverFailed(&prefixMaskCircuit{}, &prefixMaskCircuit{
+		Length:   -1,
+		WantMask: [5]frontend.Variable{0, 0, 0, 0, 0},
+		Single:   0,
+	})
+
+	assert.ProverFailed(&prefixMaskCircuit{}, &prefixMaskCircuit{
+		Length:   2,
+		WantMask: [5]frontend.Variable{1, 1, 0, 0, 0},
+		Single:   2,
+	})
+}
@@ -0,0 +1,103 @@
+package semaphore
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// The BLAKE-512 hash function of the SHA-3 competition, which derives the
+// secret scalars of the EdDSA keys of circomlib and of Semaphore. It is not
+// BLAKE2b.
+
+var blakeIV = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+var blakeC = [16]uint64{
+	0x243f6a8885a308d3, 0x13198a2e03707344, 0xa4093822299f31d0, 0x082efa98ec4e6c89,
+	0x452821e638d01377, 0xbe5466cf34e90c6c, 0xc0ac29b7c97c50dd, 0x3f84d5b5b5470917,
+	0x9216d5d98979fb1b, 0xd1310ba698dfb5ac, 0x2ffd72dbd01adfb7, 0xb8e1afed6a267e96,
+	0xba7c9045f12c7f99, 0x24a19947b3916cf7, 0x0801f2e2858efc16, 0x636920d871574e69,
+}
+
+var blakeSigma = [10][16]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+// blake512 returns the BLAKE-512 hash of msg.
+func blake512(msg []byte) [64]byte {
+	nbBits := uint64(len(msg)) * 8
+	// the padding is a 1 bit, zeros and a 1 bit followed by the 128-bit
+	// length of the message in bits.
+	padded := append(append([]byte{}, msg...), 0x80)
+	for len(padded)%128 != 112 {
+		padded = append(padded, 0)
+	}
+	padded[len(padded)-1] |= 0x01
+	padded = binary.BigEndian.AppendUint64(padded, 0)
+	padded = binary.BigEndian.AppendUint64(padded, nbBits)
+
+	h := blakeIV
+	for i := 0; i < len(padded)/128; i++ {
+		// the counter is the number of message bits up to the end of the
+		// block, or zero if the block contains only padding.
+		var t uint64
+		if start := uint64(i) * 1024; start < nbBits {
+			t = min(nbBits, start+1024)
+		}
+		blakeCompress(&h, padded[i*128:(i+1)*128], t)
+	}
+	var res [64]byte
+	for i := range h {
+		binary.BigEndian.PutUint64(res[8*i:], h[i])
+	}
+	return res
+}
+
+// compress applies the compression function to the chain value h with the
+// block and the counter t of the hashed message bits.
+func blakeCompress(h *[8]uint64, block []byte, t uint64) {
+	var m [16]uint64
+	for i := range m {
+		m[i] = binary.BigEndian.Uint64(block[8*i:])
+	}
+	var v [16]uint64
+	copy(v[:8], h[:])
+	copy(v[8:], blakeC[:8])
+	v[12] ^= t
+	v[13] ^= t
+	g := func(r, i, a, b, c, d int) {
+		s := &blakeSigma[r%10]
+		v[a] += v[b] + (m[s[2*i]] ^ blakeC[s[2*i+1]])
+		v[d] = bits.RotateLeft64(v[d]^v[a], -32)
+		v[c] += v[d]
+		v[b] = bits.RotateLeft64(v[b]^v[c], -25)
+		v[a] += v[b] + (m[s[2*i+1]] ^ blakeC[s[2*i]])
+		v[d] = bits.RotateLeft64(v[d]^v[a], -16)
+		v[c] += v[d]
+		v[b] = bits.RotateLeft64(v[b]^v[c], -11)
+	}
+	for r := 0; r < 16; r++ {
+		g(r, 0, 0, 4, 8, 12)
+		g(r, 1, 1, 5, 9, 13)
+		g(r, 2, 2, 6, 10, 14)
+		g(r, 3, 3, 7, 11, 15)
+		g(r, 4, 0, 5, 10, 15)
+		g(r, 5, 1, 6, 11, 12)
+		g(r, 6, 2, 7, 8, 13)
+		g(r, 7, 3, 4, 9, 14)
+	}
+	for i := range h {
+		h[i] ^= v[i] ^ v[i+8]
+	}
+}
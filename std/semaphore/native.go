@@ -0,0 +1,198 @@
+package semaphore
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gnark/std/internal/babyjubjub"
+	"golang.org/x/crypto/sha3"
+)
+
+// Identity is a Semaphore identity outside of the circuit.
+type Identity struct {
+	secret *big.Int
+}
+
+// NewIdentity returns the identity with the secret scalar, that is the
+// secretScalar of the Semaphore identity derived from its private key. It
+// returns an error if the secret is not smaller than the order of the
+// subgroup of Baby Jubjub.
+func NewIdentity(secret *big.Int) (*Identity, error) {
+	params := edbn254.GetEdwardsCurve()
+	if secret.Sign() < 0 || secret.Cmp(&params.Order) >= 0 {
+		return nil, errors.New("secret is not smaller than the subgroup order")
+	}
+	return &Identity{secret: new(big.Int).Set(secret)}, nil
+}
+
+// NewIdentityFromPrivateKey returns the identity with the private key, as the
+// Identity of @semaphore-protocol/identity. The secret scalar is derived with
+// [DeriveSecretScalar]. A text private key in JavaScript is given by its UTF-8
+// encoding.
+func NewIdentityFromPrivateKey(privateKey []byte) *Identity {
+	return &Identity{secret: DeriveSecretScalar(privateKey)}
+}
+
+// DeriveSecretScalar returns the secret scalar of the Baby Jubjub EdDSA private
+// key, as deriveSecretScalar of @zk-kit/eddsa-poseidon. The first 32 bytes of
+// the BLAKE-512 hash of the private key are pruned as in EdDSA, read in
+// little-endian order, shifted right by 3 bits and reduced modulo the order of
+// the subgroup.
+func DeriveSecretScalar(privateKey []byte) *big.Int {
+	h := blake512(privateKey)
+	buf := h[:32]
+	buf[0] &= 0xf8
+	buf[31] &= 0x7f
+	buf[31] |= 0x40
+	for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	res := new(big.Int).SetBytes(buf)
+	res.Rsh(res, 3)
+	params := edbn254.GetEdwardsCurve()
+	return res.Mod(res, &params.Order)
+}
+
+// Secret returns the secret scalar of the identity.
+func (id *Identity) Secret() *big.Int {
+	return new(big.Int).Set(id.secret)
+}
+
+// PublicKey returns the public key of the identity in the circomlib
+// coordinates of Baby Jubjub.
+func (id *Identity) PublicKey() (x, y *big.Int) {
+	params := edbn254.GetEdwardsCurve()
+	var pk edbn254.PointAffine
+	pk.ScalarMultiplication(&params.Base, id.secret)
+	return babyjubjub.NativeToCircomlib(&pk)
+}
+
+// Commitment returns the identity commitment.
+func (id *Identity) Commitment() *big.Int {
+	res, err := poseidon.Hash(id.PublicKey())
+	if err != nil {
+		// the coordinates are reduced
+		panic(err)
+	}
+	return res
+}
+
+// Nullifier returns the nullifier of the identity in the scope, which must be
+// a reduced field element, as the output of [Hash].
+func (id *Identity) Nullifier(scope *big.Int) (*big.Int, error) {
+	return poseidon.Hash(scope, id.secret)
+}
+
+// Group is a Semaphore group outside of the circuit, that is a lean incremental
+// Merkle tree of identity commitments hashed with Poseidon.
+type Group struct {
+	tree *merkle.LeanTree
+}
+
+// NewGroup returns a group with the members.
+func NewGroup(members ...*big.Int) (*Group, error) {
+	g := &Group{tree: merkle.NewLeanTree(poseidon.NewNative(), fr.Modulus())}
+	for i := range members {
+		if err := g.AddMember(members[i]); err != nil {
+			return nil, fmt.Errorf("add member %d: %w", i, err)
+		}
+	}
+	return g, nil
+}
+
+// AddMember adds the identity commitment to the group. It returns an error if
+// the commitment is zero, which marks removed members in Semaphore, or is not
+// a reduced field element.
+func (g *Group) AddMember(commitment *big.Int) error {
+	if commitment.Sign() == 0 {
+		return errors.New("member cannot be zero")
+	}
+	return g.tree.Insert(commitment)
+}
+
+// Members returns the identity commitments of the group.
+func (g *Group) Members() []*big.Int {
+	return g.tree.Leaves()
+}
+
+// IndexOf returns the index of the identity commitment in the group, or -1 if
+// it is not a member.
+func (g *Group) IndexOf(commitment *big.Int) int {
+	for i, m := range g.tree.Leaves() {
+		if m.Cmp(commitment) == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// Depth returns the depth of the tree of the group.
+func (g *Group) Depth() int {
+	return g.tree.Depth()
+}
+
+// Root returns the root of the tree of the group. It returns an error if the
+// group is empty.
+func (g *Group) Root() (*big.Int, error) {
+	return g.tree.Root()
+}
+
+// Hash returns the hash of a message or a scope as in Semaphore, that is the
+// Keccak-256 hash of its 32-byte big-endian encoding shifted right by 8 bits
+// so that it is a field element. It returns an error if the value is negative
+// or does not fit in 32 bytes.
+func Hash(v *big.Int) (*big.Int, error) {
+	if v.Sign() < 0 || v.BitLen() > 256 {
+		return nil, errors.New("value does not fit in 32 bytes")
+	}
+	var buf [32]byte
+	v.FillBytes(buf[:])
+	h := sha3.NewLegacyKeccak256()
+	h.Write(buf[:])
+	res := new(big.Int).SetBytes(h.Sum(nil))
+	return res.Rsh(res, 8), nil
+}
+
+// Assignment returns the assignment of [Circuit] of the given maximal depth for
+// the identity signalling the message in the scope as a member of the group.
+// The message and the scope are hashed with [Hash].
+func Assignment(id *Identity, group *Group, message, scope *big.Int, maxDepth int) (*Circuit, error) {
+	index := group.IndexOf(id.Commitment())
+	if index < 0 {
+		return nil, errors.New("identity is not a member of the group")
+	}
+	proof, err := group.tree.Proof(index, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("merkle proof: %w", err)
+	}
+	messageHash, err := Hash(message)
+	if err != nil {
+		return nil, fmt.Errorf("message: %w", err)
+	}
+	scopeHash, err := Hash(scope)
+	if err != nil {
+		return nil, fmt.Errorf("scope: %w", err)
+	}
+	nullifier, err := id.Nullifier(scopeHash)
+	if err != nil {
+		return nil, fmt.Errorf("nullifier: %w", err)
+	}
+	res := &Circuit{
+		MerkleRoot:          proof.RootHash,
+		Nullifier:           nullifier,
+		Message:             messageHash,
+		Scope:               scopeHash,
+		Secret:              id.Secret(),
+		MerkleProofLength:   proof.Depth,
+		MerkleProofIndex:    proof.Index,
+		MerkleProofSiblings: make([]frontend.Variable, maxDepth),
+	}
+	copy(res.MerkleProofSiblings, proof.Siblings)
+	return res, nil
+}
@@ -0,0 +1,112 @@
+// Package semaphore implements the Semaphore v4 anonymous signalling protocol
+// in-circuit.
+//
+// A Semaphore identity is a Baby Jubjub key pair. Its secret is the secret
+// scalar s derived from the private key by [DeriveSecretScalar] and its public
+// key is A = [s]Base8 in the circomlib coordinates of Baby Jubjub. The
+// identity commitment H(A.x, A.y) is a leaf of the lean incremental Merkle
+// tree of a group. A member proves that it knows the
+// secret of a commitment in the group with root MerkleRoot and signals a
+// message in a scope with the nullifier H(scope, s), which is the same for
+// all the signals of the identity in the scope.
+//
+// The [Circuit] uses the circomlib Poseidon hash function of
+// [poseidon.NewPoseidon] and has the public inputs of the Semaphore v4 circom
+// circuit, with messages and scopes hashed by [Hash]. As in the circom
+// circuit, the message is squared so that it appears in a constraint and
+// cannot be changed for a given proof. The gadgets take any
+// [hash.FieldHasher] for composing them into other circuits.
+//
+// [poseidon.NewPoseidon]: https://pkg.go.dev/github.com/consensys/gnark/std/hash/poseidon#NewPoseidon
+package semaphore
+
+import (
+	"fmt"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gnark/std/internal/babyjubjub"
+)
+
+// IdentityCommitment returns the commitment H(A.x, A.y) to the public key A of
+// the secret in the circomlib coordinates of Baby Jubjub. It asserts that the
+// secret is smaller than the order of the subgroup.
+func IdentityCommitment(api frontend.API, h hash.FieldHasher, secret frontend.Variable) (frontend.Variable, error) {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	params := curve.Params()
+	api.AssertIsLessOrEqual(secret, new(big.Int).Sub(params.Order, big.NewInt(1)))
+	// the base point of gnark is Base8 of circomlib in the a = -1 form
+	pk := curve.ScalarMul(twistededwards.Point{X: params.Base[0], Y: params.Base[1]}, secret)
+	pk = babyjubjub.ToCircomlib(api, pk)
+	h.Reset()
+	h.Write(pk.X, pk.Y)
+	return h.Sum(), nil
+}
+
+// Nullifier returns the nullifier H(scope, secret) of the identity in the
+// scope.
+func Nullifier(h hash.FieldHasher, scope, secret frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(scope, secret)
+	return h.Sum()
+}
+
+// AssertSignal asserts that the identity of the secret is a member of the
+// group with root proof.RootHash and that nullifier is its nullifier in the
+// scope.
+func AssertSignal(api frontend.API, h hash.FieldHasher, secret frontend.Variable, proof merkle.LeanProof, scope, nullifier frontend.Variable) error {
+	commitment, err := IdentityCommitment(api, h, secret)
+	if err != nil {
+		return fmt.Errorf("identity commitment: %w", err)
+	}
+	proof.VerifyProof(api, h, commitment)
+	api.AssertIsEqual(Nullifier(h, scope, secret), nullifier)
+	return nil
+}
+
+// Circuit is the Semaphore v4 circuit for groups of depth at most the number of
+// siblings.
+type Circuit struct {
+	MerkleRoot frontend.Variable `gnark:",public"`
+	Nullifier  frontend.Variable `gnark:",public"`
+	Message    frontend.Variable `gnark:",public"`
+	Scope      frontend.Variable `gnark:",public"`
+
+	Secret              frontend.Variable
+	MerkleProofLength   frontend.Variable
+	MerkleProofIndex    frontend.Variable
+	MerkleProofSiblings []frontend.Variable
+}
+
+// PlaceholderCircuit returns a placeholder circuit for groups of the given
+// maximal depth to be used for compiling the circuit.
+func PlaceholderCircuit(maxDepth int) *Circuit {
+	return &Circuit{MerkleProofSiblings: make([]frontend.Variable, maxDepth)}
+}
+
+// Define declares the circuit constraints.
+func (c *Circuit) Define(api frontend.API) error {
+	h, err := poseidon.NewPoseidon(api)
+	if err != nil {
+		return fmt.Errorf("new poseidon: %w", err)
+	}
+	proof := merkle.LeanProof{
+		RootHash: c.MerkleRoot,
+		Depth:    c.MerkleProofLength,
+		Index:    c.MerkleProofIndex,
+		Siblings: c.MerkleProofSiblings,
+	}
+	// a public input which is not used in any constraint is not bound to the
+	// proof by some backends, such as Groth16. The square is the dummy
+	// constraint of the circom circuit.
+	api.Mul(c.Message, c.Message)
+	return AssertSignal(api, &h, c.Secret, proof, c.Scope, c.Nullifier)
+}
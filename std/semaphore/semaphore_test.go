@@ -0,0 +1,205 @@
+package semaphore
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	edbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gnark/test"
+)
+
+func randomIdentity(assert *test.Assert) *Identity {
+	params := edbn254.GetEdwardsCurve()
+	secret, err := rand.Int(rand.Reader, &params.Order)
+	assert.NoError(err)
+	id, err := NewIdentity(secret)
+	assert.NoError(err)
+	return id
+}
+
+func TestPublicKey(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the public key of the secret 1 is Base8 of circomlib.
+	id, err := NewIdentity(big.NewInt(1))
+	assert.NoError(err)
+	x, y := id.PublicKey()
+	assert.Equal("5299619240641551281634865583518297030282874472190772894086521144482721001553", x.String())
+	assert.Equal("16950150798460657717958625567821834550301663161624707787222815936182638968203", y.String())
+
+	// the public keys are on the circomlib curve 168700x² + y² = 1 + 168696x²y².
+	x, y = randomIdentity(assert).PublicKey()
+	modulus := fr.Modulus()
+	x2 := new(big.Int).Mul(x, x)
+	y2 := new(big.Int).Mul(y, y)
+	lhs := new(big.Int).Mul(big.NewInt(168700), x2)
+	lhs.Add(lhs, y2).Mod(lhs, modulus)
+	rhs := new(big.Int).Mul(x2, y2)
+	rhs.Mul(rhs, big.NewInt(168696)).Add(rhs, big.NewInt(1)).Mod(rhs, modulus)
+	assert.Equal(lhs, rhs)
+
+	params := edbn254.GetEdwardsCurve()
+	_, err = NewIdentity(&params.Order)
+	assert.Error(err)
+}
+
+func TestBlake512(t *testing.T) {
+	assert := test.NewAssert(t)
+	// test vectors of the BLAKE submission to the SHA-3 competition.
+	for _, v := range []struct {
+		msg      []byte
+		expected string
+	}{
+		{[]byte{}, "a8cfbbd73726062df0c6864dda65defe58ef0cc52a5625090fa17601e1eecd1b628e94f396ae402a00acc9eab77b4d4c2e852aaaa25a636d80af3fc7913ef5b8"},
+		{[]byte{0}, "97961587f6d970faba6d2478045de6d1fabd09b61ae50932054d52bc29d31be4ff9102b9f69e2bbdb83be13d4b9c06091e5fa0b48bd081b634058be0ec49beb3"},
+		{make([]byte, 144), "313717d608e9cf758dcb1eb0f0c3cf9fc150b2d500fb33f51c52afc99d358a2f1374b8a38bba7974e7f6ef79cab16f22ce1e649d6e01ad9589c213045d545dde"},
+	} {
+		h := blake512(v.msg)
+		assert.Equal(v.expected, hex.EncodeToString(h[:]), len(v.msg))
+	}
+}
+
+func TestDeriveSecretScalar(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the public key of the private key "secret" in the tests of
+	// derivePublicKey of @zk-kit/eddsa-poseidon, which Semaphore v4 uses for
+	// the identities.
+	id := NewIdentityFromPrivateKey([]byte("secret"))
+	assert.Equal("1072931509665125050858164614503996272893941281138625620671594663472720926391", id.Secret().String())
+	x, y := id.PublicKey()
+	assert.Equal("17191193026255111087474416516591393721975640005415762645730433950079177536248", x.String())
+	assert.Equal("13751717961795090314625781035919035073474308127816403910435238282697898234143", y.String())
+	expected, err := poseidon.Hash(x, y)
+	assert.NoError(err)
+	assert.Equal(expected, id.Commitment())
+	assert.NotEqual(id.Secret(), DeriveSecretScalar([]byte("secret2")))
+}
+
+func TestGroupRoot(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the root of the members 1 and 2 is the circomlib Poseidon hash of [1, 2].
+	group, err := NewGroup(big.NewInt(1), big.NewInt(2))
+	assert.NoError(err)
+	root, err := group.Root()
+	assert.NoError(err)
+	assert.Equal("7853200120776062878684798364095072458815029376092732009249414926327459813530", root.String())
+
+	// in the LeanIMT, the third member has no sibling and is its own parent.
+	assert.NoError(group.AddMember(big.NewInt(3)))
+	expected, err := poseidon.Hash(root, big.NewInt(3))
+	assert.NoError(err)
+	root, err = group.Root()
+	assert.NoError(err)
+	assert.Equal(expected, root)
+}
+
+func TestHash(t *testing.T) {
+	assert := test.NewAssert(t)
+	// keccak256 of the zero word shifted by 8 bits.
+	res, err := Hash(big.NewInt(0))
+	assert.NoError(err)
+	assert.Equal("0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e5", "0x"+res.Text(16))
+	_, err = Hash(new(big.Int).Lsh(big.NewInt(1), 256))
+	assert.Error(err)
+}
+
+func TestCircuit(t *testing.T) {
+	assert := test.NewAssert(t)
+	const maxDepth = 4
+	ids := make([]*Identity, 5)
+	group, err := NewGroup()
+	assert.NoError(err)
+	for i := range ids {
+		ids[i] = randomIdentity(assert)
+		assert.NoError(group.AddMember(ids[i].Commitment()))
+	}
+	assert.Error(group.AddMember(big.NewInt(0)))
+	message, scope := big.NewInt(42), big.NewInt(7)
+
+	valid, err := Assignment(ids[4], group, message, scope, maxDepth)
+	assert.NoError(err)
+	otherScope, err := Assignment(ids[4], group, message, big.NewInt(8), maxDepth)
+	assert.NoError(err)
+	// a nullifier of another scope.
+	wrongNullifier := *valid
+	wrongNullifier.Nullifier = otherScope.Nullifier
+	// the secret of another member.
+	wrongSecret := *valid
+	wrongSecret.Secret = ids[3].Secret()
+	// the same public key with a secret larger than the subgroup order gives
+	// another nullifier.
+	params := edbn254.GetEdwardsCurve()
+	unreduced := *valid
+	s := new(big.Int).Add(ids[4].Secret(), &params.Order)
+	unreduced.Secret = s
+	unreduced.Nullifier, err = poseidon.Hash(valid.Scope.(*big.Int), s)
+	assert.NoError(err)
+
+	assert.CheckCircuit(PlaceholderCircuit(maxDepth),
+		test.WithValidAssignment(valid),
+		test.WithInvalidAssignment(&wrongNullifier),
+		test.WithInvalidAssignment(&wrongSecret),
+		test.WithInvalidAssignment(&unreduced),
+		test.WithCurves(ecc.BN254), test.NoFuzzing())
+
+	for i := range ids {
+		assignment, err := Assignment(ids[i], group, message, scope, maxDepth)
+		assert.NoError(err)
+		err = test.IsSolved(PlaceholderCircuit(maxDepth), assignment, ecc.BN254.ScalarField())
+		assert.NoError(err, i)
+	}
+	_, err = Assignment(randomIdentity(assert), group, message, scope, maxDepth)
+	assert.Error(err)
+	_, err = Assignment(ids[0], group, message, scope, 2)
+	assert.Error(err)
+}
+
+func TestCircuitSingleMember(t *testing.T) {
+	assert := test.NewAssert(t)
+	id := randomIdentity(assert)
+	group, err := NewGroup(id.Commitment())
+	assert.NoError(err)
+	assert.Equal(0, group.Depth())
+	root, err := group.Root()
+	assert.NoError(err)
+	assert.Equal(id.Commitment(), root)
+	assignment, err := Assignment(id, group, big.NewInt(1), big.NewInt(2), 1)
+	assert.NoError(err)
+	err = test.IsSolved(PlaceholderCircuit(1), assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+func TestMessageBinding(t *testing.T) {
+	assert := test.NewAssert(t)
+	const maxDepth = 2
+	id := randomIdentity(assert)
+	group, err := NewGroup(id.Commitment(), randomIdentity(assert).Commitment())
+	assert.NoError(err)
+	assignment, err := Assignment(id, group, big.NewInt(42), big.NewInt(7), maxDepth)
+	assert.NoError(err)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, PlaceholderCircuit(maxDepth))
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	public, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, public))
+
+	// the proof does not verify for another message.
+	assignment.Message = big.NewInt(999)
+	public, err = frontend.NewWitness(assignment, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	assert.NoError(err)
+	assert.Error(groth16.Verify(proof, vk, public))
+}
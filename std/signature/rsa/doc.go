@@ -0,0 +1,12 @@
+// Package rsa implements RSA PKCS #1 v1.5 signature verification.
+//
+// The package uses the variable-modulus operations of the [emulated] package,
+// so that the modulus of the public key can be a witness. The emulation
+// parameter must be large enough to hold the modulus, for instance
+// [emparams.Mod1e2048] for RSA-2048 and [emparams.Mod1e4096] for keys up to
+// 4096 bits. Only the public exponent 65537 is supported.
+//
+// See [RFC 8017] for the signature verification algorithm.
+//
+// [RFC 8017]: https://www.rfc-editor.org/rfc/rfc8017#section-8.2.2
+package rsa
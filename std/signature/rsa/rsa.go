@@ -0,0 +1,97 @@
+package rsa
+
+import (
+	"crypto"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// E is the public exponent of the keys.
+const E = 65537
+
+// PublicKey represents the public key to verify the signature for.
+type PublicKey[T emulated.FieldParams] struct {
+	N emulated.Element[T]
+}
+
+// Signature represents the signature for some message.
+type Signature[T emulated.FieldParams] struct {
+	S emulated.Element[T]
+}
+
+// hashPrefixes are the DER encodings of the DigestInfo prefixes of the
+// supported hash functions.
+var hashPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA256:   {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384:   {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512:   {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+	crypto.SHA3_256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x08, 0x05, 0x00, 0x04, 0x20},
+}
+
+// VerifyPKCS1v15 asserts that the signature sig verifies for the digest hashed
+// of a message hashed with the hash function hash, and the public key pk. The
+// modulus of the public key must have exactly nbBits bits.
+//
+// As required by RSAVP1 in RFC 8017, the signature must be less than N.
+func (pk PublicKey[T]) VerifyPKCS1v15(api frontend.API, nbBits int, hash crypto.Hash, hashed []uints.U8, sig *Signature[T]) error {
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return fmt.Errorf("new field: %w", err)
+	}
+	var fp T
+	if nbBits%8 != 0 || nbBits < 512 || nbBits > int(fp.NbLimbs()*fp.BitsPerLimb()) {
+		return fmt.Errorf("unsupported modulus size %d", nbBits)
+	}
+	prefix, ok := hashPrefixes[hash]
+	if !ok {
+		return fmt.Errorf("unsupported hash function %s", hash)
+	}
+	if len(hashed) != hash.Size() {
+		return fmt.Errorf("expected digest of %d bytes", hash.Size())
+	}
+
+	// the modulus has exactly nbBits bits.
+	nBits := f.ToBits(f.Reduce(&pk.N))
+	for i := nbBits; i < len(nBits); i++ {
+		api.AssertIsEqual(nBits[i], 0)
+	}
+	api.AssertIsEqual(nBits[nbBits-1], 1)
+
+	// the signature representative is in the range [0, N-1], otherwise s+N
+	// would also be accepted.
+	f.AssertIsLessOrEqual(f.Reduce(&sig.S), f.Reduce(f.Sub(&pk.N, f.One())))
+
+	// m = s^e mod N, where e = 2^16+1.
+	m := &sig.S
+	for i := 0; i < 16; i++ {
+		m = f.ModMul(m, m, &pk.N)
+	}
+	m = f.ModMul(m, &sig.S, &pk.N)
+
+	// the encoded message is 0x00 || 0x01 || 0xff ... 0xff || 0x00 || prefix ||
+	// hashed, of the byte length of the modulus.
+	k := nbBits / 8
+	if k < len(prefix)+len(hashed)+11 {
+		return fmt.Errorf("modulus too short for the hash function")
+	}
+	em := make([]byte, k-len(hashed))
+	em[1] = 0x01
+	for i := 2; i < len(em)-len(prefix)-1; i++ {
+		em[i] = 0xff
+	}
+	copy(em[len(em)-len(prefix):], prefix)
+	padding := new(big.Int).SetBytes(em)
+	padding.Lsh(padding, uint(8*len(hashed)))
+	digestBits := make([]frontend.Variable, 0, 8*len(hashed))
+	for i := len(hashed) - 1; i >= 0; i-- {
+		digestBits = append(digestBits, bits.ToBinary(api, hashed[i].Val, bits.WithNbDigits(8))...)
+	}
+	expected := f.Add(f.NewElement(padding), f.FromBits(digestBits...))
+	f.ModAssertIsEqual(m, expected, &pk.N)
+	return nil
+}
@@ -0,0 +1,81 @@
+package rsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type rsaCircuit struct {
+	Pub    PublicKey[emparams.Mod1e2048]
+	Sig    Signature[emparams.Mod1e2048]
+	Hashed [32]uints.U8
+
+	nbBits int
+}
+
+func (c *rsaCircuit) Define(api frontend.API) error {
+	return c.Pub.VerifyPKCS1v15(api, c.nbBits, crypto.SHA256, c.Hashed[:], &c.Sig)
+}
+
+func TestVerifyPKCS1v15(t *testing.T) {
+	assert := test.NewAssert(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	hashed := sha256.Sum256([]byte("testing RSA PKCS #1 v1.5"))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	assert.NoError(err)
+
+	assignment := func(msg [32]byte) *rsaCircuit {
+		var res rsaCircuit
+		res.Pub.N = emulated.ValueOf[emparams.Mod1e2048](key.N)
+		res.Sig.S = emulated.ValueOf[emparams.Mod1e2048](sig)
+		copy(res.Hashed[:], uints.NewU8Array(msg[:]))
+		return &res
+	}
+	err = test.IsSolved(&rsaCircuit{nbBits: 2048}, assignment(hashed), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	wrong := hashed
+	wrong[0] ^= 1
+	err = test.IsSolved(&rsaCircuit{nbBits: 2048}, assignment(wrong), ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestVerifyPKCS1v15NonCanonical(t *testing.T) {
+	assert := test.NewAssert(t)
+	// a smaller key so that s+N fits in the emulated element.
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(err)
+	hashed := sha256.Sum256([]byte("testing RSA PKCS #1 v1.5"))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	assert.NoError(err)
+	s := new(big.Int).SetBytes(sig)
+
+	assignment := func(s *big.Int) *rsaCircuit {
+		var res rsaCircuit
+		res.Pub.N = emulated.ValueOf[emparams.Mod1e2048](key.N)
+		res.Sig.S = emulated.ValueOf[emparams.Mod1e2048](s)
+		copy(res.Hashed[:], uints.NewU8Array(hashed[:]))
+		return &res
+	}
+	err = test.IsSolved(&rsaCircuit{nbBits: 1024}, assignment(s), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// s+N is congruent to the signature, but is rejected as RSAVP1 requires
+	// s < N.
+	sN := new(big.Int).Add(s, key.N)
+	assert.Error(rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hashed[:], sN.Bytes()))
+	err = test.IsSolved(&rsaCircuit{nbBits: 1024}, assignment(sN), ecc.BN254.ScalarField())
+	assert.Error(err)
+}
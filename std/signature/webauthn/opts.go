@@ -0,0 +1,61 @@
+package webauthn
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+type config struct {
+	userVerification bool
+	rpIDHash         []byte
+	maxDepth         int
+}
+
+// Option allows to modify the behaviour of [Verify].
+type Option func(cfg *config) error
+
+// WithUserVerification requires the user verified flag of the authenticator
+// data to be set. By default only the user present flag is required.
+func WithUserVerification() Option {
+	return func(cfg *config) error {
+		cfg.userVerification = true
+		return nil
+	}
+}
+
+// WithRPID requires the authenticator data to be scoped to the relying party
+// identifier rpID. By default the relying party identifier hash is not checked.
+func WithRPID(rpID string) Option {
+	return func(cfg *config) error {
+		if rpID == "" {
+			return fmt.Errorf("empty relying party identifier")
+		}
+		h := sha256.Sum256([]byte(rpID))
+		cfg.rpIDHash = h[:]
+		return nil
+	}
+}
+
+// WithMaxDepth sets the maximum nesting depth of the client data JSON. If not
+// set, then the depth is 2, which allows for the tokenBinding object.
+func WithMaxDepth(maxDepth int) Option {
+	return func(cfg *config) error {
+		if maxDepth < 1 {
+			return fmt.Errorf("invalid maximum depth %d", maxDepth)
+		}
+		cfg.maxDepth = maxDepth
+		return nil
+	}
+}
+
+func newCfg(opts ...Option) (*config, error) {
+	cfg := &config{
+		maxDepth: 2,
+	}
+	for i := range opts {
+		if err := opts[i](cfg); err != nil {
+			return nil, fmt.Errorf("option %d: %w", i, err)
+		}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,174 @@
+// Package webauthn implements verification of WebAuthn assertions, as produced
+// by passkeys.
+//
+// An assertion consists of the authenticator data, the client data JSON and an
+// ECDSA P-256 signature over SHA-256(authenticatorData ||
+// SHA-256(clientDataJSON)). The verifier parses the client data JSON to check
+// the type and the challenge, checks the flags of the authenticator data and
+// verifies the signature.
+//
+// See [WebAuthn] for the verification procedure.
+//
+// [WebAuthn]: https://www.w3.org/TR/webauthn-2/#sctn-verifying-assertion
+package webauthn
+
+import (
+	"fmt"
+	stdbits "math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/encoding/base64"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/json"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// PublicKey is the P-256 public key of a credential.
+type PublicKey = ecdsa.PublicKey[emulated.P256Fp, emulated.P256Fr]
+
+// Signature is the signature of an assertion.
+type Signature = ecdsa.Signature[emulated.P256Fr]
+
+// assertionType is the type of the client data of assertions.
+const assertionType = "webauthn.get"
+
+// the layout of the authenticator data.
+const (
+	rpIDHashLen      = 32
+	flagsOffset      = 32
+	minAuthDataLen   = 37
+	flagUserPresent  = 0
+	flagUserVerified = 2
+)
+
+// Assertion is a WebAuthn assertion. The authenticator data and the client
+// data JSON have variable lengths.
+type Assertion struct {
+	AuthenticatorData       []uints.U8
+	AuthenticatorDataLength frontend.Variable
+	ClientDataJSON          []uints.U8
+	ClientDataJSONLength    frontend.Variable
+	Signature               Signature
+}
+
+// Verify asserts that the assertion a is signed by the public key pk and that
+// its client data has the type webauthn.get and the base64url encoding of
+// challenge as challenge.
+func Verify(api frontend.API, pk *PublicKey, challenge []uints.U8, a *Assertion, opts ...Option) error {
+	cfg, err := newCfg(opts...)
+	if err != nil {
+		return fmt.Errorf("new config: %w", err)
+	}
+	if len(a.AuthenticatorData) < minAuthDataLen {
+		return fmt.Errorf("authenticator data must have at least %d bytes", minAuthDataLen)
+	}
+	if err := checkClientData(api, cfg, challenge, a); err != nil {
+		return err
+	}
+
+	// the authenticator data has at least the relying party identifier hash,
+	// the flags and the signature counter.
+	n := len(a.AuthenticatorData)
+	bits.ToBinary(api, api.Sub(a.AuthenticatorDataLength, minAuthDataLen), bits.WithNbDigits(stdbits.Len(uint(n))))
+	flags := bits.ToBinary(api, a.AuthenticatorData[flagsOffset].Val, bits.WithNbDigits(8))
+	api.AssertIsEqual(flags[flagUserPresent], 1)
+	if cfg.userVerification {
+		api.AssertIsEqual(flags[flagUserVerified], 1)
+	}
+	if cfg.rpIDHash != nil {
+		for i := 0; i < rpIDHashLen; i++ {
+			api.AssertIsEqual(a.AuthenticatorData[i].Val, cfg.rpIDHash[i])
+		}
+	}
+
+	clientDataHash, err := hash(api, a.ClientDataJSON, a.ClientDataJSONLength)
+	if err != nil {
+		return err
+	}
+	// the signed data is authenticatorData || clientDataHash, where the hash
+	// is placed after the variable length authenticator data using a lookup
+	// in the concatenation of the authenticator data and the hash.
+	t := logderivlookup.New(api)
+	for i := range a.AuthenticatorData {
+		t.Insert(a.AuthenticatorData[i].Val)
+	}
+	for i := range clientDataHash {
+		t.Insert(clientDataHash[i].Val)
+	}
+	signedLen := api.Add(a.AuthenticatorDataLength, len(clientDataHash))
+	inAuthData := selector.PrefixMask(api, a.AuthenticatorDataLength, n+len(clientDataHash))
+	inSigned := selector.PrefixMask(api, signedLen, n+len(clientDataHash))
+	signed := make([]uints.U8, n+len(clientDataHash))
+	for i := range signed {
+		// the index of the byte i of the hash is n+i-length and the positions
+		// after the signed data are replaced by the first position.
+		shift := api.Mul(api.Sub(1, inAuthData[i]), api.Sub(n, a.AuthenticatorDataLength))
+		idx := api.Mul(inSigned[i], api.Add(i, shift))
+		signed[i] = uints.U8{Val: api.Mul(t.Lookup(idx)[0], inSigned[i])}
+	}
+	digest, err := hash(api, signed, signedLen)
+	if err != nil {
+		return err
+	}
+
+	f, err := emulated.NewField[emulated.P256Fr](api)
+	if err != nil {
+		return fmt.Errorf("new field: %w", err)
+	}
+	digestBits := make([]frontend.Variable, 0, 8*len(digest))
+	for i := len(digest) - 1; i >= 0; i-- {
+		digestBits = append(digestBits, bits.ToBinary(api, digest[i].Val, bits.WithNbDigits(8))...)
+	}
+	pk.Verify(api, sw_emulated.GetP256Params(), f.FromBits(digestBits...), &a.Signature)
+	return nil
+}
+
+// checkClientData asserts that the client data JSON has the type webauthn.get
+// and the base64url encoding of challenge as challenge.
+func checkClientData(api frontend.API, cfg *config, challenge []uints.U8, a *Assertion) error {
+	p, err := json.NewParser(api, cfg.maxDepth)
+	if err != nil {
+		return fmt.Errorf("new json parser: %w", err)
+	}
+	encodedLen := base64.RawURLEncoding.EncodedMaxLen(len(challenge))
+	values, err := p.Extract(a.ClientDataJSON, a.ClientDataJSONLength,
+		json.Query{Path: []string{"type"}, MaxLen: len(assertionType)},
+		json.Query{Path: []string{"challenge"}, MaxLen: encodedLen},
+	)
+	if err != nil {
+		return fmt.Errorf("extract client data: %w", err)
+	}
+	typ, encoded := values[0], values[1]
+	api.AssertIsEqual(typ.IsString, 1)
+	api.AssertIsEqual(typ.Length, len(assertionType))
+	for i := range typ.Bytes {
+		api.AssertIsEqual(typ.Bytes[i].Val, assertionType[i])
+	}
+
+	api.AssertIsEqual(encoded.IsString, 1)
+	decoded, decodedLen, err := base64.NewCodec(api, base64.RawURLEncoding).Decode(encoded.Bytes, encoded.Length)
+	if err != nil {
+		return fmt.Errorf("decode challenge: %w", err)
+	}
+	api.AssertIsEqual(decodedLen, len(challenge))
+	for i := range challenge {
+		api.AssertIsEqual(decoded[i].Val, challenge[i].Val)
+	}
+	return nil
+}
+
+// hash returns the SHA-256 digest of the first length bytes of data.
+func hash(api frontend.API, data []uints.U8, length frontend.Variable) ([]uints.U8, error) {
+	h, err := sha2.New(api)
+	if err != nil {
+		return nil, fmt.Errorf("new sha2: %w", err)
+	}
+	h.Write(data)
+	return h.FixedLengthSum(length), nil
+}
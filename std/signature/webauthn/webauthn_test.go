@@ -0,0 +1,115 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	stdbase64 "encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+const rpID = "example.com"
+
+type webauthnCircuit struct {
+	opts      []Option
+	PublicKey PublicKey
+	Challenge [32]uints.U8
+	AuthData  [48]uints.U8
+	AuthLen   frontend.Variable
+	Client    [160]uints.U8
+	ClientLen frontend.Variable
+	Signature Signature
+}
+
+func (c *webauthnCircuit) Define(api frontend.API) error {
+	return Verify(api, &c.PublicKey, c.Challenge[:], &Assertion{
+		AuthenticatorData:       c.AuthData[:],
+		AuthenticatorDataLength: c.AuthLen,
+		ClientDataJSON:          c.Client[:],
+		ClientDataJSONLength:    c.ClientLen,
+		Signature:               c.Signature,
+	}, c.opts...)
+}
+
+func assertion(t *testing.T, key *ecdsa.PrivateKey, challenge [32]byte, flags byte, typ string) *webauthnCircuit {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	authData := append(rpIDHash[:], flags, 0, 0, 0, 42)
+	client := `{"type":"` + typ + `","challenge":"` + stdbase64.RawURLEncoding.EncodeToString(challenge[:]) +
+		`","origin":"https://example.com","crossOrigin":false}`
+	clientHash := sha256.Sum256([]byte(client))
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientHash[:]...))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var res webauthnCircuit
+	res.PublicKey = PublicKey{
+		X: emulated.ValueOf[emulated.P256Fp](key.X),
+		Y: emulated.ValueOf[emulated.P256Fp](key.Y),
+	}
+	copy(res.Challenge[:], uints.NewU8Array(challenge[:]))
+	data := make([]byte, len(res.AuthData))
+	copy(data, authData)
+	copy(res.AuthData[:], uints.NewU8Array(data))
+	res.AuthLen = len(authData)
+	data = make([]byte, len(res.Client))
+	copy(data, client)
+	copy(res.Client[:], uints.NewU8Array(data))
+	res.ClientLen = len(client)
+	res.Signature = Signature{
+		R: emulated.ValueOf[emulated.P256Fr](r),
+		S: emulated.ValueOf[emulated.P256Fr](s),
+	}
+	return &res
+}
+
+func TestVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+	var challenge [32]byte
+	_, err = rand.Read(challenge[:])
+	assert.NoError(err)
+
+	// user present and verified.
+	a := assertion(t, key, challenge, 0x05, "webauthn.get")
+	err = test.IsSolved(&webauthnCircuit{opts: []Option{WithUserVerification(), WithRPID(rpID)}}, a, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// another challenge.
+	wrong := *a
+	wrong.Challenge[0] = uints.NewU8(challenge[0] ^ 1)
+	err = test.IsSolved(&webauthnCircuit{}, &wrong, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// another relying party.
+	err = test.IsSolved(&webauthnCircuit{opts: []Option{WithRPID("example.org")}}, a, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// another signature.
+	wrong = *a
+	wrong.Signature.S = emulated.ValueOf[emulated.P256Fr](big.NewInt(1))
+	err = test.IsSolved(&webauthnCircuit{}, &wrong, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// user present but not verified.
+	a = assertion(t, key, challenge, 0x01, "webauthn.get")
+	err = test.IsSolved(&webauthnCircuit{}, a, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	err = test.IsSolved(&webauthnCircuit{opts: []Option{WithUserVerification()}}, a, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// registration instead of assertion.
+	a = assertion(t, key, challenge, 0x05, "webauthn.create")
+	err = test.IsSolved(&webauthnCircuit{}, a, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -0,0 +1,105 @@
+package ssz
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	fbits "github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Proof is a Merkle branch of a node given by its generalized index.
+//
+// The generalized index of the root is 1 and the children of the node with
+// generalized index i have the generalized indices 2i and 2i+1. The depth of
+// the node is the number of siblings in the branch and the generalized index
+// is in [2^depth, 2^(depth+1)). The generalized index is a variable, so the
+// same circuit can verify the branches of different nodes of the same depth.
+type Proof struct {
+	// Branch are the siblings of the nodes on the path from the node to the
+	// root, starting from the sibling of the node.
+	Branch [][]uints.U8
+	// GIndex is the generalized index of the node.
+	GIndex frontend.Variable
+}
+
+// PlaceholderProof returns a placeholder proof of the given depth for
+// compiling the circuit.
+func PlaceholderProof(depth int) Proof {
+	p := Proof{Branch: make([][]uints.U8, depth)}
+	for i := range p.Branch {
+		p.Branch[i] = make([]uints.U8, ChunkSize)
+	}
+	return p
+}
+
+// ValueOfProof returns the assignment of the proof given by the branch and the
+// generalized index. The number of siblings in the branch must be the depth of
+// the generalized index.
+func ValueOfProof(branch [][]byte, gindex uint64) (Proof, error) {
+	if gindex == 0 {
+		return Proof{}, errors.New("invalid generalized index 0")
+	}
+	if depth := bits.Len64(gindex) - 1; depth != len(branch) {
+		return Proof{}, fmt.Errorf("generalized index depth %d does not match branch length %d", depth, len(branch))
+	}
+	p := Proof{Branch: make([][]uints.U8, len(branch)), GIndex: gindex}
+	for i := range branch {
+		if len(branch[i]) != ChunkSize {
+			return Proof{}, fmt.Errorf("sibling %d has %d bytes", i, len(branch[i]))
+		}
+		p.Branch[i] = uints.NewU8Array(branch[i])
+	}
+	return p, nil
+}
+
+// VerifyProof asserts that the leaf is the node at the generalized index of
+// the proof in the tree with the given root.
+func (h *Hasher) VerifyProof(root, leaf []uints.U8, proof *Proof) error {
+	if len(root) != ChunkSize || len(leaf) != ChunkSize {
+		return errors.New("root and leaf must be 32 bytes")
+	}
+	depth := len(proof.Branch)
+	// the decomposition asserts that the generalized index is smaller than
+	// 2^(depth+1) and the most significant bit asserts that it is at least
+	// 2^depth.
+	path := fbits.ToBinary(h.api, proof.GIndex, fbits.WithNbDigits(depth+1))
+	h.api.AssertIsEqual(path[depth], 1)
+	node := leaf
+	for i := 0; i < depth; i++ {
+		if len(proof.Branch[i]) != ChunkSize {
+			return fmt.Errorf("sibling %d has %d bytes", i, len(proof.Branch[i]))
+		}
+		left := make([]uints.U8, ChunkSize)
+		right := make([]uints.U8, ChunkSize)
+		for j := range left {
+			left[j] = uints.U8{Val: h.api.Select(path[i], proof.Branch[i][j].Val, node[j].Val)}
+			right[j] = uints.U8{Val: h.api.Select(path[i], node[j].Val, proof.Branch[i][j].Val)}
+		}
+		node = h.HashNodes(left, right)
+	}
+	return h.assertIsEqual(node, root)
+}
+
+// ConcatGeneralizedIndices returns the generalized index of the node at the
+// generalized index gindices[n-1] in the subtree rooted at the node of
+// gindices[n-2] and so on, in the tree of gindices[0]. For example, the
+// generalized index of a field of a container in a field of another container
+// is the concatenation of the generalized indices of the fields. It panics if
+// the result does not fit in 64 bits.
+func ConcatGeneralizedIndices(gindices ...uint64) uint64 {
+	res := uint64(1)
+	for _, g := range gindices {
+		if g == 0 {
+			panic("invalid generalized index 0")
+		}
+		depth := bits.Len64(g) - 1
+		if bits.Len64(res)+depth > 64 {
+			panic("generalized index overflow")
+		}
+		res = res<<depth | (g ^ (1 << depth))
+	}
+	return res
+}
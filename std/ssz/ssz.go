@@ -0,0 +1,132 @@
+// Package ssz implements the SSZ (Simple Serialize) merkleization used by the
+// Ethereum consensus layer.
+//
+// The hash tree root of an SSZ object is computed by packing its serialization
+// or the hash tree roots of its elements into 32-byte chunks and merkleizing
+// the chunks with SHA-256:
+//   - the basic types are packed into a single chunk in little-endian order;
+//   - the vectors of basic types and the bitvectors are packed and merkleized;
+//   - the containers and the vectors of composite types merkleize the hash
+//     tree roots of their fields or elements;
+//   - the lists and the bitlists are merkleized up to their limit and the
+//     length of the list is mixed in.
+//
+// The lists are given as fixed-size slices of their maximum capacity in the
+// circuit, with the actual length given as a variable. The elements after the
+// length are ignored. The subtrees of the padding chunks up to the limit are
+// constant, so the cost of a list depends only logarithmically on its limit.
+//
+// The package also verifies Merkle branches given by generalized indices, as
+// used by the light client protocol. See [Proof].
+//
+// See https://github.com/ethereum/consensus-specs/blob/dev/ssz/simple-serialize.md
+// for the specification.
+package ssz
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// ChunkSize is the size of the chunks in bytes.
+const ChunkSize = 32
+
+// maxDepth is the maximum depth of the merkleized trees. The limits are at most
+// 2^64 elements.
+const maxDepth = 64
+
+// zeroHashes are the roots of the subtrees of zero chunks of height 0 to
+// maxDepth.
+var zeroHashes = func() [][]byte {
+	res := make([][]byte, maxDepth+1)
+	res[0] = make([]byte, ChunkSize)
+	for i := 1; i < len(res); i++ {
+		h := sha256.Sum256(append(append([]byte{}, res[i-1]...), res[i-1]...))
+		res[i] = h[:]
+	}
+	return res
+}()
+
+// Hasher computes the SSZ hash tree roots in circuit. The roots are 32-byte
+// slices.
+type Hasher struct {
+	api frontend.API
+}
+
+// NewHasher returns a new [Hasher].
+func NewHasher(api frontend.API) (*Hasher, error) {
+	if _, err := sha2.New(api); err != nil {
+		return nil, fmt.Errorf("new sha2: %w", err)
+	}
+	return &Hasher{api: api}, nil
+}
+
+// HashNodes returns the SHA-256 hash of the concatenation of the chunks left
+// and right.
+func (h *Hasher) HashNodes(left, right []uints.U8) []uints.U8 {
+	// the hasher was already initialized in [NewHasher], so it does not fail.
+	sh, err := sha2.New(h.api)
+	if err != nil {
+		panic(err)
+	}
+	sh.Write(left)
+	sh.Write(right)
+	return sh.Sum()
+}
+
+// Merkleize returns the root of the binary Merkle tree of the chunks, padded
+// with zero chunks to the next power of two of limit. It returns an error if
+// the number of chunks exceeds the limit or if a chunk is not 32 bytes.
+func (h *Hasher) Merkleize(chunks [][]uints.U8, limit int) ([]uints.U8, error) {
+	if limit < len(chunks) {
+		return nil, fmt.Errorf("number of chunks %d exceeds limit %d", len(chunks), limit)
+	}
+	for i := range chunks {
+		if len(chunks[i]) != ChunkSize {
+			return nil, fmt.Errorf("chunk %d has %d bytes", i, len(chunks[i]))
+		}
+	}
+	depth := 0
+	if limit > 1 {
+		depth = bits.Len(uint(limit - 1))
+	}
+	if len(chunks) == 0 {
+		return uints.NewU8Array(zeroHashes[depth]), nil
+	}
+	layer := chunks
+	for d := 0; d < depth; d++ {
+		next := make([][]uints.U8, (len(layer)+1)/2)
+		for i := range next {
+			right := uints.NewU8Array(zeroHashes[d])
+			if 2*i+1 < len(layer) {
+				right = layer[2*i+1]
+			}
+			next[i] = h.HashNodes(layer[2*i], right)
+		}
+		layer = next
+	}
+	return layer[0], nil
+}
+
+// MixInLength returns the hash of the root and the length serialized as a
+// 256-bit little-endian integer. The length must fit in 64 bits.
+func (h *Hasher) MixInLength(root []uints.U8, length frontend.Variable) []uints.U8 {
+	return h.HashNodes(root, pad(h.SerializeUint(length, 8)))
+}
+
+// assertIsEqual asserts that the chunks a and b are equal.
+func (h *Hasher) assertIsEqual(a, b []uints.U8) error {
+	if len(a) != len(b) {
+		return errors.New("mismatching chunk lengths")
+	}
+	for i := range a {
+		h.api.AssertIsEqual(a[i].Val, b[i].Val)
+	}
+	return nil
+}
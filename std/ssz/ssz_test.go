@@ -0,0 +1,323 @@
+package ssz
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/bits"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+func hashNodes(a, b []byte) []byte {
+	h := sha256.Sum256(append(append([]byte{}, a...), b...))
+	return h[:]
+}
+
+// merkleize is the native merkleization of the chunks padded to the next power
+// of two of limit.
+func merkleize(chunks [][]byte, limit int) []byte {
+	depth := 0
+	if limit > 1 {
+		depth = bits.Len(uint(limit - 1))
+	}
+	layer := chunks
+	if len(layer) == 0 {
+		layer = [][]byte{make([]byte, ChunkSize)}
+	}
+	for d := 0; d < depth; d++ {
+		next := make([][]byte, (len(layer)+1)/2)
+		for i := range next {
+			right := zeroHashes[d]
+			if 2*i+1 < len(layer) {
+				right = layer[2*i+1]
+			}
+			next[i] = hashNodes(layer[2*i], right)
+		}
+		layer = next
+	}
+	return layer[0]
+}
+
+func packBytes(b []byte) [][]byte {
+	var res [][]byte
+	for i := 0; i < len(b); i += ChunkSize {
+		c := make([]byte, ChunkSize)
+		copy(c, b[i:min(i+ChunkSize, len(b))])
+		res = append(res, c)
+	}
+	return res
+}
+
+func mixInLength(root []byte, length uint64) []byte {
+	l := make([]byte, ChunkSize)
+	binary.LittleEndian.PutUint64(l, length)
+	return hashNodes(root, l)
+}
+
+func uint64Root(v uint64) []byte {
+	res := make([]byte, ChunkSize)
+	binary.LittleEndian.PutUint64(res, v)
+	return res
+}
+
+func randomRoot(seed byte) []byte {
+	h := sha256.Sum256([]byte{seed})
+	return h[:]
+}
+
+func TestZeroHashes(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.Equal("f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b", hex.EncodeToString(zeroHashes[1]))
+	assert.Equal(merkleize(nil, 1<<10), zeroHashes[10])
+}
+
+type headerCircuit struct {
+	Slot, ProposerIndex             frontend.Variable
+	ParentRoot, StateRoot, BodyRoot [ChunkSize]uints.U8
+	Expected                        [ChunkSize]uints.U8
+}
+
+func (c *headerCircuit) Define(api frontend.API) error {
+	h, err := NewHasher(api)
+	if err != nil {
+		return err
+	}
+	slot, err := h.HashTreeRootUint(c.Slot, 8)
+	if err != nil {
+		return err
+	}
+	proposer, err := h.HashTreeRootUint(c.ProposerIndex, 8)
+	if err != nil {
+		return err
+	}
+	root, err := h.HashTreeRootContainer(slot, proposer, c.ParentRoot[:], c.StateRoot[:], c.BodyRoot[:])
+	if err != nil {
+		return err
+	}
+	return h.assertIsEqual(root, c.Expected[:])
+}
+
+func TestHashTreeRootContainer(t *testing.T) {
+	assert := test.NewAssert(t)
+	parent, state, body := randomRoot(1), randomRoot(2), randomRoot(3)
+	expected := merkleize([][]byte{uint64Root(8_000_000), uint64Root(123456), parent, state, body}, 5)
+	var assignment headerCircuit
+	assignment.Slot = 8_000_000
+	assignment.ProposerIndex = 123456
+	copy(assignment.ParentRoot[:], uints.NewU8Array(parent))
+	copy(assignment.StateRoot[:], uints.NewU8Array(state))
+	copy(assignment.BodyRoot[:], uints.NewU8Array(body))
+	copy(assignment.Expected[:], uints.NewU8Array(expected))
+	err := test.IsSolved(&headerCircuit{}, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	assignment.Slot = 8_000_001
+	err = test.IsSolved(&headerCircuit{}, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type listCircuit struct {
+	Values       [5]frontend.Variable
+	NbValues     frontend.Variable
+	Roots        [3][ChunkSize]uints.U8
+	NbRoots      frontend.Variable
+	Bits         [10]frontend.Variable
+	NbBits       frontend.Variable
+	ExpectedList [3][ChunkSize]uints.U8
+}
+
+func (c *listCircuit) Define(api frontend.API) error {
+	h, err := NewHasher(api)
+	if err != nil {
+		return err
+	}
+	values := make([][]uints.U8, len(c.Values))
+	for i := range values {
+		values[i] = h.SerializeUint(c.Values[i], 8)
+	}
+	r0, err := h.HashTreeRootBasicList(values, c.NbValues, 1024)
+	if err != nil {
+		return err
+	}
+	roots := make([][]uints.U8, len(c.Roots))
+	for i := range roots {
+		roots[i] = c.Roots[i][:]
+	}
+	r1, err := h.HashTreeRootList(roots, c.NbRoots, 1<<40)
+	if err != nil {
+		return err
+	}
+	r2, err := h.HashTreeRootBitlist(c.Bits[:], c.NbBits, 2048)
+	if err != nil {
+		return err
+	}
+	for i, r := range [][]uints.U8{r0, r1, r2} {
+		if err := h.assertIsEqual(r, c.ExpectedList[i][:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestHashTreeRootList(t *testing.T) {
+	assert := test.NewAssert(t)
+	var assignment listCircuit
+
+	// List[uint64, 1024] of length 3, the remaining values are ignored.
+	var serialized []byte
+	for i := range assignment.Values {
+		v := uint64(1000*i + 7)
+		assignment.Values[i] = v
+		if i < 3 {
+			serialized = binary.LittleEndian.AppendUint64(serialized, v)
+		}
+	}
+	assignment.NbValues = 3
+	e0 := mixInLength(merkleize(packBytes(serialized), 1024*8/ChunkSize), 3)
+
+	// List[Root, 2^40] of length 2.
+	var roots [][]byte
+	for i := range assignment.Roots {
+		r := randomRoot(byte(10 + i))
+		copy(assignment.Roots[i][:], uints.NewU8Array(r))
+		if i < 2 {
+			roots = append(roots, r)
+		}
+	}
+	assignment.NbRoots = 2
+	e1 := mixInLength(merkleize(roots, 1<<40), 2)
+
+	// Bitlist[2048] of length 7.
+	bitsVal := []int{1, 0, 1, 1, 0, 0, 1, 1, 1, 1}
+	var packed byte
+	for i := range assignment.Bits {
+		assignment.Bits[i] = bitsVal[i]
+		if i < 7 {
+			packed |= byte(bitsVal[i]) << i
+		}
+	}
+	assignment.NbBits = 7
+	e2 := mixInLength(merkleize(packBytes([]byte{packed}), 2048/256), 7)
+
+	for i, e := range [][]byte{e0, e1, e2} {
+		copy(assignment.ExpectedList[i][:], uints.NewU8Array(e))
+	}
+	err := test.IsSolved(&listCircuit{}, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	wrong := assignment
+	wrong.NbBits = 11
+	err = test.IsSolved(&listCircuit{}, &wrong, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type listLimitCircuit struct {
+	Roots   [3][ChunkSize]uints.U8
+	NbRoots frontend.Variable
+	limit   int
+}
+
+func (c *listLimitCircuit) Define(api frontend.API) error {
+	h, err := NewHasher(api)
+	if err != nil {
+		return err
+	}
+	roots := make([][]uints.U8, len(c.Roots))
+	for i := range roots {
+		roots[i] = c.Roots[i][:]
+	}
+	_, err = h.HashTreeRootList(roots, c.NbRoots, c.limit)
+	return err
+}
+
+func TestHashTreeRootListLimit(t *testing.T) {
+	assert := test.NewAssert(t)
+	var assignment listLimitCircuit
+	for i := range assignment.Roots {
+		copy(assignment.Roots[i][:], uints.NewU8Array(randomRoot(byte(i))))
+	}
+	assignment.NbRoots = 2
+	err := test.IsSolved(&listLimitCircuit{limit: 4}, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// more elements than the limit of the list type, even if only the first
+	// ones are in the list.
+	err = test.IsSolved(&listLimitCircuit{limit: 2}, &assignment, ecc.BN254.ScalarField())
+	assert.ErrorContains(err, "number of elements 3 exceeds limit 2")
+}
+
+type proofCircuit struct {
+	Root, Leaf [ChunkSize]uints.U8
+	Proof      Proof
+}
+
+func (c *proofCircuit) Define(api frontend.API) error {
+	h, err := NewHasher(api)
+	if err != nil {
+		return err
+	}
+	return h.VerifyProof(c.Root[:], c.Leaf[:], &c.Proof)
+}
+
+func TestVerifyProof(t *testing.T) {
+	assert := test.NewAssert(t)
+	// a state container with five fields, where the fourth field is a block
+	// header. We prove the state root field of the header.
+	header := [][]byte{uint64Root(42), uint64Root(7), randomRoot(1), randomRoot(2), randomRoot(3)}
+	headerRoot := merkleize(header, len(header))
+	state := [][]byte{uint64Root(1), randomRoot(4), randomRoot(5), headerRoot, randomRoot(6)}
+	stateRoot := merkleize(state, len(state))
+
+	// the branches of the field i in the containers of 8 chunks, from the leaf
+	// to the root.
+	branch := func(chunks [][]byte, i int) [][]byte {
+		layer := make([][]byte, 8)
+		for j := range layer {
+			layer[j] = make([]byte, ChunkSize)
+			if j < len(chunks) {
+				copy(layer[j], chunks[j])
+			}
+		}
+		var res [][]byte
+		for len(layer) > 1 {
+			res = append(res, layer[i^1])
+			next := make([][]byte, len(layer)/2)
+			for j := range next {
+				next[j] = hashNodes(layer[2*j], layer[2*j+1])
+			}
+			layer, i = next, i/2
+		}
+		return res
+	}
+	gindex := ConcatGeneralizedIndices(8+3, 8+3)
+	assert.Equal(uint64(91), gindex)
+	proofBranch := append(branch(header, 3), branch(state, 3)...)
+
+	proof, err := ValueOfProof(proofBranch, gindex)
+	assert.NoError(err)
+	assignment := proofCircuit{Proof: proof}
+	copy(assignment.Root[:], uints.NewU8Array(stateRoot))
+	copy(assignment.Leaf[:], uints.NewU8Array(header[3]))
+	circuit := proofCircuit{Proof: PlaceholderProof(len(proofBranch))}
+	err = test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the state root is not the body root.
+	wrong := assignment
+	wrong.Proof.GIndex = gindex + 1
+	err = test.IsSolved(&circuit, &wrong, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// the generalized index must be of the depth of the branch.
+	wrong.Proof.GIndex = gindex - 64
+	err = test.IsSolved(&circuit, &wrong, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	_, err = ValueOfProof(proofBranch, gindex/2)
+	assert.Error(err)
+}
@@ -0,0 +1,213 @@
+package ssz
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// SerializeUint returns the little-endian serialization of v on nbBytes bytes.
+// It asserts that v fits in nbBytes bytes.
+func (h *Hasher) SerializeUint(v frontend.Variable, nbBytes int) []uints.U8 {
+	b := bits.ToBinary(h.api, v, bits.WithNbDigits(8*nbBytes))
+	return packBits(h.api, b)
+}
+
+// HashTreeRootUint returns the hash tree root of the unsigned integer v of
+// nbBytes bytes, for example 8 for uint64. It asserts that v fits in nbBytes
+// bytes.
+func (h *Hasher) HashTreeRootUint(v frontend.Variable, nbBytes int) ([]uints.U8, error) {
+	if nbBytes <= 0 || nbBytes > ChunkSize {
+		return nil, fmt.Errorf("invalid integer size %d", nbBytes)
+	}
+	return pad(h.SerializeUint(v, nbBytes)), nil
+}
+
+// HashTreeRootBool returns the hash tree root of the boolean b. It asserts
+// that b is boolean.
+func (h *Hasher) HashTreeRootBool(b frontend.Variable) []uints.U8 {
+	h.api.AssertIsBoolean(b)
+	return pad([]uints.U8{{Val: b}})
+}
+
+// HashTreeRootByteVector returns the hash tree root of the fixed-size byte
+// vector b. For a 32-byte vector, such as Bytes32 or Root, it is b itself.
+func (h *Hasher) HashTreeRootByteVector(b []uints.U8) ([]uints.U8, error) {
+	if len(b) == 0 {
+		return nil, errors.New("empty vector")
+	}
+	chunks := pack(b)
+	return h.Merkleize(chunks, len(chunks))
+}
+
+// HashTreeRootBasicVector returns the hash tree root of the vector of basic
+// elements given by their serializations. All the elements must have the same
+// size.
+func (h *Hasher) HashTreeRootBasicVector(elems [][]uints.U8) ([]uints.U8, error) {
+	if len(elems) == 0 {
+		return nil, errors.New("empty vector")
+	}
+	b, err := concat(elems)
+	if err != nil {
+		return nil, err
+	}
+	chunks := pack(b)
+	return h.Merkleize(chunks, len(chunks))
+}
+
+// HashTreeRootBasicList returns the hash tree root of the list of basic
+// elements given by their serializations. Only the first length elements are
+// in the list and length must be at most len(elems). The maximum number of
+// elements of the list type is limit. All the elements must have the same
+// size.
+func (h *Hasher) HashTreeRootBasicList(elems [][]uints.U8, length frontend.Variable, limit int) ([]uints.U8, error) {
+	if len(elems) == 0 {
+		return nil, errors.New("no elements")
+	}
+	if limit < len(elems) {
+		return nil, fmt.Errorf("number of elements %d exceeds limit %d", len(elems), limit)
+	}
+	flags := selector.PrefixMask(h.api, length, len(elems))
+	masked := make([][]uints.U8, len(elems))
+	for i := range elems {
+		masked[i] = mask(h.api, flags[i], elems[i])
+	}
+	b, err := concat(masked)
+	if err != nil {
+		return nil, err
+	}
+	root, err := h.Merkleize(pack(b), (limit*len(elems[0])+ChunkSize-1)/ChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	return h.MixInLength(root, length), nil
+}
+
+// HashTreeRootContainer returns the hash tree root of the container given by
+// the hash tree roots of its fields.
+func (h *Hasher) HashTreeRootContainer(fields ...[]uints.U8) ([]uints.U8, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("empty container")
+	}
+	return h.Merkleize(fields, len(fields))
+}
+
+// HashTreeRootVector returns the hash tree root of the vector of composite
+// elements given by their hash tree roots.
+func (h *Hasher) HashTreeRootVector(roots [][]uints.U8) ([]uints.U8, error) {
+	if len(roots) == 0 {
+		return nil, errors.New("empty vector")
+	}
+	return h.Merkleize(roots, len(roots))
+}
+
+// HashTreeRootList returns the hash tree root of the list of composite
+// elements given by their hash tree roots. Only the first length elements are
+// in the list and length must be at most len(roots). The maximum number of
+// elements of the list type is limit.
+func (h *Hasher) HashTreeRootList(roots [][]uints.U8, length frontend.Variable, limit int) ([]uints.U8, error) {
+	if limit < len(roots) {
+		return nil, fmt.Errorf("number of elements %d exceeds limit %d", len(roots), limit)
+	}
+	flags := selector.PrefixMask(h.api, length, len(roots))
+	masked := make([][]uints.U8, len(roots))
+	for i := range roots {
+		masked[i] = mask(h.api, flags[i], roots[i])
+	}
+	root, err := h.Merkleize(masked, limit)
+	if err != nil {
+		return nil, err
+	}
+	return h.MixInLength(root, length), nil
+}
+
+// HashTreeRootBitvector returns the hash tree root of the bitvector. It
+// asserts that the bits are boolean.
+func (h *Hasher) HashTreeRootBitvector(b []frontend.Variable) ([]uints.U8, error) {
+	if len(b) == 0 {
+		return nil, errors.New("empty bitvector")
+	}
+	for i := range b {
+		h.api.AssertIsBoolean(b[i])
+	}
+	chunks := pack(packBits(h.api, b))
+	return h.Merkleize(chunks, (len(b)+8*ChunkSize-1)/(8*ChunkSize))
+}
+
+// HashTreeRootBitlist returns the hash tree root of the bitlist. Only the
+// first length bits are in the list and length must be at most len(b). The
+// maximum number of bits of the bitlist type is limit. It asserts that the
+// bits are boolean.
+func (h *Hasher) HashTreeRootBitlist(b []frontend.Variable, length frontend.Variable, limit int) ([]uints.U8, error) {
+	if limit < len(b) {
+		return nil, fmt.Errorf("number of bits %d exceeds limit %d", len(b), limit)
+	}
+	flags := selector.PrefixMask(h.api, length, len(b))
+	masked := make([]frontend.Variable, len(b))
+	for i := range b {
+		h.api.AssertIsBoolean(b[i])
+		masked[i] = h.api.Mul(flags[i], b[i])
+	}
+	root, err := h.Merkleize(pack(packBits(h.api, masked)), (limit+8*ChunkSize-1)/(8*ChunkSize))
+	if err != nil {
+		return nil, err
+	}
+	return h.MixInLength(root, length), nil
+}
+
+// packBits returns the bytes of the bits in little-endian bit order, padding
+// the last byte with zeros. The bits must be boolean.
+func packBits(api frontend.API, b []frontend.Variable) []uints.U8 {
+	res := make([]uints.U8, (len(b)+7)/8)
+	for i := range res {
+		end := min(8*i+8, len(b))
+		res[i] = uints.U8{Val: bits.FromBinary(api, b[8*i:end], bits.WithUnconstrainedInputs())}
+	}
+	return res
+}
+
+// pack returns the bytes in 32-byte chunks, padding the last chunk with zeros.
+func pack(b []uints.U8) [][]uints.U8 {
+	res := make([][]uints.U8, (len(b)+ChunkSize-1)/ChunkSize)
+	for i := range res {
+		end := min(ChunkSize*(i+1), len(b))
+		res[i] = pad(b[ChunkSize*i : end])
+	}
+	return res
+}
+
+// pad returns the bytes padded with zeros to a chunk.
+func pad(b []uints.U8) []uints.U8 {
+	res := make([]uints.U8, ChunkSize)
+	copy(res, b)
+	for i := len(b); i < ChunkSize; i++ {
+		res[i] = uints.NewU8(0)
+	}
+	return res
+}
+
+// concat returns the concatenation of the serializations of the elements. It
+// returns an error if the elements have different sizes.
+func concat(elems [][]uints.U8) ([]uints.U8, error) {
+	var res []uints.U8
+	for i := range elems {
+		if len(elems[i]) == 0 || len(elems[i]) != len(elems[0]) {
+			return nil, fmt.Errorf("element %d has invalid size %d", i, len(elems[i]))
+		}
+		res = append(res, elems[i]...)
+	}
+	return res, nil
+}
+
+// mask returns the bytes if active is 1 and zeros if active is 0.
+func mask(api frontend.API, active frontend.Variable, b []uints.U8) []uints.U8 {
+	res := make([]uints.U8, len(b))
+	for i := range b {
+		res[i] = uints.U8{Val: api.Mul(active, b[i].Val)}
+	}
+	return res
+}
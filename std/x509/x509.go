@@ -0,0 +1,335 @@
+// Package x509 implements in-circuit parsing and verification of X.509
+// certificates.
+//
+// The certificate is given as its DER encoding with a variable length. The
+// parser navigates the structure of the certificate to locate the TBS
+// certificate, the validity, the subject public key and the signature, and the
+// signature of the issuer over the TBS certificate can be verified. The
+// signature algorithms ECDSA with SHA-256 over P-256 and RSA PKCS #1 v1.5 with
+// SHA-256 are supported.
+//
+// Only the certificates of 256 to 65535 bytes are supported, so that the TBS
+// certificate always starts after the header of 4 bytes of the certificate.
+package x509
+
+import (
+	"crypto"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/asn1"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/cmp"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+	"github.com/consensys/gnark/std/signature/rsa"
+)
+
+const (
+	// tbsOffset is the position of the TBS certificate.
+	tbsOffset = 4
+	// maxAlgorithmLen is the maximal length of the contents of the algorithm
+	// identifiers of the signature.
+	maxAlgorithmLen = 32
+)
+
+var (
+	oidECDSAWithSHA256   = asn1.ObjectIdentifier(1, 2, 840, 10045, 4, 3, 2)
+	oidSHA256WithRSA     = asn1.ObjectIdentifier(1, 2, 840, 113549, 1, 1, 11)
+	oidECPublicKey       = asn1.ObjectIdentifier(1, 2, 840, 10045, 2, 1)
+	oidP256              = asn1.ObjectIdentifier(1, 2, 840, 10045, 3, 1, 7)
+	oidRSAEncryption     = asn1.ObjectIdentifier(1, 2, 840, 113549, 1, 1, 1)
+	rsaPublicKeyExponent = []byte{0x01, 0x00, 0x01}
+	timeBound            = new(big.Int).Exp(big.NewInt(10), big.NewInt(14), nil)
+)
+
+// Certificate is a parsed certificate. The elements are located in the DER
+// encoding of the certificate and can be further parsed with [Certificate.Parser].
+type Certificate struct {
+	api    frontend.API
+	parser *asn1.Parser
+	data   []uints.U8
+	// digitT is 1 for the bytes of the decimal digits and centuryT maps the
+	// two-digit years of UTCTime to their century.
+	digitT   *logderivlookup.Table
+	centuryT *logderivlookup.Table
+
+	// TBS is the TBS certificate, signed by the issuer.
+	TBS asn1.Element
+	// SerialNumber is the serial number of the certificate.
+	SerialNumber asn1.Element
+	// Issuer and Subject are the distinguished names of the issuer and the
+	// subject.
+	Issuer, Subject asn1.Element
+	// NotBefore and NotAfter are the bounds of the validity period, as the
+	// decimal numbers YYYYMMDDhhmmss in UTC so that they can be compared as
+	// integers.
+	NotBefore, NotAfter frontend.Variable
+	// PublicKeyAlgorithm is the algorithm identifier of the subject public key.
+	PublicKeyAlgorithm asn1.Element
+	// PublicKey is the bit string of the subject public key.
+	PublicKey asn1.Element
+	// SignatureAlgorithm is the algorithm identifier of the signature.
+	SignatureAlgorithm asn1.Element
+	// Signature is the bit string of the signature.
+	Signature asn1.Element
+}
+
+// Parse parses the certificate given by the first length bytes of data.
+func Parse(api frontend.API, data []uints.U8, length frontend.Variable) (*Certificate, error) {
+	if len(data) < 256 {
+		return nil, fmt.Errorf("data must have at least 256 bytes")
+	}
+	p := asn1.NewParser(api, data)
+	c := &Certificate{api: api, parser: p, data: data}
+
+	cert := p.Expect(0, asn1.TagSequence)
+	api.AssertIsEqual(cert.Offset, tbsOffset)
+	api.AssertIsEqual(cert.End, length)
+	c.TBS = p.Expect(tbsOffset, asn1.TagSequence)
+	c.SignatureAlgorithm = p.Expect(c.TBS.End, asn1.TagSequence)
+	c.Signature = p.Expect(c.SignatureAlgorithm.End, asn1.TagBitString)
+	api.AssertIsEqual(c.Signature.End, cert.End)
+
+	// the version is optional.
+	first := p.Child(c.TBS)
+	hasVersion := api.IsZero(api.Sub(first.Tag, asn1.ClassContextSpecific|asn1.Constructed))
+	c.SerialNumber = p.Expect(api.Select(hasVersion, first.End, first.Start), asn1.TagInteger)
+	signature := p.Expect(c.SerialNumber.End, asn1.TagSequence)
+	// the signature algorithm of the TBS certificate is the one of the
+	// certificate (RFC 5280, section 4.1.1.2).
+	api.AssertIsEqual(signature.Length, c.SignatureAlgorithm.Length)
+	inner := p.Contents(signature, maxAlgorithmLen)
+	outer := p.Contents(c.SignatureAlgorithm, maxAlgorithmLen)
+	for i := range inner {
+		api.AssertIsEqual(inner[i].Val, outer[i].Val)
+	}
+	c.Issuer = p.Expect(signature.End, asn1.TagSequence)
+	validity := p.Expect(c.Issuer.End, asn1.TagSequence)
+	c.Subject = p.Expect(validity.End, asn1.TagSequence)
+	spki := p.Expect(c.Subject.End, asn1.TagSequence)
+	p.AssertIsWithin(spki, c.TBS)
+	c.PublicKeyAlgorithm = p.Expect(spki.Offset, asn1.TagSequence)
+	c.PublicKey = p.Expect(c.PublicKeyAlgorithm.End, asn1.TagBitString)
+	p.AssertIsWithin(c.PublicKey, spki)
+
+	c.digitT = logderivlookup.New(api)
+	for b := 0; b < 256; b++ {
+		c.digitT.Insert(utils.BoolToInt(b >= '0' && b <= '9'))
+	}
+	// the two-digit years are in the range 1950-2049.
+	c.centuryT = logderivlookup.New(api)
+	for y := 0; y < 100; y++ {
+		if y < 50 {
+			c.centuryT.Insert(2000)
+		} else {
+			c.centuryT.Insert(1900)
+		}
+	}
+	notBefore := p.Child(validity)
+	notAfter := p.Next(notBefore)
+	p.AssertIsWithin(notAfter, validity)
+	c.NotBefore = c.time(notBefore)
+	c.NotAfter = c.time(notAfter)
+	return c, nil
+}
+
+// Parser returns the parser of the DER encoding of the certificate.
+func (c *Certificate) Parser() *asn1.Parser {
+	return c.parser
+}
+
+// AssertIsValidAt asserts that the time now, given as the decimal number
+// YYYYMMDDhhmmss in UTC, is in the validity period of the certificate.
+func (c *Certificate) AssertIsValidAt(now frontend.Variable) {
+	comparator := cmp.NewBoundedComparator(c.api, timeBound, false)
+	comparator.AssertIsLessEq(c.NotBefore, now)
+	comparator.AssertIsLessEq(now, c.NotAfter)
+}
+
+// HashTBS returns the SHA-256 digest of the TBS certificate.
+func (c *Certificate) HashTBS() ([]uints.U8, error) {
+	h, err := sha2.New(c.api)
+	if err != nil {
+		return nil, fmt.Errorf("new sha2: %w", err)
+	}
+	h.Write(c.data[tbsOffset:])
+	return h.FixedLengthSum(c.api.Sub(c.TBS.End, tbsOffset)), nil
+}
+
+// P256PublicKey returns the subject public key. It asserts that the key is an
+// uncompressed ECDSA P-256 public key.
+func (c *Certificate) P256PublicKey() (*ecdsa.PublicKey[emulated.P256Fp, emulated.P256Fr], error) {
+	api := c.api
+	c.assertAlgorithm(c.PublicKeyAlgorithm, oidECPublicKey, oidP256)
+	// the bit string has no unused bits and the point is uncompressed.
+	key := c.parser.Contents(c.PublicKey, 66)
+	api.AssertIsEqual(c.PublicKey.Length, 66)
+	api.AssertIsEqual(key[0].Val, 0)
+	api.AssertIsEqual(key[1].Val, 4)
+	x, err := fromBytes[emulated.P256Fp](api, key[2:34])
+	if err != nil {
+		return nil, err
+	}
+	y, err := fromBytes[emulated.P256Fp](api, key[34:66])
+	if err != nil {
+		return nil, err
+	}
+	return &ecdsa.PublicKey[emulated.P256Fp, emulated.P256Fr]{X: *x, Y: *y}, nil
+}
+
+// RSAPublicKey returns the subject public key of the certificate c. It asserts
+// that the key is an RSA public key with a modulus of at most nbBits bits and
+// the public exponent 65537.
+func RSAPublicKey[T emulated.FieldParams](c *Certificate, nbBits int) (*rsa.PublicKey[T], error) {
+	api, p := c.api, c.parser
+	if nbBits%8 != 0 {
+		return nil, fmt.Errorf("modulus size must be a multiple of 8")
+	}
+	c.assertAlgorithm(c.PublicKeyAlgorithm, oidRSAEncryption, nil)
+	api.AssertIsEqual(p.At(c.PublicKey.Offset), 0)
+	key := p.Expect(api.Add(c.PublicKey.Offset, 1), asn1.TagSequence)
+	n := p.Child(key)
+	e := p.Next(n)
+	p.AssertIsWithin(e, key)
+	p.AssertContentsEqual(e, rsaPublicKeyExponent)
+	modulus, err := fromBytes[T](api, p.Integer(n, nbBits/8))
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey[T]{N: *modulus}, nil
+}
+
+// VerifyP256 asserts that the certificate is signed with ECDSA with SHA-256 by
+// the issuer with the P-256 public key issuer.
+func (c *Certificate) VerifyP256(issuer *ecdsa.PublicKey[emulated.P256Fp, emulated.P256Fr]) error {
+	api, p := c.api, c.parser
+	c.assertAlgorithm(c.SignatureAlgorithm, oidECDSAWithSHA256, nil)
+	api.AssertIsEqual(p.At(c.Signature.Offset), 0)
+	sig := p.Expect(api.Add(c.Signature.Offset, 1), asn1.TagSequence)
+	r := p.Child(sig)
+	s := p.Next(r)
+	p.AssertIsWithin(s, sig)
+	rEl, err := fromBytes[emulated.P256Fr](api, p.Integer(r, 32))
+	if err != nil {
+		return err
+	}
+	sEl, err := fromBytes[emulated.P256Fr](api, p.Integer(s, 32))
+	if err != nil {
+		return err
+	}
+	digest, err := c.HashTBS()
+	if err != nil {
+		return err
+	}
+	msg, err := fromBytes[emulated.P256Fr](api, digest)
+	if err != nil {
+		return err
+	}
+	issuer.Verify(api, sw_emulated.GetP256Params(), msg, &ecdsa.Signature[emulated.P256Fr]{R: *rEl, S: *sEl})
+	return nil
+}
+
+// VerifyRSA asserts that the certificate c is signed with RSA PKCS #1 v1.5
+// with SHA-256 by the issuer with the RSA public key issuer of nbBits bits.
+func VerifyRSA[T emulated.FieldParams](c *Certificate, issuer *rsa.PublicKey[T], nbBits int) error {
+	api, p := c.api, c.parser
+	if nbBits%8 != 0 {
+		return fmt.Errorf("modulus size must be a multiple of 8")
+	}
+	c.assertAlgorithm(c.SignatureAlgorithm, oidSHA256WithRSA, nil)
+	// the bit string has no unused bits and the signature has the byte length
+	// of the modulus.
+	api.AssertIsEqual(c.Signature.Length, nbBits/8+1)
+	sig := p.Contents(c.Signature, nbBits/8+1)
+	api.AssertIsEqual(sig[0].Val, 0)
+	s, err := fromBytes[T](api, sig[1:])
+	if err != nil {
+		return err
+	}
+	digest, err := c.HashTBS()
+	if err != nil {
+		return err
+	}
+	return issuer.VerifyPKCS1v15(api, nbBits, crypto.SHA256, digest, &rsa.Signature[T]{S: *s})
+}
+
+// assertAlgorithm asserts that the algorithm identifier alg has the object
+// identifier oid and, if params is not nil, the object identifier params as
+// parameters. Otherwise the parameters are either absent or null.
+func (c *Certificate) assertAlgorithm(alg asn1.Element, oid, params []byte) {
+	api, p := c.api, c.parser
+	id := p.Expect(alg.Offset, asn1.TagOID)
+	p.AssertContentsEqual(id, oid)
+	if params != nil {
+		param := p.Expect(id.End, asn1.TagOID)
+		p.AssertContentsEqual(param, params)
+		api.AssertIsEqual(param.End, alg.End)
+		return
+	}
+	// the null parameters are two bytes long.
+	absent := api.IsZero(api.Sub(id.End, alg.End))
+	api.AssertIsEqual(api.Mul(api.Sub(1, absent), api.Sub(alg.End, api.Add(id.End, 2))), 0)
+	api.AssertIsEqual(api.Mul(api.Sub(1, absent), p.At(id.End)), api.Mul(api.Sub(1, absent), asn1.TagNull))
+	api.AssertIsEqual(api.Mul(api.Sub(1, absent), p.At(api.Add(id.End, 1))), 0)
+}
+
+// time returns the time of the element e, which is either a UTCTime or a
+// GeneralizedTime, as the decimal number YYYYMMDDhhmmss.
+func (c *Certificate) time(e asn1.Element) frontend.Variable {
+	api := c.api
+	isUTC := api.IsZero(api.Sub(e.Tag, asn1.TagUTCTime))
+	api.AssertIsEqual(api.Mul(api.Sub(e.Tag, asn1.TagUTCTime), api.Sub(e.Tag, asn1.TagGeneralizedTime)), 0)
+	// the UTCTime has the format YYMMDDhhmmssZ and the GeneralizedTime the
+	// format YYYYMMDDhhmmssZ.
+	api.AssertIsEqual(e.Length, api.Sub(15, api.Mul(isUTC, 2)))
+	contents := c.parser.Contents(e, 15)
+	digits := make([]frontend.Variable, 15)
+	for i := range digits {
+		digits[i] = api.Sub(contents[i].Val, '0')
+	}
+	// the byte after the digits is Z.
+	for i := 0; i < 15; i++ {
+		switch i {
+		case 12:
+			isZ := api.Select(isUTC, api.IsZero(api.Sub(contents[i].Val, 'Z')), c.digitT.Lookup(contents[i].Val)[0])
+			api.AssertIsEqual(isZ, 1)
+		case 13:
+			api.AssertIsEqual(api.Mul(api.Sub(1, isUTC), api.Sub(1, c.digitT.Lookup(contents[i].Val)[0])), 0)
+		case 14:
+			api.AssertIsEqual(api.Mul(api.Sub(1, isUTC), api.Sub(contents[i].Val, 'Z')), 0)
+		default:
+			api.AssertIsEqual(c.digitT.Lookup(contents[i].Val)[0], 1)
+		}
+	}
+	number := func(ds []frontend.Variable) frontend.Variable {
+		var res frontend.Variable = 0
+		for _, d := range ds {
+			res = api.Add(api.Mul(res, 10), d)
+		}
+		return res
+	}
+	yy := number(digits[:2])
+	utc := api.Add(api.Mul(api.Add(c.centuryT.Lookup(yy)[0], yy), 10_000_000_000), number(digits[2:12]))
+	generalized := number(digits[:14])
+	return api.Select(isUTC, utc, generalized)
+}
+
+// fromBytes returns the element given by the big-endian bytes bs.
+func fromBytes[T emulated.FieldParams](api frontend.API, bs []uints.U8) (*emulated.Element[T], error) {
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return nil, fmt.Errorf("new field: %w", err)
+	}
+	res := make([]frontend.Variable, 0, 8*len(bs))
+	for i := len(bs) - 1; i >= 0; i-- {
+		res = append(res, bits.ToBinary(api, bs[i].Val, bits.WithNbDigits(8))...)
+	}
+	return f.FromBits(res...), nil
+}
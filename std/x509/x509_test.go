@@ -0,0 +1,223 @@
+package x509
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	stdx509 "crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/math/uints"
+	gnarkecdsa "github.com/consensys/gnark/std/signature/ecdsa"
+	gnarkrsa "github.com/consensys/gnark/std/signature/rsa"
+	"github.com/consensys/gnark/test"
+)
+
+type p256PublicKey = gnarkecdsa.PublicKey[emulated.P256Fp, emulated.P256Fr]
+
+func p256Key(pk *ecdsa.PublicKey) p256PublicKey {
+	return p256PublicKey{
+		X: emulated.ValueOf[emulated.P256Fp](pk.X),
+		Y: emulated.ValueOf[emulated.P256Fp](pk.Y),
+	}
+}
+
+func certificate(template, parent *stdx509.Certificate, pub, priv any) ([]byte, error) {
+	return stdx509.CreateCertificate(rand.Reader, template, parent, pub, priv)
+}
+
+func template(serial int64, cn string, notBefore, notAfter time.Time, isCA bool) *stdx509.Certificate {
+	return &stdx509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+}
+
+func certAssignment(der []byte, n int) ([]uints.U8, int) {
+	data := make([]byte, n)
+	copy(data, der)
+	return uints.NewU8Array(data), len(der)
+}
+
+type p256Circuit struct {
+	Cert      [512]uints.U8
+	Length    frontend.Variable
+	Issuer    p256PublicKey
+	Subject   p256PublicKey
+	NotBefore frontend.Variable
+	NotAfter  frontend.Variable
+	Now       frontend.Variable
+}
+
+func (c *p256Circuit) Define(api frontend.API) error {
+	cert, err := Parse(api, c.Cert[:], c.Length)
+	if err != nil {
+		return err
+	}
+	if err := cert.VerifyP256(&c.Issuer); err != nil {
+		return err
+	}
+	key, err := cert.P256PublicKey()
+	if err != nil {
+		return err
+	}
+	f, err := emulated.NewField[emulated.P256Fp](api)
+	if err != nil {
+		return err
+	}
+	f.AssertIsEqual(&key.X, &c.Subject.X)
+	f.AssertIsEqual(&key.Y, &c.Subject.Y)
+	api.AssertIsEqual(cert.NotBefore, c.NotBefore)
+	api.AssertIsEqual(cert.NotAfter, c.NotAfter)
+	cert.AssertIsValidAt(c.Now)
+	return nil
+}
+
+func TestP256(t *testing.T) {
+	assert := test.NewAssert(t)
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+	notBefore := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	caTemplate := template(1, "Test CA", notBefore, time.Date(2060, 1, 1, 0, 0, 0, 0, time.UTC), true)
+	// the end of the validity after 2049 is encoded as a GeneralizedTime.
+	leafTemplate := template(0x1234567890, "leaf.example.com", notBefore, time.Date(2051, 6, 7, 8, 9, 10, 0, time.UTC), false)
+	caDER, err := certificate(caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(err)
+	ca, err := stdx509.ParseCertificate(caDER)
+	assert.NoError(err)
+	leafDER, err := certificate(leafTemplate, ca, &leafKey.PublicKey, caKey)
+	assert.NoError(err)
+
+	assignment := func(der []byte, issuer *ecdsa.PublicKey, now int64) *p256Circuit {
+		var res p256Circuit
+		data, length := certAssignment(der, len(res.Cert))
+		copy(res.Cert[:], data)
+		res.Length = length
+		res.Issuer = p256Key(issuer)
+		res.Subject = p256Key(&leafKey.PublicKey)
+		res.NotBefore = 20240102030405
+		res.NotAfter = 20510607080910
+		res.Now = now
+		return &res
+	}
+	err = test.IsSolved(&p256Circuit{}, assignment(leafDER, &caKey.PublicKey, 20250101000000), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// signed by another issuer.
+	err = test.IsSolved(&p256Circuit{}, assignment(leafDER, &leafKey.PublicKey, 20250101000000), ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// not valid yet.
+	err = test.IsSolved(&p256Circuit{}, assignment(leafDER, &caKey.PublicKey, 20240101000000), ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// the TBS certificate is modified.
+	tampered := append([]byte{}, leafDER...)
+	tampered[len(tampered)-200] ^= 1
+	err = test.IsSolved(&p256Circuit{}, assignment(tampered, &caKey.PublicKey, 20250101000000), ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type rsaCircuit struct {
+	Cert   [1024]uints.U8
+	Length frontend.Variable
+	Issuer gnarkrsa.PublicKey[emparams.Mod1e2048]
+}
+
+func (c *rsaCircuit) Define(api frontend.API) error {
+	cert, err := Parse(api, c.Cert[:], c.Length)
+	if err != nil {
+		return err
+	}
+	if err := VerifyRSA(cert, &c.Issuer, 2048); err != nil {
+		return err
+	}
+	// the certificate is self-signed.
+	key, err := RSAPublicKey[emparams.Mod1e2048](cert, 2048)
+	if err != nil {
+		return err
+	}
+	f, err := emulated.NewField[emparams.Mod1e2048](api)
+	if err != nil {
+		return err
+	}
+	f.AssertIsEqual(&key.N, &c.Issuer.N)
+	return nil
+}
+
+func TestRSA(t *testing.T) {
+	assert := test.NewAssert(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	caTemplate := template(1, "Test RSA CA", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC), true)
+	der, err := certificate(caTemplate, caTemplate, &key.PublicKey, key)
+	assert.NoError(err)
+
+	var assignment rsaCircuit
+	data, length := certAssignment(der, len(assignment.Cert))
+	copy(assignment.Cert[:], data)
+	assignment.Length = length
+	assignment.Issuer.N = emulated.ValueOf[emparams.Mod1e2048](key.N)
+	err = test.IsSolved(&rsaCircuit{}, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	assignment.Issuer.N = emulated.ValueOf[emparams.Mod1e2048](other.N)
+	err = test.IsSolved(&rsaCircuit{}, &assignment, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type parseCircuit struct {
+	Cert   [512]uints.U8
+	Length frontend.Variable
+}
+
+func (c *parseCircuit) Define(api frontend.API) error {
+	_, err := Parse(api, c.Cert[:], c.Length)
+	return err
+}
+
+func TestSignatureAlgorithm(t *testing.T) {
+	assert := test.NewAssert(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+	caTemplate := template(1, "Test CA", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC), true)
+	der, err := certificate(caTemplate, caTemplate, &key.PublicKey, key)
+	assert.NoError(err)
+	assignment := func(der []byte) *parseCircuit {
+		var res parseCircuit
+		data, length := certAssignment(der, len(res.Cert))
+		copy(res.Cert[:], data)
+		res.Length = length
+		return &res
+	}
+	err = test.IsSolved(&parseCircuit{}, assignment(der), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the outer algorithm identifier is ECDSA with SHA-384 while the one of
+	// the TBS certificate is ECDSA with SHA-256.
+	cert, err := stdx509.ParseCertificate(der)
+	assert.NoError(err)
+	alg := append([]byte{0x30, 0x0a, 0x06, 0x08}, oidECDSAWithSHA256...)
+	pos := len(cert.RawTBSCertificate) + tbsOffset
+	assert.Equal(alg, der[pos:pos+len(alg)])
+	tampered := append([]byte{}, der...)
+	tampered[pos+len(alg)-1] = 0x03
+	err = test.IsSolved(&parseCircuit{}, assignment(tampered), ecc.BN254.ScalarField())
+	assert.Error(err)
+}